@@ -79,6 +79,12 @@ type Config struct {
 	SimnetSlotDuration      time.Duration
 	SyntheticBlockProposals bool
 	BuilderAPI              bool
+	RedactSignatures        bool
+	AsyncVerify             bool
+	VerifyReportOnly        bool
+	GenesisValidatorsRoot   string
+	ValidatorMetrics        bool
+	ValidatorAPICacheBudget int64
 
 	TestConfig TestConfig
 }
@@ -135,9 +141,9 @@ func Run(ctx context.Context, conf Config) (err error) {
 	tblsv2.SetImplementation(tblsv2.Herumi{})
 
 	if !featureset.Enabled(featureset.HerumiBLS) {
-		log.Info(ctx, "Enabling Kryptology BLS signature backend")
 		tblsv2.SetImplementation(tblsv2.Kryptology{})
 	}
+	log.Info(ctx, "BLS signature backend enabled", z.Str("implementation", tblsv2.ImplementationName()))
 
 	// Wire processes and their dependencies
 	life := new(lifecycle.Manager)
@@ -219,6 +225,9 @@ func Run(ctx context.Context, conf Config) (err error) {
 	}
 
 	sender := new(p2p.Sender)
+	if featureset.Enabled(featureset.PeerPinning) {
+		sender.SetAllowedPeers(peerIDs...)
+	}
 
 	wirePeerInfo(life, tcpNode, peerIDs, lock.LockHash, sender)
 
@@ -304,17 +313,39 @@ func wireP2P(ctx context.Context, life *lifecycle.Manager, conf Config,
 
 	life.RegisterStop(lifecycle.StopP2PTCPNode, lifecycle.HookFuncErr(tcpNode.Close))
 
+	// Stop routers before reservers release reservations, so no stale relay addresses are routed during shutdown.
 	for _, relay := range relays {
-		life.RegisterStart(lifecycle.AsyncAppCtx, lifecycle.StartRelay, p2p.NewRelayReserver(tcpNode, relay))
+		startReserver, stopReserver := lifecycle.NewStoppable(p2p.NewRelayReserver(tcpNode, relay, p2p.WithReserveWhenPeersUnreachable(peerIDs)))
+		life.RegisterStart(lifecycle.AsyncBackground, lifecycle.StartRelay, startReserver)
+		life.RegisterStop(lifecycle.StopRelay, stopReserver)
 	}
 
 	life.RegisterStart(lifecycle.AsyncAppCtx, lifecycle.StartP2PPing, p2p.NewPingService(tcpNode, peerIDs, conf.TestConfig.TestPingConfig))
 	life.RegisterStart(lifecycle.AsyncAppCtx, lifecycle.StartP2PEventCollector, p2p.NewEventCollector(tcpNode))
-	life.RegisterStart(lifecycle.AsyncAppCtx, lifecycle.StartP2PRouters, p2p.NewRelayRouter(tcpNode, peers, relays))
+	startRouter, stopRouter := lifecycle.NewStoppable(p2p.NewRelayRouter(tcpNode, peers, relays))
+	life.RegisterStart(lifecycle.AsyncBackground, lifecycle.StartP2PRouters, startRouter)
+	life.RegisterStop(lifecycle.StopP2PRouters, stopRouter)
 
 	return tcpNode, nil
 }
 
+// verifyGenesisValidatorsRoot returns the 0x-hex expected genesis validators root or an error if it is invalid or
+// mismatches the genesis validators root reported by the beacon node.
+func verifyGenesisValidatorsRoot(ctx context.Context, eth2Cl eth2wrap.Client, expectedHex string) (eth2p0.Root, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(expectedHex, "0x"))
+	if err != nil {
+		return eth2p0.Root{}, errors.Wrap(err, "decode genesis validators root")
+	} else if len(b) != len(eth2p0.Root{}) {
+		return eth2p0.Root{}, errors.New("invalid genesis validators root length", z.Int("length", len(b)))
+	}
+
+	if err := validatorapi.VerifyGenesisValidatorsRoot(ctx, eth2Cl, eth2p0.Root(b)); err != nil {
+		return eth2p0.Root{}, err
+	}
+
+	return eth2p0.Root(b), nil
+}
+
 // wireCoreWorkflow wires the core workflow components.
 func wireCoreWorkflow(ctx context.Context, life *lifecycle.Manager, conf Config,
 	lock cluster.Lock, nodeIdx cluster.NodeIdx, tcpNode host.Host, p2pKey *k1.PrivateKey,
@@ -403,15 +434,45 @@ func wireCoreWorkflow(ctx context.Context, life *lifecycle.Manager, conf Config,
 
 	dutyDB := dutydb.NewMemDB(deadlinerFunc("dutydb"))
 
-	vapi, err := validatorapi.NewComponent(eth2Cl, allPubSharesByKey, nodeIdx.ShareIdx, feeRecipientFunc,
-		mutableConf.BuilderAPI, seenPubkeys)
+	vapiOpts := []validatorapi.Option{
+		validatorapi.WithFeeRecipientFunc(feeRecipientFunc),
+		validatorapi.WithBuilderEnabled(mutableConf.BuilderAPI),
+		validatorapi.WithSeenPubkeys(seenPubkeys),
+		validatorapi.WithRedactSignatures(conf.RedactSignatures),
+		validatorapi.WithAsyncVerify(conf.AsyncVerify),
+		validatorapi.WithVerifyReportOnly(conf.VerifyReportOnly),
+		validatorapi.WithValidatorSubmissionMetrics(conf.ValidatorMetrics),
+		validatorapi.WithCacheBudget(conf.ValidatorAPICacheBudget),
+	}
+
+	if conf.GenesisValidatorsRoot != "" {
+		genesisValidatorsRoot, err := verifyGenesisValidatorsRoot(ctx, eth2Cl, conf.GenesisValidatorsRoot)
+		if err != nil {
+			return err
+		}
+
+		// Compute fork-independent signing domains locally from the verified genesis data.
+		vapiOpts = append(vapiOpts, validatorapi.WithGenesisData(eth2p0.Version(lock.ForkVersion), genesisValidatorsRoot))
+	}
+
+	vapi, err := validatorapi.New(eth2Cl, allPubSharesByKey, nodeIdx.ShareIdx, vapiOpts...)
 	if err != nil {
 		return err
 	}
 
+	// Validate submitted attestation aggregation bits against beacon committees fetched once per epoch.
+	committees := validatorapi.NewCommitteeCache(eth2Cl)
+	vapi.RegisterCommitteeCache(committees)
+	vapi.RegisterCommitteeSize(committees.CommitteeSize)
+	if !conf.SimnetBMock { // The beacon mock's aggregate attestations aren't signed by committee validators.
+		vapi.RegisterBeaconCommittee(committees.Committee)
+	}
+
 	if err := wireVAPIRouter(life, conf.ValidatorAPIAddr, eth2Cl, vapi, vapiCalls); err != nil {
 		return err
 	}
+	life.RegisterStop(lifecycle.StopValidatorAPIComponent, lifecycle.HookFunc(vapi.Close))
+	vapi.StartGC()
 
 	parSigDB := parsigdb.NewMemDB(lock.Threshold, deadlinerFunc("parsigdb"))
 
@@ -427,7 +488,7 @@ func wireCoreWorkflow(ctx context.Context, life *lifecycle.Manager, conf Config,
 		parSigEx = parsigex.NewParSigEx(tcpNode, sender.SendAsync, nodeIdx.PeerIdx, peerIDs, verifyFunc)
 	}
 
-	sigAgg := sigagg.New(lock.Threshold)
+	sigAgg := sigagg.New(lock.Threshold, sigagg.NewVerifier(eth2Cl))
 
 	aggSigDB := aggsigdb.NewMemDB(deadlinerFunc("aggsigdb"))
 
@@ -463,7 +524,7 @@ func wireCoreWorkflow(ctx context.Context, life *lifecycle.Manager, conf Config,
 	}
 	core.Wire(sched, fetch, cons, dutyDB, vapi, parSigDB, parSigEx, sigAgg, aggSigDB, broadcaster, opts...)
 
-	err = wireValidatorMock(conf, pubshares, sched)
+	err = wireValidatorMock(conf, pubshares, allPubSharesByKey, nodeIdx.ShareIdx, sched)
 	if err != nil {
 		return err
 	}
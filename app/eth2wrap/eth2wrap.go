@@ -6,6 +6,7 @@ package eth2wrap
 import (
 	"context"
 	"net"
+	"net/http"
 	"net/url"
 	"sync"
 	"time"
@@ -24,6 +25,9 @@ import (
 
 //go:generate go run genwrap/genwrap.go
 
+// ErrRateLimited is returned when the beacon node rate limits a request, see IsRateLimited.
+var ErrRateLimited = errors.NewSentinel("beacon node rate limited")
+
 const (
 	zeroLogInfo = 1           // Avoid importing zero log for this constant.
 	bestPeriod  = time.Minute // Best client selector period.
@@ -225,6 +229,8 @@ func provide[O any](ctx context.Context, clients []Client,
 		if ctx.Err() != nil {
 			return zero, ctx.Err()
 		} else if res.Err == nil && isSuccess(res.Output) {
+			recordServedBy(ctx, res.Input.Address())
+
 			// TODO(corver): Find a better way to get the index of successful client.
 			for i, client := range clients {
 				if client.Address() == res.Input.Address() {
@@ -284,12 +290,17 @@ func incError(endpoint string) {
 func wrapError(ctx context.Context, err error, label string) error {
 	// Decompose go-eth2-client http errors
 	if e2err := new(eth2http.Error); errors.As(err, e2err) {
-		err = errors.New("nok http response",
+		fields := []z.Field{
 			z.Int("status_code", e2err.StatusCode),
 			z.Str("endpoint", e2err.Endpoint),
 			z.Str("method", e2err.Method),
 			z.Str("data", string(e2err.Data)),
-		)
+		}
+		if e2err.StatusCode == http.StatusTooManyRequests {
+			err = errors.Wrap(ErrRateLimited, "nok http response", fields...)
+		} else {
+			err = errors.New("nok http response", fields...)
+		}
 	}
 
 	// Decompose url errors
@@ -320,6 +331,18 @@ func wrapError(ctx context.Context, err error, label string) error {
 	return errors.Wrap(err, "beacon api "+label, z.Str("label", label))
 }
 
+// IsRateLimited returns true if the error indicates the beacon node rate limited the request,
+// i.e. it responded with http status 429 Too Many Requests.
+func IsRateLimited(err error) bool {
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+
+	e2err := new(eth2http.Error)
+
+	return errors.As(err, e2err) && e2err.StatusCode == http.StatusTooManyRequests
+}
+
 // newBestSelector returns a new bestSelector.
 func newBestSelector(n int, period time.Duration) *bestSelector {
 	return &bestSelector{
@@ -34,6 +34,7 @@ type Client interface {
 	eth2client.BeaconBlockRootProvider
 	eth2client.BeaconBlockSubmitter
 	eth2client.BeaconCommitteeSubscriptionsSubmitter
+	eth2client.BeaconCommitteesProvider
 	eth2client.BlindedBeaconBlockProposalProvider
 	eth2client.BlindedBeaconBlockSubmitter
 	eth2client.DepositContractProvider
@@ -56,6 +57,7 @@ type Client interface {
 	eth2client.SyncCommitteeDutiesProvider
 	eth2client.SyncCommitteeMessagesSubmitter
 	eth2client.SyncCommitteeSubscriptionsSubmitter
+	eth2client.ValidatorBalancesProvider
 	eth2client.ValidatorRegistrationsSubmitter
 	eth2client.ValidatorsProvider
 	eth2client.VoluntaryExitSubmitter
@@ -161,6 +163,46 @@ func (m multi) SignedBeaconBlock(ctx context.Context, blockID string) (*spec.Ver
 	return res0, err
 }
 
+// BeaconCommittees fetches all beacon committees for the epoch at the given state.
+func (m multi) BeaconCommittees(ctx context.Context, stateID string) ([]*apiv1.BeaconCommittee, error) {
+	const label = "beacon_committees"
+	defer latency(label)()
+
+	res0, err := provide(ctx, m.clients,
+		func(ctx context.Context, cl Client) ([]*apiv1.BeaconCommittee, error) {
+			return cl.BeaconCommittees(ctx, stateID)
+		},
+		nil, m.bestIdx,
+	)
+
+	if err != nil {
+		incError(label)
+		err = wrapError(ctx, err, label)
+	}
+
+	return res0, err
+}
+
+// BeaconCommitteesAtEpoch fetches all beacon committees for the given epoch at the given state.
+func (m multi) BeaconCommitteesAtEpoch(ctx context.Context, stateID string, epoch phase0.Epoch) ([]*apiv1.BeaconCommittee, error) {
+	const label = "beacon_committees_at_epoch"
+	defer latency(label)()
+
+	res0, err := provide(ctx, m.clients,
+		func(ctx context.Context, cl Client) ([]*apiv1.BeaconCommittee, error) {
+			return cl.BeaconCommitteesAtEpoch(ctx, stateID, epoch)
+		},
+		nil, m.bestIdx,
+	)
+
+	if err != nil {
+		incError(label)
+		err = wrapError(ctx, err, label)
+	}
+
+	return res0, err
+}
+
 // AggregateAttestation fetches the aggregate attestation given an attestation.
 func (m multi) AggregateAttestation(ctx context.Context, slot phase0.Slot, attestationDataRoot phase0.Root) (*phase0.Attestation, error) {
 	const label = "aggregate_attestation"
@@ -665,6 +707,29 @@ func (m multi) Spec(ctx context.Context) (map[string]interface{}, error) {
 	return res0, err
 }
 
+// ValidatorBalances provides the validator balances for a given state.
+// stateID can be a slot number or state root, or one of the special values "genesis", "head", "justified" or "finalized".
+// validatorIndices is a list of validator indices to restrict the returned values.  If no validators are supplied no filter
+// will be applied.
+func (m multi) ValidatorBalances(ctx context.Context, stateID string, validatorIndices []phase0.ValidatorIndex) (map[phase0.ValidatorIndex]phase0.Gwei, error) {
+	const label = "validator_balances"
+	defer latency(label)()
+
+	res0, err := provide(ctx, m.clients,
+		func(ctx context.Context, cl Client) (map[phase0.ValidatorIndex]phase0.Gwei, error) {
+			return cl.ValidatorBalances(ctx, stateID, validatorIndices)
+		},
+		nil, m.bestIdx,
+	)
+
+	if err != nil {
+		incError(label)
+		err = wrapError(ctx, err, label)
+	}
+
+	return res0, err
+}
+
 // Validators provides the validators, with their balance and status, for a given state.
 // stateID can be a slot number or state root, or one of the special values "genesis", "head", "justified" or "finalized".
 // validatorIndices is a list of validator indices to restrict the returned values.  If no validators IDs are supplied no filter
@@ -844,6 +909,26 @@ func (l *lazy) SignedBeaconBlock(ctx context.Context, blockID string) (res0 *spe
 	return cl.SignedBeaconBlock(ctx, blockID)
 }
 
+// BeaconCommittees fetches all beacon committees for the epoch at the given state.
+func (l *lazy) BeaconCommittees(ctx context.Context, stateID string) (res0 []*apiv1.BeaconCommittee, err error) {
+	cl, err := l.getClient()
+	if err != nil {
+		return res0, err
+	}
+
+	return cl.BeaconCommittees(ctx, stateID)
+}
+
+// BeaconCommitteesAtEpoch fetches all beacon committees for the given epoch at the given state.
+func (l *lazy) BeaconCommitteesAtEpoch(ctx context.Context, stateID string, epoch phase0.Epoch) (res0 []*apiv1.BeaconCommittee, err error) {
+	cl, err := l.getClient()
+	if err != nil {
+		return res0, err
+	}
+
+	return cl.BeaconCommitteesAtEpoch(ctx, stateID, epoch)
+}
+
 // AggregateAttestation fetches the aggregate attestation given an attestation.
 func (l *lazy) AggregateAttestation(ctx context.Context, slot phase0.Slot, attestationDataRoot phase0.Root) (res0 *phase0.Attestation, err error) {
 	cl, err := l.getClient()
@@ -1098,6 +1183,19 @@ func (l *lazy) Spec(ctx context.Context) (res0 map[string]interface{}, err error
 	return cl.Spec(ctx)
 }
 
+// ValidatorBalances provides the validator balances for a given state.
+// stateID can be a slot number or state root, or one of the special values "genesis", "head", "justified" or "finalized".
+// validatorIndices is a list of validator indices to restrict the returned values.  If no validators are supplied no filter
+// will be applied.
+func (l *lazy) ValidatorBalances(ctx context.Context, stateID string, validatorIndices []phase0.ValidatorIndex) (res0 map[phase0.ValidatorIndex]phase0.Gwei, err error) {
+	cl, err := l.getClient()
+	if err != nil {
+		return res0, err
+	}
+
+	return cl.ValidatorBalances(ctx, stateID, validatorIndices)
+}
+
 // Validators provides the validators, with their balance and status, for a given state.
 // stateID can be a slot number or state root, or one of the special values "genesis", "head", "justified" or "finalized".
 // validatorIndices is a list of validator indices to restrict the returned values.  If no validators IDs are supplied no filter
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	eth2v1 "github.com/attestantio/go-eth2-client/api/v1"
+	eth2http "github.com/attestantio/go-eth2-client/http"
 	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/stretchr/testify/require"
 
@@ -212,6 +213,21 @@ func TestErrors(t *testing.T) {
 		require.ErrorContains(t, err, "beacon api aggregate_attestation: nok http response")
 	})
 
+	t.Run("rate limited", func(t *testing.T) {
+		bmock, err := beaconmock.New()
+		require.NoError(t, err)
+		bmock.AttesterDutiesFunc = func(context.Context, eth2p0.Epoch, []eth2p0.ValidatorIndex) ([]*eth2v1.AttesterDuty, error) {
+			return nil, eth2http.Error{Method: "POST", Endpoint: "/eth/v1/validator/duties/attester/0", StatusCode: http.StatusTooManyRequests}
+		}
+		eth2Cl, err := eth2wrap.Instrument(bmock)
+		require.NoError(t, err)
+
+		_, err = eth2Cl.AttesterDuties(ctx, 0, nil)
+		require.ErrorContains(t, err, "beacon api attester_duties: nok http response: beacon node rate limited")
+		require.True(t, eth2wrap.IsRateLimited(err))
+		require.False(t, eth2wrap.IsRateLimited(errors.New("other")))
+	})
+
 	t.Run("zero net op error", func(t *testing.T) {
 		bmock, err := beaconmock.New()
 		require.NoError(t, err)
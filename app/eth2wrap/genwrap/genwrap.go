@@ -99,6 +99,7 @@ type Client interface {
 		"BeaconBlockProposalProvider":           true,
 		"BeaconBlockRootProvider":               false,
 		"BeaconBlockSubmitter":                  true,
+		"BeaconCommitteesProvider":              true,
 		"BeaconCommitteeSubscriptionsSubmitter": true,
 		"BlindedBeaconBlockProposalProvider":    true,
 		"BlindedBeaconBlockSubmitter":           true,
@@ -122,6 +123,7 @@ type Client interface {
 		"SyncCommitteeContributionsSubmitter":   true,
 		"SyncCommitteeMessagesSubmitter":        true,
 		"SyncCommitteeSubscriptionsSubmitter":   true,
+		"ValidatorBalancesProvider":             true,
 		"ValidatorsProvider":                    true,
 		"ValidatorRegistrationsSubmitter":       true,
 		"VoluntaryExitSubmitter":                true,
@@ -0,0 +1,55 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package eth2wrap
+
+import (
+	"context"
+	"sync"
+)
+
+type servedByKey struct{}
+
+// servedBy records the addresses of the beacon nodes that served requests.
+type servedBy struct {
+	mu        sync.Mutex
+	addresses []string
+}
+
+// WithServedBy returns a copy of the context that records the addresses of the beacon nodes
+// successfully serving requests made with it, see ServedBy.
+func WithServedBy(ctx context.Context) context.Context {
+	return context.WithValue(ctx, servedByKey{}, new(servedBy))
+}
+
+// ServedBy returns the unique addresses of the beacon nodes that served requests made with the context
+// in order of first response. It returns nil if the context was not created via WithServedBy.
+func ServedBy(ctx context.Context) []string {
+	s, ok := ctx.Value(servedByKey{}).(*servedBy)
+	if !ok {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.addresses...)
+}
+
+// recordServedBy records the address of the beacon node that served a request made with the context.
+func recordServedBy(ctx context.Context, address string) {
+	s, ok := ctx.Value(servedByKey{}).(*servedBy)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, addr := range s.addresses {
+		if addr == address {
+			return
+		}
+	}
+
+	s.addresses = append(s.addresses, address)
+}
@@ -41,16 +41,31 @@ const (
 
 	// HerumiBLS enables usage of the Herumi BLS12-381 implementation, rather than Kryptology.
 	HerumiBLS Feature = "herumi_bls"
+
+	// PeerPinning enables strict peer identity enforcement by only sending p2p messages to cluster peers.
+	PeerPinning Feature = "peer_pinning"
+
+	// StrictFeeRecipient enables rejecting blinded block proposals with a fee recipient not matching the configured
+	// fee recipient address, preventing a malicious builder or relay from redirecting block rewards.
+	// Disable it to only log a warning, e.g. for builders paying the proposer in the last transaction.
+	StrictFeeRecipient Feature = "strict_fee_recipient"
+
+	// BeaconNodeHeader enables annotating validator API responses with the beacon nodes that served them
+	// via the X-Charon-Beacon-Node header.
+	BeaconNodeHeader Feature = "beacon_node_header"
 )
 
 var (
 	// state defines the current rollout status of each feature.
 	state = map[Feature]status{
-		QBFTConsensus:  statusStable,
-		Priority:       statusStable,
-		MockAlpha:      statusAlpha,
-		RelayDiscovery: statusStable,
-		HerumiBLS:      statusStable,
+		QBFTConsensus:      statusStable,
+		Priority:           statusStable,
+		MockAlpha:          statusAlpha,
+		RelayDiscovery:     statusStable,
+		HerumiBLS:          statusStable,
+		PeerPinning:        statusAlpha,
+		StrictFeeRecipient: statusStable,
+		BeaconNodeHeader:   statusAlpha,
 		// Add all features and there status here.
 	}
 
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"runtime/pprof"
+	"sync"
 	"time"
 
 	"github.com/obolnetwork/charon/app/errors"
@@ -49,6 +50,80 @@ func (fn HookFuncCtx) Call(ctx context.Context) error {
 	return nil
 }
 
+// NewStoppable returns a start hook wrapping the hook function and a stop hook that cancels the start hook's context
+// and waits for it to return. This allows ordering the graceful shutdown of processes that are stopped by
+// context cancellation relative to other stop hooks. The start hook should be registered as AsyncBackground.
+func NewStoppable(fn IHookFunc) (start IHookFunc, stop IHookFunc) {
+	s := &stoppable{
+		fn:   fn,
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	return HookFunc(s.start), HookFunc(s.stop)
+}
+
+// stoppable is a hook function that can be stopped by a stop hook.
+type stoppable struct {
+	fn   IHookFunc
+	quit chan struct{}
+	done chan struct{}
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+}
+
+// start calls the hook function with a context that is cancelled when stopped.
+func (s *stoppable) start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	defer close(s.done)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-s.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return s.fn.Call(ctx)
+}
+
+// stop cancels the hook function's context and waits for it to return, if started.
+func (s *stoppable) stop(ctx context.Context) error {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	s.stopped = true
+	started := s.started
+	close(s.quit)
+	s.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "wait for hook to stop")
+	}
+}
+
 // HookStartType defines the type of start hook.
 type HookStartType int
 
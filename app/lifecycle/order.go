@@ -35,6 +35,9 @@ const (
 	StopDutyDB
 	StopBeaconMock // Close this before validator API, since it can hold long-lived connections.
 	StopValidatorAPI
+	StopValidatorAPIComponent // Close after the validator API server, since requests may use the component.
+	StopP2PRouters            // Stop routing relay addresses before releasing relay reservations, avoiding stale addresses.
+	StopRelay
 	StopTracing // Low level services...
 	StopP2PPeerDB
 	StopP2PTCPNode
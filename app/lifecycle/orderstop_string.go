@@ -15,16 +15,19 @@ func _() {
 	_ = x[StopDutyDB-2]
 	_ = x[StopBeaconMock-3]
 	_ = x[StopValidatorAPI-4]
-	_ = x[StopTracing-5]
-	_ = x[StopP2PPeerDB-6]
-	_ = x[StopP2PTCPNode-7]
-	_ = x[StopP2PUDPNode-8]
-	_ = x[StopMonitoringAPI-9]
+	_ = x[StopValidatorAPIComponent-5]
+	_ = x[StopP2PRouters-6]
+	_ = x[StopRelay-7]
+	_ = x[StopTracing-8]
+	_ = x[StopP2PPeerDB-9]
+	_ = x[StopP2PTCPNode-10]
+	_ = x[StopP2PUDPNode-11]
+	_ = x[StopMonitoringAPI-12]
 }
 
-const _OrderStop_name = "SchedulerRetryerDutyDBBeaconMockValidatorAPITracingP2PPeerDBP2PTCPNodeP2PUDPNodeMonitoringAPI"
+const _OrderStop_name = "SchedulerRetryerDutyDBBeaconMockValidatorAPIValidatorAPIComponentP2PRoutersRelayTracingP2PPeerDBP2PTCPNodeP2PUDPNodeMonitoringAPI"
 
-var _OrderStop_index = [...]uint8{0, 9, 16, 22, 32, 44, 51, 60, 70, 80, 93}
+var _OrderStop_index = [...]uint8{0, 9, 16, 22, 32, 44, 65, 75, 80, 87, 96, 106, 116, 129}
 
 func (i OrderStop) String() string {
 	if i < 0 || i >= OrderStop(len(_OrderStop_index)-1) {
@@ -164,9 +164,12 @@ func InitLogger(config Config) error {
 }
 
 // InitConsoleForT initialises a console logger for testing purposes.
-func InitConsoleForT(_ *testing.T, ws zapcore.WriteSyncer, opts ...func(*zapcore.EncoderConfig)) {
+func InitConsoleForT(t *testing.T, ws zapcore.WriteSyncer, opts ...func(*zapcore.EncoderConfig)) {
+	t.Helper()
 	initMu.Lock()
 	defer initMu.Unlock()
+
+	restoreForT(t)
 	logger = newConsoleLogger(zapcore.DebugLevel, ws, opts...)
 }
 
@@ -176,6 +179,8 @@ func InitJSONForT(t *testing.T, ws zapcore.WriteSyncer, opts ...func(*zapcore.En
 	initMu.Lock()
 	defer initMu.Unlock()
 
+	restoreForT(t)
+
 	var err error
 	logger, err = newStructuredLogger("json", zapcore.DebugLevel, ws, defaultCallerSkip, opts...)
 	require.NoError(t, err)
@@ -187,11 +192,27 @@ func InitLogfmtForT(t *testing.T, ws zapcore.WriteSyncer, opts ...func(*zapcore.
 	initMu.Lock()
 	defer initMu.Unlock()
 
+	restoreForT(t)
+
 	var err error
 	logger, err = newStructuredLogger("logfmt", zapcore.DebugLevel, ws, defaultCallerSkip, opts...)
 	require.NoError(t, err)
 }
 
+// restoreForT restores the current logger when the test completes, so the writer of the test's logger,
+// often a buffer, isn't written to by subsequent tests. It must be called with the lock held.
+func restoreForT(t *testing.T) {
+	t.Helper()
+
+	prev := logger
+	t.Cleanup(func() {
+		initMu.Lock()
+		defer initMu.Unlock()
+
+		logger = prev
+	})
+}
+
 // Stop stops all log processors.
 func Stop(ctx context.Context) {
 	initMu.Lock()
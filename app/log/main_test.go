@@ -0,0 +1,15 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package log_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/obolnetwork/charon/testutil"
+)
+
+// TestMain is defined in a separate file, since the goldens of log_test.go contain its line numbers.
+func TestMain(m *testing.M) {
+	os.Exit(testutil.MainWithGoldens(m))
+}
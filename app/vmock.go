@@ -16,19 +16,23 @@ import (
 	"github.com/obolnetwork/charon/app/log"
 	"github.com/obolnetwork/charon/app/z"
 	"github.com/obolnetwork/charon/core"
+	"github.com/obolnetwork/charon/core/validatorapi"
 	"github.com/obolnetwork/charon/eth2util"
 	"github.com/obolnetwork/charon/eth2util/keystore"
+	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
 	"github.com/obolnetwork/charon/testutil/validatormock"
 )
 
 // wireValidatorMock wires the validator mock if enabled. It connects via http validatorapi.Router.
-func wireValidatorMock(conf Config, pubshares []eth2p0.BLSPubKey, sched core.Scheduler) error {
+func wireValidatorMock(conf Config, pubshares []eth2p0.BLSPubKey, allPubSharesByKey map[core.PubKey]map[int]tblsv2.PublicKey,
+	shareIdx int, sched core.Scheduler,
+) error {
 	if !conf.SimnetVMock {
 		return nil
 	}
 
 	// Create stateful wrapper
-	vMockWrap, err := newVMockWrapper(conf, pubshares)
+	vMockWrap, err := newVMockWrapper(conf, pubshares, allPubSharesByKey, shareIdx)
 	if err != nil {
 		return err
 	}
@@ -105,9 +109,11 @@ type vMockState struct {
 type vMockCallback func(context.Context, vMockState) error
 
 // newVMockWrapper returns a stateful validator mock wrapper function.
-func newVMockWrapper(conf Config, pubshares []eth2p0.BLSPubKey) (func(ctx context.Context, slot int64, callback vMockCallback), error) {
+func newVMockWrapper(conf Config, pubshares []eth2p0.BLSPubKey, allPubSharesByKey map[core.PubKey]map[int]tblsv2.PublicKey,
+	shareIdx int,
+) (func(ctx context.Context, slot int64, callback vMockCallback), error) {
 	// Immutable state and providers.
-	signFunc, err := newVMockSigner(conf, pubshares)
+	signFunc, err := newVMockSigner(conf, pubshares, allPubSharesByKey, shareIdx)
 	if err != nil {
 		return nil, err
 	}
@@ -211,7 +217,10 @@ func newVMockEth2Provider(conf Config) func() (eth2wrap.Client, error) {
 }
 
 // newVMockSigner returns a validator mock sign function using keystore loaded from disk.
-func newVMockSigner(conf Config, pubshares []eth2p0.BLSPubKey) (validatormock.SignFunc, error) {
+// It verifies that the keys include this node's share of each distributed validator.
+func newVMockSigner(conf Config, pubshares []eth2p0.BLSPubKey, allPubSharesByKey map[core.PubKey]map[int]tblsv2.PublicKey,
+	shareIdx int,
+) (validatormock.SignFunc, error) {
 	secrets := conf.TestConfig.SimnetKeys
 	if len(secrets) == 0 {
 		var err error
@@ -232,6 +241,9 @@ func newVMockSigner(conf Config, pubshares []eth2p0.BLSPubKey) (validatormock.Si
 	if len(secrets) < len(pubshares) {
 		return nil, errors.New("some validator mock keys missing", z.Int("expect", len(pubshares)), z.Int("found", len(secrets)))
 	}
+	if err := validatorapi.VerifyShareAssignment(allPubSharesByKey, shareIdx, secrets); err != nil {
+		return nil, errors.Wrap(err, "verify validator mock key share assignment")
+	}
 	for i, pubshare := range pubshares {
 		_, err := signer(pubshare, []byte("test signing"))
 		if err != nil {
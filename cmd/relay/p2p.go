@@ -62,7 +62,7 @@ func startP2P(ctx context.Context, config Config, key *k1.PrivateKey, reporter m
 
 	// Reservations are valid for 30min (github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/relay/constraints.go:14)
 	relayResources := relay.DefaultResources()
-	relayResources.Limit.Data = 32 * (1 << 20) // 32MB
+	relayResources.Limit.Data = p2p.RelayCircuitDataLimit
 	relayResources.MaxReservationsPerPeer = config.MaxResPerPeer
 	relayResources.MaxReservationsPerIP = config.MaxResPerPeer
 	relayResources.MaxReservations = config.MaxConns
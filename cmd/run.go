@@ -73,6 +73,12 @@ func bindRunFlags(cmd *cobra.Command, config *app.Config) {
 	cmd.Flags().StringVar(&config.SimnetValidatorKeysDir, "simnet-validator-keys-dir", ".charon/validator_keys", "The directory containing the simnet validator key shares.")
 	cmd.Flags().BoolVar(&config.BuilderAPI, "builder-api", false, "Enables the builder api. Will only produce builder blocks. Builder API must also be enabled on the validator client. Beacon node must be connected to a builder-relay to access the builder network.")
 	cmd.Flags().BoolVar(&config.SyntheticBlockProposals, "synthetic-block-proposals", false, "Enables additional synthetic block proposal duties. Used for testing of rare duties.")
+	cmd.Flags().BoolVar(&config.RedactSignatures, "redact-signatures", false, "Excludes signature material from partial signature verification failure logs.")
+	cmd.Flags().BoolVar(&config.AsyncVerify, "async-verify", false, "Verifies submitted attestation partial signatures asynchronously, temporarily quarantining validators with mismatching signatures. Reduces latency, only use in trusted environments.")
+	cmd.Flags().BoolVar(&config.VerifyReportOnly, "verify-report-only", false, "Only logs and counts partial signature verification failures instead of rejecting submissions. Use temporarily for validating configuration changes against live traffic, since invalid partial signatures are still stored and broadcast to peers, failing the affected duties.")
+	cmd.Flags().Int64Var(&config.ValidatorAPICacheBudget, "validator-api-cache-budget", 0, "Bounds the total approximate memory in bytes of the validator API caches, evicting the least recently used entries across caches once exceeded. Zero disables the budget.")
+	cmd.Flags().BoolVar(&config.ValidatorMetrics, "validator-metrics", false, "Enables per-validator partial signature submission metrics. Disabled by default due to high metric cardinality with many validators.")
+	cmd.Flags().StringVar(&config.GenesisValidatorsRoot, "genesis-validators-root", "", "Expected 0x-hex genesis validators root of the beacon node network. Charon refuses to start if the beacon node reports a different root. Disabled by default.")
 	cmd.Flags().DurationVar(&config.SimnetSlotDuration, "simnet-slot-duration", time.Second, "Configures slot duration in simnet beacon mock.")
 
 	wrapPreRunE(cmd, func(cmd *cobra.Command, args []string) error {
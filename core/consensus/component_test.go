@@ -30,7 +30,7 @@ import (
 
 func TestMain(m *testing.M) {
 	tblsv2.SetImplementation(tblsv2.Herumi{})
-	os.Exit(m.Run())
+	os.Exit(testutil.MainWithGoldens(m))
 }
 
 func TestComponent(t *testing.T) {
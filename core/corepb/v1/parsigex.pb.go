@@ -75,6 +75,210 @@ func (x *ParSigExMsg) GetDataSet() *ParSignedDataSet {
 	return nil
 }
 
+type ParSigExBatchEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Duty   *Duty          `protobuf:"bytes,1,opt,name=duty,proto3" json:"duty,omitempty"`
+	Pubkey string         `protobuf:"bytes,2,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
+	Data   *ParSignedData `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ParSigExBatchEntry) Reset() {
+	*x = ParSigExBatchEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_corepb_v1_parsigex_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParSigExBatchEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParSigExBatchEntry) ProtoMessage() {}
+
+func (x *ParSigExBatchEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_core_corepb_v1_parsigex_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParSigExBatchEntry.ProtoReflect.Descriptor instead.
+func (*ParSigExBatchEntry) Descriptor() ([]byte, []int) {
+	return file_core_corepb_v1_parsigex_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ParSigExBatchEntry) GetDuty() *Duty {
+	if x != nil {
+		return x.Duty
+	}
+	return nil
+}
+
+func (x *ParSigExBatchEntry) GetPubkey() string {
+	if x != nil {
+		return x.Pubkey
+	}
+	return ""
+}
+
+func (x *ParSigExBatchEntry) GetData() *ParSignedData {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ParSigExBatchMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*ParSigExBatchEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *ParSigExBatchMsg) Reset() {
+	*x = ParSigExBatchMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_corepb_v1_parsigex_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParSigExBatchMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParSigExBatchMsg) ProtoMessage() {}
+
+func (x *ParSigExBatchMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_core_corepb_v1_parsigex_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParSigExBatchMsg.ProtoReflect.Descriptor instead.
+func (*ParSigExBatchMsg) Descriptor() ([]byte, []int) {
+	return file_core_corepb_v1_parsigex_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ParSigExBatchMsg) GetEntries() []*ParSigExBatchEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ParSigExBatchAck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ParSigExBatchAck) Reset() {
+	*x = ParSigExBatchAck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_corepb_v1_parsigex_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParSigExBatchAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParSigExBatchAck) ProtoMessage() {}
+
+func (x *ParSigExBatchAck) ProtoReflect() protoreflect.Message {
+	mi := &file_core_corepb_v1_parsigex_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParSigExBatchAck.ProtoReflect.Descriptor instead.
+func (*ParSigExBatchAck) Descriptor() ([]byte, []int) {
+	return file_core_corepb_v1_parsigex_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ParSigExBatchAck) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ParSigExBatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Acks []*ParSigExBatchAck `protobuf:"bytes,1,rep,name=acks,proto3" json:"acks,omitempty"`
+}
+
+func (x *ParSigExBatchResponse) Reset() {
+	*x = ParSigExBatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_core_corepb_v1_parsigex_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParSigExBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParSigExBatchResponse) ProtoMessage() {}
+
+func (x *ParSigExBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_core_corepb_v1_parsigex_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParSigExBatchResponse.ProtoReflect.Descriptor instead.
+func (*ParSigExBatchResponse) Descriptor() ([]byte, []int) {
+	return file_core_corepb_v1_parsigex_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ParSigExBatchResponse) GetAcks() []*ParSigExBatchAck {
+	if x != nil {
+		return x.Acks
+	}
+	return nil
+}
+
 var File_core_corepb_v1_parsigex_proto protoreflect.FileDescriptor
 
 var file_core_corepb_v1_parsigex_proto_rawDesc = []byte{
@@ -90,10 +294,31 @@ var file_core_corepb_v1_parsigex_proto_rawDesc = []byte{
 	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x63, 0x6f, 0x72,
 	0x65, 0x70, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64,
 	0x44, 0x61, 0x74, 0x61, 0x53, 0x65, 0x74, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x53, 0x65, 0x74,
-	0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f,
-	0x62, 0x6f, 0x6c, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x63, 0x68, 0x61, 0x72, 0x6f,
-	0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x70, 0x62, 0x2f, 0x76, 0x31,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x22, 0x89, 0x01, 0x0a, 0x12, 0x50, 0x61, 0x72, 0x53, 0x69, 0x67, 0x45, 0x78, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x28, 0x0a, 0x04, 0x64, 0x75, 0x74, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x70, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x75, 0x74, 0x79, 0x52, 0x04, 0x64, 0x75, 0x74,
+	0x79, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x31, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x63,
+	0x6f, 0x72, 0x65, 0x70, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x53, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x50, 0x0a, 0x10,
+	0x50, 0x61, 0x72, 0x53, 0x69, 0x67, 0x45, 0x78, 0x42, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x73, 0x67,
+	0x12, 0x3c, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x22, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x70, 0x62, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x53, 0x69, 0x67, 0x45, 0x78, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x28,
+	0x0a, 0x10, 0x50, 0x61, 0x72, 0x53, 0x69, 0x67, 0x45, 0x78, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41,
+	0x63, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x4d, 0x0a, 0x15, 0x50, 0x61, 0x72, 0x53,
+	0x69, 0x67, 0x45, 0x78, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x34, 0x0a, 0x04, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x20, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x70, 0x62, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x72, 0x53, 0x69, 0x67, 0x45, 0x78, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41, 0x63,
+	0x6b, 0x52, 0x04, 0x61, 0x63, 0x6b, 0x73, 0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x62, 0x6f, 0x6c, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x2f, 0x63, 0x68, 0x61, 0x72, 0x6f, 0x6e, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x63, 0x6f,
+	0x72, 0x65, 0x70, 0x62, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -108,20 +333,29 @@ func file_core_corepb_v1_parsigex_proto_rawDescGZIP() []byte {
 	return file_core_corepb_v1_parsigex_proto_rawDescData
 }
 
-var file_core_corepb_v1_parsigex_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_core_corepb_v1_parsigex_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_core_corepb_v1_parsigex_proto_goTypes = []interface{}{
-	(*ParSigExMsg)(nil),      // 0: core.corepb.v1.ParSigExMsg
-	(*Duty)(nil),             // 1: core.corepb.v1.Duty
-	(*ParSignedDataSet)(nil), // 2: core.corepb.v1.ParSignedDataSet
+	(*ParSigExMsg)(nil),           // 0: core.corepb.v1.ParSigExMsg
+	(*ParSigExBatchEntry)(nil),    // 1: core.corepb.v1.ParSigExBatchEntry
+	(*ParSigExBatchMsg)(nil),      // 2: core.corepb.v1.ParSigExBatchMsg
+	(*ParSigExBatchAck)(nil),      // 3: core.corepb.v1.ParSigExBatchAck
+	(*ParSigExBatchResponse)(nil), // 4: core.corepb.v1.ParSigExBatchResponse
+	(*Duty)(nil),                  // 5: core.corepb.v1.Duty
+	(*ParSignedDataSet)(nil),      // 6: core.corepb.v1.ParSignedDataSet
+	(*ParSignedData)(nil),         // 7: core.corepb.v1.ParSignedData
 }
 var file_core_corepb_v1_parsigex_proto_depIdxs = []int32{
-	1, // 0: core.corepb.v1.ParSigExMsg.duty:type_name -> core.corepb.v1.Duty
-	2, // 1: core.corepb.v1.ParSigExMsg.data_set:type_name -> core.corepb.v1.ParSignedDataSet
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	5, // 0: core.corepb.v1.ParSigExMsg.duty:type_name -> core.corepb.v1.Duty
+	6, // 1: core.corepb.v1.ParSigExMsg.data_set:type_name -> core.corepb.v1.ParSignedDataSet
+	5, // 2: core.corepb.v1.ParSigExBatchEntry.duty:type_name -> core.corepb.v1.Duty
+	7, // 3: core.corepb.v1.ParSigExBatchEntry.data:type_name -> core.corepb.v1.ParSignedData
+	1, // 4: core.corepb.v1.ParSigExBatchMsg.entries:type_name -> core.corepb.v1.ParSigExBatchEntry
+	3, // 5: core.corepb.v1.ParSigExBatchResponse.acks:type_name -> core.corepb.v1.ParSigExBatchAck
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_core_corepb_v1_parsigex_proto_init() }
@@ -143,6 +377,54 @@ func file_core_corepb_v1_parsigex_proto_init() {
 				return nil
 			}
 		}
+		file_core_corepb_v1_parsigex_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParSigExBatchEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_corepb_v1_parsigex_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParSigExBatchMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_corepb_v1_parsigex_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParSigExBatchAck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_core_corepb_v1_parsigex_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParSigExBatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -150,7 +432,7 @@ func file_core_corepb_v1_parsigex_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_core_corepb_v1_parsigex_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -27,6 +27,29 @@ var (
 	_ Eth2SignedData = SyncCommitteeSelection{}
 )
 
+// dutyDomainNames maps each duty type to the signing domain name of its eth2 signed data.
+// Duty types not signed with a single domain (e.g. DutySignature, DutyInfoSync) are omitted.
+var dutyDomainNames = map[DutyType]signing.DomainName{
+	DutyProposer:                signing.DomainBeaconProposer,
+	DutyBuilderProposer:         signing.DomainBeaconProposer,
+	DutyAttester:                signing.DomainBeaconAttester,
+	DutyExit:                    signing.DomainExit,
+	DutyBuilderRegistration:     signing.DomainApplicationBuilder,
+	DutyRandao:                  signing.DomainRandao,
+	DutyPrepareAggregator:       signing.DomainSelectionProof,
+	DutyAggregator:              signing.DomainAggregateAndProof,
+	DutySyncMessage:             signing.DomainSyncCommittee,
+	DutyPrepareSyncContribution: signing.DomainSyncCommitteeSelectionProof,
+	DutySyncContribution:        signing.DomainContributionAndProof,
+}
+
+// DutyDomainName returns the signing domain name of the duty type's eth2 signed data and true,
+// or false if the duty type isn't signed with a single domain.
+func DutyDomainName(duty DutyType) (signing.DomainName, bool) {
+	name, ok := dutyDomainNames[duty]
+	return name, ok
+}
+
 // VerifyEth2SignedData verifies signature associated with given Eth2SignedData.
 func VerifyEth2SignedData(ctx context.Context, eth2Cl eth2wrap.Client, data Eth2SignedData, pubkey tblsv2.PublicKey) error {
 	epoch, err := data.Epoch(ctx, eth2Cl)
@@ -45,7 +68,7 @@ func VerifyEth2SignedData(ctx context.Context, eth2Cl eth2wrap.Client, data Eth2
 // Implement Eth2SignedData for VersionedSignedBeaconBlock.
 
 func (VersionedSignedBeaconBlock) DomainName() signing.DomainName {
-	return signing.DomainBeaconProposer
+	return dutyDomainNames[DutyProposer]
 }
 
 func (b VersionedSignedBeaconBlock) Epoch(ctx context.Context, eth2Cl eth2wrap.Client) (eth2p0.Epoch, error) {
@@ -60,7 +83,7 @@ func (b VersionedSignedBeaconBlock) Epoch(ctx context.Context, eth2Cl eth2wrap.C
 // Implement Eth2SignedData for VersionedSignedBlindedBeaconBlock.
 
 func (VersionedSignedBlindedBeaconBlock) DomainName() signing.DomainName {
-	return signing.DomainBeaconProposer
+	return dutyDomainNames[DutyBuilderProposer]
 }
 
 func (b VersionedSignedBlindedBeaconBlock) Epoch(ctx context.Context, eth2Cl eth2wrap.Client) (eth2p0.Epoch, error) {
@@ -75,7 +98,7 @@ func (b VersionedSignedBlindedBeaconBlock) Epoch(ctx context.Context, eth2Cl eth
 // Implement Eth2SignedData for Attestation.
 
 func (Attestation) DomainName() signing.DomainName {
-	return signing.DomainBeaconAttester
+	return dutyDomainNames[DutyAttester]
 }
 
 func (a Attestation) Epoch(_ context.Context, _ eth2wrap.Client) (eth2p0.Epoch, error) {
@@ -85,7 +108,7 @@ func (a Attestation) Epoch(_ context.Context, _ eth2wrap.Client) (eth2p0.Epoch,
 // Implement Eth2SignedData for SignedVoluntaryExit.
 
 func (SignedVoluntaryExit) DomainName() signing.DomainName {
-	return signing.DomainExit
+	return dutyDomainNames[DutyExit]
 }
 
 func (e SignedVoluntaryExit) Epoch(_ context.Context, _ eth2wrap.Client) (eth2p0.Epoch, error) {
@@ -95,7 +118,7 @@ func (e SignedVoluntaryExit) Epoch(_ context.Context, _ eth2wrap.Client) (eth2p0
 // Implement Eth2SignedData for VersionedSignedValidatorRegistration.
 
 func (VersionedSignedValidatorRegistration) DomainName() signing.DomainName {
-	return signing.DomainApplicationBuilder
+	return dutyDomainNames[DutyBuilderRegistration]
 }
 
 func (VersionedSignedValidatorRegistration) Epoch(context.Context, eth2wrap.Client) (eth2p0.Epoch, error) {
@@ -106,7 +129,7 @@ func (VersionedSignedValidatorRegistration) Epoch(context.Context, eth2wrap.Clie
 // Implement Eth2SignedData for SignedRandao.
 
 func (SignedRandao) DomainName() signing.DomainName {
-	return signing.DomainRandao
+	return dutyDomainNames[DutyRandao]
 }
 
 func (s SignedRandao) Epoch(_ context.Context, _ eth2wrap.Client) (eth2p0.Epoch, error) {
@@ -116,7 +139,7 @@ func (s SignedRandao) Epoch(_ context.Context, _ eth2wrap.Client) (eth2p0.Epoch,
 // Implement Eth2SignedData for BeaconCommitteeSelection.
 
 func (BeaconCommitteeSelection) DomainName() signing.DomainName {
-	return signing.DomainSelectionProof
+	return dutyDomainNames[DutyPrepareAggregator]
 }
 
 func (s BeaconCommitteeSelection) Epoch(ctx context.Context, eth2Cl eth2wrap.Client) (eth2p0.Epoch, error) {
@@ -126,7 +149,7 @@ func (s BeaconCommitteeSelection) Epoch(ctx context.Context, eth2Cl eth2wrap.Cli
 // Implement Eth2SignedData for SignedAggregateAndProof.
 
 func (SignedAggregateAndProof) DomainName() signing.DomainName {
-	return signing.DomainAggregateAndProof
+	return dutyDomainNames[DutyAggregator]
 }
 
 func (s SignedAggregateAndProof) Epoch(ctx context.Context, eth2Cl eth2wrap.Client) (eth2p0.Epoch, error) {
@@ -136,7 +159,7 @@ func (s SignedAggregateAndProof) Epoch(ctx context.Context, eth2Cl eth2wrap.Clie
 // Implement Eth2SignedData for SignedSyncMessage.
 
 func (SignedSyncMessage) DomainName() signing.DomainName {
-	return signing.DomainSyncCommittee
+	return dutyDomainNames[DutySyncMessage]
 }
 
 func (s SignedSyncMessage) Epoch(ctx context.Context, eth2Cl eth2wrap.Client) (eth2p0.Epoch, error) {
@@ -146,7 +169,7 @@ func (s SignedSyncMessage) Epoch(ctx context.Context, eth2Cl eth2wrap.Client) (e
 // Implement Eth2SignedData for SignedSyncContributionAndProof.
 
 func (SignedSyncContributionAndProof) DomainName() signing.DomainName {
-	return signing.DomainContributionAndProof
+	return dutyDomainNames[DutySyncContribution]
 }
 
 func (s SignedSyncContributionAndProof) Epoch(ctx context.Context, eth2Cl eth2wrap.Client) (eth2p0.Epoch, error) {
@@ -156,7 +179,7 @@ func (s SignedSyncContributionAndProof) Epoch(ctx context.Context, eth2Cl eth2wr
 // Implement Eth2SignedData for SyncCommitteeSelection.
 
 func (SyncCommitteeSelection) DomainName() signing.DomainName {
-	return signing.DomainSyncCommitteeSelectionProof
+	return dutyDomainNames[DutyPrepareSyncContribution]
 }
 
 func (s SyncCommitteeSelection) Epoch(ctx context.Context, eth2Cl eth2wrap.Client) (eth2p0.Epoch, error) {
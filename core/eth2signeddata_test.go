@@ -117,6 +117,48 @@ func TestVerifyEth2SignedData(t *testing.T) {
 	}
 }
 
+func TestDutyDomainName(t *testing.T) {
+	tests := []struct {
+		duty   core.DutyType
+		domain signing.DomainName
+		data   core.Eth2SignedData
+	}{
+		{duty: core.DutyProposer, domain: signing.DomainBeaconProposer, data: testutil.RandomBellatrixCoreVersionedSignedBeaconBlock()},
+		{duty: core.DutyAttester, domain: signing.DomainBeaconAttester, data: core.NewAttestation(testutil.RandomAttestation())},
+		{duty: core.DutySignature},
+		{duty: core.DutyExit, domain: signing.DomainExit, data: core.NewSignedVoluntaryExit(testutil.RandomExit())},
+		{duty: core.DutyBuilderProposer, domain: signing.DomainBeaconProposer, data: testutil.RandomCapellaVersionedSignedBlindedBeaconBlock()},
+		{duty: core.DutyBuilderRegistration, domain: signing.DomainApplicationBuilder, data: testutil.RandomCoreVersionedSignedValidatorRegistration(t)},
+		{duty: core.DutyRandao, domain: signing.DomainRandao, data: testutil.RandomCoreSignedRandao()},
+		{duty: core.DutyPrepareAggregator, domain: signing.DomainSelectionProof, data: testutil.RandomCoreBeaconCommitteeSelection()},
+		{duty: core.DutyAggregator, domain: signing.DomainAggregateAndProof, data: core.SignedAggregateAndProof{}},
+		{duty: core.DutySyncMessage, domain: signing.DomainSyncCommittee, data: core.NewSignedSyncMessage(testutil.RandomSyncCommitteeMessage())},
+		{duty: core.DutyPrepareSyncContribution, domain: signing.DomainSyncCommitteeSelectionProof, data: testutil.RandomCoreSyncCommitteeSelection()},
+		{duty: core.DutySyncContribution, domain: signing.DomainContributionAndProof, data: core.NewSignedSyncContributionAndProof(testutil.RandomSignedSyncContributionAndProof())},
+		{duty: core.DutyInfoSync},
+	}
+
+	var tested []core.DutyType
+	for _, test := range tests {
+		t.Run(test.duty.String(), func(t *testing.T) {
+			domain, ok := core.DutyDomainName(test.duty)
+			require.Equal(t, test.domain != "", ok)
+			require.Equal(t, test.domain, domain)
+
+			if test.data != nil {
+				require.Equal(t, test.domain, test.data.DomainName())
+			}
+		})
+		tested = append(tested, test.duty)
+	}
+
+	// Ensure new duty types are added to the table.
+	require.ElementsMatch(t, core.AllDutyTypes(), tested)
+
+	_, ok := core.DutyDomainName(core.DutyUnknown)
+	require.False(t, ok)
+}
+
 func sign(t *testing.T, data []byte) (core.Signature, tblsv2.PublicKey) {
 	t.Helper()
 
@@ -13,6 +13,7 @@ import (
 
 	"github.com/obolnetwork/charon/app/errors"
 	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/app/featureset"
 	"github.com/obolnetwork/charon/app/log"
 	"github.com/obolnetwork/charon/app/version"
 	"github.com/obolnetwork/charon/app/z"
@@ -284,7 +285,13 @@ func (f *Fetcher) fetchBuilderProposerData(ctx context.Context, slot int64, defS
 			return nil, err
 		}
 
-		verifyFeeRecipientBlindedBlock(ctx, block, f.feeRecipientFunc(pubkey))
+		if err := verifyFeeRecipientBlindedBlock(block, f.feeRecipientFunc(pubkey)); err != nil {
+			feeRecipientMismatchCounter.Inc()
+			if featureset.Enabled(featureset.StrictFeeRecipient) {
+				return nil, errors.Wrap(err, "verify blinded block", z.Str("pubkey", pubkey.String()))
+			}
+			log.Warn(ctx, "Proposing blinded block with unexpected fee recipient address", err, z.Str("pubkey", pubkey.String()))
+		}
 
 		coreBlock, err := core.NewVersionedBlindedBeaconBlock(block)
 		if err != nil {
@@ -375,8 +382,10 @@ func verifyFeeRecipient(ctx context.Context, block *eth2spec.VersionedBeaconBloc
 	}
 }
 
-// verifyFeeRecipientBlindedBlock logs a warning when fee recipient is not correctly populated in the provided blinded beacon block.
-func verifyFeeRecipientBlindedBlock(ctx context.Context, block *eth2api.VersionedBlindedBeaconBlock, feeRecipientAddress string) {
+// verifyFeeRecipientBlindedBlock returns an error if the fee recipient of the provided blinded beacon block
+// doesn't match the configured fee recipient address. Rejecting such blocks prevents a malicious builder or relay from
+// redirecting block rewards, since the validator client cannot verify the payload of a blinded block.
+func verifyFeeRecipientBlindedBlock(block *eth2api.VersionedBlindedBeaconBlock, feeRecipientAddress string) error {
 	// Note that fee-recipient is not available in forks earlier than bellatrix.
 	var actualAddr string
 
@@ -386,11 +395,17 @@ func verifyFeeRecipientBlindedBlock(ctx context.Context, block *eth2api.Versione
 	case eth2spec.DataVersionCapella:
 		actualAddr = fmt.Sprintf("%#x", block.Capella.Body.ExecutionPayloadHeader.FeeRecipient)
 	default:
-		return
+		return nil
 	}
 
-	if actualAddr != "" && !strings.EqualFold(actualAddr, feeRecipientAddress) {
-		log.Warn(ctx, "Proposing block with unexpected fee recipient address", nil,
+	if feeRecipientAddress == "" {
+		return nil // No fee recipient configured, nothing to enforce.
+	}
+
+	if !strings.EqualFold(actualAddr, feeRecipientAddress) {
+		return errors.New("unexpected fee recipient address in blinded block",
 			z.Str("expected", feeRecipientAddress), z.Str("actual", actualAddr))
 	}
+
+	return nil
 }
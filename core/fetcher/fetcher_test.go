@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/featureset"
 	"github.com/obolnetwork/charon/core"
 	"github.com/obolnetwork/charon/core/fetcher"
 	"github.com/obolnetwork/charon/eth2util/eth2exp"
@@ -301,6 +302,54 @@ func TestFetchBlocks(t *testing.T) {
 		err = fetch.Fetch(ctx, duty, defSet)
 		require.NoError(t, err)
 	})
+
+	t.Run("fetch DutyBuilderProposer mismatching fee recipient", func(t *testing.T) {
+		duty := core.NewBuilderProposerDuty(slot)
+		fetch, err := fetcher.New(bmock, func(core.PubKey) string {
+			return "0x0000000000000000000000000000000000000001"
+		})
+		require.NoError(t, err)
+
+		fetch.RegisterAggSigDB(func(ctx context.Context, duty core.Duty, key core.PubKey) (core.SignedData, error) {
+			return randaoByPubKey[key], nil
+		})
+
+		fetch.Subscribe(func(ctx context.Context, resDuty core.Duty, resDataSet core.UnsignedDataSet) error {
+			require.Fail(t, "unexpected blinded block with mismatching fee recipient")
+			return nil
+		})
+
+		// Mismatching fee recipients are rejected by default.
+		err = fetch.Fetch(ctx, duty, defSet)
+		require.ErrorContains(t, err, "unexpected fee recipient address in blinded block")
+	})
+
+	t.Run("fetch DutyBuilderProposer lenient fee recipient", func(t *testing.T) {
+		featureset.DisableForT(t, featureset.StrictFeeRecipient)
+
+		duty := core.NewBuilderProposerDuty(slot)
+		fetch, err := fetcher.New(bmock, func(core.PubKey) string {
+			return "0x0000000000000000000000000000000000000001"
+		})
+		require.NoError(t, err)
+
+		fetch.RegisterAggSigDB(func(ctx context.Context, duty core.Duty, key core.PubKey) (core.SignedData, error) {
+			return randaoByPubKey[key], nil
+		})
+
+		var fetched bool
+		fetch.Subscribe(func(ctx context.Context, resDuty core.Duty, resDataSet core.UnsignedDataSet) error {
+			require.Len(t, resDataSet, 2)
+			fetched = true
+
+			return nil
+		})
+
+		// Mismatching fee recipients are only logged if strict fee recipients are disabled.
+		err = fetch.Fetch(ctx, duty, defSet)
+		require.NoError(t, err)
+		require.True(t, fetched)
+	})
 }
 
 func TestFetchSyncContribution(t *testing.T) {
@@ -15,3 +15,10 @@ var inconsistentAttDataCounter = promauto.NewCounter(prometheus.CounterOpts{
 	Name:      "inconsistent_att_data_total",
 	Help:      "Total number of inconsistent attestation data detected. Note this is expected.",
 })
+
+var feeRecipientMismatchCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "core",
+	Subsystem: "fetcher",
+	Name:      "fee_recipient_mismatch_total",
+	Help:      "Total number of blinded block proposals with a fee recipient not matching the configured fee recipient address.",
+})
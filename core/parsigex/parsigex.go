@@ -23,11 +23,16 @@ import (
 	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
 )
 
-const protocolID = "/charon/parsigex/1.0.0"
+const (
+	protocolID      = "/charon/parsigex/1.0.0"
+	batchProtocolID = "/charon/parsigex/batch/1.0.0"
+	// batchStreamProtocolID streams an ack per batch entry back as soon as the entry is handled.
+	batchStreamProtocolID = "/charon/parsigex/batchstream/1.0.0"
+)
 
 // Protocols returns the supported protocols of this package in order of precedence.
 func Protocols() []protocol.ID {
-	return []protocol.ID{protocolID}
+	return []protocol.ID{protocolID, batchProtocolID, batchStreamProtocolID}
 }
 
 func NewParSigEx(tcpNode host.Host, sendFunc p2p.SendFunc, peerIdx int, peers []peer.ID, verifyFunc func(context.Context, core.Duty, core.PubKey, core.ParSignedData) error) *ParSigEx {
@@ -39,6 +44,14 @@ func NewParSigEx(tcpNode host.Host, sendFunc p2p.SendFunc, peerIdx int, peers []
 		verifyFunc: verifyFunc,
 	}
 	parSigEx.tcpNode.SetStreamHandler(protocolID, parSigEx.handle)
+	p2p.RegisterHandler("parsigex", tcpNode, batchProtocolID,
+		func() proto.Message { return new(pbv1.ParSigExBatchMsg) },
+		parSigEx.handleBatch,
+	)
+	p2p.RegisterStreamingHandler("parsigex", tcpNode, batchStreamProtocolID,
+		func() proto.Message { return new(pbv1.ParSigExBatchMsg) },
+		parSigEx.handleBatchStream,
+	)
 
 	return parSigEx
 }
@@ -101,6 +114,211 @@ func (m *ParSigEx) handle(s network.Stream) {
 	}
 }
 
+// handleBatch verifies each entry of a received batch individually and returns an ack per entry in order.
+// Verified entries are passed to the subscribers grouped by duty.
+func (m *ParSigEx) handleBatch(ctx context.Context, _ peer.ID, req proto.Message) (proto.Message, bool, error) {
+	msg, ok := req.(*pbv1.ParSigExBatchMsg)
+	if !ok {
+		return nil, false, errors.New("invalid parsigex batch message")
+	}
+
+	var (
+		resp   = new(pbv1.ParSigExBatchResponse)
+		duties []core.Duty
+		sets   = make(map[core.Duty]core.ParSignedDataSet)
+	)
+	for i, entry := range msg.Entries {
+		ack := new(pbv1.ParSigExBatchAck)
+		resp.Acks = append(resp.Acks, ack)
+
+		duty, pubkey, data, err := m.verifyBatchEntry(ctx, entry)
+		if err != nil {
+			log.Warn(ctx, "Peer exchanged invalid partial signature in batch", err, z.Int("index", i))
+			ack.Error = err.Error()
+
+			continue
+		}
+
+		if _, ok := sets[duty]; !ok {
+			duties = append(duties, duty)
+			sets[duty] = make(core.ParSignedDataSet)
+		}
+
+		if err := sets[duty].Add(pubkey, data); err != nil {
+			log.Warn(ctx, "Peer exchanged conflicting partial signature in batch", err, z.Int("index", i))
+			ack.Error = err.Error()
+		}
+	}
+
+	for _, duty := range duties {
+		dutyCtx, span := core.StartDutyTrace(log.WithCtx(ctx, z.Any("duty", duty)), duty, "core/parsigex.HandleBatch")
+		for _, sub := range m.subs {
+			if err := sub(dutyCtx, duty, sets[duty]); err != nil {
+				log.Error(dutyCtx, "Subscribe error", err)
+			}
+		}
+		span.End()
+	}
+
+	return resp, true, nil
+}
+
+// handleBatchStream handles each entry of a received batch individually, passing verified entries to the subscribers
+// and streaming an ack per entry back in order as soon as the entry is handled.
+func (m *ParSigEx) handleBatchStream(ctx context.Context, _ peer.ID, req proto.Message, send func(proto.Message) error) error {
+	msg, ok := req.(*pbv1.ParSigExBatchMsg)
+	if !ok {
+		return errors.New("invalid parsigex batch message")
+	}
+
+	for i, entry := range msg.Entries {
+		ack := new(pbv1.ParSigExBatchAck)
+
+		duty, pubkey, data, err := m.verifyBatchEntry(ctx, entry)
+		if err != nil {
+			log.Warn(ctx, "Peer exchanged invalid partial signature in batch", err, z.Int("index", i))
+			ack.Error = err.Error()
+		} else {
+			dutyCtx, span := core.StartDutyTrace(log.WithCtx(ctx, z.Any("duty", duty)), duty, "core/parsigex.HandleBatchStream")
+			for _, sub := range m.subs {
+				if err := sub(dutyCtx, duty, core.ParSignedDataSet{pubkey: data}); err != nil {
+					log.Error(dutyCtx, "Subscribe error", err)
+				}
+			}
+			span.End()
+		}
+
+		if err := send(ack); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyBatchEntry returns the duty, pubkey and verified partially signed data of the batch entry.
+func (m *ParSigEx) verifyBatchEntry(ctx context.Context, entry *pbv1.ParSigExBatchEntry) (core.Duty, core.PubKey, core.ParSignedData, error) {
+	if entry.Duty == nil || entry.Data == nil {
+		return core.Duty{}, "", core.ParSignedData{}, errors.New("incomplete batch entry")
+	}
+
+	duty := core.DutyFromProto(entry.Duty)
+	pubkey := core.PubKey(entry.Pubkey)
+
+	data, err := core.ParSignedDataFromProto(duty.Type, entry.Data)
+	if err != nil {
+		return core.Duty{}, "", core.ParSignedData{}, err
+	}
+
+	if err := m.verifyFunc(ctx, duty, pubkey, data); err != nil {
+		return core.Duty{}, "", core.ParSignedData{}, err
+	}
+
+	return duty, pubkey, data, nil
+}
+
+// BatchEntry is a partially signed duty data entry of a batch.
+type BatchEntry struct {
+	Duty   core.Duty
+	PubKey core.PubKey
+	Data   core.ParSignedData
+}
+
+// SendBatch sends the partially signed data entries to the peer in a single message, instead of a stream per duty.
+// It returns the peer's error per entry in order of the entries, nil for accepted entries.
+func (m *ParSigEx) SendBatch(ctx context.Context, peerID peer.ID, entries []BatchEntry) ([]error, error) {
+	ctx = log.WithTopic(ctx, "parsigex")
+
+	msg, err := batchMsg(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(pbv1.ParSigExBatchResponse)
+	if err := p2p.SendReceive(ctx, m.tcpNode, peerID, msg, resp, batchProtocolID); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Acks) != len(entries) {
+		return nil, errors.New("mismatching batch ack count", z.Int("expect", len(entries)), z.Int("actual", len(resp.Acks)))
+	}
+
+	errs := make([]error, len(entries))
+	for i, ack := range resp.Acks {
+		errs[i] = ackError(i, ack)
+	}
+
+	return errs, nil
+}
+
+// SendBatchStream sends the partially signed data entries to the peer in a single message like SendBatch,
+// but calls ackFunc with the index and the peer's error, nil if accepted, of each entry as soon as its ack
+// is streamed back, allowing acks to be processed before the whole batch is handled by the peer.
+func (m *ParSigEx) SendBatchStream(ctx context.Context, peerID peer.ID, entries []BatchEntry, ackFunc func(int, error)) error {
+	ctx = log.WithTopic(ctx, "parsigex")
+
+	msg, err := batchMsg(entries)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = p2p.SendReceiveStreaming(ctx, m.tcpNode, peerID, msg,
+		func() proto.Message { return new(pbv1.ParSigExBatchAck) },
+		func(resp proto.Message) error {
+			ack, ok := resp.(*pbv1.ParSigExBatchAck)
+			if !ok {
+				return errors.New("invalid parsigex batch ack")
+			} else if count >= len(entries) {
+				return errors.New("unexpected batch ack", z.Int("index", count))
+			}
+
+			ackFunc(count, ackError(count, ack))
+			count++
+
+			return nil
+		},
+		batchStreamProtocolID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if count != len(entries) {
+		return errors.New("mismatching batch ack count", z.Int("expect", len(entries)), z.Int("actual", count))
+	}
+
+	return nil
+}
+
+// batchMsg returns the batch message of the partially signed data entries.
+func batchMsg(entries []BatchEntry) (*pbv1.ParSigExBatchMsg, error) {
+	msg := new(pbv1.ParSigExBatchMsg)
+	for _, entry := range entries {
+		pb, err := core.ParSignedDataToProto(entry.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		msg.Entries = append(msg.Entries, &pbv1.ParSigExBatchEntry{
+			Duty:   core.DutyToProto(entry.Duty),
+			Pubkey: string(entry.PubKey),
+			Data:   pb,
+		})
+	}
+
+	return msg, nil
+}
+
+// ackError returns the peer's error of the ack of the ith batch entry, or nil if accepted.
+func ackError(i int, ack *pbv1.ParSigExBatchAck) error {
+	if ack.Error == "" {
+		return nil
+	}
+
+	return errors.New("peer rejected partial signature", z.Int("index", i), z.Str("reason", ack.Error))
+}
+
 // Broadcast broadcasts the partially signed duty data set to all peers.
 func (m *ParSigEx) Broadcast(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
 	ctx = log.WithTopic(ctx, "parsigex")
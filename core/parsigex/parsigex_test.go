@@ -15,6 +15,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/peerstore"
 	"github.com/stretchr/testify/require"
 
+	"github.com/obolnetwork/charon/app/errors"
 	"github.com/obolnetwork/charon/core"
 	"github.com/obolnetwork/charon/core/parsigex"
 	"github.com/obolnetwork/charon/eth2util"
@@ -27,7 +28,7 @@ import (
 
 func TestMain(m *testing.M) {
 	tblsv2.SetImplementation(tblsv2.Herumi{})
-	os.Exit(m.Run())
+	os.Exit(testutil.MainWithGoldens(m))
 }
 
 func TestParSigEx(t *testing.T) {
@@ -106,6 +107,171 @@ func TestParSigEx(t *testing.T) {
 	wg.Wait()
 }
 
+func TestParSigExBatch(t *testing.T) {
+	const (
+		epoch      = 123
+		shareIdx   = 1
+		invalidIdx = 2
+	)
+
+	var hosts []host.Host
+	var peers []peer.ID
+	for i := 0; i < 2; i++ {
+		h := testutil.CreateHost(t, testutil.AvailableAddr(t))
+		hosts = append(hosts, h)
+		peers = append(peers, h.ID())
+	}
+	hosts[0].Peerstore().AddAddrs(hosts[1].ID(), hosts[1].Addrs(), peerstore.PermanentAddrTTL)
+
+	verifyFunc := func(_ context.Context, _ core.Duty, _ core.PubKey, data core.ParSignedData) error {
+		if data.ShareIdx == invalidIdx {
+			return errors.New("invalid share index")
+		}
+
+		return nil
+	}
+
+	sender := parsigex.NewParSigEx(hosts[0], p2p.Send, 0, peers, verifyFunc)
+	receiver := parsigex.NewParSigEx(hosts[1], p2p.Send, 1, peers, verifyFunc)
+
+	var (
+		mu       sync.Mutex
+		received = make(map[core.Duty]core.ParSignedDataSet)
+	)
+	receiver.Subscribe(func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received[duty] = set
+
+		return nil
+	})
+
+	duty1 := core.NewRandaoDuty(1)
+	duty2 := core.NewRandaoDuty(2)
+	pubkey1 := testutil.RandomCorePubKey(t)
+	pubkey2 := testutil.RandomCorePubKey(t)
+
+	entries := []parsigex.BatchEntry{
+		{Duty: duty1, PubKey: pubkey1, Data: core.NewPartialSignedRandao(epoch, testutil.RandomEth2Signature(), shareIdx)},
+		{Duty: duty1, PubKey: pubkey2, Data: core.NewPartialSignedRandao(epoch, testutil.RandomEth2Signature(), invalidIdx)},
+		{Duty: duty2, PubKey: pubkey2, Data: core.NewPartialSignedRandao(epoch, testutil.RandomEth2Signature(), shareIdx)},
+		{Duty: duty1, PubKey: pubkey1, Data: core.NewPartialSignedRandao(epoch, testutil.RandomEth2Signature(), shareIdx)},
+	}
+	entries = append(entries, entries[2]) // Identical entries are ignored.
+
+	errs, err := sender.SendBatch(context.Background(), hosts[1].ID(), entries)
+	require.NoError(t, err)
+	require.Len(t, errs, len(entries))
+	require.NoError(t, errs[0])
+	require.ErrorContains(t, errs[1], "peer rejected partial signature")
+	require.NoError(t, errs[2])
+	require.ErrorContains(t, errs[3], "peer rejected partial signature") // Conflicts with the first entry.
+	require.NoError(t, errs[4])
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, map[core.Duty]core.ParSignedDataSet{
+		duty1: {pubkey1: entries[0].Data},
+		duty2: {pubkey2: entries[2].Data},
+	}, received)
+}
+
+func TestParSigExBatchStream(t *testing.T) {
+	const (
+		epoch      = 123
+		firstIdx   = 1
+		invalidIdx = 2
+		shareIdx   = 3
+	)
+
+	var hosts []host.Host
+	var peers []peer.ID
+	for i := 0; i < 2; i++ {
+		h := testutil.CreateHost(t, testutil.AvailableAddr(t))
+		hosts = append(hosts, h)
+		peers = append(peers, h.ID())
+	}
+	hosts[0].Peerstore().AddAddrs(hosts[1].ID(), hosts[1].Addrs(), peerstore.PermanentAddrTTL)
+
+	// firstAcked is closed when the sender received the ack of the first entry.
+	firstAcked := make(chan struct{})
+
+	// verifyFunc blocks verifying all but the first entry until the first ack was received,
+	// so this only succeeds if acks are streamed back before the whole batch is handled.
+	verifyFunc := func(ctx context.Context, _ core.Duty, _ core.PubKey, data core.ParSignedData) error {
+		if data.ShareIdx == firstIdx {
+			return nil
+		}
+
+		select {
+		case <-firstAcked:
+		case <-ctx.Done():
+			return errors.New("first ack not streamed")
+		}
+
+		if data.ShareIdx == invalidIdx {
+			return errors.New("invalid share index")
+		}
+
+		return nil
+	}
+
+	sender := parsigex.NewParSigEx(hosts[0], p2p.Send, 0, peers, verifyFunc)
+	receiver := parsigex.NewParSigEx(hosts[1], p2p.Send, 1, peers, verifyFunc)
+
+	var (
+		mu       sync.Mutex
+		received = make(map[core.Duty]core.ParSignedDataSet)
+	)
+	receiver.Subscribe(func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if received[duty] == nil {
+			received[duty] = make(core.ParSignedDataSet)
+		}
+		for pubkey, data := range set {
+			received[duty][pubkey] = data
+		}
+
+		return nil
+	})
+
+	duty1 := core.NewRandaoDuty(1)
+	duty2 := core.NewRandaoDuty(2)
+	pubkey1 := testutil.RandomCorePubKey(t)
+	pubkey2 := testutil.RandomCorePubKey(t)
+
+	entries := []parsigex.BatchEntry{
+		{Duty: duty1, PubKey: pubkey1, Data: core.NewPartialSignedRandao(epoch, testutil.RandomEth2Signature(), firstIdx)},
+		{Duty: duty1, PubKey: pubkey2, Data: core.NewPartialSignedRandao(epoch, testutil.RandomEth2Signature(), invalidIdx)},
+		{Duty: duty2, PubKey: pubkey2, Data: core.NewPartialSignedRandao(epoch, testutil.RandomEth2Signature(), shareIdx)},
+	}
+
+	var (
+		indices []int
+		errs    []error
+	)
+	err := sender.SendBatchStream(context.Background(), hosts[1].ID(), entries, func(i int, err error) {
+		if i == 0 {
+			close(firstAcked)
+		}
+		indices = append(indices, i)
+		errs = append(errs, err)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2}, indices)
+	require.NoError(t, errs[0])
+	require.ErrorContains(t, errs[1], "peer rejected partial signature")
+	require.NoError(t, errs[2])
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, map[core.Duty]core.ParSignedDataSet{
+		duty1: {pubkey1: entries[0].Data},
+		duty2: {pubkey2: entries[2].Data},
+	}, received)
+}
+
 func TestParSigExVerifier(t *testing.T) {
 	ctx := context.Background()
 
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 
 	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/z"
 	pbv1 "github.com/obolnetwork/charon/core/corepb/v1"
 )
 
@@ -25,8 +26,31 @@ func DutyFromProto(duty *pbv1.Duty) Duty {
 	}
 }
 
-// ParSignedDataFromProto returns the data from a protobuf.
+// Wire encoding versions of partial signed data exchanged between peers. Decoders support all versions
+// up to the current version so that clusters with mixed charon versions interoperate during rolling upgrades.
+const (
+	// parSignedDataV0 is the legacy unversioned encoding of peers that predate versioning.
+	parSignedDataV0 uint32 = 0
+	// parSignedDataV1 is identical to v0 but explicitly versioned.
+	parSignedDataV1 uint32 = 1
+
+	// parSignedDataVersion is the current encoding version.
+	parSignedDataVersion = parSignedDataV1
+)
+
+// ParSignedDataFromProto returns the data from a protobuf of any supported encoding version.
 func ParSignedDataFromProto(typ DutyType, data *pbv1.ParSignedData) (ParSignedData, error) {
+	switch data.Version {
+	case parSignedDataV0, parSignedDataV1:
+		return parSignedDataFromProtoV1(typ, data)
+	default:
+		return ParSignedData{}, errors.New("unsupported partial signed data version",
+			z.U64("version", uint64(data.Version)), z.U64("max_version", uint64(parSignedDataVersion)))
+	}
+}
+
+// parSignedDataFromProtoV1 returns the data from a v0 or v1 encoded protobuf.
+func parSignedDataFromProtoV1(typ DutyType, data *pbv1.ParSignedData) (ParSignedData, error) {
 	// TODO(corver): This can panic due to json unmarshalling unexpected data.
 	//  For now, it is a good way to catch compatibility issues. But we should
 	//  recover panics and return an error before launching mainnet.
@@ -115,7 +139,7 @@ func ParSignedDataFromProto(typ DutyType, data *pbv1.ParSignedData) (ParSignedDa
 	}, nil
 }
 
-// ParSignedDataToProto returns the data as a protobuf.
+// ParSignedDataToProto returns the data as a protobuf of the current encoding version.
 func ParSignedDataToProto(data ParSignedData) (*pbv1.ParSignedData, error) {
 	d, err := data.MarshalJSON()
 	if err != nil {
@@ -126,6 +150,7 @@ func ParSignedDataToProto(data ParSignedData) (*pbv1.ParSignedData, error) {
 		Data:      d,
 		Signature: data.Signature(),
 		ShareIdx:  int32(data.ShareIdx),
+		Version:   parSignedDataVersion,
 	}, nil
 }
 
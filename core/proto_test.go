@@ -112,6 +112,56 @@ func TestParSignedDataSetProto(t *testing.T) {
 	}
 }
 
+func TestParSignedDataVersions(t *testing.T) {
+	att := core.NewPartialAttestation(testutil.RandomAttestation(), 2)
+
+	// decode unmarshals the wire encoded payload and decodes it.
+	decode := func(t *testing.T, pb *pbv1.ParSignedData) (core.ParSignedData, error) {
+		t.Helper()
+
+		b, err := proto.Marshal(pb)
+		require.NoError(t, err)
+
+		wire := new(pbv1.ParSignedData)
+		require.NoError(t, proto.Unmarshal(b, wire))
+
+		return core.ParSignedDataFromProto(core.DutyAttester, wire)
+	}
+
+	t.Run("old version", func(t *testing.T) {
+		data, err := att.MarshalJSON()
+		require.NoError(t, err)
+
+		// Encoded by a peer that predates versioning.
+		decoded, err := decode(t, &pbv1.ParSignedData{
+			Data:      data,
+			Signature: att.Signature(),
+			ShareIdx:  int32(att.ShareIdx),
+		})
+		require.NoError(t, err)
+		require.Equal(t, att, decoded)
+	})
+
+	t.Run("new version", func(t *testing.T) {
+		pb, err := core.ParSignedDataToProto(att)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, pb.Version)
+
+		decoded, err := decode(t, pb)
+		require.NoError(t, err)
+		require.Equal(t, att, decoded)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		pb, err := core.ParSignedDataToProto(att)
+		require.NoError(t, err)
+		pb.Version = 99
+
+		_, err = decode(t, pb)
+		require.ErrorContains(t, err, "unsupported partial signed data version")
+	})
+}
+
 func TestUnsignedDataToProto(t *testing.T) {
 	tests := []struct {
 		Type core.DutyType
@@ -24,6 +24,10 @@ import (
 	"github.com/obolnetwork/charon/testutil/beaconmock"
 )
 
+func TestMain(m *testing.M) {
+	os.Exit(testutil.MainWithGoldens(m))
+}
+
 var integration = flag.Bool("integration", false, "enable integration test, requires BEACON_URL vars.")
 
 // TestIntegration runs an integration test for the Scheduler.
@@ -0,0 +1,16 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package sigagg
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/obolnetwork/charon/app/promauto"
+)
+
+var invalidAggregateCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "core",
+	Subsystem: "sigagg",
+	Name:      "invalid_aggregate_total",
+	Help:      "The total count of threshold aggregated signatures that failed verification against the DV public key by duty type",
+}, []string{"duty"})
@@ -10,23 +10,44 @@ import (
 	"context"
 
 	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/eth2wrap"
 	"github.com/obolnetwork/charon/app/log"
 	"github.com/obolnetwork/charon/app/tracer"
+	"github.com/obolnetwork/charon/app/z"
 	"github.com/obolnetwork/charon/core"
 	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
 	tblsconv2 "github.com/obolnetwork/charon/tbls/v2/tblsconv"
 )
 
-// New returns a new aggregator instance.
-func New(threshold int) *Aggregator {
-	return &Aggregator{threshold: threshold}
+// New returns a new aggregator instance that verifies aggregated signatures using the provided function.
+func New(threshold int, verifyFunc func(context.Context, core.PubKey, core.SignedData) error) *Aggregator {
+	return &Aggregator{threshold: threshold, verifyFunc: verifyFunc}
+}
+
+// NewVerifier returns a signature verification function for aggregated core workflow eth2 signatures
+// verifying them against the DV group public key.
+func NewVerifier(eth2Cl eth2wrap.Client) func(context.Context, core.PubKey, core.SignedData) error {
+	return func(ctx context.Context, pubkey core.PubKey, data core.SignedData) error {
+		eth2Signed, ok := data.(core.Eth2SignedData)
+		if !ok {
+			return errors.New("invalid eth2 signed data")
+		}
+
+		pk, err := tblsconv2.PubkeyFromCore(pubkey)
+		if err != nil {
+			return err
+		}
+
+		return core.VerifyEth2SignedData(ctx, eth2Cl, eth2Signed, pk)
+	}
 }
 
 // Aggregator aggregates *threshold* partial signed duty data objects
 // into an aggregated signed duty data object ready to be broadcasted.
 type Aggregator struct {
-	threshold int
-	subs      []func(context.Context, core.Duty, core.PubKey, core.SignedData) error
+	threshold  int
+	verifyFunc func(context.Context, core.PubKey, core.SignedData) error
+	subs       []func(context.Context, core.Duty, core.PubKey, core.SignedData) error
 }
 
 // Subscribe registers a callback for aggregated signed duty data.
@@ -68,6 +89,17 @@ func (a *Aggregator) Aggregate(ctx context.Context, duty core.Duty, pubkey core.
 		return err
 	}
 
+	// Verify the aggregated signature against the DV group public key.
+	if err := a.verifyFunc(ctx, pubkey, aggSig); err != nil {
+		// This indicates that consensus and threshold aggregation produced an invalid signature,
+		// which should never happen, so signal it loudly.
+		invalidAggregateCounter.WithLabelValues(duty.Type.String()).Inc()
+		log.Error(ctx, "Invalid aggregated signature, not broadcasting", err,
+			z.Any("duty", duty), z.Any("pubkey", pubkey))
+
+		return errors.Wrap(err, "invalid aggregated signature")
+	}
+
 	log.Debug(ctx, "Threshold aggregated partial signatures")
 
 	// Call subscriptions.
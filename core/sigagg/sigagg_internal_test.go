@@ -0,0 +1,73 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package sigagg
+
+import (
+	"context"
+	"testing"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obolnetwork/charon/core"
+	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
+	tblsconv2 "github.com/obolnetwork/charon/tbls/v2/tblsconv"
+	testutil "github.com/obolnetwork/charon/testutil"
+)
+
+func TestInvalidAggregate(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		threshold = 3
+		peers     = 4
+	)
+
+	att := testutil.RandomAttestation()
+	msg, err := att.MarshalSSZ()
+	require.NoError(t, err)
+
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+	groupPubKey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	// Sign with shares of another secret, resulting in an aggregate that is invalid for the group public key.
+	otherSecret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+	shares, err := tblsv2.ThresholdSplit(otherSecret, peers, threshold)
+	require.NoError(t, err)
+
+	var parSigs []core.ParSignedData
+	for idx, share := range shares {
+		sig, err := tblsv2.Sign(share, msg)
+		require.NoError(t, err)
+
+		att.Signature = tblsconv2.SigToETH2(sig)
+		parSigs = append(parSigs, core.NewPartialAttestation(att, idx))
+	}
+
+	pk, err := tblsconv2.PubkeyToETH2(groupPubKey)
+	require.NoError(t, err)
+	pubkey := core.PubKeyFrom48Bytes(pk)
+
+	verifyFunc := func(_ context.Context, _ core.PubKey, data core.SignedData) error {
+		sig, err := tblsconv2.SigFromCore(data.Signature())
+		require.NoError(t, err)
+
+		return tblsv2.Verify(groupPubKey, msg, sig)
+	}
+
+	agg := New(threshold, verifyFunc)
+	agg.Subscribe(func(context.Context, core.Duty, core.PubKey, core.SignedData) error {
+		require.Fail(t, "invalid aggregate must not be propagated")
+		return nil
+	})
+
+	duty := core.NewAttesterDuty(123)
+	before := promtestutil.ToFloat64(invalidAggregateCounter.WithLabelValues(duty.Type.String()))
+
+	err = agg.Aggregate(ctx, duty, pubkey, parSigs)
+	require.ErrorContains(t, err, "invalid aggregated signature")
+	require.Equal(t, before+1, promtestutil.ToFloat64(invalidAggregateCounter.WithLabelValues(duty.Type.String())))
+}
@@ -79,7 +79,7 @@ func TestSigAgg_DutyAttester(t *testing.T) {
 	require.NoError(t, err)
 	expect := tblsconv2.SigToCore(aggSig)
 
-	agg := sigagg.New(threshold)
+	agg := sigagg.New(threshold, noopVerifier)
 
 	// Assert output
 	agg.Subscribe(func(_ context.Context, _ core.Duty, _ core.PubKey, aggData core.SignedData) error {
@@ -143,7 +143,7 @@ func TestSigAgg_DutyRandao(t *testing.T) {
 	require.NoError(t, err)
 	expect := tblsconv2.SigToCore(aggSig)
 
-	agg := sigagg.New(threshold)
+	agg := sigagg.New(threshold, noopVerifier)
 
 	// Assert output
 	agg.Subscribe(func(_ context.Context, _ core.Duty, _ core.PubKey, aggData core.SignedData) error {
@@ -211,7 +211,7 @@ func TestSigAgg_DutyExit(t *testing.T) {
 	require.NoError(t, err)
 	expect := tblsconv2.SigToCore(aggSig)
 
-	agg := sigagg.New(threshold)
+	agg := sigagg.New(threshold, noopVerifier)
 
 	// Assert output
 	agg.Subscribe(func(_ context.Context, _ core.Duty, _ core.PubKey, aggData core.SignedData) error {
@@ -336,7 +336,7 @@ func TestSigAgg_DutyProposer(t *testing.T) {
 			require.NoError(t, err)
 			expect := tblsconv2.SigToCore(aggSig)
 
-			agg := sigagg.New(threshold)
+			agg := sigagg.New(threshold, noopVerifier)
 
 			// Assert output
 			agg.Subscribe(func(_ context.Context, _ core.Duty, _ core.PubKey, aggData core.SignedData) error {
@@ -443,7 +443,7 @@ func TestSigAgg_DutyBuilderProposer(t *testing.T) {
 			require.NoError(t, err)
 			expect := tblsconv2.SigToCore(aggSig)
 
-			agg := sigagg.New(threshold)
+			agg := sigagg.New(threshold, noopVerifier)
 
 			// Assert output
 			agg.Subscribe(func(_ context.Context, _ core.Duty, _ core.PubKey, aggData core.SignedData) error {
@@ -540,7 +540,7 @@ func TestSigAgg_DutyBuilderRegistration(t *testing.T) {
 			require.NoError(t, err)
 			expect := tblsconv2.SigToCore(aggSig)
 
-			agg := sigagg.New(threshold)
+			agg := sigagg.New(threshold, noopVerifier)
 
 			// Assert output
 			agg.Subscribe(func(_ context.Context, _ core.Duty, _ core.PubKey, aggData core.SignedData) error {
@@ -560,3 +560,8 @@ func TestSigAgg_DutyBuilderRegistration(t *testing.T) {
 		})
 	}
 }
+
+// noopVerifier is a sigagg verify function that doesn't verify since test signatures are not signed with eth2 domains.
+func noopVerifier(context.Context, core.PubKey, core.SignedData) error {
+	return nil
+}
@@ -3,6 +3,7 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/obolnetwork/charon/app/errors"
 	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/app/z"
 	"github.com/obolnetwork/charon/eth2util/signing"
 )
 
@@ -447,6 +449,88 @@ func (s ParSignedDataSet) Clone() (ParSignedDataSet, error) {
 	return resp, nil
 }
 
+// Add adds the partial signed data of the public key to the set. It returns an error if the set
+// already contains different partial signed data for the public key, identical data is ignored.
+func (s ParSignedDataSet) Add(pubkey PubKey, data ParSignedData) error {
+	existing, ok := s[pubkey]
+	if !ok {
+		s[pubkey] = data
+		return nil
+	}
+
+	equal, err := parSignedDataEqual(existing, data)
+	if err != nil {
+		return err
+	} else if !equal {
+		return errors.New("conflicting partial signed data", z.Any("pubkey", pubkey))
+	}
+
+	return nil
+}
+
+// MergeParSignedDataSets returns a new set containing the partial signed data of all the sets.
+// It returns an error if the sets contain different partial signed data for the same public key.
+func MergeParSignedDataSets(sets ...ParSignedDataSet) (ParSignedDataSet, error) {
+	resp := make(ParSignedDataSet)
+	for _, set := range sets {
+		for pubkey, data := range set {
+			if err := resp.Add(pubkey, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// ParSignedDataSetsBySlot groups partial signed data sets by slot.
+type ParSignedDataSetsBySlot map[int64]ParSignedDataSet
+
+// Add adds the partial signed data of the public key to the set of the slot, see ParSignedDataSet.Add.
+func (s ParSignedDataSetsBySlot) Add(slot int64, pubkey PubKey, data ParSignedData) error {
+	set, ok := s[slot]
+	if !ok {
+		set = make(ParSignedDataSet)
+		s[slot] = set
+	}
+
+	if err := set.Add(pubkey, data); err != nil {
+		return errors.Wrap(err, "add partial signed data", z.I64("slot", slot))
+	}
+
+	return nil
+}
+
+// Set sets the partial signed data of the public key at the slot, replacing any existing data.
+func (s ParSignedDataSetsBySlot) Set(slot int64, pubkey PubKey, data ParSignedData) {
+	set, ok := s[slot]
+	if !ok {
+		set = make(ParSignedDataSet)
+		s[slot] = set
+	}
+
+	set[pubkey] = data
+}
+
+// parSignedDataEqual returns true if the partial signed data have the same share index and identical signed data.
+func parSignedDataEqual(x, y ParSignedData) (bool, error) {
+	if x.ShareIdx != y.ShareIdx {
+		return false, nil
+	}
+
+	bx, err := x.MarshalJSON()
+	if err != nil {
+		return false, errors.Wrap(err, "marshal partial signed data")
+	}
+
+	by, err := y.MarshalJSON()
+	if err != nil {
+		return false, errors.Wrap(err, "marshal partial signed data")
+	}
+
+	return bytes.Equal(bx, by), nil
+}
+
 // Slot is a beacon chain slot including chain metadata to infer epoch and next slot.
 type Slot struct {
 	Slot          int64
@@ -11,6 +11,7 @@ import (
 
 	"github.com/obolnetwork/charon/app/tracer"
 	"github.com/obolnetwork/charon/core"
+	"github.com/obolnetwork/charon/testutil"
 )
 
 func TestBackwardsCompatability(t *testing.T) {
@@ -66,3 +67,49 @@ func TestWithDutySpanCtx(t *testing.T) {
 	require.True(t, span2.SpanContext().IsValid())
 	require.True(t, span2.SpanContext().IsSampled())
 }
+
+func TestMergeParSignedDataSets(t *testing.T) {
+	pk1 := testutil.RandomCorePubKey(t)
+	pk2 := testutil.RandomCorePubKey(t)
+	data1 := core.NewPartialSignedRandao(1, testutil.RandomEth2Signature(), 1)
+	data2 := core.NewPartialSignedRandao(1, testutil.RandomEth2Signature(), 1)
+
+	set1 := core.ParSignedDataSet{pk1: data1}
+	set2 := core.ParSignedDataSet{pk1: data1, pk2: data2}
+
+	merged, err := core.MergeParSignedDataSets(set1, set2)
+	require.NoError(t, err)
+	require.Len(t, merged, 2)
+	require.Equal(t, data1, merged[pk1])
+	require.Equal(t, data2, merged[pk2])
+	require.Len(t, set1, 1) // Inputs are not mutated.
+
+	// Identical data is deduplicated.
+	require.NoError(t, merged.Add(pk1, data1))
+	require.Len(t, merged, 2)
+
+	// Different data for the same pubkey conflicts.
+	require.ErrorContains(t, merged.Add(pk1, data2), "conflicting partial signed data")
+
+	_, err = core.MergeParSignedDataSets(set1, core.ParSignedDataSet{pk1: data2})
+	require.ErrorContains(t, err, "conflicting partial signed data")
+}
+
+func TestParSignedDataSetsBySlot(t *testing.T) {
+	pk := testutil.RandomCorePubKey(t)
+	data1 := core.NewPartialSignedRandao(1, testutil.RandomEth2Signature(), 1)
+	data2 := core.NewPartialSignedRandao(1, testutil.RandomEth2Signature(), 1)
+
+	sets := make(core.ParSignedDataSetsBySlot)
+	require.NoError(t, sets.Add(1, pk, data1))
+	require.NoError(t, sets.Add(2, pk, data2))
+	require.NoError(t, sets.Add(1, pk, data1))
+	require.Len(t, sets, 2)
+	require.Equal(t, data1, sets[1][pk])
+	require.Equal(t, data2, sets[2][pk])
+
+	require.ErrorContains(t, sets.Add(1, pk, data2), "conflicting partial signed data")
+
+	sets.Set(1, pk, data2)
+	require.Equal(t, data2, sets[1][pk])
+}
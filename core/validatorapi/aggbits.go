@@ -0,0 +1,163 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/z"
+	"github.com/obolnetwork/charon/core"
+	"github.com/obolnetwork/charon/eth2util/signing"
+	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
+	"github.com/obolnetwork/charon/tbls/v2/tblsconv"
+)
+
+// AggBitsResolver returns the validator committee indices of the validators that signed
+// a submitted attestation as resolved from its aggregation bits. Attestations resolving to multiple
+// validators are rejected, since their single aggregate signature isn't a partial signature of each validator.
+type AggBitsResolver func(att *eth2p0.Attestation) ([]int, error)
+
+// SingleBitResolver is the default AggBitsResolver. It requires exactly one aggregation bit set
+// since validator clients submit unaggregated attestations signed by a single validator.
+func SingleBitResolver(att *eth2p0.Attestation) ([]int, error) {
+	indices := att.AggregationBits.BitIndices()
+	if len(indices) != 1 {
+		return nil, errors.New("unexpected number of aggregation bits",
+			z.Str("aggbits", fmt.Sprintf("%#x", []byte(att.AggregationBits))))
+	}
+
+	return indices, nil
+}
+
+// attSigner is a distributed validator that signed a submitted attestation.
+type attSigner struct {
+	Pubkey   core.PubKey
+	ShareIdx int
+}
+
+// resolveAttSigners returns the distributed validator that signed the attestation using the registered
+// aggregation bits resolver. It returns an error if the attestation doesn't resolve to exactly one validator,
+// since an attestation contains one signature, which can only be stored as the partial signature of one validator.
+func (c Component) resolveAttSigners(ctx context.Context, att *eth2p0.Attestation) ([]attSigner, error) {
+	resolver := c.aggBitsResolver
+	if resolver == nil {
+		resolver = SingleBitResolver
+	}
+
+	indices, err := resolver(att)
+	if err != nil {
+		return nil, err
+	} else if len(indices) != 1 {
+		return nil, apiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "attestation not signed by exactly one validator",
+			Err:        errors.New("unexpected number of attestation signers", z.Int("signers", len(indices))),
+		}
+	}
+
+	p, err := c.presets.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyAttIndices(p, att.Data.Index, indices[0]); err != nil {
+		return nil, err
+	}
+
+	if err := c.verifyCommitteeSize(ctx, att); err != nil {
+		return nil, err
+	}
+
+	pubkey, err := c.lookupAttPubKey(ctx, att, indices[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return []attSigner{{Pubkey: pubkey, ShareIdx: c.shareIdxByPubKey(pubkey)}}, nil
+}
+
+// verifyAggregateAttestation verifies the signature of an aggregate attestation against the public keys of the
+// participating beacon committee validators. Note that aggregate attestations contain full (not partial) signatures,
+// so these are the validators' root public keys. It is a noop if no beacon committee function is registered.
+func (c Component) verifyAggregateAttestation(ctx context.Context, att *eth2p0.Attestation) error {
+	if c.insecureTest || c.committeeFunc == nil {
+		return nil
+	}
+
+	if att == nil || att.Data == nil || att.Data.Target == nil {
+		return errors.New("invalid aggregate attestation")
+	}
+
+	committee, err := c.committeeFunc(ctx, int64(att.Data.Slot), int64(att.Data.Index))
+	if err != nil {
+		return err
+	}
+
+	if att.AggregationBits.Len() != uint64(len(committee)) {
+		return apiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "aggregate attestation aggregation bits length mismatches committee size",
+			Err: errors.New("invalid aggregate attestation aggregation bits length",
+				z.U64("aggbits_len", att.AggregationBits.Len()), z.Int("committee_size", len(committee))),
+		}
+	}
+
+	var valIdxs []eth2p0.ValidatorIndex
+	for _, idx := range att.AggregationBits.BitIndices() {
+		valIdxs = append(valIdxs, committee[idx])
+	}
+
+	if len(valIdxs) == 0 {
+		return apiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "aggregate attestation without participants",
+			Err:        errors.New("no aggregation bits set"),
+		}
+	}
+
+	vals, err := c.eth2Cl.Validators(ctx, "head", valIdxs)
+	if err != nil {
+		return err
+	}
+
+	var pubkeys []tblsv2.PublicKey
+	for _, vIdx := range valIdxs {
+		val, ok := vals[vIdx]
+		if !ok || val == nil || val.Validator == nil {
+			return errors.New("aggregate attestation validator not found", z.U64("validator_index", uint64(vIdx)))
+		}
+
+		pubkeys = append(pubkeys, tblsv2.PublicKey(val.Validator.PublicKey))
+	}
+
+	root, err := att.Data.HashTreeRoot()
+	if err != nil {
+		return errors.Wrap(err, "hash attestation data")
+	}
+
+	sigData, err := signing.GetDataRoot(ctx, c.eth2Cl, signing.DomainBeaconAttester, att.Data.Target.Epoch, root)
+	if err != nil {
+		return err
+	}
+
+	sig, err := tblsconv.SignatureFromBytes(att.Signature[:])
+	if err != nil {
+		return err
+	}
+
+	if err := tblsv2.VerifyAggregate(pubkeys, sig, sigData[:]); err != nil {
+		parSigVerifyFailures.WithLabelValues(string(signing.DomainBeaconAttester)).Inc()
+		return apiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "invalid aggregate attestation signature",
+			Err:        errors.Wrap(err, "verify aggregate attestation signature", z.Int("participants", len(pubkeys))),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/log"
+	"github.com/obolnetwork/charon/core"
+)
+
+// quarantineTTL is the duration a validator is quarantined after failing asynchronous partial signature verification.
+const quarantineTTL = 10 * time.Minute
+
+// newQuarantine returns a new empty quarantine.
+func newQuarantine() *quarantine {
+	return &quarantine{
+		expiries: make(map[core.PubKey]time.Time),
+	}
+}
+
+// quarantine tracks the DV root public keys whose submissions are rejected after
+// failing asynchronous partial signature verification, until the quarantine expires.
+type quarantine struct {
+	mu       sync.Mutex
+	expiries map[core.PubKey]time.Time
+}
+
+// Add quarantines the public keys until quarantineTTL after now.
+func (q *quarantine) Add(now time.Time, pubkeys ...core.PubKey) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, pubkey := range pubkeys {
+		q.expiries[pubkey] = now.Add(quarantineTTL)
+	}
+}
+
+// Contains returns true if the public key is quarantined at now, removing it if its quarantine expired.
+// A nil quarantine contains nothing.
+func (q *quarantine) Contains(now time.Time, pubkey core.PubKey) bool {
+	if q == nil {
+		return false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	expiry, ok := q.expiries[pubkey]
+	if ok && !now.Before(expiry) {
+		delete(q.expiries, pubkey)
+		return false
+	}
+
+	return ok
+}
+
+// sigMismatchError is returned if a signature doesn't match the signed data, as opposed to
+// verification failing for other reasons like beacon node errors.
+type sigMismatchError struct {
+	Err error
+}
+
+func (e sigMismatchError) Error() string {
+	return e.Err.Error()
+}
+
+func (e sigMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// isSigMismatch returns true if the error is a signature mismatch, see sigMismatchError.
+func isSigMismatch(err error) bool {
+	return errors.As(err, new(sigMismatchError))
+}
+
+// asyncVerification is a deferred partial signature verification of the distributed validators.
+type asyncVerification struct {
+	Pubkeys []core.PubKey
+	Verify  func(context.Context) error
+	// Record is an optional function called once verification succeeded, e.g. recording slashing protection.
+	Record func(context.Context) error
+}
+
+// verifyQuarantine returns a bad request API error if the public key is quarantined.
+func (c Component) verifyQuarantine(pubkey core.PubKey) error {
+	if !c.quarantine.Contains(c.clock.Now(), pubkey) {
+		return nil
+	}
+
+	return apiError{
+		StatusCode: http.StatusBadRequest,
+		Message:    "validator quarantined after failed partial signature verification",
+		Err:        errors.New("quarantined validator", pubkeyField("pubkey", pubkey)),
+	}
+}
+
+// verifyAsync verifies the partial signatures in the background after they were optimistically stored,
+// quarantining the public keys if the signatures mismatch. Other verification errors are only logged.
+func (c Component) verifyAsync(verifications []asyncVerification) {
+	for _, v := range verifications {
+		v := v
+		c.bg.Go(func(ctx context.Context) {
+			err := v.Verify(ctx)
+			if err == nil {
+				if v.Record == nil {
+					return
+				}
+
+				if err := v.Record(ctx); err != nil && ctx.Err() == nil {
+					log.Error(ctx, "Failed recording asynchronously verified partial signature", err,
+						pubkeysField("pubkeys", v.Pubkeys))
+				}
+
+				return
+			} else if ctx.Err() != nil {
+				return
+			} else if !isSigMismatch(err) {
+				vapiAsyncVerifyErrors.Inc()
+				log.Warn(ctx, "Asynchronous partial signature verification failed, not quarantining validator", err,
+					pubkeysField("pubkeys", v.Pubkeys))
+
+				return
+			}
+
+			vapiAsyncVerifyFailures.Inc()
+			c.quarantine.Add(c.clock.Now(), v.Pubkeys...)
+
+			log.Error(ctx, "Quarantining validator after asynchronous partial signature verification failed", err,
+				pubkeysField("pubkeys", v.Pubkeys))
+		})
+	}
+}
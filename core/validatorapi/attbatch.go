@@ -0,0 +1,143 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/z"
+	"github.com/obolnetwork/charon/core"
+)
+
+// newAttBatcher returns a new attestation batcher that coalesces partial signed attestations
+// per slot submitted within the window before flushing them via the flush function.
+// The after function returns a channel that receives once the duration elapsed, e.g. clockwork.Clock.After.
+// The goFunc function waits for the window in a background goroutine, e.g. background.Go; pending batches
+// fail when its context is closed, and new batches fail once it is closed.
+func newAttBatcher(window time.Duration, after func(time.Duration) <-chan time.Time,
+	goFunc func(func(context.Context)) bool, flush func(context.Context, core.Duty, core.ParSignedDataSet) error,
+) *attBatcher {
+	return &attBatcher{
+		window:  window,
+		after:   after,
+		goFunc:  goFunc,
+		flush:   flush,
+		pending: make(map[int64]*attBatch),
+	}
+}
+
+// attBatch is a pending batch of partial signed attestations of a slot.
+type attBatch struct {
+	set  core.ParSignedDataSet
+	done chan struct{}
+	err  error
+}
+
+// attBatcher coalesces partial signed attestations per slot.
+type attBatcher struct {
+	window time.Duration
+	after  func(time.Duration) <-chan time.Time
+	goFunc func(func(context.Context)) bool
+	flush  func(context.Context, core.Duty, core.ParSignedDataSet) error
+
+	mu      sync.Mutex
+	pending map[int64]*attBatch
+}
+
+// Add adds the set to the pending batch of the slot and blocks until the batch is flushed,
+// returning the flush result. It returns an error if the set conflicts with a different
+// partial signed attestation of the same public key already in the batch or if the batcher is closed.
+func (b *attBatcher) Add(ctx context.Context, slot int64, set core.ParSignedDataSet) error {
+	batch, err := b.add(ctx, slot, set)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-batch.done:
+		return batch.err
+	}
+}
+
+// add merges the set into the pending batch of the slot, starting a new batch if none is pending.
+func (b *attBatcher) add(ctx context.Context, slot int64, set core.ParSignedDataSet) (*attBatch, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batch, ok := b.pending[slot]
+	if !ok {
+		batch = &attBatch{
+			set:  make(core.ParSignedDataSet),
+			done: make(chan struct{}),
+		}
+		// Flush with a context detached from the first submitter's cancellation since the batch is shared.
+		flushCtx := detachedCtx{ctx}
+		elapsed := b.after(b.window)
+		started := b.goFunc(func(ctx context.Context) {
+			select {
+			case <-elapsed:
+				b.flushSlot(flushCtx, slot)
+			case <-ctx.Done():
+				b.failSlot(slot, errors.New("attestation batch not flushed since validator api closed",
+					z.I64("slot", slot)))
+			}
+		})
+		if !started {
+			return nil, errors.New("validator api closed")
+		}
+
+		b.pending[slot] = batch
+	}
+
+	merged, err := core.MergeParSignedDataSets(batch.set, set)
+	if err != nil {
+		return nil, apiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "conflicting attestation for the same validator and slot",
+			Err:        errors.Wrap(err, "conflicting batched attestation", z.I64("slot", slot)),
+		}
+	}
+	batch.set = merged
+
+	return batch, nil
+}
+
+// flushSlot removes the pending batch of the slot and flushes it.
+func (b *attBatcher) flushSlot(ctx context.Context, slot int64) {
+	batch := b.remove(slot)
+	batch.err = b.flush(ctx, core.NewAttesterDuty(slot), batch.set)
+	close(batch.done)
+}
+
+// failSlot removes the pending batch of the slot and fails it with the error without flushing it.
+func (b *attBatcher) failSlot(slot int64, err error) {
+	batch := b.remove(slot)
+	batch.err = err
+	close(batch.done)
+}
+
+// remove removes and returns the pending batch of the slot.
+func (b *attBatcher) remove(slot int64) *attBatch {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batch := b.pending[slot]
+	delete(b.pending, slot)
+
+	return batch
+}
+
+// detachedCtx is a context that retains the parent's values but not its deadline or cancellation.
+type detachedCtx struct {
+	context.Context
+}
+
+func (detachedCtx) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedCtx) Done() <-chan struct{}       { return nil }
+func (detachedCtx) Err() error                  { return nil }
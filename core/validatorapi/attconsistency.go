@@ -0,0 +1,151 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/log"
+	"github.com/obolnetwork/charon/app/z"
+)
+
+// newAttConsistency returns a new empty attestation data consistency checker.
+func newAttConsistency() *attConsistency {
+	return &attConsistency{
+		firsts:  make(map[eth2p0.Slot]*eth2p0.AttestationData),
+		entries: make(map[eth2p0.Slot]*cacheEntry),
+	}
+}
+
+// attConsistency caches the first-seen attestation data per slot to detect attestation data
+// of different committees in the same slot disagreeing on source, target or head, which indicates a consensus bug.
+type attConsistency struct {
+	// budget bounds the memory of the cache together with other caches, it is nil if unbounded.
+	budget *cacheBudget
+
+	mu      sync.Mutex
+	firsts  map[eth2p0.Slot]*eth2p0.AttestationData
+	entries map[eth2p0.Slot]*cacheEntry
+}
+
+// attDataEntrySize is the approximate size in bytes of cached first-seen attestation data.
+const attDataEntrySize = 8 + 8 + 8 + 32 + 2*(8+32)
+
+// Check returns the names of the fields (source, target or head) that the attestation data
+// disagrees on with the first-seen attestation data of the slot.
+func (a *attConsistency) Check(data *eth2p0.AttestationData) []string {
+	fields, entry := a.check(data)
+	a.budget.Track(entry) // Track without holding the lock, since it may evict entries of this cache.
+
+	return fields
+}
+
+// check returns the mismatching fields of the attestation data, caching it as first-seen data
+// if none is cached for the slot and returning the new cache entry to track.
+func (a *attConsistency) check(data *eth2p0.AttestationData) ([]string, *cacheEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	first, ok := a.firsts[data.Slot]
+	if !ok {
+		a.firsts[data.Slot] = data
+
+		slot := data.Slot
+		var entry *cacheEntry
+		entry = a.budget.Entry(cacheAttConsistency, attDataEntrySize, func() { a.evict(slot, entry) })
+		if entry != nil {
+			a.entries[slot] = entry
+		}
+
+		return nil, entry
+	}
+	a.budget.Touch(a.entries[data.Slot])
+
+	var fields []string
+	if !checkpointEqual(first.Source, data.Source) {
+		fields = append(fields, "source")
+	}
+	if !checkpointEqual(first.Target, data.Target) {
+		fields = append(fields, "target")
+	}
+	if first.BeaconBlockRoot != data.BeaconBlockRoot {
+		fields = append(fields, "head")
+	}
+
+	return fields, nil
+}
+
+// evict removes the first-seen attestation data of the slot evicted by the cache budget, unless it was replaced since.
+func (a *attConsistency) evict(slot eth2p0.Slot, entry *cacheEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.entries[slot] != entry {
+		return
+	}
+
+	delete(a.entries, slot)
+	delete(a.firsts, slot)
+}
+
+// Trim evicts the first-seen attestation data of slots before the slot.
+func (a *attConsistency) Trim(slot eth2p0.Slot) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for s := range a.firsts {
+		if s < slot {
+			a.budget.Remove(a.entries[s])
+			delete(a.entries, s)
+			delete(a.firsts, s)
+		}
+	}
+}
+
+// checkpointEqual returns true if the checkpoints are equal.
+func checkpointEqual(a, b *eth2p0.Checkpoint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Epoch == b.Epoch && a.Root == b.Root
+}
+
+// verifyAttConsistency flags attestation data that disagrees with the first-seen attestation data of the slot
+// from another committee. It returns an internal server error if rejecting inconsistent attestation data is enabled.
+func (c Component) verifyAttConsistency(ctx context.Context, data *eth2p0.AttestationData) error {
+	if c.attConsistency == nil {
+		return nil
+	}
+
+	fields := c.attConsistency.Check(data)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	for _, field := range fields {
+		vapiAttDataInconsistentTotal.WithLabelValues(field).Inc()
+	}
+
+	err := errors.New("inconsistent attestation data across committees",
+		z.U64("slot", uint64(data.Slot)),
+		z.U64("committee_index", uint64(data.Index)),
+		z.Any("fields", fields),
+	)
+
+	if !c.rejectInconsistentAtt {
+		log.Warn(ctx, "Attestation data disagrees with other committee in same slot, possible consensus bug", err)
+		return nil
+	}
+
+	return apiError{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "attestation data disagrees with other committee in same slot",
+		Err:        err,
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"fmt"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// AttestationReceiptStatus is the outcome of a submitted attestation.
+type AttestationReceiptStatus string
+
+const (
+	// AttestationAccepted indicates the attestation was accepted. Note that signatures of accepted
+	// attestations are only verified after submission if asynchronous verification is enabled.
+	AttestationAccepted AttestationReceiptStatus = "accepted"
+	// AttestationDuplicate indicates the attestation was already submitted in the same batch.
+	AttestationDuplicate AttestationReceiptStatus = "duplicate"
+	// AttestationLate indicates the attestation was accepted after its attestation window closed,
+	// so it will likely not be included on-chain.
+	AttestationLate AttestationReceiptStatus = "late"
+	// AttestationVerificationFailed indicates the attestation signature is invalid.
+	AttestationVerificationFailed AttestationReceiptStatus = "verification_failed"
+	// AttestationRejected indicates the attestation was rejected for another reason, e.g. unknown validator,
+	// slashable or conflicting attestation.
+	AttestationRejected AttestationReceiptStatus = "rejected"
+)
+
+// AttestationReceipt is the outcome of the attestation at the index of a submitted batch.
+type AttestationReceipt struct {
+	Index   int                      `json:"index"`
+	Status  AttestationReceiptStatus `json:"status"`
+	Message string                   `json:"message,omitempty"`
+}
+
+// AttestationSlotsError is returned when storing the attestations of some slots of a submission spanning multiple
+// slots failed. Attestations of each slot are stored atomically and the attestations of the stored slots were stored,
+// so callers can retry only the attestations of the failed slots. It wraps the error of the first failed slot.
+type AttestationSlotsError struct {
+	// Stored are the slots whose attestations were stored in ascending order.
+	Stored []int64
+	// Failed are the slots whose attestations failed to be stored in ascending order.
+	Failed []int64
+	// Err is the error of the first failed slot.
+	Err error
+}
+
+func (e AttestationSlotsError) Error() string {
+	return fmt.Sprintf("store attestations of slots[stored=%v,failed=%v]: %v", e.Stored, e.Failed, e.Err)
+}
+
+func (e AttestationSlotsError) Unwrap() error {
+	return e.Err
+}
+
+// AttestationReceiptsSubmitter is the interface for submitting attestations and returning per-attestation receipts.
+type AttestationReceiptsSubmitter interface {
+	// SubmitAttestationsWithReceipts submits the attestations and returns a receipt of each attestation,
+	// so a batch with partial failures reports which attestations failed rather than failing the whole batch.
+	SubmitAttestationsWithReceipts(ctx context.Context, attestations []*eth2p0.Attestation) ([]AttestationReceipt, error)
+}
+
+// SubmitAttestationsWithReceipts implements AttestationReceiptsSubmitter for the router.
+func (c Component) SubmitAttestationsWithReceipts(ctx context.Context, attestations []*eth2p0.Attestation,
+) ([]AttestationReceipt, error) {
+	return c.submitAttestations(ctx, attestations, true)
+}
@@ -0,0 +1,150 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Names of the caches sharing the cache memory budget.
+const (
+	cacheRandaoRoots    = "randao_roots"
+	cacheAttConsistency = "att_consistency"
+	cacheValIndices     = "validator_indices"
+	cacheValidators     = "validators"
+	cacheDomains        = "domains"
+	cacheVerified       = "verified_signatures"
+	cacheCommittees     = "committees"
+)
+
+// newCacheBudget returns a new cache memory budget of the total approximate size in bytes.
+func newCacheBudget(limit int64) *cacheBudget {
+	return &cacheBudget{
+		limit: limit,
+		sizes: make(map[string]int64),
+		lru:   list.New(),
+	}
+}
+
+// cacheBudget bounds the total approximate memory of multiple caches by evicting the least recently used
+// entries across all caches once the budget is exceeded. A nil budget doesn't track or evict anything.
+//
+// Caches must not hold their own lock when calling Track, since it calls the evict functions of evicted entries,
+// which acquire the lock of their cache. Touch and Remove may be called with the cache lock held.
+type cacheBudget struct {
+	limit int64
+
+	mu    sync.Mutex
+	total int64
+	sizes map[string]int64
+	lru   *list.List // Of *cacheEntry, the front is the most recently used.
+}
+
+// cacheEntry is an entry of a cache tracked by the cache budget.
+type cacheEntry struct {
+	cache   string
+	size    int64
+	evict   func()
+	elem    *list.Element
+	removed bool
+}
+
+// Entry returns a new untracked entry of the cache with the approximate size in bytes. The evict function
+// removes the entry from its cache if evicted. It returns nil if the budget is nil.
+func (b *cacheBudget) Entry(cache string, size int64, evict func()) *cacheEntry {
+	if b == nil {
+		return nil
+	}
+
+	return &cacheEntry{cache: cache, size: size, evict: evict}
+}
+
+// Track starts tracking the entry as the most recently used and evicts the least recently used entries
+// of all caches while the budget is exceeded. The most recently used entry is never evicted.
+func (b *cacheBudget) Track(entry *cacheEntry) {
+	if b == nil || entry == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if entry.removed || entry.elem != nil {
+		b.mu.Unlock()
+		return
+	}
+
+	entry.elem = b.lru.PushFront(entry)
+	b.resize(entry.cache, entry.size)
+
+	var evicted []*cacheEntry
+	for b.total > b.limit && b.lru.Len() > 1 {
+		victim, _ := b.lru.Back().Value.(*cacheEntry)
+		b.remove(victim)
+		evicted = append(evicted, victim)
+	}
+	b.mu.Unlock()
+
+	for _, victim := range evicted {
+		vapiCacheEvictions.WithLabelValues(victim.cache).Inc()
+		victim.evict()
+	}
+}
+
+// Touch marks the entry as the most recently used.
+func (b *cacheBudget) Touch(entry *cacheEntry) {
+	if b == nil || entry == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if entry.elem != nil {
+		b.lru.MoveToFront(entry.elem)
+	}
+}
+
+// Remove stops tracking the entry after its cache removed it.
+func (b *cacheBudget) Remove(entry *cacheEntry) {
+	if b == nil || entry == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(entry)
+}
+
+// Size returns the total approximate size in bytes of all tracked entries.
+func (b *cacheBudget) Size() int64 {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.total
+}
+
+// remove stops tracking the entry. It must be called with the mutex held.
+func (b *cacheBudget) remove(entry *cacheEntry) {
+	entry.removed = true
+	if entry.elem == nil {
+		return
+	}
+
+	b.lru.Remove(entry.elem)
+	entry.elem = nil
+	b.resize(entry.cache, -entry.size)
+}
+
+// resize adds the delta to the size of the cache and the total size. It must be called with the mutex held.
+func (b *cacheBudget) resize(cache string, delta int64) {
+	b.sizes[cache] += delta
+	b.total += delta
+
+	vapiCacheSizeBytes.WithLabelValues(cache).Set(float64(b.sizes[cache]))
+	vapiCacheTotalSizeBytes.Set(float64(b.total))
+}
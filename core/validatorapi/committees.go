@@ -0,0 +1,169 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/app/z"
+	"github.com/obolnetwork/charon/eth2util"
+)
+
+// committeeKey identifies a beacon committee within an epoch.
+type committeeKey struct {
+	Slot  eth2p0.Slot
+	Index eth2p0.CommitteeIndex
+}
+
+// NewCommitteeCache returns a new committee cache that fetches all beacon committees
+// of an epoch from the beacon node once and reuses them for the rest of the epoch.
+func NewCommitteeCache(eth2Cl eth2wrap.Client) *CommitteeCache {
+	return &CommitteeCache{
+		eth2Cl:     eth2Cl,
+		committees: make(map[eth2p0.Epoch]map[committeeKey][]eth2p0.ValidatorIndex),
+		entries:    make(map[eth2p0.Epoch]*cacheEntry),
+	}
+}
+
+// CommitteeCache caches beacon committees by epoch, slot and committee index.
+type CommitteeCache struct {
+	eth2Cl eth2wrap.Client
+	// fetches deduplicates concurrent beacon node fetches of the same epoch, by epoch.
+	fetches singleflight.Group
+	// budget bounds the memory of the cache together with the component caches, it is nil if unbounded,
+	// see Component.RegisterCommitteeCache.
+	budget *cacheBudget
+
+	mu         sync.Mutex
+	committees map[eth2p0.Epoch]map[committeeKey][]eth2p0.ValidatorIndex
+	entries    map[eth2p0.Epoch]*cacheEntry
+}
+
+// CommitteeSize returns the size of the beacon committee of the provided slot and committee index.
+func (c *CommitteeCache) CommitteeSize(ctx context.Context, slot, commIdx int64) (int, error) {
+	committee, err := c.Committee(ctx, slot, commIdx)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(committee), nil
+}
+
+// Committee returns the validator indices of the beacon committee of the provided slot and committee index.
+func (c *CommitteeCache) Committee(ctx context.Context, slot, commIdx int64) ([]eth2p0.ValidatorIndex, error) {
+	epoch, err := eth2util.EpochFromSlot(ctx, c.eth2Cl, eth2p0.Slot(slot))
+	if err != nil {
+		return nil, err
+	}
+
+	committees, err := c.getOrFetch(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	committee, ok := committees[committeeKey{Slot: eth2p0.Slot(slot), Index: eth2p0.CommitteeIndex(commIdx)}]
+	if !ok {
+		return nil, errors.New("beacon committee not found", z.I64("slot", slot), z.I64("committee_index", commIdx))
+	}
+
+	return committee, nil
+}
+
+// getOrFetch returns the cached committees of the epoch, fetching them from the beacon node if not cached.
+// Concurrent fetches of the same epoch are deduplicated and the lock isn't held while fetching,
+// so lookups of cached epochs don't wait for fetches.
+func (c *CommitteeCache) getOrFetch(ctx context.Context, epoch eth2p0.Epoch) (map[committeeKey][]eth2p0.ValidatorIndex, error) {
+	if committees, ok := c.get(epoch); ok {
+		return committees, nil
+	}
+
+	resp, err, _ := c.fetches.Do(strconv.FormatUint(uint64(epoch), 10), func() (interface{}, error) {
+		if committees, ok := c.get(epoch); ok {
+			return committees, nil // Fetched by a previous flight.
+		}
+
+		resp, err := c.eth2Cl.BeaconCommitteesAtEpoch(ctx, "head", epoch)
+		if err != nil {
+			return nil, err
+		}
+
+		committees := make(map[committeeKey][]eth2p0.ValidatorIndex)
+		for _, comm := range resp {
+			committees[committeeKey{Slot: comm.Slot, Index: comm.Index}] = comm.Validators
+		}
+
+		c.budget.Track(c.set(epoch, committees)) // Track without holding the lock, since it may evict entries of this cache.
+
+		return committees, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(map[committeeKey][]eth2p0.ValidatorIndex), nil
+}
+
+// get returns the cached committees of the epoch and true or false if not cached.
+func (c *CommitteeCache) get(epoch eth2p0.Epoch) (map[committeeKey][]eth2p0.ValidatorIndex, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	committees, ok := c.committees[epoch]
+	c.budget.Touch(c.entries[epoch])
+
+	return committees, ok
+}
+
+// committeeEntrySize is the approximate size in bytes of a cached beacon committee excluding its validator indices.
+const committeeEntrySize = 8 + 8 + 24
+
+// set caches the committees of the epoch and returns the new cache entry to track. Only the committees
+// of the latest two epochs are retained to support attestations across epoch boundaries.
+func (c *CommitteeCache) set(epoch eth2p0.Epoch, committees map[committeeKey][]eth2p0.ValidatorIndex) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.committees[epoch] = committees
+
+	for e := range c.committees {
+		if e+1 < epoch {
+			c.budget.Remove(c.entries[e])
+			delete(c.entries, e)
+			delete(c.committees, e)
+		}
+	}
+
+	var size int64
+	for _, committee := range committees {
+		size += committeeEntrySize + 8*int64(len(committee))
+	}
+
+	c.budget.Remove(c.entries[epoch])
+	var entry *cacheEntry
+	entry = c.budget.Entry(cacheCommittees, size, func() { c.evict(epoch, entry) })
+	if entry != nil {
+		c.entries[epoch] = entry
+	}
+
+	return entry
+}
+
+// evict removes the committees of the epoch evicted by the cache budget, unless they were replaced since.
+func (c *CommitteeCache) evict(epoch eth2p0.Epoch, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[epoch] != entry {
+		return
+	}
+
+	delete(c.entries, epoch)
+	delete(c.committees, epoch)
+}
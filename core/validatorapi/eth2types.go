@@ -107,6 +107,12 @@ type proposeBlockResponseCapella struct {
 	Data    *capella.BeaconBlock `json:"data"`
 }
 
+// attestationReceiptsResponse defines the response to the submitPoolAttestations endpoint if receipts are requested,
+// which is a charon extension of the beacon API.
+type attestationReceiptsResponse struct {
+	Data []AttestationReceipt `json:"data"`
+}
+
 type validatorsResponse struct {
 	Data []v1Validator `json:"data"`
 }
@@ -115,6 +121,17 @@ type validatorResponse struct {
 	Data v1Validator `json:"data"`
 }
 
+// validatorBalancesResponse defines the response to the getStateValidatorBalances endpoint.
+// See https://ethereum.github.io/beacon-APIs/#/Beacon/getStateValidatorBalances.
+type validatorBalancesResponse struct {
+	Data []validatorBalance `json:"data"`
+}
+
+type validatorBalance struct {
+	Index   eth2p0.ValidatorIndex `json:"index,string"`
+	Balance eth2p0.Gwei           `json:"balance,string"`
+}
+
 type aggregateBeaconCommitteeSelectionsJSON struct {
 	Data []*eth2exp.BeaconCommitteeSelection `json:"data"`
 }
@@ -0,0 +1,160 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/obolnetwork/charon/app/log"
+)
+
+const (
+	// defaultStateRetention is the default number of epochs before the current epoch for which per-slot state is retained.
+	defaultStateRetention = 2
+	// maxTrackedSlots bounds the number of slots tracked by per-slot state, evicting the oldest slots first.
+	maxTrackedSlots = 1024
+	// gcInterval is the interval at which per-slot state is garbage collected.
+	gcInterval = time.Minute
+)
+
+// trimmer is implemented by per-slot state that can be trimmed, e.g. MemSlashingProtector.
+type trimmer interface {
+	// Trim evicts state of epochs before the epoch.
+	Trim(epoch eth2p0.Epoch)
+}
+
+// StartGC starts a background goroutine that periodically evicts cached per-slot state older than
+// the configured retention epochs before the current epoch. It stops when the component is closed.
+func (c Component) StartGC() {
+	c.bg.Go(func(ctx context.Context) {
+		ticker := c.clock.NewTicker(gcInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.Chan():
+				if err := c.gc(ctx); err != nil {
+					log.Warn(ctx, "Failed evicting validator api per-slot state", err)
+				}
+			}
+		}
+	})
+}
+
+// gc evicts per-slot state older than the retention epochs before the current epoch.
+func (c Component) gc(ctx context.Context) error {
+	slot, err := c.currentSlot(ctx)
+	if err != nil {
+		return err
+	}
+
+	slotsPerEpoch, err := c.slotsPerEpoch(ctx)
+	if err != nil {
+		return err
+	}
+
+	retention := c.stateRetention
+	if retention == 0 {
+		retention = defaultStateRetention
+	}
+
+	epoch := uint64(slot) / slotsPerEpoch
+	if epoch < retention {
+		return nil
+	}
+
+	cutoff := eth2p0.Epoch(epoch - retention)
+	c.trimState(eth2p0.Slot(uint64(cutoff)*slotsPerEpoch), cutoff)
+
+	return nil
+}
+
+// trimState evicts per-slot state before the slot and epoch.
+func (c Component) trimState(slot eth2p0.Slot, epoch eth2p0.Epoch) {
+	c.slotGauges.Trim(slot)
+	c.attConsistency.Trim(slot)
+
+	if t, ok := c.slashingProtector.(trimmer); ok {
+		t.Trim(epoch)
+	}
+}
+
+// newSlotGauges returns a new per-slot gauge tracker.
+func newSlotGauges() *slotGauges {
+	return &slotGauges{
+		deletes: make(map[eth2p0.Slot][]func()),
+	}
+}
+
+// slotGauges tracks the label values of gauges with a slot label, so they can be deleted
+// once the slot is old, preventing unbounded metric cardinality growth.
+type slotGauges struct {
+	mu      sync.Mutex
+	deletes map[eth2p0.Slot][]func()
+}
+
+// Set sets the value of the gauge with the slot as first label value followed by the other label values.
+func (g *slotGauges) Set(gauge *prometheus.GaugeVec, slot eth2p0.Slot, val float64, labels ...string) {
+	labels = append([]string{fmt.Sprint(slot)}, labels...)
+	gauge.WithLabelValues(labels...).Set(val)
+
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.deletes[slot] = append(g.deletes[slot], func() { gauge.DeleteLabelValues(labels...) })
+
+	// Evict the oldest slots if too many are tracked.
+	for len(g.deletes) > maxTrackedSlots {
+		oldest := slot
+		for s := range g.deletes {
+			if s < oldest {
+				oldest = s
+			}
+		}
+		g.deleteSlot(oldest)
+	}
+}
+
+// Trim deletes the gauges of slots before the slot.
+func (g *slotGauges) Trim(slot eth2p0.Slot) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for s := range g.deletes {
+		if s < slot {
+			g.deleteSlot(s)
+		}
+	}
+}
+
+// Len returns the number of tracked slots.
+func (g *slotGauges) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.deletes)
+}
+
+// deleteSlot deletes the gauges of the slot. It must be called with the mutex held.
+func (g *slotGauges) deleteSlot(slot eth2p0.Slot) {
+	for _, del := range g.deletes[slot] {
+		del()
+	}
+	delete(g.deletes, slot)
+}
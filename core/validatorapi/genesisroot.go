@@ -0,0 +1,60 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+
+	eth2client "github.com/attestantio/go-eth2-client"
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/z"
+	"github.com/obolnetwork/charon/core"
+	"github.com/obolnetwork/charon/eth2util/signing"
+	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
+)
+
+// VerifyGenesisValidatorsRoot returns an error if the genesis validators root reported by the beacon node
+// mismatches the expected root. Since signing domains of some duties (e.g. voluntary exits) depend on it,
+// this prevents signing against the wrong network.
+func VerifyGenesisValidatorsRoot(ctx context.Context, eth2Cl eth2client.GenesisProvider, expected eth2p0.Root) error {
+	genesis, err := eth2Cl.Genesis(ctx)
+	if err != nil {
+		return errors.Wrap(err, "fetch genesis")
+	}
+
+	if genesis.GenesisValidatorsRoot != expected {
+		return errors.New("mismatching beacon node genesis validators root, beacon node on wrong network",
+			z.Hex("expected", expected[:]), z.Hex("actual", genesis.GenesisValidatorsRoot[:]))
+	}
+
+	return nil
+}
+
+// genesisData is the genesis fork version and genesis validators root of the network, configured via WithGenesisData.
+type genesisData struct {
+	ForkVersion    eth2p0.Version
+	ValidatorsRoot eth2p0.Root
+}
+
+// LocalDomain returns the signing domain of the fork-independent domain name computed from the genesis data and true,
+// or false if the domain name isn't fork-independent or if the genesis data is nil, i.e., not configured.
+func (g *genesisData) LocalDomain(name signing.DomainName) (eth2p0.Domain, bool, error) {
+	if g == nil {
+		return eth2p0.Domain{}, false, nil
+	}
+
+	return signing.LocalDomain(name, g.ForkVersion, g.ValidatorsRoot)
+}
+
+// verifyWithLocalDomain returns an error if the eth2 signed data signature doesn't match the public share
+// using the locally computed signing domain, without querying the beacon node.
+func verifyWithLocalDomain(data core.Eth2SignedData, domain eth2p0.Domain, pubshare tblsv2.PublicKey) error {
+	root, err := data.MessageRoot()
+	if err != nil {
+		return err
+	}
+
+	return signing.VerifyWithDomain(domain, root, data.Signature().ToETH2(), pubshare)
+}
@@ -0,0 +1,34 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"net/http"
+
+	"github.com/obolnetwork/charon/app/errors"
+)
+
+// errMaintenance is returned by submissions while the component is in maintenance mode.
+var errMaintenance = errors.NewSentinel("maintenance mode")
+
+// SetMaintenance enables or disables maintenance mode, e.g. during a planned beacon node restart. While enabled,
+// submissions are rejected with a retryable service unavailable error, while read queries are still served.
+// It is safe to call concurrently with submissions.
+func (c *Component) SetMaintenance(enabled bool) {
+	c.maintenance.Store(enabled)
+}
+
+// verifyNotMaintenance returns a retryable service unavailable API error if the component is in maintenance mode.
+func (c Component) verifyNotMaintenance() error {
+	if !c.maintenance.Load() {
+		return nil
+	}
+
+	vapiMaintenanceRejections.Inc()
+
+	return apiError{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "charon is in maintenance mode, please retry later",
+		Err:        errMaintenance,
+	}
+}
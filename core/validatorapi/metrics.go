@@ -25,6 +25,148 @@ var (
 		Name:      "request_error_total",
 		Help:      "The total number of validatorapi request errors",
 	}, []string{"endpoint", "status_code"})
+
+	invalidAttIndices = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "invalid_attestation_index_total",
+		Help:      "The total number of submitted attestations rejected due to out-of-range indices by index type",
+	}, []string{"index_type"})
+
+	parSigVerifyFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "partial_signature_verification_failure_total",
+		Help:      "The total number of submitted partial signatures failing verification by signature domain",
+	}, []string{"domain"})
+
+	vapiVerifyWouldReject = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "verify_would_reject_total",
+		Help:      "The total number of submitted partial signatures failing verification that were not rejected in report-only mode by signature domain",
+	}, []string{"domain"})
+
+	vapiAsyncVerifyFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "async_verification_failure_total",
+		Help:      "The total number of optimistically stored partial signatures failing asynchronous verification, quarantining the validator",
+	})
+
+	vapiAsyncVerifyErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "async_verification_error_total",
+		Help:      "The total number of asynchronous partial signature verifications failing without a signature mismatch, e.g. due to beacon node errors",
+	})
+
+	vapiAttDataInconsistentTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "attestation_data_inconsistent_total",
+		Help:      "The total number of attestation data disagreeing with another committee in the same slot by field (source, target or head)",
+	}, []string{"field"})
+
+	// vapiValidatorSubmissions has high cardinality, so it is only populated if enabled.
+	vapiValidatorSubmissions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "validator_submissions_total",
+		Help:      "The total number of submitted partial signatures by duty type and validator (abbreviated DV public key), only populated if enabled",
+	}, []string{"duty", "pubkey"})
+
+	vapiBeaconRateLimitedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "beacon_rate_limited_total",
+		Help:      "The total number of beacon node requests that were rate limited by endpoint",
+	}, []string{"endpoint"})
+
+	vapiPubkeyLookupSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "attestation_pubkey_lookup_seconds",
+		Help:      "The latency in seconds of resolving submitted attestation public keys via dutyDB. A slow lookup indicates dutyDB or consensus lag",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .5, 1, 2, 4, 8},
+	})
+
+	vapiProposerRandaoReady = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "proposer_randao_ready",
+		Help:      "Set to 1 if an aggregated randao reveal is available for the proposal slot when proposer duties are queried, else 0",
+	}, []string{"slot"})
+
+	vapiSyncContributionReady = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "sync_contribution_ready",
+		Help:      "Set to 1 if a sync committee contribution is available for the slot and subcommittee when it is queried, else 0",
+	}, []string{"slot", "subcommittee"})
+
+	vapiSlashingRejectedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "slashing_rejected_total",
+		Help:      "The total number of submitted attestations rejected by slashing protection",
+	})
+
+	vapiCacheSizeBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "cache_size_bytes",
+		Help:      "Approximate memory in bytes of the cache entries tracked by the cache budget by cache",
+	}, []string{"cache"})
+
+	vapiCacheTotalSizeBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "cache_total_size_bytes",
+		Help:      "Approximate total memory in bytes of all cache entries tracked by the cache budget",
+	})
+
+	vapiCacheEvictions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "cache_evictions_total",
+		Help:      "The total number of cache entries evicted due to exceeding the cache budget by cache",
+	}, []string{"cache"})
+
+	vapiSubmitQueuedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "attestation_submit_queued_total",
+		Help:      "The total number of attestation submissions queued due to the concurrency limit",
+	})
+
+	vapiSubmitQueueTimeoutTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "attestation_submit_queue_timeout_total",
+		Help:      "The total number of attestation submissions rejected after timing out in the concurrency limit queue",
+	})
+
+	vapiSmallCluster = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "small_cluster",
+		Help:      "Set to 1 if the cluster size inferred from the public shares doesn't tolerate any faulty node, otherwise 0",
+	})
+
+	vapiMaintenanceRejections = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "maintenance_rejections_total",
+		Help:      "The total number of submissions rejected in maintenance mode",
+	})
+
+	vapiVerifyQueuedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Subsystem: "validatorapi",
+		Name:      "verification_queued_total",
+		Help:      "The total number of partial signature verifications queued due to all verification workers of the class being busy",
+	}, []string{"class"})
 )
 
 func incAPIErrors(endpoint string, statusCode int) {
@@ -0,0 +1,220 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"time"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/jonboulle/clockwork"
+
+	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/core"
+	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
+)
+
+// options configures a Component constructed via New.
+type options struct {
+	shareIdxByKey         map[core.PubKey]int
+	feeRecipientFunc      func(core.PubKey) string
+	builderEnabled        core.BuilderEnabled
+	seenPubkeys           func(core.PubKey)
+	insecure              bool
+	redactSigs            bool
+	asyncVerify           bool
+	verifyReportOnly      bool
+	rejectInconsistentAtt bool
+	validatorMetrics      bool
+	awaitTimeout          time.Duration
+	stateRetention        uint64
+	submitLimit           int
+	submitQueueTimeout    time.Duration
+	verifyWorkers         map[VerifyClass]int
+	cacheBudget           int64
+	clock                 clockwork.Clock
+	genesis               *genesisData
+}
+
+// Option configures a Component constructed via New.
+type Option func(*options)
+
+// WithShareIndices returns an option that configures a share index per DV root public key,
+// for a node that may hold a different share index per distributed validator (multi-operator host).
+func WithShareIndices(shareIdxByKey map[core.PubKey]int) Option {
+	return func(o *options) {
+		o.shareIdxByKey = shareIdxByKey
+	}
+}
+
+// WithFeeRecipientFunc returns an option that configures the fee recipient address per DV root public key.
+func WithFeeRecipientFunc(fn func(core.PubKey) string) Option {
+	return func(o *options) {
+		o.feeRecipientFunc = fn
+	}
+}
+
+// WithBuilderEnabled returns an option that configures whether the builder API is enabled per slot.
+func WithBuilderEnabled(builderEnabled core.BuilderEnabled) Option {
+	return func(o *options) {
+		o.builderEnabled = builderEnabled
+	}
+}
+
+// WithSeenPubkeys returns an option that configures a function called with DV root public keys
+// queried or submitted by the validator client.
+func WithSeenPubkeys(fn func(core.PubKey)) Option {
+	return func(o *options) {
+		o.seenPubkeys = fn
+	}
+}
+
+// WithInsecureSkipVerify returns an option that disables partial signature verification. Only use it for testing.
+func WithInsecureSkipVerify() Option {
+	return func(o *options) {
+		o.insecure = true
+	}
+}
+
+// WithRedactSignatures returns an option that excludes signature material from partial signature verification errors.
+func WithRedactSignatures(redact bool) Option {
+	return func(o *options) {
+		o.redactSigs = redact
+	}
+}
+
+// WithAsyncVerify returns an option that enables asynchronous partial signature verification of submitted
+// attestations, see Component.SetAsyncVerify. Only use it in trusted environments.
+func WithAsyncVerify(async bool) Option {
+	return func(o *options) {
+		o.asyncVerify = async
+	}
+}
+
+// WithVerifyReportOnly returns an option that only reports partial signature verification failures
+// instead of rejecting submissions, see Component.SetVerifyReportOnly.
+func WithVerifyReportOnly(reportOnly bool) Option {
+	return func(o *options) {
+		o.verifyReportOnly = reportOnly
+	}
+}
+
+// WithRejectInconsistentAttestationData returns an option that rejects attestation data disagreeing with
+// the attestation data of another committee in the same slot, see Component.SetRejectInconsistentAttestationData.
+func WithRejectInconsistentAttestationData(reject bool) Option {
+	return func(o *options) {
+		o.rejectInconsistentAtt = reject
+	}
+}
+
+// WithValidatorSubmissionMetrics returns an option that enables counting submitted partial signatures per validator,
+// see Component.SetValidatorSubmissionMetrics.
+func WithValidatorSubmissionMetrics(enabled bool) Option {
+	return func(o *options) {
+		o.validatorMetrics = enabled
+	}
+}
+
+// WithClock returns an option that overrides the time source used to compute the current slot, see Component.SetClock.
+func WithClock(clock clockwork.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithGenesisData returns an option that configures the network's genesis fork version and genesis validators root,
+// used to compute fork-independent signing domains (e.g. builder registrations) locally without querying the beacon node.
+func WithGenesisData(forkVersion eth2p0.Version, validatorsRoot eth2p0.Root) Option {
+	return func(o *options) {
+		o.genesis = &genesisData{ForkVersion: forkVersion, ValidatorsRoot: validatorsRoot}
+	}
+}
+
+// WithAwaitTimeout returns an option that overrides the maximum duration to await unsigned attestation data and blocks.
+func WithAwaitTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.awaitTimeout = timeout
+	}
+}
+
+// WithStateRetention returns an option that overrides the number of epochs before the current epoch
+// for which per-slot state is retained by the garbage collector.
+func WithStateRetention(epochs uint64) Option {
+	return func(o *options) {
+		o.stateRetention = epochs
+	}
+}
+
+// WithAttestationSubmissionLimit returns an option that limits the number of attestation submissions
+// processed concurrently, see Component.SetAttestationSubmissionLimit.
+func WithAttestationSubmissionLimit(limit int, queueTimeout time.Duration) Option {
+	return func(o *options) {
+		o.submitLimit = limit
+		o.submitQueueTimeout = queueTimeout
+	}
+}
+
+// WithVerificationWorkers returns an option that limits the number of partial signature verifications
+// of the class processed concurrently, see Component.SetVerificationWorkers.
+func WithVerificationWorkers(class VerifyClass, workers int) Option {
+	return func(o *options) {
+		if o.verifyWorkers == nil {
+			o.verifyWorkers = make(map[VerifyClass]int)
+		}
+		o.verifyWorkers[class] = workers
+	}
+}
+
+// WithCacheBudget returns an option that bounds the total approximate memory in bytes of the component caches,
+// see Component.SetCacheBudget.
+func WithCacheBudget(bytes int64) Option {
+	return func(o *options) {
+		o.cacheBudget = bytes
+	}
+}
+
+// New returns a new instance of the validator API core workflow component configured by the options.
+// The shareIdx is this node's share index of all distributed validators unless overridden by WithShareIndices.
+func New(eth2Cl eth2wrap.Client, allPubSharesByKey map[core.PubKey]map[int]tblsv2.PublicKey, shareIdx int, opts ...Option) (*Component, error) {
+	o := options{
+		builderEnabled: func(int64) bool { return false },
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.shareIdxByKey == nil {
+		o.shareIdxByKey = make(map[core.PubKey]int)
+		for corePubkey := range allPubSharesByKey {
+			o.shareIdxByKey[corePubkey] = shareIdx
+		}
+	}
+
+	c, err := NewComponentWithShareIndices(eth2Cl, allPubSharesByKey, o.shareIdxByKey, o.feeRecipientFunc, o.builderEnabled, o.seenPubkeys)
+	if err != nil {
+		return nil, err
+	}
+
+	warnSmallCluster(context.Background(), allPubSharesByKey)
+
+	c.shareIdx = shareIdx
+	c.insecureTest = o.insecure
+	c.redactSigs = o.redactSigs
+	c.asyncVerify = o.asyncVerify
+	c.verifyReportOnly = o.verifyReportOnly
+	if o.clock != nil {
+		c.clock = o.clock
+	}
+	c.genesis = o.genesis
+	c.rejectInconsistentAtt = o.rejectInconsistentAtt
+	c.validatorMetrics = o.validatorMetrics
+	c.awaitTimeout = o.awaitTimeout
+	c.stateRetention = o.stateRetention
+	c.SetAttestationSubmissionLimit(o.submitLimit, o.submitQueueTimeout)
+	for class, workers := range o.verifyWorkers {
+		c.SetVerificationWorkers(class, workers)
+	}
+	c.SetCacheBudget(o.cacheBudget)
+
+	return c, nil
+}
@@ -0,0 +1,124 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"sync"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/app/z"
+)
+
+// preset contains the eth2 preset constants used to validate submissions, since they differ
+// between presets, e.g. the minimal preset used by testnets has 8 slots per epoch and 4 committees per slot.
+type preset struct {
+	Name                      string
+	SlotsPerEpoch             uint64
+	MaxCommitteesPerSlot      uint64
+	MaxValidatorsPerCommittee uint64
+}
+
+// mainnetPreset is the mainnet preset, used if no beacon node is configured.
+var mainnetPreset = preset{
+	Name:                      "mainnet",
+	SlotsPerEpoch:             32,
+	MaxCommitteesPerSlot:      maxCommitteesPerSlot,
+	MaxValidatorsPerCommittee: maxValidatorsPerCommittee,
+}
+
+// EpochFromSlot returns the epoch of the slot.
+func (p preset) EpochFromSlot(slot eth2p0.Slot) eth2p0.Epoch {
+	return eth2p0.Epoch(uint64(slot) / p.SlotsPerEpoch)
+}
+
+// newPresetCache returns a new preset cache that queries the active preset from the beacon node spec.
+func newPresetCache(eth2Cl eth2wrap.Client) *presetCache {
+	return &presetCache{eth2Cl: eth2Cl}
+}
+
+// presetCache caches the beacon node's active preset, since it never changes.
+type presetCache struct {
+	eth2Cl eth2wrap.Client
+
+	mu      sync.Mutex
+	fetched bool
+	preset  preset
+}
+
+// Get returns the beacon node's active preset. Preset constants absent from the spec default to mainnet values.
+// It returns the mainnet preset if no beacon node is configured.
+func (c *presetCache) Get(ctx context.Context) (preset, error) {
+	if c == nil || c.eth2Cl == nil {
+		return mainnetPreset, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fetched {
+		return c.preset, nil
+	}
+
+	spec, err := c.eth2Cl.Spec(ctx)
+	if err != nil {
+		return preset{}, errors.Wrap(err, "get spec")
+	}
+
+	slotsPerEpoch, err := c.eth2Cl.SlotsPerEpoch(ctx)
+	if err != nil {
+		return preset{}, errors.Wrap(err, "get slots per epoch")
+	} else if slotsPerEpoch == 0 {
+		return preset{}, errors.New("zero slots per epoch")
+	}
+
+	p := mainnetPreset
+	p.Name, _ = spec["PRESET_BASE"].(string)
+	p.SlotsPerEpoch = slotsPerEpoch
+
+	for key, field := range map[string]*uint64{
+		"MAX_COMMITTEES_PER_SLOT":      &p.MaxCommitteesPerSlot,
+		"MAX_VALIDATORS_PER_COMMITTEE": &p.MaxValidatorsPerCommittee,
+	} {
+		val, ok := spec[key]
+		if !ok {
+			continue
+		}
+
+		uintVal, ok := val.(uint64)
+		if !ok {
+			return preset{}, errors.New("invalid spec value", z.Str("key", key))
+		}
+
+		*field = uintVal
+	}
+
+	c.fetched = true
+	c.preset = p
+
+	return p, nil
+}
+
+// slotsPerEpoch returns the slots per epoch of the beacon node's active preset.
+func (c Component) slotsPerEpoch(ctx context.Context) (uint64, error) {
+	p, err := c.presets.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return p.SlotsPerEpoch, nil
+}
+
+// epochFromSlot returns the epoch of the slot using the beacon node's active preset.
+// It should be used by all duty paths instead of querying slots per epoch from the beacon node.
+func (c Component) epochFromSlot(ctx context.Context, slot eth2p0.Slot) (eth2p0.Epoch, error) {
+	p, err := c.presets.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return p.EpochFromSlot(slot), nil
+}
@@ -0,0 +1,82 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/obolnetwork/charon/app/z"
+	"github.com/obolnetwork/charon/core"
+)
+
+// PubKeyLogFormat defines how validator public keys are formatted in log fields of the package.
+type PubKeyLogFormat int32
+
+const (
+	// PubKeyLogAbbreviated formats public keys as the hex of their first 4 bytes. This is the default.
+	PubKeyLogAbbreviated PubKeyLogFormat = iota
+	// PubKeyLogHashed formats public keys as the hex of the first 4 bytes of their sha256 hash,
+	// so logs cannot be linked to public keys.
+	PubKeyLogHashed
+	// PubKeyLogFull formats public keys in full.
+	PubKeyLogFull
+)
+
+// pubkeyLogFormat is the configured public key log format.
+var pubkeyLogFormat atomic.Int32
+
+// SetPubKeyLogFormat sets the format of validator public keys in log fields of the package.
+func SetPubKeyLogFormat(format PubKeyLogFormat) {
+	pubkeyLogFormat.Store(int32(format))
+}
+
+// formatPubKey returns the public key bytes formatted per the configured format.
+func formatPubKey(b []byte) string {
+	switch PubKeyLogFormat(pubkeyLogFormat.Load()) {
+	case PubKeyLogFull:
+		return "0x" + hex.EncodeToString(b)
+	case PubKeyLogHashed:
+		hash := sha256.Sum256(b)
+		return "sha256:" + hex.EncodeToString(hash[:4])
+	default:
+		if len(b) > 4 {
+			b = b[:4]
+		}
+
+		return "0x" + hex.EncodeToString(b)
+	}
+}
+
+// logPubKey returns the DV public key formatted per the configured format.
+func logPubKey(pubkey core.PubKey) string {
+	b, err := pubkey.Bytes()
+	if err != nil {
+		return pubkey.String()
+	}
+
+	return formatPubKey(b)
+}
+
+// pubkeyField returns a log field of the DV public key formatted per the configured format.
+func pubkeyField(key string, pubkey core.PubKey) z.Field {
+	return z.Str(key, logPubKey(pubkey))
+}
+
+// eth2PubkeyField returns a log field of the eth2 public key formatted per the configured format.
+func eth2PubkeyField(key string, pubkey eth2p0.BLSPubKey) z.Field {
+	return z.Str(key, formatPubKey(pubkey[:]))
+}
+
+// pubkeysField returns a log field of the DV public keys formatted per the configured format.
+func pubkeysField(key string, pubkeys []core.PubKey) z.Field {
+	var resp []string
+	for _, pubkey := range pubkeys {
+		resp = append(resp, logPubKey(pubkey))
+	}
+
+	return z.Any(key, resp)
+}
@@ -0,0 +1,131 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"sync"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/core"
+	"github.com/obolnetwork/charon/eth2util"
+	"github.com/obolnetwork/charon/eth2util/signing"
+	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
+)
+
+// newRandaoRootCache returns a new randao signing root cache that computes signing roots via the function.
+func newRandaoRootCache(signingRootFunc func(context.Context, eth2p0.Epoch) ([32]byte, error)) *randaoRootCache {
+	return &randaoRootCache{
+		signingRootFunc: signingRootFunc,
+		roots:           make(map[eth2p0.Epoch][32]byte),
+		entries:         make(map[eth2p0.Epoch]*cacheEntry),
+	}
+}
+
+// newEth2RandaoRootCache returns a new randao signing root cache that computes the domain-wrapped
+// signing roots of epochs using the beacon node provided domains.
+func newEth2RandaoRootCache(eth2Cl eth2wrap.Client) *randaoRootCache {
+	return newRandaoRootCache(func(ctx context.Context, epoch eth2p0.Epoch) ([32]byte, error) {
+		root, err := eth2util.SignedEpoch{Epoch: epoch}.HashTreeRoot()
+		if err != nil {
+			return [32]byte{}, errors.Wrap(err, "hash epoch")
+		}
+
+		return signing.GetDataRoot(ctx, eth2Cl, signing.DomainRandao, epoch, root)
+	})
+}
+
+// randaoRootCache caches the randao signing roots of recent epochs, since the randao reveals
+// of all proposers in an epoch sign the same root.
+type randaoRootCache struct {
+	signingRootFunc func(context.Context, eth2p0.Epoch) ([32]byte, error)
+
+	// budget bounds the memory of the cache together with other caches, it is nil if unbounded.
+	budget *cacheBudget
+
+	mu      sync.Mutex
+	roots   map[eth2p0.Epoch][32]byte
+	entries map[eth2p0.Epoch]*cacheEntry
+}
+
+// randaoRootEntrySize is the approximate size in bytes of a cached randao signing root.
+const randaoRootEntrySize = 8 + 32
+
+// SigningRoot returns the domain-wrapped randao signing root of the epoch.
+// Only the roots of the latest two epochs are retained.
+func (c *randaoRootCache) SigningRoot(ctx context.Context, epoch eth2p0.Epoch) ([32]byte, error) {
+	root, entry, err := c.getOrCompute(ctx, epoch)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	c.budget.Track(entry) // Track without holding the lock, since it may evict entries of this cache.
+
+	return root, nil
+}
+
+// getOrCompute returns the cached signing root of the epoch or computes and caches it,
+// returning the new cache entry to track.
+func (c *randaoRootCache) getOrCompute(ctx context.Context, epoch eth2p0.Epoch) ([32]byte, *cacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if root, ok := c.roots[epoch]; ok {
+		c.budget.Touch(c.entries[epoch])
+		return root, nil, nil
+	}
+
+	root, err := c.signingRootFunc(ctx, epoch)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+
+	c.roots[epoch] = root
+
+	for e := range c.roots {
+		if e+1 < epoch {
+			c.budget.Remove(c.entries[e])
+			delete(c.entries, e)
+			delete(c.roots, e)
+		}
+	}
+
+	var entry *cacheEntry
+	entry = c.budget.Entry(cacheRandaoRoots, randaoRootEntrySize, func() { c.evict(epoch, entry) })
+	if entry != nil {
+		c.entries[epoch] = entry
+	}
+
+	return root, entry, nil
+}
+
+// evict removes the signing root of the epoch evicted by the cache budget, unless it was replaced since.
+func (c *randaoRootCache) evict(epoch eth2p0.Epoch, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[epoch] != entry {
+		return
+	}
+
+	delete(c.entries, epoch)
+	delete(c.roots, epoch)
+}
+
+// verifyRandao returns an error if the randao reveal signature doesn't match the cached signing root of its epoch.
+func (c *randaoRootCache) verifyRandao(ctx context.Context, randao core.SignedRandao, pubshare tblsv2.PublicKey) error {
+	var zeroSig eth2p0.BLSSignature
+	if randao.SignedEpoch.Signature == zeroSig {
+		return errors.New("no signature found")
+	}
+
+	root, err := c.SigningRoot(ctx, randao.SignedEpoch.Epoch)
+	if err != nil {
+		return err
+	}
+
+	return tblsv2.Verify(pubshare, root[:], tblsv2.Signature(randao.SignedEpoch.Signature))
+}
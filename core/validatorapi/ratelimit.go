@@ -0,0 +1,50 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/app/expbackoff"
+	"github.com/obolnetwork/charon/app/log"
+	"github.com/obolnetwork/charon/app/z"
+)
+
+// maxRateLimitAttempts is the maximum number of attempts of a beacon node request that is rate limited.
+const maxRateLimitAttempts = 4
+
+// rateLimitBackoff is the backoff between attempts of a rate limited beacon node request.
+var rateLimitBackoff = expbackoff.Config{
+	BaseDelay:  100 * time.Millisecond,
+	Multiplier: 2,
+	Jitter:     0.2,
+	MaxDelay:   time.Second,
+}
+
+// withRateLimitRetry calls the beacon node request function, retrying it with backoff if the beacon node
+// rate limits the request, up to maxRateLimitAttempts. Other errors are returned immediately.
+func withRateLimitRetry[T any](ctx context.Context, endpoint string, fn func() (T, error)) (T, error) {
+	backoff := expbackoff.New(ctx, expbackoff.WithConfig(rateLimitBackoff))
+
+	for attempt := 1; ; attempt++ {
+		resp, err := fn()
+		if err == nil || !eth2wrap.IsRateLimited(err) {
+			return resp, err
+		}
+
+		vapiBeaconRateLimitedTotal.WithLabelValues(endpoint).Inc()
+
+		if attempt >= maxRateLimitAttempts {
+			return resp, err
+		}
+
+		log.Debug(ctx, "Beacon node rate limited request, retrying", z.Str("endpoint", endpoint), z.Int("attempt", attempt))
+		backoff()
+
+		if ctx.Err() != nil {
+			return resp, err
+		}
+	}
+}
@@ -16,8 +16,10 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	eth2client "github.com/attestantio/go-eth2-client"
@@ -35,6 +37,7 @@ import (
 
 	"github.com/obolnetwork/charon/app/errors"
 	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/app/featureset"
 	"github.com/obolnetwork/charon/app/log"
 	"github.com/obolnetwork/charon/app/z"
 	"github.com/obolnetwork/charon/core"
@@ -42,6 +45,10 @@ import (
 	tblsconv2 "github.com/obolnetwork/charon/tbls/v2/tblsconv"
 )
 
+// beaconNodeHeader is the response header annotating the beacon node addresses that served the request,
+// if the featureset.BeaconNodeHeader feature is enabled.
+const beaconNodeHeader = "X-Charon-Beacon-Node"
+
 // Handler defines the request handler providing the business logic
 // for the validator API router.
 type Handler interface {
@@ -49,6 +56,7 @@ type Handler interface {
 	eth2client.AggregateAttestationsSubmitter
 	eth2client.AttestationDataProvider
 	eth2client.AttestationsSubmitter
+	AttestationReceiptsSubmitter
 	eth2client.AttesterDutiesProvider
 	eth2client.BeaconBlockProposalProvider
 	eth2client.BeaconBlockSubmitter
@@ -62,6 +70,7 @@ type Handler interface {
 	eth2client.SyncCommitteeDutiesProvider
 	eth2client.SyncCommitteeMessagesSubmitter
 	eth2exp.SyncCommitteeSelectionAggregator
+	eth2client.ValidatorBalancesProvider
 	eth2client.ValidatorsProvider
 	eth2client.ValidatorRegistrationsSubmitter
 	eth2client.VoluntaryExitSubmitter
@@ -114,6 +123,11 @@ func NewRouter(h Handler, eth2Cl eth2wrap.Client) (*mux.Router, error) {
 			Path:    "/eth/v1/beacon/states/{state_id}/validators/{validator_id}",
 			Handler: getValidator(h),
 		},
+		{
+			Name:    "get_validator_balances",
+			Path:    "/eth/v1/beacon/states/{state_id}/validator_balances",
+			Handler: getValidatorBalances(h),
+		},
 		{
 			Name:    "propose_block",
 			Path:    "/eth/v2/validator/blocks/{slot}",
@@ -221,6 +235,49 @@ func (a apiError) Error() string {
 	return fmt.Sprintf("api error[status=%d,msg=%s]: %v", a.StatusCode, a.Message, a.Err)
 }
 
+var (
+	errMappingsMu sync.Mutex
+	errMappings   []func(error) (int, bool)
+)
+
+// RegisterErrorMapping registers a custom mapping of errors to http status codes used by ErrorToHTTPStatus,
+// allowing operators to map their own wrapped errors. The mapping returns false if it doesn't apply to the error.
+// Custom mappings take precedence over built-in mappings, in the order registered.
+func RegisterErrorMapping(fn func(error) (statusCode int, ok bool)) {
+	errMappingsMu.Lock()
+	defer errMappingsMu.Unlock()
+
+	errMappings = append(errMappings, fn)
+}
+
+// ErrorToHTTPStatus returns the http status code of an error returned by the component.
+// Note that cancelled contexts are not mapped, since only a cancelled request context is a client timeout, see writeError.
+// It applies the registered custom mappings first, then the following built-in mappings:
+//   - api errors map to their status code,
+//   - beacon node rate limiting maps to 503 Service Unavailable,
+//   - all other errors map to 500 Internal Server Error.
+func ErrorToHTTPStatus(err error) int {
+	errMappingsMu.Lock()
+	mappings := errMappings
+	errMappingsMu.Unlock()
+
+	for _, mapping := range mappings {
+		if statusCode, ok := mapping(err); ok {
+			return statusCode
+		}
+	}
+
+	var aerr apiError
+	switch {
+	case errors.As(err, &aerr):
+		return aerr.StatusCode
+	case eth2wrap.IsRateLimited(err):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // handlerFunc is a convenient handler function providing a context, parsed path parameters,
 // the request body, and returning the response struct or an error.
 type handlerFunc func(ctx context.Context, params map[string]string, query url.Values, body []byte) (res interface{}, err error)
@@ -235,6 +292,7 @@ func wrap(endpoint string, handler handlerFunc) http.Handler {
 		ctx = log.WithTopic(ctx, "vapi")
 		ctx = log.WithCtx(ctx, z.Str("vapi_endpoint", endpoint))
 		ctx = withCtxDuration(ctx)
+		ctx = eth2wrap.WithServedBy(ctx)
 
 		// TODO(corver): Add support for octet-stream (SSZ).
 		contentType := r.Header.Get("Content-Type")
@@ -254,17 +312,44 @@ func wrap(endpoint string, handler handlerFunc) http.Handler {
 		}
 
 		res, err := handler(ctx, mux.Vars(r), r.URL.Query(), body)
+
+		// Annotate the logs and optionally the response with the beacon nodes that served the request, if any.
+		if servedBy := eth2wrap.ServedBy(ctx); len(servedBy) > 0 {
+			var hosts []string
+			for _, addr := range servedBy {
+				hosts = append(hosts, redactBeaconNode(addr))
+			}
+			beaconNodes := strings.Join(hosts, ",")
+			ctx = log.WithCtx(ctx, z.Str("beacon_node", beaconNodes))
+			if featureset.Enabled(featureset.BeaconNodeHeader) {
+				w.Header().Set(beaconNodeHeader, beaconNodes)
+			}
+		}
+
 		if err != nil {
 			writeError(ctx, w, endpoint, err)
 			return
 		}
 
+		log.Debug(ctx, "Validator api request served", getCtxDuration(ctx))
+
 		writeResponse(ctx, w, endpoint, res)
 	}
 
 	return wrapTrace(endpoint, wrap)
 }
 
+// redactBeaconNode returns the scheme and host of the beacon node address, stripping any credentials,
+// path and query parameters (e.g. API keys) that must not be exposed to validator clients or logged.
+func redactBeaconNode(addr string) string {
+	u, err := url.Parse(addr)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+
+	return u.Scheme + "://" + u.Host
+}
+
 // wrapTrace wraps the passed handler in a OpenTelemetry tracing span.
 func wrapTrace(endpoint string, handler http.HandlerFunc) http.Handler {
 	return otelhttp.NewHandler(handler, "core/validatorapi."+endpoint)
@@ -308,6 +393,38 @@ func getValidator(p eth2client.ValidatorsProvider) handlerFunc {
 	}
 }
 
+// getValidatorBalances returns a handler function for the get validator balances by index endpoint.
+func getValidatorBalances(p eth2client.ValidatorBalancesProvider) handlerFunc {
+	return func(ctx context.Context, params map[string]string, query url.Values, _ []byte) (interface{}, error) {
+		stateID := params["state_id"]
+
+		var vIdxs []eth2p0.ValidatorIndex
+		for _, id := range getValidatorIDs(query) {
+			vIdx, err := strconv.ParseUint(id, 10, 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "parse validator index")
+			}
+			vIdxs = append(vIdxs, eth2p0.ValidatorIndex(vIdx))
+		}
+
+		balances, err := p.ValidatorBalances(ctx, stateID, vIdxs)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := []validatorBalance{} // Return empty json array instead of null.
+		for vIdx, balance := range balances {
+			resp = append(resp, validatorBalance{Index: vIdx, Balance: balance})
+		}
+
+		sort.Slice(resp, func(i, j int) bool {
+			return resp[i].Index < resp[j].Index
+		})
+
+		return validatorBalancesResponse{Data: resp}, nil
+	}
+}
+
 // attestationData returns a handler function for the attestation data endpoint.
 func attestationData(p eth2client.AttestationDataProvider) handlerFunc {
 	return func(ctx context.Context, params map[string]string, query url.Values, _ []byte) (interface{}, error) {
@@ -335,15 +452,29 @@ func attestationData(p eth2client.AttestationDataProvider) handlerFunc {
 }
 
 // submitAttestations returns a handler function for the attestation submitter endpoint.
-func submitAttestations(p eth2client.AttestationsSubmitter) handlerFunc {
-	return func(ctx context.Context, _ map[string]string, _ url.Values, body []byte) (interface{}, error) {
+// It responds with per-attestation receipts if the "receipts=true" query parameter is provided.
+func submitAttestations(p interface {
+	eth2client.AttestationsSubmitter
+	AttestationReceiptsSubmitter
+},
+) handlerFunc {
+	return func(ctx context.Context, _ map[string]string, query url.Values, body []byte) (interface{}, error) {
 		var atts []*eth2p0.Attestation
 		err := json.Unmarshal(body, &atts)
 		if err != nil {
 			return nil, errors.Wrap(err, "unmarshal attestations")
 		}
 
-		return nil, p.SubmitAttestations(ctx, atts)
+		if query.Get("receipts") != "true" {
+			return nil, p.SubmitAttestations(ctx, atts)
+		}
+
+		receipts, err := p.SubmitAttestationsWithReceipts(ctx, atts)
+		if err != nil {
+			return nil, err
+		}
+
+		return attestationReceiptsResponse{Data: receipts}, nil
 	}
 }
 
@@ -899,14 +1030,19 @@ func writeError(ctx context.Context, w http.ResponseWriter, endpoint string, err
 		}
 	}
 
+	statusCode := ErrorToHTTPStatus(err)
+
 	var aerr apiError
 	if !errors.As(err, &aerr) {
 		aerr = apiError{
-			StatusCode: http.StatusInternalServerError,
-			Message:    "Internal server error",
-			Err:        err,
+			Message: "Internal server error",
+			Err:     err,
+		}
+		if statusCode != http.StatusInternalServerError {
+			aerr.Message = http.StatusText(statusCode)
 		}
 	}
+	aerr.StatusCode = statusCode // Custom mappings may override api error status codes.
 
 	if aerr.StatusCode/100 == 4 {
 		// 4xx status codes are client errors (not server), so log as debug only.
@@ -0,0 +1,89 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/log"
+	"github.com/obolnetwork/charon/app/z"
+	"github.com/obolnetwork/charon/cluster"
+	"github.com/obolnetwork/charon/core"
+	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
+)
+
+// minFaultTolerantNodes is the minimum number of nodes of a cluster that tolerates a faulty node (3f+1 for f=1).
+const minFaultTolerantNodes = 4
+
+// warnSmallCluster logs a warning and sets the small cluster gauge if the cluster size inferred from the number
+// of public shares per distributed validator doesn't tolerate any faulty node, e.g. a 1-of-1 cluster.
+// Such degenerate configurations are usually misconfigurations. It returns true if the warning was logged.
+func warnSmallCluster(ctx context.Context, allPubSharesByKey map[core.PubKey]map[int]tblsv2.PublicKey) bool {
+	var nodes int
+	for _, pubShares := range allPubSharesByKey {
+		if len(pubShares) > nodes {
+			nodes = len(pubShares)
+		}
+	}
+
+	if nodes == 0 || nodes >= minFaultTolerantNodes {
+		vapiSmallCluster.Set(0)
+		return false
+	}
+
+	vapiSmallCluster.Set(1)
+	log.Warn(ctx, "Cluster too small to tolerate any faulty node, check configuration", nil,
+		z.Int("nodes", nodes), z.Int("threshold", cluster.Threshold(nodes)), z.Int("min_nodes", minFaultTolerantNodes))
+
+	return true
+}
+
+// shareAssignmentMsg is the message signed by each key share to verify its assignment.
+var shareAssignmentMsg = []byte("charon share assignment self-check")
+
+// VerifyShareAssignment returns an error if the secret key shares do not include the key share of each distributed
+// validator at this node's share index. A share is held if its signature is verifiable against the public share.
+// This catches misassigned shares, e.g. the Mth key share assigned to the Nth charon peer, before any duty.
+func VerifyShareAssignment(allPubSharesByKey map[core.PubKey]map[int]tblsv2.PublicKey, shareIdx int, secrets []tblsv2.PrivateKey) error {
+	var sigs []tblsv2.Signature
+	for _, secret := range secrets {
+		sig, err := tblsv2.Sign(secret, shareAssignmentMsg)
+		if err != nil {
+			return errors.Wrap(err, "sign share assignment message")
+		}
+		sigs = append(sigs, sig)
+	}
+
+	// holds returns true if any of the secrets' signatures is verifiable against the public share.
+	holds := func(pubShare tblsv2.PublicKey) bool {
+		for _, sig := range sigs {
+			if tblsv2.Verify(pubShare, shareAssignmentMsg, sig) == nil {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for pubkey, pubShares := range allPubSharesByKey {
+		pubShare, ok := pubShares[shareIdx]
+		if !ok {
+			return errors.New("public share not found", pubkeyField("pubkey", pubkey), z.Int("share_index", shareIdx))
+		} else if holds(pubShare) {
+			continue
+		}
+
+		// Identify the misassigned share index for better diagnostics.
+		for idx, other := range pubShares {
+			if idx != shareIdx && holds(other) {
+				return errors.New("mismatching key share index, Mth key share assigned to Nth charon peer",
+					pubkeyField("pubkey", pubkey), z.Int("expected_index", shareIdx), z.Int("actual_index", idx))
+			}
+		}
+
+		return errors.New("key share not found", pubkeyField("pubkey", pubkey), z.Int("share_index", shareIdx))
+	}
+
+	return nil
+}
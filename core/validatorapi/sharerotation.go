@@ -0,0 +1,61 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/obolnetwork/charon/core"
+	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
+)
+
+// SetShareRotation configures a key rotation, e.g. via a DKG ceremony, where the public shares of this node change
+// at the rotation epoch. Partial signatures of duties before the rotation epoch are verified against the current
+// public shares and those of later duties against the rotated public shares by DV root public key. Likewise, duties
+// and validators returned to validator clients contain the public shares valid at their epoch. Distributed
+// validators without a rotated public share are not rotated. Submissions by validator clients holding the rotated
+// key shares are also accepted, enabling live key rotation without downtime. Rotations can be chained by calling
+// this again with a later rotation epoch. It must be called before the component is used.
+func (c *Component) SetShareRotation(rotationEpoch eth2p0.Epoch, pubsharesByKey map[core.PubKey]tblsv2.PublicKey) error {
+	keysByShare := make(map[eth2p0.BLSPubKey]eth2p0.BLSPubKey)
+	sharesByKey := make(map[eth2p0.BLSPubKey]eth2p0.BLSPubKey)
+	for pubkey, pubshare := range pubsharesByKey {
+		eth2Pubkey, err := pubkey.ToETH2()
+		if err != nil {
+			return err
+		}
+		keysByShare[eth2p0.BLSPubKey(pubshare)] = eth2Pubkey
+		sharesByKey[eth2Pubkey] = eth2p0.BLSPubKey(pubshare)
+	}
+
+	prevVerifyShare := c.getVerifyShareFunc
+	c.getVerifyShareFunc = func(pubkey core.PubKey, epoch eth2p0.Epoch) (tblsv2.PublicKey, error) {
+		pubshare, ok := pubsharesByKey[pubkey]
+		if !ok || epoch < rotationEpoch {
+			return prevVerifyShare(pubkey, epoch)
+		}
+
+		return pubshare, nil
+	}
+
+	prevPubKey, prevPubShare := c.getPubKeyFunc, c.getPubShareFunc
+	c.getPubShareFunc = func(pubkey eth2p0.BLSPubKey, epoch eth2p0.Epoch) (eth2p0.BLSPubKey, bool) {
+		share, prevOK := prevPubShare(pubkey, epoch) // Also marks the root public key as seen.
+		if rotated, ok := sharesByKey[pubkey]; ok && epoch >= rotationEpoch {
+			return rotated, true
+		}
+
+		return share, prevOK
+	}
+
+	c.getPubKeyFunc = func(share eth2p0.BLSPubKey) (eth2p0.BLSPubKey, error) {
+		if key, ok := keysByShare[share]; ok {
+			_, _ = prevPubShare(key, rotationEpoch) // Marks the root public key as seen.
+			return key, nil
+		}
+
+		return prevPubKey(share)
+	}
+
+	return nil
+}
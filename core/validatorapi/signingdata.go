@@ -0,0 +1,354 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/core"
+	"github.com/obolnetwork/charon/eth2util/signing"
+	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
+)
+
+// domainKey identifies the signing domain of a domain name at an epoch.
+type domainKey struct {
+	Name  signing.DomainName
+	Epoch eth2p0.Epoch
+}
+
+// signingDataKey identifies the domain-wrapped signing data of a message root.
+type signingDataKey struct {
+	Domain domainKey
+	Root   eth2p0.Root
+}
+
+// newSigningDataCache returns a new signing data cache of a batch of submitted signatures
+// using the component's domain and verified signature caches.
+func newSigningDataCache(eth2Cl eth2wrap.Client, domains *domainCache, verified *verifiedCache) *signingDataCache {
+	return &signingDataCache{
+		eth2Cl:   eth2Cl,
+		domains:  domains,
+		verified: verified,
+		roots:    make(map[signingDataKey][32]byte),
+	}
+}
+
+// signingDataCache memoizes the domain-wrapped signing data per message root, since the signatures
+// of a batch sharing these sign identical data. It is safe for concurrent use by asynchronous verification.
+type signingDataCache struct {
+	eth2Cl   eth2wrap.Client
+	domains  *domainCache
+	verified *verifiedCache
+
+	mu    sync.Mutex
+	roots map[signingDataKey][32]byte
+}
+
+// SigningData returns the hash tree root of the message root wrapped with the domain of the domain name at the epoch.
+func (c *signingDataCache) SigningData(ctx context.Context, name signing.DomainName, epoch eth2p0.Epoch, root eth2p0.Root) ([32]byte, error) {
+	dKey := domainKey{Name: name, Epoch: epoch}
+	key := signingDataKey{Domain: dKey, Root: root}
+
+	c.mu.Lock()
+	data, ok := c.roots[key]
+	c.mu.Unlock()
+
+	if ok {
+		return data, nil
+	}
+
+	domain, err := c.domains.Get(ctx, c.eth2Cl, dKey)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	data, err = (&eth2p0.SigningData{ObjectRoot: root, Domain: domain}).HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "marshal signing data")
+	}
+
+	c.mu.Lock()
+	c.roots[key] = data
+	c.mu.Unlock()
+
+	return data, nil
+}
+
+// Verify returns an error if the signature of the eth2 signed data doesn't match its cached signing data.
+// Signatures verified before aren't verified again.
+func (c *signingDataCache) Verify(ctx context.Context, data core.Eth2SignedData, pubshare tblsv2.PublicKey) error {
+	msg, err := c.message(ctx, data, pubshare)
+	if err != nil {
+		return err
+	}
+
+	if c.verified.Contains(msg.Epoch, msg.Key) {
+		return nil
+	}
+
+	if err := tblsv2.Verify(pubshare, msg.Data[:], msg.Sig); err != nil {
+		return sigMismatchError{Err: err}
+	}
+
+	c.verified.Add(msg.Epoch, msg.Key)
+
+	return nil
+}
+
+// VerifyBatch returns an error if any signature of the eth2 signed data doesn't match its cached signing data
+// and the public share of the same index. The batch is verified at once, so the error doesn't identify the
+// invalid signatures, see Verify. Signatures verified before aren't verified again.
+func (c *signingDataCache) VerifyBatch(ctx context.Context, datas []core.Eth2SignedData, pubshares []tblsv2.PublicKey) error {
+	var (
+		unverified []signedMessage
+		keys       []tblsv2.PublicKey
+		msgs       [][]byte
+		sigs       []tblsv2.Signature
+	)
+	for i, data := range datas {
+		msg, err := c.message(ctx, data, pubshares[i])
+		if err != nil {
+			return err
+		}
+
+		if c.verified.Contains(msg.Epoch, msg.Key) {
+			continue
+		}
+
+		unverified = append(unverified, msg)
+		keys = append(keys, pubshares[i])
+		msgs = append(msgs, msg.Data[:])
+		sigs = append(sigs, msg.Sig)
+	}
+
+	if err := tblsv2.BatchVerify(keys, msgs, sigs); err != nil {
+		return sigMismatchError{Err: err}
+	}
+
+	for _, msg := range unverified {
+		c.verified.Add(msg.Epoch, msg.Key)
+	}
+
+	return nil
+}
+
+// signedMessage is the cached signing data and the signature of eth2 signed data.
+type signedMessage struct {
+	Epoch eth2p0.Epoch
+	Data  [32]byte
+	Sig   tblsv2.Signature
+	// Key identifies the signature of the signing data by the public share in the verified signature cache.
+	Key [32]byte
+}
+
+// message returns the cached signing data and the signature of the eth2 signed data signed by the public share.
+func (c *signingDataCache) message(ctx context.Context, data core.Eth2SignedData, pubshare tblsv2.PublicKey) (signedMessage, error) {
+	var zeroSig eth2p0.BLSSignature
+	sig := data.Signature().ToETH2()
+	if sig == zeroSig {
+		return signedMessage{}, sigMismatchError{Err: errors.New("no signature found")}
+	}
+
+	epoch, err := data.Epoch(ctx, c.eth2Cl)
+	if err != nil {
+		return signedMessage{}, err
+	}
+
+	root, err := data.MessageRoot()
+	if err != nil {
+		return signedMessage{}, err
+	}
+
+	msg, err := c.SigningData(ctx, data.DomainName(), epoch, root)
+	if err != nil {
+		return signedMessage{}, err
+	}
+
+	h := sha256.New()
+	_, _ = h.Write(pubshare[:])
+	_, _ = h.Write(msg[:])
+	_, _ = h.Write(sig[:])
+
+	var key [32]byte
+	copy(key[:], h.Sum(nil))
+
+	return signedMessage{Epoch: epoch, Data: msg, Sig: tblsv2.Signature(sig), Key: key}, nil
+}
+
+// domainEntrySize is the approximate size in bytes of a cached signing domain.
+const domainEntrySize = 16 + 8 + 32
+
+// newDomainCache returns a new empty signing domain cache.
+func newDomainCache() *domainCache {
+	return &domainCache{
+		domains: make(map[domainKey]eth2p0.Domain),
+		entries: make(map[domainKey]*cacheEntry),
+	}
+}
+
+// domainCache caches signing domains per domain name and epoch across submissions.
+// Only the domains of the latest two epochs per domain name are retained.
+type domainCache struct {
+	// budget bounds the memory of the cache together with other caches, it is nil if unbounded.
+	budget *cacheBudget
+
+	mu      sync.Mutex
+	domains map[domainKey]eth2p0.Domain
+	entries map[domainKey]*cacheEntry
+}
+
+// Get returns the cached signing domain or fetches and caches it. The lock isn't held while fetching,
+// so concurrent verifications may fetch the same domain.
+func (c *domainCache) Get(ctx context.Context, eth2Cl eth2wrap.Client, key domainKey) (eth2p0.Domain, error) {
+	c.mu.Lock()
+	domain, ok := c.domains[key]
+	c.budget.Touch(c.entries[key])
+	c.mu.Unlock()
+
+	if ok {
+		return domain, nil
+	}
+
+	domain, err := signing.GetDomain(ctx, eth2Cl, key.Name, key.Epoch)
+	if err != nil {
+		return eth2p0.Domain{}, err
+	}
+
+	c.budget.Track(c.set(key, domain)) // Track without holding the lock, since it may evict entries of this cache.
+
+	return domain, nil
+}
+
+// set caches the domain, pruning domains of the name older than the previous epoch,
+// and returns the new cache entry to track.
+func (c *domainCache) set(key domainKey, domain eth2p0.Domain) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.domains[key]; ok {
+		return nil // Fetched concurrently.
+	}
+
+	c.domains[key] = domain
+
+	for k := range c.domains {
+		if k.Name == key.Name && k.Epoch+1 < key.Epoch {
+			c.budget.Remove(c.entries[k])
+			delete(c.entries, k)
+			delete(c.domains, k)
+		}
+	}
+
+	var entry *cacheEntry
+	entry = c.budget.Entry(cacheDomains, domainEntrySize, func() { c.evict(key, entry) })
+	if entry != nil {
+		c.entries[key] = entry
+	}
+
+	return entry
+}
+
+// evict removes the domain evicted by the cache budget, unless it was replaced since.
+func (c *domainCache) evict(key domainKey, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[key] != entry {
+		return
+	}
+
+	delete(c.entries, key)
+	delete(c.domains, key)
+}
+
+// verifiedEntrySize is the approximate size in bytes of a cached verified signature.
+const verifiedEntrySize = 8 + 32
+
+// newVerifiedCache returns a new empty verified signature cache.
+func newVerifiedCache() *verifiedCache {
+	return &verifiedCache{
+		epochs: make(map[eth2p0.Epoch]map[[32]byte]*cacheEntry),
+	}
+}
+
+// verifiedCache deduplicates the verification of identical signatures, like the same attestation
+// submitted by redundant validator clients or resubmitted with other attestations, by caching
+// the verified signatures of the latest two epochs.
+type verifiedCache struct {
+	// budget bounds the memory of the cache together with other caches, it is nil if unbounded.
+	budget *cacheBudget
+
+	mu     sync.Mutex
+	epochs map[eth2p0.Epoch]map[[32]byte]*cacheEntry
+}
+
+// Contains returns true if the signature identified by the key was verified. A nil cache contains nothing.
+func (c *verifiedCache) Contains(epoch eth2p0.Epoch, key [32]byte) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.epochs[epoch][key]
+	c.budget.Touch(entry)
+
+	return ok
+}
+
+// Add caches the signature identified by the key as verified, pruning signatures older than the previous epoch.
+func (c *verifiedCache) Add(epoch eth2p0.Epoch, key [32]byte) {
+	if c == nil {
+		return
+	}
+
+	c.budget.Track(c.add(epoch, key)) // Track without holding the lock, since it may evict entries of this cache.
+}
+
+// add caches the verified signature and returns the new cache entry to track.
+func (c *verifiedCache) add(epoch eth2p0.Epoch, key [32]byte) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.epochs[epoch][key]; ok {
+		return nil
+	}
+
+	for e, entries := range c.epochs {
+		if e+1 < epoch {
+			for _, entry := range entries {
+				c.budget.Remove(entry)
+			}
+			delete(c.epochs, e)
+		}
+	}
+
+	if _, ok := c.epochs[epoch]; !ok {
+		c.epochs[epoch] = make(map[[32]byte]*cacheEntry)
+	}
+
+	var entry *cacheEntry
+	entry = c.budget.Entry(cacheVerified, verifiedEntrySize, func() { c.evict(epoch, key, entry) })
+	c.epochs[epoch][key] = entry
+
+	return entry
+}
+
+// evict removes the verified signature evicted by the cache budget, unless it was replaced since.
+func (c *verifiedCache) evict(epoch eth2p0.Epoch, key [32]byte, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if current, ok := c.epochs[epoch][key]; !ok || current != entry {
+		return
+	}
+
+	delete(c.epochs[epoch], key)
+}
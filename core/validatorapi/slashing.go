@@ -0,0 +1,184 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"sync"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/z"
+	"github.com/obolnetwork/charon/core"
+)
+
+// SlashingProtector checks submitted attestations against a local anti-slashing record.
+type SlashingProtector interface {
+	// CheckAttestation returns an error if the attestation data is slashable for the DV root public key
+	// given previously recorded attestations. It doesn't record the attestation data.
+	CheckAttestation(ctx context.Context, pubkey core.PubKey, data *eth2p0.AttestationData) error
+
+	// RecordAttestation records the attestation data for the DV root public key. It returns an error
+	// without recording if the attestation data is slashable, see CheckAttestation.
+	RecordAttestation(ctx context.Context, pubkey core.PubKey, data *eth2p0.AttestationData) error
+}
+
+// NewMemSlashingProtector returns a new in-memory slashing protector.
+func NewMemSlashingProtector() *MemSlashingProtector {
+	return &MemSlashingProtector{
+		records:    make(map[core.PubKey][]attRecord),
+		watermarks: make(map[core.PubKey]attWatermark),
+	}
+}
+
+// attRecord is a previously checked attestation.
+type attRecord struct {
+	Source eth2p0.Epoch
+	Target eth2p0.Epoch
+	Root   eth2p0.Root
+}
+
+// maxAttRecords bounds the number of attestation records retained per public key.
+const maxAttRecords = 1024
+
+// MemSlashingProtector is an in-memory SlashingProtector rejecting double and surround votes.
+// Trimmed records are replaced by per public key source and target epoch low watermarks, rejecting
+// any attestations that could conflict with trimmed records.
+type MemSlashingProtector struct {
+	mu         sync.Mutex
+	records    map[core.PubKey][]attRecord
+	watermarks map[core.PubKey]attWatermark
+}
+
+// attWatermark is the highest source and target epochs of trimmed attestation records.
+type attWatermark struct {
+	Source eth2p0.Epoch
+	Target eth2p0.Epoch
+}
+
+// Trim evicts the attestation records with target epochs before the epoch.
+func (p *MemSlashingProtector) Trim(epoch eth2p0.Epoch) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for pubkey, records := range p.records {
+		var retained []attRecord
+		for _, record := range records {
+			if record.Target >= epoch {
+				retained = append(retained, record)
+			} else {
+				p.raiseWatermark(pubkey, record)
+			}
+		}
+
+		if len(retained) == 0 {
+			delete(p.records, pubkey)
+		} else {
+			p.records[pubkey] = retained
+		}
+	}
+}
+
+// raiseWatermark raises the low watermarks of the public key to include the trimmed record.
+// It must be called with the mutex held.
+func (p *MemSlashingProtector) raiseWatermark(pubkey core.PubKey, record attRecord) {
+	watermark := p.watermarks[pubkey]
+	if record.Source > watermark.Source {
+		watermark.Source = record.Source
+	}
+	if record.Target > watermark.Target {
+		watermark.Target = record.Target
+	}
+	p.watermarks[pubkey] = watermark
+}
+
+// CheckAttestation implements SlashingProtector, see its godoc.
+func (p *MemSlashingProtector) CheckAttestation(_ context.Context, pubkey core.PubKey, data *eth2p0.AttestationData) error {
+	record, err := newAttRecord(data)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.check(pubkey, record)
+
+	return err
+}
+
+// RecordAttestation implements SlashingProtector, see its godoc.
+func (p *MemSlashingProtector) RecordAttestation(_ context.Context, pubkey core.PubKey, data *eth2p0.AttestationData) error {
+	record, err := newAttRecord(data)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if identical, err := p.check(pubkey, record); err != nil {
+		return err
+	} else if identical {
+		return nil // Identical attestations are already recorded.
+	}
+
+	p.records[pubkey] = append(p.records[pubkey], record)
+
+	// Evict the oldest record if too many are retained.
+	if records := p.records[pubkey]; len(records) > maxAttRecords {
+		oldest := 0
+		for i, r := range records {
+			if r.Target < records[oldest].Target {
+				oldest = i
+			}
+		}
+		p.raiseWatermark(pubkey, records[oldest])
+		p.records[pubkey] = append(records[:oldest], records[oldest+1:]...)
+	}
+
+	return nil
+}
+
+// newAttRecord returns the attestation record of the attestation data.
+func newAttRecord(data *eth2p0.AttestationData) (attRecord, error) {
+	root, err := data.HashTreeRoot()
+	if err != nil {
+		return attRecord{}, errors.Wrap(err, "hash attestation data")
+	}
+
+	return attRecord{
+		Source: data.Source.Epoch,
+		Target: data.Target.Epoch,
+		Root:   root,
+	}, nil
+}
+
+// check returns an error if the record is slashable given the previous records of the public key,
+// or true if an identical record exists. It must be called with the mutex held.
+func (p *MemSlashingProtector) check(pubkey core.PubKey, record attRecord) (bool, error) {
+	// Attestations not strictly after the trimmed records could be slashable.
+	if watermark, ok := p.watermarks[pubkey]; ok && (record.Target <= watermark.Target || record.Source < watermark.Source) {
+		return false, errors.New("attestation conflicts with trimmed slashing history", pubkeyField("pubkey", pubkey),
+			z.U64("source_epoch", uint64(record.Source)), z.U64("target_epoch", uint64(record.Target)))
+	}
+
+	for _, prev := range p.records[pubkey] {
+		if prev.Target == record.Target {
+			if prev.Root == record.Root {
+				return true, nil // Identical attestations are not slashable.
+			}
+
+			return false, errors.New("slashable double vote", pubkeyField("pubkey", pubkey), z.U64("target_epoch", uint64(record.Target)))
+		}
+
+		if (prev.Source < record.Source && record.Target < prev.Target) ||
+			(record.Source < prev.Source && prev.Target < record.Target) {
+			return false, errors.New("slashable surround vote", pubkeyField("pubkey", pubkey),
+				z.U64("source_epoch", uint64(record.Source)), z.U64("target_epoch", uint64(record.Target)))
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,70 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/z"
+)
+
+// newSubmitLimiter returns a limiter of the number of submissions processed concurrently.
+// Excess submissions queue for at most the queue timeout, or until their context is closed if zero.
+func newSubmitLimiter(limit int, queueTimeout time.Duration) *submitLimiter {
+	return &submitLimiter{
+		sem:          make(chan struct{}, limit),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// submitLimiter is a semaphore limiting concurrent submission processing, providing back-pressure
+// to validator clients instead of overwhelming partial signature verification.
+type submitLimiter struct {
+	sem          chan struct{}
+	queueTimeout time.Duration
+}
+
+// Acquire blocks until the submission may be processed and returns a function that must be called when done.
+// It returns a retryable service unavailable error if the submission was queued for longer than the queue timeout.
+// A nil limiter doesn't limit submissions.
+func (l *submitLimiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	release := func() { <-l.sem }
+
+	select {
+	case l.sem <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	vapiSubmitQueuedTotal.Inc()
+
+	var timeout <-chan time.Time
+	if l.queueTimeout > 0 {
+		timer := time.NewTimer(l.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case l.sem <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timeout:
+		vapiSubmitQueueTimeoutTotal.Inc()
+
+		return nil, apiError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "too many concurrent submissions, please retry",
+			Err: errors.New("submission queue timeout",
+				z.Int("limit", cap(l.sem)), z.Any("queue_timeout", l.queueTimeout)),
+		}
+	}
+}
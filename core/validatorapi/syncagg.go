@@ -0,0 +1,67 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/prysmaticlabs/go-bitfield"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/z"
+	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
+	"github.com/obolnetwork/charon/tbls/v2/tblsconv"
+)
+
+// AggregateSyncContributions returns the sync aggregate combining the provided per-subcommittee
+// sync committee contributions. The contributions' aggregation bits are merged into the full sync committee
+// bitfield at their subcommittee offsets and their signatures are aggregated. It returns an error if any
+// participation bits overlap or if the contributions are not for the same slot and beacon block root.
+func AggregateSyncContributions(contribs []*altair.SyncCommitteeContribution) (*altair.SyncAggregate, error) {
+	if len(contribs) == 0 {
+		return nil, errors.New("no sync committee contributions")
+	}
+
+	var (
+		syncBits = bitfield.NewBitvector512()
+		subSize  = bitfield.NewBitvector128().Len()
+		sigs     []tblsv2.Signature
+	)
+	for _, contrib := range contribs {
+		if contrib.Slot != contribs[0].Slot || contrib.BeaconBlockRoot != contribs[0].BeaconBlockRoot {
+			return nil, errors.New("mismatching sync committee contribution slot or beacon block root",
+				z.U64("slot", uint64(contrib.Slot)))
+		}
+
+		offset := contrib.SubcommitteeIndex * subSize
+		if offset+subSize > syncBits.Len() {
+			return nil, errors.New("sync committee contribution subcommittee index out of range",
+				z.U64("subcommittee_index", contrib.SubcommitteeIndex))
+		}
+
+		for _, idx := range contrib.AggregationBits.BitIndices() {
+			bit := offset + uint64(idx)
+			if syncBits.BitAt(bit) {
+				return nil, errors.New("overlapping sync committee participation bits",
+					z.U64("subcommittee_index", contrib.SubcommitteeIndex), z.U64("bit", bit))
+			}
+			syncBits.SetBitAt(bit, true)
+		}
+
+		sig, err := tblsconv.SignatureFromBytes(contrib.Signature[:])
+		if err != nil {
+			return nil, err
+		}
+
+		sigs = append(sigs, sig)
+	}
+
+	aggSig, err := tblsv2.Aggregate(sigs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &altair.SyncAggregate{
+		SyncCommitteeBits:      syncBits,
+		SyncCommitteeSignature: tblsconv.SigToETH2(aggSig),
+	}, nil
+}
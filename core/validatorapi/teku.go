@@ -5,7 +5,6 @@ package validatorapi
 import (
 	"context"
 	"fmt"
-	"time"
 )
 
 type TekuProposerConfigResponse struct {
@@ -50,7 +49,7 @@ func (c Component) TekuProposerConfig(ctx context.Context) (TekuProposerConfigRe
 		return TekuProposerConfigResponse{}, err
 	}
 
-	slot, err := c.slotFromTimestamp(ctx, time.Now())
+	slot, err := c.currentSlot(ctx)
 	if err != nil {
 		return TekuProposerConfigResponse{}, err
 	}
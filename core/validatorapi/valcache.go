@@ -0,0 +1,151 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	eth2v1 "github.com/attestantio/go-eth2-client/api/v1"
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+const (
+	// stableValidatorsTTL is the duration validators of stable states (finalized, genesis or block roots) are cached.
+	// The finalized state only advances once per epoch, so this is at most an epoch stale.
+	stableValidatorsTTL = 6 * time.Minute
+	// headValidatorsTTL is the duration validators of other states (e.g. head) are cached,
+	// long enough to deduplicate bursts of identical validator client queries.
+	headValidatorsTTL = 2 * time.Second
+)
+
+// validatorsKey identifies a cached validators query by state and queried index or public share set.
+type validatorsKey struct {
+	StateID string
+	Query   string
+}
+
+// indicesQuery returns the cache query of the validator indices, independent of their order.
+func indicesQuery(indices []eth2p0.ValidatorIndex) string {
+	strs := make([]string, 0, len(indices))
+	for _, vIdx := range indices {
+		strs = append(strs, strconv.FormatUint(uint64(vIdx), 10))
+	}
+	sort.Strings(strs)
+
+	return "indices:" + strings.Join(strs, ",")
+}
+
+// pubsharesQuery returns the cache query of the public shares, independent of their order.
+func pubsharesQuery(pubshares []eth2p0.BLSPubKey) string {
+	strs := make([]string, 0, len(pubshares))
+	for _, pubshare := range pubshares {
+		strs = append(strs, hex.EncodeToString(pubshare[:]))
+	}
+	sort.Strings(strs)
+
+	return "pubshares:" + strings.Join(strs, ",")
+}
+
+// validatorsTTL returns the duration validators of the state are cached.
+func validatorsTTL(stateID string) time.Duration {
+	if stateID == "finalized" || stateID == "genesis" || strings.HasPrefix(stateID, "0x") {
+		return stableValidatorsTTL
+	}
+
+	return headValidatorsTTL
+}
+
+// newValidatorsCache returns a new validators cache using the time source.
+func newValidatorsCache(nowFunc func() time.Time) *validatorsCache {
+	return &validatorsCache{
+		nowFunc: nowFunc,
+		entries: make(map[validatorsKey]*validatorsEntry),
+	}
+}
+
+// validatorsCache caches validators query responses, with public keys already rewritten to public shares,
+// by state and queried set. Stable states are cached aggressively and other states briefly, see validatorsTTL.
+type validatorsCache struct {
+	nowFunc func() time.Time
+	// budget bounds the memory of the cache together with other caches, it is nil if unbounded.
+	budget *cacheBudget
+
+	mu      sync.Mutex
+	entries map[validatorsKey]*validatorsEntry
+}
+
+// validatorsEntry is a cached validators query response.
+type validatorsEntry struct {
+	vals   map[eth2p0.ValidatorIndex]*eth2v1.Validator
+	expiry time.Time
+	entry  *cacheEntry
+}
+
+// validatorEntrySize is the approximate size in bytes of a cached validator.
+const validatorEntrySize = 8 + 8 + 8 + 48 + 32 + 8 + 1 + 4*8
+
+// Get returns a copy of the cached unexpired validators of the key and true or false if not cached.
+func (c *validatorsCache) Get(key validatorsKey) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.nowFunc().Before(e.expiry) {
+		return nil, false
+	}
+
+	c.budget.Touch(e.entry)
+
+	resp := make(map[eth2p0.ValidatorIndex]*eth2v1.Validator, len(e.vals))
+	for vIdx, val := range e.vals {
+		resp[vIdx] = val
+	}
+
+	return resp, true
+}
+
+// Set caches a copy of the validators of the key, also pruning expired entries.
+func (c *validatorsCache) Set(key validatorsKey, vals map[eth2p0.ValidatorIndex]*eth2v1.Validator) {
+	cached := make(map[eth2p0.ValidatorIndex]*eth2v1.Validator, len(vals))
+	for vIdx, val := range vals {
+		cached[vIdx] = val
+	}
+
+	c.mu.Lock()
+	now := c.nowFunc()
+	for k, e := range c.entries {
+		if !now.Before(e.expiry) {
+			c.budget.Remove(e.entry)
+			delete(c.entries, k)
+		}
+	}
+
+	if prev, ok := c.entries[key]; ok {
+		c.budget.Remove(prev.entry)
+	}
+
+	e := &validatorsEntry{vals: cached, expiry: now.Add(validatorsTTL(key.StateID))}
+	e.entry = c.budget.Entry(cacheValidators, int64(len(key.Query))+int64(len(cached))*validatorEntrySize,
+		func() { c.evict(key, e) })
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	c.budget.Track(e.entry) // Track without holding the lock, since it may evict entries of this cache.
+}
+
+// evict removes the validators of the key evicted by the cache budget, unless they were replaced since.
+func (c *validatorsCache) evict(key validatorsKey, e *validatorsEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[key] != e {
+		return
+	}
+
+	delete(c.entries, key)
+}
@@ -5,7 +5,11 @@ package validatorapi
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -14,6 +18,7 @@ import (
 	eth2spec "github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/altair"
 	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/jonboulle/clockwork"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/obolnetwork/charon/app/errors"
@@ -29,6 +34,17 @@ import (
 	tblsconv2 "github.com/obolnetwork/charon/tbls/v2/tblsconv"
 )
 
+const (
+	// maxCommitteesPerSlot is the MAX_COMMITTEES_PER_SLOT mainnet preset value, the default upper bound of committee indices.
+	maxCommitteesPerSlot = 64
+	// maxValidatorsPerCommittee is the MAX_VALIDATORS_PER_COMMITTEE mainnet preset value, the default upper bound of validator committee indices.
+	maxValidatorsPerCommittee = 2048
+	// randaoProbeTimeout bounds the aggSigDB query checking whether a proposal slot's randao reveal is ready.
+	randaoProbeTimeout = 10 * time.Millisecond
+	// syncContributionProbeTimeout bounds the dutyDB query checking whether a sync committee contribution is ready.
+	syncContributionProbeTimeout = 10 * time.Millisecond
+)
+
 // NewComponentInsecure returns a new instance of the validator API core workflow component
 // that does not perform signature verification.
 func NewComponentInsecure(_ *testing.T, eth2Cl eth2wrap.Client, shareIdx int) (*Component, error) {
@@ -37,12 +53,34 @@ func NewComponentInsecure(_ *testing.T, eth2Cl eth2wrap.Client, shareIdx int) (*
 		shareIdx:       shareIdx,
 		builderEnabled: func(int64) bool { return false },
 		insecureTest:   true,
+		presets:        newPresetCache(eth2Cl),
+		domains:        newDomainCache(),
+		slotGauges:     newSlotGauges(),
+		quarantine:     newQuarantine(),
+		attConsistency: newAttConsistency(),
+		clock:          clockwork.NewRealClock(),
+		maintenance:    new(atomic.Bool),
+		bg:             newBackground(),
 	}, nil
 }
 
 // NewComponent returns a new instance of the validator API core workflow component.
+// It is a wrapper of New for backwards compatibility.
 func NewComponent(eth2Cl eth2wrap.Client, allPubSharesByKey map[core.PubKey]map[int]tblsv2.PublicKey,
 	shareIdx int, feeRecipientFunc func(core.PubKey) string, builderEnabled core.BuilderEnabled, seenPubkeys func(core.PubKey),
+) (*Component, error) {
+	return New(eth2Cl, allPubSharesByKey, shareIdx,
+		WithFeeRecipientFunc(feeRecipientFunc),
+		WithBuilderEnabled(builderEnabled),
+		WithSeenPubkeys(seenPubkeys),
+	)
+}
+
+// NewComponentWithShareIndices returns a new instance of the validator API core workflow component
+// for a node that may hold a different share index per distributed validator (multi-operator host).
+// The shareIdxByKey maps each DV root public key to this node's share index.
+func NewComponentWithShareIndices(eth2Cl eth2wrap.Client, allPubSharesByKey map[core.PubKey]map[int]tblsv2.PublicKey,
+	shareIdxByKey map[core.PubKey]int, feeRecipientFunc func(core.PubKey) string, builderEnabled core.BuilderEnabled, seenPubkeys func(core.PubKey),
 ) (*Component, error) {
 	var (
 		sharesByKey     = make(map[eth2p0.BLSPubKey]eth2p0.BLSPubKey)
@@ -51,6 +89,11 @@ func NewComponent(eth2Cl eth2wrap.Client, allPubSharesByKey map[core.PubKey]map[
 		coreSharesByKey = make(map[core.PubKey]core.PubKey)
 	)
 	for corePubkey, shares := range allPubSharesByKey {
+		shareIdx, ok := shareIdxByKey[corePubkey]
+		if !ok {
+			return nil, errors.New("missing share index for public key", pubkeyField("pubkey", corePubkey))
+		}
+
 		pubshare := shares[shareIdx]
 		coreShare, err := core.PubKeyFromBytes(pubshare[:])
 		if err != nil {
@@ -74,7 +117,7 @@ func NewComponent(eth2Cl eth2wrap.Client, allPubSharesByKey map[core.PubKey]map[
 		keysByShare[eth2Share] = eth2Pubkey
 	}
 
-	getVerifyShareFunc := func(pubkey core.PubKey) (tblsv2.PublicKey, error) {
+	getVerifyShareFunc := func(pubkey core.PubKey, _ eth2p0.Epoch) (tblsv2.PublicKey, error) {
 		pubshare, ok := sharesByCoreKey[pubkey]
 		if !ok {
 			return tblsv2.PublicKey{}, errors.New("unknown public key")
@@ -83,7 +126,7 @@ func NewComponent(eth2Cl eth2wrap.Client, allPubSharesByKey map[core.PubKey]map[
 		return pubshare, nil
 	}
 
-	getPubShareFunc := func(pubkey eth2p0.BLSPubKey) (eth2p0.BLSPubKey, bool) {
+	getPubShareFunc := func(pubkey eth2p0.BLSPubKey, _ eth2p0.Epoch) (eth2p0.BLSPubKey, bool) {
 		share, ok := sharesByKey[pubkey]
 
 		if seenPubkeys != nil {
@@ -96,16 +139,16 @@ func NewComponent(eth2Cl eth2wrap.Client, allPubSharesByKey map[core.PubKey]map[
 	getPubKeyFunc := func(share eth2p0.BLSPubKey) (eth2p0.BLSPubKey, error) {
 		key, ok := keysByShare[share]
 		if !ok {
-			for _, shares := range allPubSharesByKey {
+			for corePubkey, shares := range allPubSharesByKey {
 				for keyshareIdx, pubshare := range shares {
 					if eth2p0.BLSPubKey(pubshare) == share {
 						return eth2p0.BLSPubKey{}, errors.New("mismatching validator client key share index, Mth key share submitted to Nth charon peer",
-							z.Int("key_share_index", keyshareIdx-1), z.Int("charon_peer_index", shareIdx-1)) // 0-indexed
+							z.Int("key_share_index", keyshareIdx-1), z.Int("charon_peer_index", shareIdxByKey[corePubkey]-1)) // 0-indexed
 					}
 				}
 			}
 
-			return eth2p0.BLSPubKey{}, errors.New("unknown public key")
+			return eth2p0.BLSPubKey{}, errors.New("unknown public key", eth2PubkeyField("pubshare", share))
 		}
 
 		if seenPubkeys != nil {
@@ -115,16 +158,32 @@ func NewComponent(eth2Cl eth2wrap.Client, allPubSharesByKey map[core.PubKey]map[
 		return key, nil
 	}
 
-	return &Component{
+	c := &Component{
 		getVerifyShareFunc: getVerifyShareFunc,
 		getPubShareFunc:    getPubShareFunc,
 		getPubKeyFunc:      getPubKeyFunc,
 		sharesByKey:        coreSharesByKey,
+		shareIdxByKey:      shareIdxByKey,
 		eth2Cl:             eth2Cl,
-		shareIdx:           shareIdx,
 		feeRecipientFunc:   feeRecipientFunc,
 		builderEnabled:     builderEnabled,
-	}, nil
+		randaoRoots:        newEth2RandaoRootCache(eth2Cl),
+		domains:            newDomainCache(),
+		verified:           newVerifiedCache(),
+		presets:            newPresetCache(eth2Cl),
+		slotGauges:         newSlotGauges(),
+		quarantine:         newQuarantine(),
+		attConsistency:     newAttConsistency(),
+		clock:              clockwork.NewRealClock(),
+		maintenance:        new(atomic.Bool),
+		bg:                 newBackground(),
+	}
+	c.SetVerificationWorkers(VerifyClassProposal, defaultVerifyWorkers)
+	c.SetVerificationWorkers(VerifyClassOther, defaultVerifyWorkers)
+	c.valIndices = newEth2ValIndexCache(c)
+	c.validators = newValidatorsCache(func() time.Time { return c.clock.Now() })
+
+	return c, nil
 }
 
 type Component struct {
@@ -134,15 +193,48 @@ type Component struct {
 	feeRecipientFunc func(core.PubKey) string
 	builderEnabled   core.BuilderEnabled
 
-	// getVerifyShareFunc maps public shares (what the VC thinks as its public key)
-	// to public keys (the DV root public key)
-	getVerifyShareFunc func(core.PubKey) (tblsv2.PublicKey, error)
-	// getPubShareFunc returns the public share for a root public key.
-	getPubShareFunc func(eth2p0.BLSPubKey) (eth2p0.BLSPubKey, bool)
+	// getVerifyShareFunc maps public keys (the DV root public key) to the public shares (what the VC thinks
+	// as its public key) valid at the epoch of the signed data, see SetShareRotation.
+	getVerifyShareFunc func(core.PubKey, eth2p0.Epoch) (tblsv2.PublicKey, error)
+	// getPubShareFunc returns the public share for a root public key valid at the epoch, see SetShareRotation.
+	getPubShareFunc func(eth2p0.BLSPubKey, eth2p0.Epoch) (eth2p0.BLSPubKey, bool)
 	// getPubKeyFunc returns the root public key for a public share.
 	getPubKeyFunc func(eth2p0.BLSPubKey) (eth2p0.BLSPubKey, error)
 	// sharesByKey contains this node's public shares (value) by root public (key)
 	sharesByKey map[core.PubKey]core.PubKey
+	// shareIdxByKey contains this node's share index (value) by root public key (key)
+	shareIdxByKey map[core.PubKey]int
+	// randaoRoots caches randao signing roots per epoch.
+	randaoRoots *randaoRootCache
+	// domains caches signing domains per domain name and epoch.
+	domains *domainCache
+	// verified caches verified attestation signatures to deduplicate verifications.
+	verified *verifiedCache
+	// committees caches beacon committees per epoch, it is nil if not registered, see RegisterCommitteeCache.
+	committees *CommitteeCache
+	// presets caches the beacon node's active preset.
+	presets *presetCache
+	// valIndices caches the validator indices of the root public keys per epoch.
+	valIndices *valIndexCache
+	// validators caches validators query responses by state and queried set.
+	validators *validatorsCache
+	// slotGauges tracks per-slot gauges for garbage collection.
+	slotGauges *slotGauges
+	// quarantine contains the root public keys that failed asynchronous partial signature verification.
+	quarantine *quarantine
+	// attConsistency caches the first-seen attestation data per slot to detect inconsistencies across committees.
+	attConsistency *attConsistency
+	// clock is the time source of slot computations, replaceable in tests.
+	clock clockwork.Clock
+	// genesis is the configured genesis data of locally computed fork-independent signing domains, it is nil if not configured.
+	genesis *genesisData
+	// cacheBudget bounds the total memory of the caches above, it is nil if unbounded.
+	cacheBudget *cacheBudget
+	// maintenance is true while submissions are rejected in maintenance mode, see SetMaintenance.
+	maintenance *atomic.Bool
+
+	// bg manages background goroutines like cache prewarmers and refreshers.
+	bg *background
 
 	// Registered input functions
 
@@ -154,7 +246,97 @@ type Component struct {
 	awaitAggAttFunc           func(ctx context.Context, slot int64, attestationRoot eth2p0.Root) (*eth2p0.Attestation, error)
 	awaitAggSigDBFunc         func(context.Context, core.Duty, core.PubKey) (core.SignedData, error)
 	dutyDefFunc               func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error)
+	committeeSizeFunc         func(ctx context.Context, slot, commIdx int64) (int, error)
+	committeeFunc             func(ctx context.Context, slot, commIdx int64) ([]eth2p0.ValidatorIndex, error)
+	aggBitsResolver           AggBitsResolver
+	slashingProtector         SlashingProtector
+	attBatcher                *attBatcher
+	attSubmitLimiter          *submitLimiter
+	verifyPools               map[VerifyClass]*verifyPool
 	subs                      []func(context.Context, core.Duty, core.ParSignedDataSet) error
+	storeErrClassifier        func(error) StoreErrClass
+	awaitTimeout              time.Duration
+	stateRetention            uint64
+	redactSigs                bool
+	asyncVerify               bool
+	verifyReportOnly          bool
+	rejectInconsistentAtt     bool
+	validatorMetrics          bool
+}
+
+// StoreErrClass classifies errors returned by subscribed partial signed data store functions.
+type StoreErrClass int
+
+const (
+	// StoreErrUnknown is the class of unclassified errors, returned as internal server errors.
+	StoreErrUnknown StoreErrClass = iota
+	// StoreErrTransient is the class of temporary errors, returned as retryable service unavailable errors.
+	StoreErrTransient
+	// StoreErrPermanent is the class of errors caused by invalid data, returned as bad request errors.
+	StoreErrPermanent
+)
+
+// RegistrationStatus reports which input and output functions are registered with the component.
+type RegistrationStatus struct {
+	PubKeyByAttestation   bool
+	AwaitAttestation      bool
+	AwaitBeaconBlock      bool
+	AwaitBlindedBlock     bool
+	AwaitSyncContribution bool
+	AwaitAggAttestation   bool
+	AwaitAggSigDB         bool
+	GetDutyDefinition     bool
+	CommitteeSize         bool
+	BeaconCommittee       bool
+	AggBitsResolver       bool
+	SlashingProtector     bool
+	StoreErrClassifier    bool
+	Subscriptions         int
+}
+
+// InspectRegistrations returns the component's registration status, surfacing wiring mistakes
+// like required input functions that were not registered.
+func (c Component) InspectRegistrations() RegistrationStatus {
+	return RegistrationStatus{
+		PubKeyByAttestation:   c.pubKeyByAttFunc != nil,
+		AwaitAttestation:      c.awaitAttFunc != nil,
+		AwaitBeaconBlock:      c.awaitBlockFunc != nil,
+		AwaitBlindedBlock:     c.awaitBlindedBlockFunc != nil,
+		AwaitSyncContribution: c.awaitSyncContributionFunc != nil,
+		AwaitAggAttestation:   c.awaitAggAttFunc != nil,
+		AwaitAggSigDB:         c.awaitAggSigDBFunc != nil,
+		GetDutyDefinition:     c.dutyDefFunc != nil,
+		CommitteeSize:         c.committeeSizeFunc != nil,
+		BeaconCommittee:       c.committeeFunc != nil,
+		AggBitsResolver:       c.aggBitsResolver != nil,
+		SlashingProtector:     c.slashingProtector != nil,
+		StoreErrClassifier:    c.storeErrClassifier != nil,
+		Subscriptions:         len(c.subs),
+	}
+}
+
+// GroupPubKey returns the distributed validator root (group) public key used to verify final aggregated signatures.
+// The provided public key is either the root public key or this node's public share of the distributed validator.
+func (c Component) GroupPubKey(pubkey core.PubKey) (tblsv2.PublicKey, error) {
+	for root, share := range c.sharesByKey {
+		if pubkey != root && pubkey != share {
+			continue
+		}
+
+		b, err := root.Bytes()
+		if err != nil {
+			return tblsv2.PublicKey{}, err
+		}
+
+		return tblsconv2.PubkeyFromBytes(b)
+	}
+
+	return tblsv2.PublicKey{}, errors.New("unknown public key", pubkeyField("pubkey", pubkey))
+}
+
+// Close stops all background goroutines, blocking until they exit or the context is closed.
+func (c Component) Close(ctx context.Context) error {
+	return c.bg.Close(ctx)
 }
 
 // RegisterAwaitBeaconBlock registers a function to query unsigned beacon block.
@@ -187,6 +369,34 @@ func (c *Component) RegisterPubKeyByAttestation(fn func(ctx context.Context, slo
 	c.pubKeyByAttFunc = fn
 }
 
+// RegisterCommitteeSize registers a function to query beacon committee sizes, e.g. CommitteeCache.CommitteeSize.
+// When registered, submitted attestation aggregation bits are validated against the committee size.
+// It only supports a single function, since it is an input of the component.
+func (c *Component) RegisterCommitteeSize(fn func(ctx context.Context, slot, commIdx int64) (int, error)) {
+	c.committeeSizeFunc = fn
+}
+
+// RegisterBeaconCommittee registers a function to query beacon committee validator indices, e.g. CommitteeCache.Committee.
+// When registered, the aggregate attestation signature of submitted aggregate and proofs is verified against
+// the public keys of the participating committee validators.
+// It only supports a single function, since it is an input of the component.
+func (c *Component) RegisterBeaconCommittee(fn func(ctx context.Context, slot, commIdx int64) ([]eth2p0.ValidatorIndex, error)) {
+	c.committeeFunc = fn
+}
+
+// RegisterAggBitsResolver registers a function resolving the validators that signed submitted attestations
+// from their aggregation bits. It defaults to SingleBitResolver.
+// It only supports a single function.
+func (c *Component) RegisterAggBitsResolver(fn AggBitsResolver) {
+	c.aggBitsResolver = fn
+}
+
+// RegisterSlashingProtector registers a slashing protector, e.g. MemSlashingProtector.
+// When registered, submitted attestations that are slashable for the DV public key are rejected.
+func (c *Component) RegisterSlashingProtector(p SlashingProtector) {
+	c.slashingProtector = p
+}
+
 // RegisterGetDutyDefinition registers a function to query duty definitions.
 // It supports a single function, since it is an input of the component.
 func (c *Component) RegisterGetDutyDefinition(fn func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error)) {
@@ -207,282 +417,869 @@ func (c *Component) RegisterAwaitAggSigDB(fn func(context.Context, core.Duty, co
 // Subscribe registers a partial signed data set store function.
 // It supports multiple functions since it is the output of the component.
 func (c *Component) Subscribe(fn func(context.Context, core.Duty, core.ParSignedDataSet) error) {
+	first := len(c.subs) == 0
 	c.subs = append(c.subs, func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		if first { // Count submissions once, not per subscriber.
+			c.countSubmissions(duty, set)
+		}
+
 		// Clone before calling each subscriber.
 		clone, err := set.Clone()
 		if err != nil {
 			return err
 		}
 
-		return fn(ctx, duty, clone)
+		err = fn(ctx, duty, clone)
+		if err != nil {
+			return c.classifyStoreErr(err)
+		}
+
+		return nil
 	})
 }
 
-// AttestationData implements the eth2client.AttesterDutiesProvider for the router.
-func (c Component) AttestationData(parent context.Context, slot eth2p0.Slot, committeeIndex eth2p0.CommitteeIndex) (*eth2p0.AttestationData, error) {
-	ctx, span := core.StartDutyTrace(parent, core.NewAttesterDuty(int64(slot)), "core/validatorapi.AttestationData")
-	defer span.End()
+// countSubmissions increments the per-validator submission counters if enabled.
+func (c *Component) countSubmissions(duty core.Duty, set core.ParSignedDataSet) {
+	if !c.validatorMetrics {
+		return
+	}
 
-	return c.awaitAttFunc(ctx, int64(slot), int64(committeeIndex))
+	for pubkey := range set {
+		vapiValidatorSubmissions.WithLabelValues(duty.Type.String(), pubkey.String()).Inc()
+	}
 }
 
-// SubmitAttestations implements the eth2client.AttestationsSubmitter for the router.
-func (c Component) SubmitAttestations(ctx context.Context, attestations []*eth2p0.Attestation) error {
-	duty := core.NewAttesterDuty(int64(attestations[0].Data.Slot))
-	if len(attestations) > 0 {
-		// Pick the first attestation slot to use as trace root.
-		var span trace.Span
-		ctx, span = core.StartDutyTrace(ctx, duty, "core/validatorapi.SubmitAttestations")
-		defer span.End()
-	}
+// RegisterStoreErrClassifier registers a function that classifies errors returned by subscribed
+// partial signed data store functions. Transient errors result in retryable API responses.
+// It supports a single function.
+func (c *Component) RegisterStoreErrClassifier(fn func(error) StoreErrClass) {
+	c.storeErrClassifier = fn
+}
 
-	setsBySlot := make(map[int64]core.ParSignedDataSet)
-	for _, att := range attestations {
-		slot := int64(att.Data.Slot)
+// SetRedactSignatures configures whether signature material (message root and signature)
+// is excluded from partial signature verification errors.
+func (c *Component) SetRedactSignatures(redact bool) {
+	c.redactSigs = redact
+}
 
-		// Determine the validator that sent this by mapping values from original AttestationDuty via the dutyDB
-		indices := att.AggregationBits.BitIndices()
-		if len(indices) != 1 {
-			return errors.New("unexpected number of aggregation bits",
-				z.Str("aggbits", fmt.Sprintf("%#x", []byte(att.AggregationBits))))
-		}
+// SetAsyncVerify enables asynchronous partial signature verification of submitted attestations.
+// Partial signatures are then stored optimistically and verified in the background, temporarily quarantining
+// validators whose signatures mismatch. Only use it in trusted environments, the default is synchronous verification.
+func (c *Component) SetAsyncVerify(async bool) {
+	c.asyncVerify = async
+}
 
-		pubkey, err := c.pubKeyByAttFunc(ctx, slot, int64(att.Data.Index), int64(indices[0]))
-		if err != nil {
-			return err
-		}
+// SetVerifyReportOnly configures whether partial signature verification failures are only reported (logged and counted
+// in core_validatorapi_verify_would_reject_total) instead of rejecting the submission. This allows validating a new
+// configuration against live traffic. Unlike WithInsecureSkipVerify, signatures are still verified.
+// Note that invalid partial signatures are still stored and broadcast to peers, failing the affected duties,
+// so it should only be enabled temporarily.
+func (c *Component) SetVerifyReportOnly(reportOnly bool) {
+	c.verifyReportOnly = reportOnly
+}
 
-		parSigData := core.NewPartialAttestation(att, c.shareIdx)
+// SetRejectInconsistentAttestationData configures whether attestation data that disagrees on source, target or head
+// with the attestation data of another committee in the same slot is rejected. It is only flagged by default.
+func (c *Component) SetRejectInconsistentAttestationData(reject bool) {
+	c.rejectInconsistentAtt = reject
+}
 
-		// Verify attestation signature
-		err = c.verifyPartialSig(ctx, parSigData, pubkey)
-		if err != nil {
-			return err
-		}
+// SetValidatorSubmissionMetrics configures whether submitted partial signatures are counted per validator (DV public key),
+// which helps detect a validator client that stopped submitting for a single validator. It is disabled by default
+// due to the high cardinality of the metric.
+func (c *Component) SetValidatorSubmissionMetrics(enabled bool) {
+	c.validatorMetrics = enabled
+}
 
-		// Encode partial signed data and add to a set
-		set, ok := setsBySlot[slot]
-		if !ok {
-			set = make(core.ParSignedDataSet)
-			setsBySlot[slot] = set
-		}
+// SetClock overrides the time source used to compute the current slot, allowing tests to control it deterministically.
+func (c *Component) SetClock(clock clockwork.Clock) {
+	c.clock = clock
+}
+
+// SetAttestationBatchWindow enables coalescing of submitted attestations per slot within the window
+// before storing them as a single partial signed data set, trading a little latency for fewer downstream operations.
+func (c *Component) SetAttestationBatchWindow(window time.Duration) {
+	after := func(d time.Duration) <-chan time.Time { return c.clock.After(d) }
+	c.attBatcher = newAttBatcher(window, after, c.bg.Go, func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		return c.storeAttestations(ctx, duty, set)
+	})
+}
 
-		set[pubkey] = parSigData
+// SetAttestationSubmissionLimit limits the number of attestation submissions processed concurrently to the limit,
+// queueing excess submissions for at most the queue timeout (or until cancelled if zero) before rejecting them with
+// a retryable error. This provides back-pressure against many validator clients submitting at once. Zero disables the limit.
+func (c *Component) SetAttestationSubmissionLimit(limit int, queueTimeout time.Duration) {
+	if limit <= 0 {
+		c.attSubmitLimiter = nil
+		return
 	}
 
-	// Send sets to subscriptions.
-	for slot, set := range setsBySlot {
-		duty := core.NewAttesterDuty(slot)
-		ctx := log.WithCtx(ctx, z.Any("duty", duty))
+	c.attSubmitLimiter = newSubmitLimiter(limit, queueTimeout)
+}
 
-		for _, sub := range c.subs {
-			// No need to clone since sub auto clones.
-			err := sub(ctx, duty, set)
-			if err != nil {
-				return err
-			}
-		}
+// SetVerificationWorkers limits the number of partial signature verifications of the class processed concurrently,
+// including asynchronous verifications, queueing excess verifications until a worker is available. Each class is
+// processed by a separate pool, so a backlog of attestation verifications doesn't delay latency-critical block
+// proposal verifications. It defaults to the number of CPUs per class. Zero disables the limit of the class.
+func (c *Component) SetVerificationWorkers(class VerifyClass, workers int) {
+	if workers <= 0 {
+		delete(c.verifyPools, class)
+		return
 	}
 
-	return nil
+	if c.verifyPools == nil {
+		c.verifyPools = make(map[VerifyClass]*verifyPool)
+	}
+	c.verifyPools[class] = newVerifyPool(class, workers)
 }
 
-// BeaconBlockProposal submits the randao for aggregation and inclusion in DutyProposer and then queries the dutyDB for an unsigned beacon block.
-func (c Component) BeaconBlockProposal(ctx context.Context, slot eth2p0.Slot, randao eth2p0.BLSSignature, _ []byte) (*eth2spec.VersionedBeaconBlock, error) {
-	// Get proposer pubkey (this is a blocking query).
-	pubkey, err := c.getProposerPubkey(ctx, core.NewProposerDuty(int64(slot)))
-	if err != nil {
-		return nil, err
+// SetCacheBudget bounds the total approximate memory in bytes of the randao root, attestation data consistency,
+// validator index, validators, signing domain, verified signature and committee caches, evicting the least
+// recently used entries across all caches once exceeded. Zero disables the budget.
+// It must be called before the component is used.
+func (c *Component) SetCacheBudget(bytes int64) {
+	if bytes <= 0 {
+		c.cacheBudget = nil
+	} else {
+		c.cacheBudget = newCacheBudget(bytes)
 	}
 
-	epoch, err := eth2util.EpochFromSlot(ctx, c.eth2Cl, slot)
-	if err != nil {
-		return nil, err
+	if c.randaoRoots != nil {
+		c.randaoRoots.budget = c.cacheBudget
 	}
-
-	sigEpoch := eth2util.SignedEpoch{
-		Epoch:     epoch,
-		Signature: randao,
+	if c.attConsistency != nil {
+		c.attConsistency.budget = c.cacheBudget
+	}
+	if c.valIndices != nil {
+		c.valIndices.budget = c.cacheBudget
+	}
+	if c.validators != nil {
+		c.validators.budget = c.cacheBudget
+	}
+	if c.domains != nil {
+		c.domains.budget = c.cacheBudget
+	}
+	if c.verified != nil {
+		c.verified.budget = c.cacheBudget
 	}
+	if c.committees != nil {
+		c.committees.budget = c.cacheBudget
+	}
+}
 
-	duty := core.NewRandaoDuty(int64(slot))
-	parSig := core.NewPartialSignedRandao(sigEpoch.Epoch, sigEpoch.Signature, c.shareIdx)
+// RegisterCommitteeCache registers the beacon committee cache, bounding its memory by the cache budget.
+// It must be called before the component is used, see SetCacheBudget.
+func (c *Component) RegisterCommitteeCache(committees *CommitteeCache) {
+	c.committees = committees
+	committees.budget = c.cacheBudget
+}
 
-	// Verify randao signature
-	err = c.verifyPartialSig(ctx, parSig, pubkey)
-	if err != nil {
-		return nil, err
-	}
+// SetAwaitTimeout overrides the maximum duration to await unsigned attestation data and blocks.
+// It defaults to the slot duration so that stalled duties time out within the slot.
+func (c *Component) SetAwaitTimeout(timeout time.Duration) {
+	c.awaitTimeout = timeout
+}
 
-	for _, sub := range c.subs {
-		// No need to clone since sub auto clones.
-		parsigSet := core.ParSignedDataSet{
-			pubkey: parSig,
-		}
-		err := sub(ctx, duty, parsigSet)
+// SetStateRetention overrides the number of epochs before the current epoch for which per-slot state
+// is retained by the garbage collector, see StartGC.
+func (c *Component) SetStateRetention(epochs uint64) {
+	c.stateRetention = epochs
+}
+
+// withAwaitTimeout returns a copy of the parent context that times out after the await timeout.
+func (c Component) withAwaitTimeout(parent context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.awaitTimeout
+	if timeout == 0 && c.eth2Cl != nil {
+		slotDuration, err := c.eth2Cl.SlotDuration(parent)
 		if err != nil {
-			return nil, err
+			log.Warn(parent, "Failed fetching slot duration for await timeout", err)
+		} else {
+			timeout = slotDuration
 		}
 	}
 
-	// In the background, the following needs to happen before the
-	// unsigned beacon block will be returned below:
-	//  - Threshold number of VCs need to submit their partial randao reveals.
-	//  - These signatures will be exchanged and aggregated.
-	//  - The aggregated signature will be stored in AggSigDB.
-	//  - Scheduler (in the meantime) will schedule a DutyProposer (to create a unsigned block).
-	//  - Fetcher will then block waiting for an aggregated randao reveal.
-	//  - Once it is found, Fetcher will fetch an unsigned block from the beacon
-	//    node including the aggregated randao in the request.
-	//  - Consensus will agree upon the unsigned block and insert the resulting block in the DutyDB.
-	//  - Once inserted, the query below will return.
-
-	// Query unsigned block (this is blocking).
-	block, err := c.awaitBlockFunc(ctx, int64(slot))
-	if err != nil {
-		return nil, err
+	if timeout <= 0 {
+		return context.WithCancel(parent)
 	}
 
-	return block, nil
+	return context.WithTimeout(parent, timeout)
 }
 
-func (c Component) SubmitBeaconBlock(ctx context.Context, block *eth2spec.VersionedSignedBeaconBlock) error {
-	// Calculate slot epoch
-	slot, err := block.Slot()
-	if err != nil {
+// awaitTimeoutErr returns a gateway timeout api error if the error was caused by the await timeout
+// and not by the parent context, else it returns the error as is.
+func awaitTimeoutErr(parent context.Context, err error) error {
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) || parent.Err() != nil {
 		return err
 	}
 
-	pubkey, err := c.getProposerPubkey(ctx, core.NewProposerDuty(int64(slot)))
-	if err != nil {
-		return err
+	return apiError{
+		StatusCode: http.StatusGatewayTimeout,
+		Message:    "timeout awaiting duty data",
+		Err:        err,
 	}
+}
 
-	// Save Partially Signed Block to ParSigDB
-	duty := core.NewProposerDuty(int64(slot))
-	ctx = log.WithCtx(ctx, z.Any("duty", duty))
+// errAttWindowClosed is the cancel cause of awaiting attestation data after the attestation window of the slot closed.
+var errAttWindowClosed = errors.NewSentinel("attestation window closed")
 
-	signedData, err := core.NewPartialVersionedSignedBeaconBlock(block, c.shareIdx)
-	if err != nil {
-		return err
-	}
+// withAttWindow returns a copy of the parent context that is cancelled with errAttWindowClosed once the
+// attestation window of the slot closes at the 1/3 deadline of the slot.
+func (c Component) withAttWindow(parent context.Context, slot eth2p0.Slot) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	cancelFunc := func() { cancel(nil) }
 
-	// Verify block signature
-	err = c.verifyPartialSig(ctx, signedData, pubkey)
-	if err != nil {
-		return err
+	deadline, ok := c.attWindowDeadline(parent, slot)
+	if !ok {
+		return ctx, cancelFunc
 	}
 
-	log.Debug(ctx, "Beacon block submitted by validator client", z.Str("block_version", block.Version.String()))
+	wait := deadline.Sub(c.clock.Now())
+	if wait <= 0 {
+		cancel(errAttWindowClosed)
+		return ctx, cancelFunc
+	}
 
-	set := core.ParSignedDataSet{pubkey: signedData}
-	for _, sub := range c.subs {
-		// No need to clone since sub auto clones.
-		err = sub(ctx, duty, set)
-		if err != nil {
-			return err
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.clock.After(wait):
+			cancel(errAttWindowClosed)
 		}
-	}
+	}()
 
-	return nil
+	return ctx, cancelFunc
 }
 
-// BlindedBeaconBlockProposal submits the randao for aggregation and inclusion in DutyBuilderProposer and then queries the dutyDB for an unsigned blinded beacon block.
-func (c Component) BlindedBeaconBlockProposal(ctx context.Context, slot eth2p0.Slot, randao eth2p0.BLSSignature, _ []byte) (*eth2api.VersionedBlindedBeaconBlock, error) {
-	// Get proposer pubkey (this is a blocking query).
-	pubkey, err := c.getProposerPubkey(ctx, core.NewBuilderProposerDuty(int64(slot)))
-	if err != nil {
-		return nil, err
+// attWindowDeadline returns the time the attestation window of the slot closes, the 1/3 deadline of the slot.
+// It returns false if the deadline is unknown.
+func (c Component) attWindowDeadline(ctx context.Context, slot eth2p0.Slot) (time.Time, bool) {
+	if c.eth2Cl == nil {
+		return time.Time{}, false
 	}
 
-	epoch, err := eth2util.EpochFromSlot(ctx, c.eth2Cl, slot)
+	genesis, err := c.eth2Cl.GenesisTime(ctx)
 	if err != nil {
-		return nil, err
+		log.Warn(ctx, "Failed fetching genesis time for attestation window", err)
+		return time.Time{}, false
 	}
 
-	sigEpoch := eth2util.SignedEpoch{
-		Epoch:     epoch,
-		Signature: randao,
+	slotDuration, err := c.eth2Cl.SlotDuration(ctx)
+	if err != nil {
+		log.Warn(ctx, "Failed fetching slot duration for attestation window", err)
+		return time.Time{}, false
 	}
 
-	duty := core.NewRandaoDuty(int64(slot))
-	parSig := core.NewPartialSignedRandao(sigEpoch.Epoch, sigEpoch.Signature, c.shareIdx)
+	return genesis.Add(slotDuration*time.Duration(slot) + slotDuration/3), true
+}
 
-	// Verify randao signature
-	err = c.verifyPartialSig(ctx, parSig, pubkey)
-	if err != nil {
-		return nil, err
-	}
+// attInclusionWindowClosed returns true if the 1/3 deadline of the next slot passed,
+// after which attestations of the slot likely miss inclusion in the next block.
+func (c Component) attInclusionWindowClosed(ctx context.Context, slot eth2p0.Slot) bool {
+	deadline, ok := c.attWindowDeadline(ctx, slot+1)
 
-	for _, sub := range c.subs {
-		// No need to clone since sub auto clones.
-		parsigSet := core.ParSignedDataSet{
-			pubkey: parSig,
-		}
-		err := sub(ctx, duty, parsigSet)
-		if err != nil {
-			return nil, err
-		}
-	}
+	return ok && !c.clock.Now().Before(deadline)
+}
 
-	// In the background, the following needs to happen before the
-	// unsigned blinded beacon block will be returned below:
-	//  - Threshold number of VCs need to submit their partial randao reveals.
-	//  - These signatures will be exchanged and aggregated.
-	//  - The aggregated signature will be stored in AggSigDB.
-	//  - Scheduler (in the meantime) will schedule a DutyBuilderProposer (to create a unsigned blinded block).
-	//  - Fetcher will then block waiting for an aggregated randao reveal.
-	//  - Once it is found, Fetcher will fetch an unsigned blinded block from the beacon
-	//    node including the aggregated randao in the request.
-	//  - Consensus will agree upon the unsigned blinded block and insert the resulting block in the DutyDB.
-	//  - Once inserted, the query below will return.
+// AttestationData implements the eth2client.AttesterDutiesProvider for the router.
+func (c Component) AttestationData(parent context.Context, slot eth2p0.Slot, committeeIndex eth2p0.CommitteeIndex) (*eth2p0.AttestationData, error) {
+	ctx, span := core.StartDutyTrace(parent, core.NewAttesterDuty(int64(slot)), "core/validatorapi.AttestationData")
+	defer span.End()
 
-	// Query unsigned block (this is blocking).
-	block, err := c.awaitBlindedBlockFunc(ctx, int64(slot))
+	awaitCtx, cancel := c.withAwaitTimeout(ctx)
+	defer cancel()
+
+	awaitCtx, cancelWindow := c.withAttWindow(awaitCtx, slot)
+	defer cancelWindow()
+
+	attData, err := c.awaitAttFunc(awaitCtx, int64(slot), int64(committeeIndex))
 	if err != nil {
+		if errors.Is(context.Cause(awaitCtx), errAttWindowClosed) && ctx.Err() == nil {
+			return nil, apiError{
+				StatusCode: http.StatusGatewayTimeout,
+				Message:    "attestation window closed",
+				Err:        errors.Wrap(errAttWindowClosed, "await attestation data", z.U64("slot", uint64(slot))),
+			}
+		}
+
+		return nil, awaitTimeoutErr(ctx, err)
+	}
+
+	if err := c.verifyAttConsistency(ctx, attData); err != nil {
 		return nil, err
 	}
 
-	return block, nil
+	return attData, nil
 }
 
-func (c Component) SubmitBlindedBeaconBlock(ctx context.Context, block *eth2api.VersionedSignedBlindedBeaconBlock) error {
-	// Calculate slot epoch
-	slot, err := block.Slot()
-	if err != nil {
-		return err
+// SubmitAttestations implements the eth2client.AttestationsSubmitter for the router.
+func (c Component) SubmitAttestations(ctx context.Context, attestations []*eth2p0.Attestation) error {
+	_, err := c.submitAttestations(ctx, attestations, false)
+	return err
+}
+
+// submitAttestations submits the attestations. If withReceipts is false, it returns an error if any attestation
+// is rejected. Otherwise, it returns a receipt of each attestation, only failing the batch if it can't be processed.
+func (c Component) submitAttestations(ctx context.Context, attestations []*eth2p0.Attestation, withReceipts bool,
+) ([]AttestationReceipt, error) {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return nil, err
 	}
 
-	pubkey, err := c.getProposerPubkey(ctx, core.NewBuilderProposerDuty(int64(slot)))
+	release, err := c.attSubmitLimiter.Acquire(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer release()
 
-	// Save Partially Signed Blinded Block to ParSigDB
-	duty := core.NewBuilderProposerDuty(int64(slot))
-	ctx = log.WithCtx(ctx, z.Any("duty", duty))
-
-	signedData, err := core.NewPartialVersionedSignedBlindedBeaconBlock(block, c.shareIdx)
-	if err != nil {
-		return err
+	if len(attestations) > 0 {
+		// Pick the first attestation slot to use as trace root.
+		duty := core.NewAttesterDuty(int64(attestations[0].Data.Slot))
+		var span trace.Span
+		ctx, span = core.StartDutyTrace(ctx, duty, "core/validatorapi.SubmitAttestations")
+		defer span.End()
 	}
 
-	// Verify Blinded block signature
-	err = c.verifyPartialSig(ctx, signedData, pubkey)
-	if err != nil {
-		return err
+	receipts := make([]AttestationReceipt, len(attestations))
+	for i := range receipts {
+		receipts[i] = AttestationReceipt{Index: i, Status: AttestationAccepted}
 	}
 
-	log.Debug(ctx, "Blinded beacon block submitted by validator client")
+	// reject returns the error if not returning receipts. Otherwise, it records the status of the rejected
+	// attestation and returns nil, unless the error is not specific to the attestation.
+	reject := func(i int, status AttestationReceiptStatus, err error) error {
+		if !withReceipts {
+			return err
+		}
 
-	set := core.ParSignedDataSet{pubkey: signedData}
-	for _, sub := range c.subs {
-		// No need to clone since sub auto clones.
-		err = sub(ctx, duty, set)
-		if err != nil {
+		apiErr := new(apiError)
+		if status != AttestationVerificationFailed && (!errors.As(err, apiErr) || apiErr.StatusCode >= http.StatusInternalServerError) {
 			return err
 		}
-	}
+
+		receipts[i].Status = status
+		receipts[i].Message = err.Error()
+
+		return nil
+	}
+
+	// submittedAtt is a submitted attestation with its resolved signers, partial signed data and signature verification.
+	type submittedAtt struct {
+		Index        int
+		Att          *eth2p0.Attestation
+		Signers      []attSigner
+		ParSigned    core.ParSignedData
+		Verification asyncVerification
+		PartialSig   partialSig
+	}
+
+	var (
+		setsBySlot  = make(core.ParSignedDataSetsBySlot)
+		attDataRoot = newAttDataRootFunc()
+		signingData = newSigningDataCache(c.eth2Cl, c.domains, c.verified)
+		submitted   []submittedAtt
+	)
+	for i, att := range attestations {
+		// Determine the validators that sent this by mapping values from original AttestationDuty via the dutyDB
+		signers, err := c.resolveAttSigners(ctx, att)
+		if err != nil {
+			if err := reject(i, AttestationRejected, err); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		var (
+			pubkeys     []core.PubKey
+			quarantined error
+		)
+		for _, signer := range signers {
+			if err := c.verifyQuarantine(signer.Pubkey); err != nil {
+				quarantined = err
+				break
+			}
+			pubkeys = append(pubkeys, signer.Pubkey)
+		}
+		if quarantined != nil {
+			if err := reject(i, AttestationRejected, quarantined); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		// Verify attestation signature, reusing the message root of identical attestation data
+		// and the signing domain of attestations in the same epoch.
+		root, err := attDataRoot(att.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		parSigData := core.NewPartialAttestation(att, signers[0].ShareIdx)
+		parSig := partialSig{
+			Duty:   core.NewAttesterDuty(int64(att.Data.Slot)),
+			ParSig: withMessageRoot(parSigData, root),
+			Pubkey: signers[0].Pubkey,
+		}
+
+		verify := func(ctx context.Context) error {
+			return c.verifyPartialSigFunc(ctx, parSig.Duty, parSig.ParSig, parSig.Pubkey, signingData.Verify)
+		}
+
+		submitted = append(submitted, submittedAtt{
+			Index:        i,
+			Att:          att,
+			Signers:      signers,
+			ParSigned:    parSigData,
+			Verification: asyncVerification{Pubkeys: pubkeys, Verify: verify},
+			PartialSig:   parSig,
+		})
+	}
+
+	var (
+		pending  []asyncVerification
+		verified []submittedAtt
+	)
+	if c.asyncVerify {
+		verified = submitted
+	} else {
+		// Verify all signatures at once, only verifying each to identify the invalid ones if the batch fails.
+		var batch []partialSig
+		for _, sub := range submitted {
+			batch = append(batch, sub.PartialSig)
+		}
+		batchOK := len(batch) > 1 && c.verifyPartialSigBatch(ctx, batch, signingData) == nil
+
+		for _, sub := range submitted {
+			if batchOK {
+				verified = append(verified, sub)
+				continue
+			}
+
+			if err := sub.Verification.Verify(ctx); err != nil {
+				if err := reject(sub.Index, AttestationVerificationFailed, err); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			verified = append(verified, sub)
+		}
+	}
+
+	for _, sub := range verified {
+		slot := int64(sub.Att.Data.Slot)
+
+		if err := c.checkAttSlashing(ctx, sub.Att, sub.Signers); err != nil {
+			if err := reject(sub.Index, AttestationRejected, err); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		// Check all signers for conflicts before recording or adding any, so rejected attestations leave no partial state.
+		duplicates, err := checkAttConflicts(setsBySlot, slot, sub.Signers, sub.ParSigned)
+		if err != nil {
+			if err := reject(sub.Index, AttestationRejected, conflictError(err)); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if c.asyncVerify {
+			// Only record unverified attestations for slashing protection once verified in the background.
+			att, signers := sub.Att, sub.Signers
+			sub.Verification.Record = func(ctx context.Context) error {
+				return c.recordAttSlashing(ctx, att, signers)
+			}
+			pending = append(pending, sub.Verification)
+		} else if err := c.recordAttSlashing(ctx, sub.Att, sub.Signers); err != nil {
+			if err := reject(sub.Index, AttestationRejected, err); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		for _, signer := range sub.Signers {
+			// Encode partial signed data and add to a set
+			if err := setsBySlot.Add(slot, signer.Pubkey, sub.ParSigned); err != nil {
+				return nil, err
+			}
+		}
+
+		if duplicates == len(sub.Signers) {
+			receipts[sub.Index].Status = AttestationDuplicate
+		} else if withReceipts && c.attInclusionWindowClosed(ctx, sub.Att.Data.Slot) {
+			receipts[sub.Index].Status = AttestationLate
+		}
+	}
+
+	// Verify optimistically stored partial signatures in the background, also if storing some slots fails below.
+	c.verifyAsync(pending)
+
+	// Send sets to subscriptions in slot order, storing all slots even if some fail.
+	slots := make([]int64, 0, len(setsBySlot))
+	for slot := range setsBySlot {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+
+	var slotsErr AttestationSlotsError
+	for _, slot := range slots {
+		var err error
+		if c.attBatcher != nil {
+			err = c.attBatcher.Add(ctx, slot, setsBySlot[slot])
+		} else {
+			err = c.storeAttestations(ctx, core.NewAttesterDuty(slot), setsBySlot[slot])
+		}
+
+		if err != nil {
+			if len(slots) == 1 {
+				return nil, err
+			}
+
+			slotsErr.Failed = append(slotsErr.Failed, slot)
+			if slotsErr.Err == nil {
+				slotsErr.Err = err
+			}
+
+			continue
+		}
+
+		slotsErr.Stored = append(slotsErr.Stored, slot)
+	}
+
+	if len(slotsErr.Failed) > 0 {
+		return nil, slotsErr
+	}
+
+	return receipts, nil
+}
+
+// checkAttSlashing returns a bad request error if the attestation is slashable for any of its signers.
+// It doesn't record the attestation, see recordAttSlashing.
+func (c Component) checkAttSlashing(ctx context.Context, att *eth2p0.Attestation, signers []attSigner) error {
+	if c.slashingProtector == nil {
+		return nil
+	}
+
+	for _, signer := range signers {
+		if err := c.slashingProtector.CheckAttestation(ctx, signer.Pubkey, att.Data); err != nil {
+			return slashingError(err)
+		}
+	}
+
+	return nil
+}
+
+// recordAttSlashing records the attestation for all its signers, returning a bad request error
+// if it became slashable since it was checked, see checkAttSlashing.
+func (c Component) recordAttSlashing(ctx context.Context, att *eth2p0.Attestation, signers []attSigner) error {
+	if c.slashingProtector == nil {
+		return nil
+	}
+
+	for _, signer := range signers {
+		if err := c.slashingProtector.RecordAttestation(ctx, signer.Pubkey, att.Data); err != nil {
+			return slashingError(err)
+		}
+	}
+
+	return nil
+}
+
+// slashingError returns a bad request error of a slashable attestation.
+func slashingError(err error) error {
+	vapiSlashingRejectedTotal.Inc()
+
+	return apiError{
+		StatusCode: http.StatusBadRequest,
+		Message:    "slashable attestation rejected",
+		Err:        err,
+	}
+}
+
+// checkAttConflicts returns an error if the partial signed data of any signer conflicts with the sets,
+// without modifying the sets. It returns the number of signers whose identical data is already in the sets.
+func checkAttConflicts(sets core.ParSignedDataSetsBySlot, slot int64, signers []attSigner, parSigned core.ParSignedData) (int, error) {
+	trial := make(core.ParSignedDataSetsBySlot)
+
+	var duplicates int
+	for _, signer := range signers {
+		if prev, ok := sets[slot][signer.Pubkey]; ok {
+			duplicates++
+			if err := trial.Add(slot, signer.Pubkey, prev); err != nil {
+				return 0, err
+			}
+		}
+
+		if err := trial.Add(slot, signer.Pubkey, parSigned); err != nil {
+			return 0, err
+		}
+	}
+
+	return duplicates, nil
+}
+
+// verifyCommitteeSize returns an error if the attestation aggregation bits length mismatches the
+// beacon committee size. It is a noop if no committee size function is registered.
+func (c Component) verifyCommitteeSize(ctx context.Context, att *eth2p0.Attestation) error {
+	if c.committeeSizeFunc == nil {
+		return nil
+	}
+
+	size, err := c.committeeSizeFunc(ctx, int64(att.Data.Slot), int64(att.Data.Index))
+	if err != nil {
+		return err
+	} else if att.AggregationBits.Len() != uint64(size) {
+		return apiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "attestation aggregation bits length mismatches committee size",
+			Err: errors.New("invalid aggregation bits length",
+				z.U64("length", att.AggregationBits.Len()), z.Int("committee_size", size)),
+		}
+	}
+
+	return nil
+}
+
+// lookupAttPubKey returns the DV root public key of the attestation's validator committee index.
+func (c Component) lookupAttPubKey(ctx context.Context, att *eth2p0.Attestation, valCommIdx int) (core.PubKey, error) {
+	t0 := time.Now()
+	pubkey, err := c.pubKeyByAttFunc(ctx, int64(att.Data.Slot), int64(att.Data.Index), int64(valCommIdx))
+	vapiPubkeyLookupSeconds.Observe(time.Since(t0).Seconds())
+
+	return pubkey, err
+}
+
+// storeAttestations sends the partial signed attestation set to the subscriptions.
+func (c Component) storeAttestations(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+	ctx = log.WithCtx(ctx, z.Any("duty", duty))
+
+	for _, sub := range c.subs {
+		// No need to clone since sub auto clones.
+		err := sub(ctx, duty, set)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// submitRandao verifies the partial randao reveal and submits it for aggregation.
+func (c Component) submitRandao(ctx context.Context, slot eth2p0.Slot, sigEpoch eth2util.SignedEpoch, pubkey core.PubKey) error {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return err
+	}
+
+	duty := core.NewRandaoDuty(int64(slot))
+	parSig := core.NewPartialSignedRandao(sigEpoch.Epoch, sigEpoch.Signature, c.shareIdxByPubKey(pubkey))
+
+	// Verify randao signature
+	err := c.verifyPartialSig(ctx, duty, parSig, pubkey)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range c.subs {
+		// No need to clone since sub auto clones.
+		parsigSet := core.ParSignedDataSet{
+			pubkey: parSig,
+		}
+		err := sub(ctx, duty, parsigSet)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BeaconBlockProposal submits the randao for aggregation and inclusion in DutyProposer and then queries the dutyDB for an unsigned beacon block.
+func (c Component) BeaconBlockProposal(ctx context.Context, slot eth2p0.Slot, randao eth2p0.BLSSignature, _ []byte) (*eth2spec.VersionedBeaconBlock, error) {
+	// The await timeout also covers the blocking proposer pubkey query below.
+	awaitCtx, cancel := c.withAwaitTimeout(ctx)
+	defer cancel()
+
+	// Get proposer pubkey (this is a blocking query).
+	pubkey, err := c.getProposerPubkey(awaitCtx, core.NewProposerDuty(int64(slot)))
+	if err != nil {
+		return nil, awaitTimeoutErr(ctx, err)
+	}
+
+	epoch, err := c.epochFromSlot(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+
+	sigEpoch := eth2util.SignedEpoch{
+		Epoch:     epoch,
+		Signature: randao,
+	}
+
+	if err := c.submitRandao(ctx, slot, sigEpoch, pubkey); err != nil {
+		return nil, err
+	}
+
+	// In the background, the following needs to happen before the
+	// unsigned beacon block will be returned below:
+	//  - Threshold number of VCs need to submit their partial randao reveals.
+	//  - These signatures will be exchanged and aggregated.
+	//  - The aggregated signature will be stored in AggSigDB.
+	//  - Scheduler (in the meantime) will schedule a DutyProposer (to create a unsigned block).
+	//  - Fetcher will then block waiting for an aggregated randao reveal.
+	//  - Once it is found, Fetcher will fetch an unsigned block from the beacon
+	//    node including the aggregated randao in the request.
+	//  - Consensus will agree upon the unsigned block and insert the resulting block in the DutyDB.
+	//  - Once inserted, the query below will return.
+
+	// Query unsigned block (this is blocking).
+	block, err := c.awaitBlockFunc(awaitCtx, int64(slot))
+	if err != nil {
+		return nil, awaitTimeoutErr(ctx, err)
+	}
+
+	return block, nil
+}
+
+func (c Component) SubmitBeaconBlock(ctx context.Context, block *eth2spec.VersionedSignedBeaconBlock) error {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return err
+	}
+
+	// Calculate slot epoch
+	slot, err := block.Slot()
+	if err != nil {
+		return err
+	}
+
+	pubkey, err := c.getProposerPubkey(ctx, core.NewProposerDuty(int64(slot)))
+	if err != nil {
+		return err
+	}
+
+	// Save Partially Signed Block to ParSigDB
+	duty := core.NewProposerDuty(int64(slot))
+	ctx = log.WithCtx(ctx, z.Any("duty", duty))
+
+	signedData, err := core.NewPartialVersionedSignedBeaconBlock(block, c.shareIdxByPubKey(pubkey))
+	if err != nil {
+		return err
+	}
+
+	// Verify block signature
+	err = c.verifyPartialSig(ctx, duty, signedData, pubkey)
+	if err != nil {
+		return err
+	}
+
+	log.Debug(ctx, "Beacon block submitted by validator client", z.Str("block_version", block.Version.String()))
+
+	set := core.ParSignedDataSet{pubkey: signedData}
+	for _, sub := range c.subs {
+		// No need to clone since sub auto clones.
+		err = sub(ctx, duty, set)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BlindedBeaconBlockProposal submits the randao for aggregation and inclusion in DutyBuilderProposer and then queries the dutyDB for an unsigned blinded beacon block.
+func (c Component) BlindedBeaconBlockProposal(ctx context.Context, slot eth2p0.Slot, randao eth2p0.BLSSignature, _ []byte) (*eth2api.VersionedBlindedBeaconBlock, error) {
+	// The await timeout also covers the blocking proposer pubkey query below.
+	awaitCtx, cancel := c.withAwaitTimeout(ctx)
+	defer cancel()
+
+	// Get proposer pubkey (this is a blocking query).
+	pubkey, err := c.getProposerPubkey(awaitCtx, core.NewBuilderProposerDuty(int64(slot)))
+	if err != nil {
+		return nil, awaitTimeoutErr(ctx, err)
+	}
+
+	epoch, err := c.epochFromSlot(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+
+	sigEpoch := eth2util.SignedEpoch{
+		Epoch:     epoch,
+		Signature: randao,
+	}
+
+	if err := c.submitRandao(ctx, slot, sigEpoch, pubkey); err != nil {
+		return nil, err
+	}
+
+	// In the background, the following needs to happen before the
+	// unsigned blinded beacon block will be returned below:
+	//  - Threshold number of VCs need to submit their partial randao reveals.
+	//  - These signatures will be exchanged and aggregated.
+	//  - The aggregated signature will be stored in AggSigDB.
+	//  - Scheduler (in the meantime) will schedule a DutyBuilderProposer (to create a unsigned blinded block).
+	//  - Fetcher will then block waiting for an aggregated randao reveal.
+	//  - Once it is found, Fetcher will fetch an unsigned blinded block from the beacon
+	//    node including the aggregated randao in the request.
+	//  - Consensus will agree upon the unsigned blinded block and insert the resulting block in the DutyDB.
+	//  - Once inserted, the query below will return.
+
+	// Query unsigned block (this is blocking).
+	block, err := c.awaitBlindedBlockFunc(awaitCtx, int64(slot))
+	if err != nil {
+		return nil, awaitTimeoutErr(ctx, err)
+	}
+
+	return block, nil
+}
+
+func (c Component) SubmitBlindedBeaconBlock(ctx context.Context, block *eth2api.VersionedSignedBlindedBeaconBlock) error {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return err
+	}
+
+	// Calculate slot epoch
+	slot, err := block.Slot()
+	if err != nil {
+		return err
+	}
+
+	pubkey, err := c.getProposerPubkey(ctx, core.NewBuilderProposerDuty(int64(slot)))
+	if err != nil {
+		return err
+	}
+
+	// Save Partially Signed Blinded Block to ParSigDB
+	duty := core.NewBuilderProposerDuty(int64(slot))
+	ctx = log.WithCtx(ctx, z.Any("duty", duty))
+
+	signedData, err := core.NewPartialVersionedSignedBlindedBeaconBlock(block, c.shareIdxByPubKey(pubkey))
+	if err != nil {
+		return err
+	}
+
+	// Verify Blinded block signature
+	err = c.verifyPartialSig(ctx, duty, signedData, pubkey)
+	if err != nil {
+		return err
+	}
+
+	log.Debug(ctx, "Blinded beacon block submitted by validator client")
+
+	set := core.ParSignedDataSet{pubkey: signedData}
+	for _, sub := range c.subs {
+		// No need to clone since sub auto clones.
+		err = sub(ctx, duty, set)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -512,13 +1309,13 @@ func (c Component) submitRegistration(ctx context.Context, registration *eth2api
 	duty := core.NewBuilderRegistrationDuty(int64(slot))
 	ctx = log.WithCtx(ctx, z.Any("duty", duty))
 
-	signedData, err := core.NewPartialVersionedSignedValidatorRegistration(registration, c.shareIdx)
+	signedData, err := core.NewPartialVersionedSignedValidatorRegistration(registration, c.shareIdxByPubKey(pubkey))
 	if err != nil {
 		return err
 	}
 
 	// Verify registration signature.
-	err = c.verifyPartialSig(ctx, signedData, pubkey)
+	err = c.verifyPartialSig(ctx, duty, signedData, pubkey)
 	if err != nil {
 		return err
 	}
@@ -538,11 +1335,15 @@ func (c Component) submitRegistration(ctx context.Context, registration *eth2api
 
 // SubmitValidatorRegistrations receives the partially signed validator (builder) registration.
 func (c Component) SubmitValidatorRegistrations(ctx context.Context, registrations []*eth2api.VersionedSignedValidatorRegistration) error {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return err
+	}
+
 	if len(registrations) == 0 {
 		return nil // Nothing to do
 	}
 
-	slot, err := c.slotFromTimestamp(ctx, time.Now())
+	slot, err := c.currentSlot(ctx)
 	if err != nil {
 		return err
 	}
@@ -564,6 +1365,10 @@ func (c Component) SubmitValidatorRegistrations(ctx context.Context, registratio
 
 // SubmitVoluntaryExit receives the partially signed voluntary exit.
 func (c Component) SubmitVoluntaryExit(ctx context.Context, exit *eth2p0.SignedVoluntaryExit) error {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return err
+	}
+
 	vals, err := c.eth2Cl.Validators(ctx, "head", []eth2p0.ValidatorIndex{exit.Message.ValidatorIndex})
 	if err != nil {
 		return err
@@ -585,7 +1390,7 @@ func (c Component) SubmitVoluntaryExit(ctx context.Context, exit *eth2p0.SignedV
 	}
 
 	// Use 1st slot in exit epoch for duty.
-	slotsPerEpoch, err := c.eth2Cl.SlotsPerEpoch(ctx)
+	slotsPerEpoch, err := c.slotsPerEpoch(ctx)
 	if err != nil {
 		return err
 	}
@@ -593,10 +1398,10 @@ func (c Component) SubmitVoluntaryExit(ctx context.Context, exit *eth2p0.SignedV
 	duty := core.NewVoluntaryExit(int64(slotsPerEpoch) * int64(exit.Message.Epoch))
 	ctx = log.WithCtx(ctx, z.Any("duty", duty))
 
-	parSigData := core.NewPartialSignedVoluntaryExit(exit, c.shareIdx)
+	parSigData := core.NewPartialSignedVoluntaryExit(exit, c.shareIdxByPubKey(pubkey))
 
 	// Verify voluntary exit signature
-	err = c.verifyPartialSig(ctx, parSigData, pubkey)
+	err = c.verifyPartialSig(ctx, duty, parSigData, pubkey)
 	if err != nil {
 		return err
 	}
@@ -616,6 +1421,10 @@ func (c Component) SubmitVoluntaryExit(ctx context.Context, exit *eth2p0.SignedV
 
 // AggregateBeaconCommitteeSelections returns aggregate beacon committee selection proofs.
 func (c Component) AggregateBeaconCommitteeSelections(ctx context.Context, selections []*eth2exp.BeaconCommitteeSelection) ([]*eth2exp.BeaconCommitteeSelection, error) {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return nil, err
+	}
+
 	var valIdxs []eth2p0.ValidatorIndex
 	for _, selection := range selections {
 		valIdxs = append(valIdxs, selection.ValidatorIndex)
@@ -626,7 +1435,7 @@ func (c Component) AggregateBeaconCommitteeSelections(ctx context.Context, selec
 		return nil, err
 	}
 
-	psigsBySlot := make(map[eth2p0.Slot]core.ParSignedDataSet)
+	psigsBySlot := make(core.ParSignedDataSetsBySlot)
 	for _, selection := range selections {
 		eth2Pubkey, err := vals[selection.ValidatorIndex].PubKey(ctx)
 		if err != nil {
@@ -638,24 +1447,21 @@ func (c Component) AggregateBeaconCommitteeSelections(ctx context.Context, selec
 			return nil, err
 		}
 
-		parSigData := core.NewPartialSignedBeaconCommitteeSelection(selection, c.shareIdx)
+		parSigData := core.NewPartialSignedBeaconCommitteeSelection(selection, c.shareIdxByPubKey(pubkey))
 
 		// Verify slot signature.
-		err = c.verifyPartialSig(ctx, parSigData, pubkey)
+		err = c.verifyPartialSig(ctx, core.NewPrepareAggregatorDuty(int64(selection.Slot)), parSigData, pubkey)
 		if err != nil {
 			return nil, err
 		}
 
-		_, ok := psigsBySlot[selection.Slot]
-		if !ok {
-			psigsBySlot[selection.Slot] = make(core.ParSignedDataSet)
+		if err := psigsBySlot.Add(int64(selection.Slot), pubkey, parSigData); err != nil {
+			return nil, conflictError(err)
 		}
-
-		psigsBySlot[selection.Slot][pubkey] = parSigData
 	}
 
 	for slot, data := range psigsBySlot {
-		duty := core.NewPrepareAggregatorDuty(int64(slot))
+		duty := core.NewPrepareAggregatorDuty(slot)
 		for _, sub := range c.subs {
 			err = sub(ctx, duty, data)
 			if err != nil {
@@ -677,6 +1483,10 @@ func (c Component) AggregateAttestation(ctx context.Context, slot eth2p0.Slot, a
 // - It verifies partial signature on AggregateAndProof.
 // - It then calls all the subscribers for further steps on partially signed aggregate and proof.
 func (c Component) SubmitAggregateAttestations(ctx context.Context, aggregateAndProofs []*eth2p0.SignedAggregateAndProof) error {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return err
+	}
+
 	var valIdxs []eth2p0.ValidatorIndex
 	for _, agg := range aggregateAndProofs {
 		valIdxs = append(valIdxs, agg.Message.AggregatorIndex)
@@ -687,7 +1497,7 @@ func (c Component) SubmitAggregateAttestations(ctx context.Context, aggregateAnd
 		return err
 	}
 
-	psigsBySlot := make(map[eth2p0.Slot]core.ParSignedDataSet)
+	psigsBySlot := make(core.ParSignedDataSetsBySlot)
 	for _, agg := range aggregateAndProofs {
 		slot := agg.Message.Aggregate.Data.Slot
 		eth2Pubkey, err := vals[agg.Message.AggregatorIndex].PubKey(ctx)
@@ -708,24 +1518,26 @@ func (c Component) SubmitAggregateAttestations(ctx context.Context, aggregateAnd
 			}
 		}
 
-		parSigData := core.NewPartialSignedAggregateAndProof(agg, c.shareIdx)
+		// Verify inner aggregate attestation signature (outcome of DutyAttester of the committee).
+		if err := c.verifyAggregateAttestation(ctx, agg.Message.Aggregate); err != nil {
+			return err
+		}
+
+		parSigData := core.NewPartialSignedAggregateAndProof(agg, c.shareIdxByPubKey(pk))
 
 		// Verify outer partial signature.
-		err = c.verifyPartialSig(ctx, parSigData, pk)
+		err = c.verifyPartialSig(ctx, core.NewAggregatorDuty(int64(slot)), parSigData, pk)
 		if err != nil {
 			return err
 		}
 
-		_, ok := psigsBySlot[slot]
-		if !ok {
-			psigsBySlot[slot] = make(core.ParSignedDataSet)
+		if err := psigsBySlot.Add(int64(slot), pk, parSigData); err != nil {
+			return conflictError(err)
 		}
-
-		psigsBySlot[slot][pk] = parSigData
 	}
 
 	for slot, data := range psigsBySlot {
-		duty := core.NewAggregatorDuty(int64(slot))
+		duty := core.NewAggregatorDuty(slot)
 		for _, sub := range c.subs {
 			err = sub(ctx, duty, data)
 			if err != nil {
@@ -739,11 +1551,40 @@ func (c Component) SubmitAggregateAttestations(ctx context.Context, aggregateAnd
 
 // SyncCommitteeContribution returns sync committee contribution data for the given subcommittee and beacon block root.
 func (c Component) SyncCommitteeContribution(ctx context.Context, slot eth2p0.Slot, subcommitteeIndex uint64, beaconBlockRoot eth2p0.Root) (*altair.SyncCommitteeContribution, error) {
+	c.reportSyncContributionReady(slot, subcommitteeIndex, beaconBlockRoot)
+
 	return c.awaitSyncContributionFunc(ctx, int64(slot), int64(subcommitteeIndex), beaconBlockRoot)
 }
 
+// reportSyncContributionReady sets the sync contribution ready metric for the slot and subcommittee depending on whether
+// the contribution is already available in the dutyDB, i.e. if threshold partial signed sync committee messages were
+// aggregated and the contribution was fetched by the time it is queried. This helps diagnose missed sync committee rewards.
+// The dutyDB is probed in the background, so the query isn't delayed.
+func (c Component) reportSyncContributionReady(slot eth2p0.Slot, subcommitteeIndex uint64, beaconBlockRoot eth2p0.Root) {
+	if c.awaitSyncContributionFunc == nil {
+		return
+	}
+
+	c.bg.Go(func(ctx context.Context) {
+		// Only probe the dutyDB, don't block waiting for the contribution.
+		ctx, cancel := context.WithTimeout(ctx, syncContributionProbeTimeout)
+		defer cancel()
+
+		var ready float64
+		if _, err := c.awaitSyncContributionFunc(ctx, int64(slot), int64(subcommitteeIndex), beaconBlockRoot); err == nil {
+			ready = 1
+		}
+
+		c.slotGauges.Set(vapiSyncContributionReady, slot, ready, fmt.Sprint(subcommitteeIndex))
+	})
+}
+
 // SubmitSyncCommitteeMessages receives the partially signed altair.SyncCommitteeMessage.
 func (c Component) SubmitSyncCommitteeMessages(ctx context.Context, messages []*altair.SyncCommitteeMessage) error {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return err
+	}
+
 	var valIdxs []eth2p0.ValidatorIndex
 	for _, msg := range messages {
 		valIdxs = append(valIdxs, msg.ValidatorIndex)
@@ -754,7 +1595,7 @@ func (c Component) SubmitSyncCommitteeMessages(ctx context.Context, messages []*
 		return err
 	}
 
-	psigsBySlot := make(map[eth2p0.Slot]core.ParSignedDataSet)
+	psigsBySlot := make(core.ParSignedDataSetsBySlot)
 	for _, msg := range messages {
 		slot := msg.Slot
 		eth2Pubkey, err := vals[msg.ValidatorIndex].PubKey(ctx)
@@ -767,22 +1608,19 @@ func (c Component) SubmitSyncCommitteeMessages(ctx context.Context, messages []*
 			return err
 		}
 
-		parSigData := core.NewPartialSignedSyncMessage(msg, c.shareIdx)
-		err = c.verifyPartialSig(ctx, parSigData, pk)
+		parSigData := core.NewPartialSignedSyncMessage(msg, c.shareIdxByPubKey(pk))
+		err = c.verifyPartialSig(ctx, core.NewSyncMessageDuty(int64(slot)), parSigData, pk)
 		if err != nil {
 			return err
 		}
 
-		_, ok := psigsBySlot[slot]
-		if !ok {
-			psigsBySlot[slot] = make(core.ParSignedDataSet)
+		if err := psigsBySlot.Add(int64(slot), pk, core.NewPartialSignedSyncMessage(msg, c.shareIdxByPubKey(pk))); err != nil {
+			return conflictError(err)
 		}
-
-		psigsBySlot[slot][pk] = core.NewPartialSignedSyncMessage(msg, c.shareIdx)
 	}
 
 	for slot, data := range psigsBySlot {
-		duty := core.NewSyncMessageDuty(int64(slot))
+		duty := core.NewSyncMessageDuty(slot)
 		for _, sub := range c.subs {
 			err = sub(ctx, duty, data)
 			if err != nil {
@@ -798,6 +1636,10 @@ func (c Component) SubmitSyncCommitteeMessages(ctx context.Context, messages []*
 // - It verifies partial signature on ContributionAndProof.
 // - It then calls all the subscribers for further steps on partially signed contribution and proof.
 func (c Component) SubmitSyncCommitteeContributions(ctx context.Context, contributionAndProofs []*altair.SignedContributionAndProof) error {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return err
+	}
+
 	var valIdxs []eth2p0.ValidatorIndex
 	for _, c := range contributionAndProofs {
 		valIdxs = append(valIdxs, c.Message.AggregatorIndex)
@@ -808,7 +1650,7 @@ func (c Component) SubmitSyncCommitteeContributions(ctx context.Context, contrib
 		return err
 	}
 
-	psigsBySlot := make(map[eth2p0.Slot]core.ParSignedDataSet)
+	psigsBySlot := make(core.ParSignedDataSetsBySlot)
 	for _, contrib := range contributionAndProofs {
 		var (
 			slot = contrib.Message.Contribution.Slot
@@ -834,22 +1676,19 @@ func (c Component) SubmitSyncCommitteeContributions(ctx context.Context, contrib
 		}
 
 		// Verify outer partial signature.
-		parSigData := core.NewPartialSignedSyncContributionAndProof(contrib, c.shareIdx)
-		err = c.verifyPartialSig(ctx, parSigData, pk)
+		parSigData := core.NewPartialSignedSyncContributionAndProof(contrib, c.shareIdxByPubKey(pk))
+		err = c.verifyPartialSig(ctx, core.NewSyncContributionDuty(int64(slot)), parSigData, pk)
 		if err != nil {
 			return err
 		}
 
-		_, ok := psigsBySlot[slot]
-		if !ok {
-			psigsBySlot[slot] = make(core.ParSignedDataSet)
-		}
-
-		psigsBySlot[slot][pk] = parSigData
+		// Validators in multiple sync subcommittees submit a contribution per subcommittee in the same slot,
+		// which aren't conflicting, but the duty only supports one per validator, so store the last.
+		psigsBySlot.Set(int64(slot), pk, parSigData)
 	}
 
 	for slot, data := range psigsBySlot {
-		duty := core.NewSyncContributionDuty(int64(slot))
+		duty := core.NewSyncContributionDuty(slot)
 		for _, sub := range c.subs {
 			err = sub(ctx, duty, data)
 			if err != nil {
@@ -863,6 +1702,10 @@ func (c Component) SubmitSyncCommitteeContributions(ctx context.Context, contrib
 
 // AggregateSyncCommitteeSelections returns aggregate sync committee selection proofs.
 func (c Component) AggregateSyncCommitteeSelections(ctx context.Context, partialSelections []*eth2exp.SyncCommitteeSelection) ([]*eth2exp.SyncCommitteeSelection, error) {
+	if err := c.verifyNotMaintenance(); err != nil {
+		return nil, err
+	}
+
 	var valIdxs []eth2p0.ValidatorIndex
 	for _, selection := range partialSelections {
 		valIdxs = append(valIdxs, selection.ValidatorIndex)
@@ -873,7 +1716,7 @@ func (c Component) AggregateSyncCommitteeSelections(ctx context.Context, partial
 		return nil, err
 	}
 
-	psigsBySlot := make(map[eth2p0.Slot]core.ParSignedDataSet)
+	psigsBySlot := make(core.ParSignedDataSetsBySlot)
 	for _, selection := range partialSelections {
 		eth2Pubkey, err := vals[selection.ValidatorIndex].PubKey(ctx)
 		if err != nil {
@@ -885,24 +1728,21 @@ func (c Component) AggregateSyncCommitteeSelections(ctx context.Context, partial
 			return nil, err
 		}
 
-		parSigData := core.NewPartialSignedSyncCommitteeSelection(selection, c.shareIdx)
+		parSigData := core.NewPartialSignedSyncCommitteeSelection(selection, c.shareIdxByPubKey(pubkey))
 
 		// Verify selection proof.
-		err = c.verifyPartialSig(ctx, parSigData, pubkey)
+		err = c.verifyPartialSig(ctx, core.NewPrepareSyncContributionDuty(int64(selection.Slot)), parSigData, pubkey)
 		if err != nil {
 			return nil, err
 		}
 
-		_, ok := psigsBySlot[selection.Slot]
-		if !ok {
-			psigsBySlot[selection.Slot] = make(core.ParSignedDataSet)
-		}
-
-		psigsBySlot[selection.Slot][pubkey] = parSigData
+		// Validators in multiple sync subcommittees submit a selection per subcommittee in the same slot,
+		// which aren't conflicting, but the duty only supports one per validator, so store the last.
+		psigsBySlot.Set(int64(selection.Slot), pubkey, parSigData)
 	}
 
 	for slot, data := range psigsBySlot {
-		duty := core.NewPrepareSyncContributionDuty(int64(slot))
+		duty := core.NewPrepareSyncContributionDuty(slot)
 		for _, sub := range c.subs {
 			err = sub(ctx, duty, data)
 			if err != nil {
@@ -915,35 +1755,64 @@ func (c Component) AggregateSyncCommitteeSelections(ctx context.Context, partial
 }
 
 func (c Component) ProposerDuties(ctx context.Context, epoch eth2p0.Epoch, validatorIndices []eth2p0.ValidatorIndex) ([]*eth2v1.ProposerDuty, error) {
-	duties, err := c.eth2Cl.ProposerDuties(ctx, epoch, validatorIndices)
+	duties, err := withRateLimitRetry(ctx, "proposer_duties", func() ([]*eth2v1.ProposerDuty, error) {
+		return c.eth2Cl.ProposerDuties(ctx, epoch, validatorIndices)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	// Replace root public keys with public shares
 	for i := 0; i < len(duties); i++ {
-		pubshare, ok := c.getPubShareFunc(duties[i].PubKey)
+		pubshare, ok := c.getPubShareFunc(duties[i].PubKey, epoch)
 		if !ok {
 			// Ignore unknown validators since ProposerDuties returns ALL proposers for the epoch if validatorIndices is empty.
 			continue
 		}
+		// Report randao readiness using the root public key before it is replaced.
+		c.reportRandaoReady(duties[i].Slot, duties[i].PubKey)
+
 		duties[i].PubKey = pubshare
 	}
 
 	return duties, nil
 }
 
+// reportRandaoReady sets the proposer randao ready metric for the slot depending on whether
+// the aggregated randao reveal of the validator is already available in the aggSigDB.
+// The aggSigDB is probed in the background, so the proposer duties query isn't delayed.
+func (c Component) reportRandaoReady(slot eth2p0.Slot, pubkey eth2p0.BLSPubKey) {
+	if c.awaitAggSigDBFunc == nil {
+		return
+	}
+
+	c.bg.Go(func(ctx context.Context) {
+		// Only probe the aggSigDB, don't block waiting for the randao.
+		ctx, cancel := context.WithTimeout(ctx, randaoProbeTimeout)
+		defer cancel()
+
+		var ready float64
+		if _, err := c.awaitAggSigDBFunc(ctx, core.NewRandaoDuty(int64(slot)), core.PubKeyFrom48Bytes(pubkey)); err == nil {
+			ready = 1
+		}
+
+		c.slotGauges.Set(vapiProposerRandaoReady, slot, ready)
+	})
+}
+
 func (c Component) AttesterDuties(ctx context.Context, epoch eth2p0.Epoch, validatorIndices []eth2p0.ValidatorIndex) ([]*eth2v1.AttesterDuty, error) {
-	duties, err := c.eth2Cl.AttesterDuties(ctx, epoch, validatorIndices)
+	duties, err := withRateLimitRetry(ctx, "attester_duties", func() ([]*eth2v1.AttesterDuty, error) {
+		return c.eth2Cl.AttesterDuties(ctx, epoch, validatorIndices)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	// Replace root public keys with public shares.
 	for i := 0; i < len(duties); i++ {
-		pubshare, ok := c.getPubShareFunc(duties[i].PubKey)
+		pubshare, ok := c.getPubShareFunc(duties[i].PubKey, epoch)
 		if !ok {
-			return nil, errors.New("pubshare not found")
+			return nil, errors.New("pubshare not found", eth2PubkeyField("pubkey", duties[i].PubKey))
 		}
 		duties[i].PubKey = pubshare
 	}
@@ -952,16 +1821,18 @@ func (c Component) AttesterDuties(ctx context.Context, epoch eth2p0.Epoch, valid
 }
 
 func (c Component) SyncCommitteeDuties(ctx context.Context, epoch eth2p0.Epoch, validatorIndices []eth2p0.ValidatorIndex) ([]*eth2v1.SyncCommitteeDuty, error) {
-	duties, err := c.eth2Cl.SyncCommitteeDuties(ctx, epoch, validatorIndices)
+	duties, err := withRateLimitRetry(ctx, "sync_committee_duties", func() ([]*eth2v1.SyncCommitteeDuty, error) {
+		return c.eth2Cl.SyncCommitteeDuties(ctx, epoch, validatorIndices)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	// Replace root public keys with public shares.
 	for i := 0; i < len(duties); i++ {
-		pubshare, ok := c.getPubShareFunc(duties[i].PubKey)
+		pubshare, ok := c.getPubShareFunc(duties[i].PubKey, epoch)
 		if !ok {
-			return nil, errors.New("pubshare not found")
+			return nil, errors.New("pubshare not found", eth2PubkeyField("pubkey", duties[i].PubKey))
 		}
 		duties[i].PubKey = pubshare
 	}
@@ -969,15 +1840,41 @@ func (c Component) SyncCommitteeDuties(ctx context.Context, epoch eth2p0.Epoch,
 	return duties, nil
 }
 
+// Validators returns the validators with the provided indices at the provided state, e.g. "head", "finalized",
+// "justified" or a slot. Validators that did not yet exist at the state are not included in the response.
+// If the beacon node returns a partial result with an error, the resolved validators are returned
+// and the failed indices are logged, so the validator client gets data for the healthy subset.
+// Complete responses are cached by state and indices, see validatorsCache.
 func (c Component) Validators(ctx context.Context, stateID string, validatorIndices []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
-	vals, err := c.eth2Cl.Validators(ctx, stateID, validatorIndices)
-	if err != nil {
+	key := validatorsKey{StateID: stateID, Query: indicesQuery(validatorIndices)}
+	if cached, ok := c.cachedValidators(key); ok {
+		return cached, nil
+	}
+
+	vals, err := withRateLimitRetry(ctx, "validators", func() (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+		return c.eth2Cl.Validators(ctx, stateID, validatorIndices)
+	})
+	if err != nil && len(vals) == 0 {
 		return nil, err
+	} else if err != nil {
+		var failed []eth2p0.ValidatorIndex
+		for _, vIdx := range validatorIndices {
+			if _, ok := vals[vIdx]; !ok {
+				failed = append(failed, vIdx)
+			}
+		}
+
+		log.Warn(ctx, "Beacon node returned partial validators, returning resolved subset", err,
+			z.Any("failed_indices", failed), z.Int("resolved", len(vals)))
+
+		return c.convertValidators(ctx, vals) // Do not cache partial responses.
 	}
 
-	return c.convertValidators(vals)
+	return c.convertAndCacheValidators(ctx, key, vals)
 }
 
+// ValidatorsByPubKey returns the validators of the public shares at the state, with public keys rewritten to
+// this node's public shares. Responses are cached by state and public shares, see validatorsCache.
 func (c Component) ValidatorsByPubKey(ctx context.Context, stateID string, pubshares []eth2p0.BLSPubKey) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
 	// Map from public shares to public keys before querying the beacon node.
 	var pubkeys []eth2p0.BLSPubKey
@@ -990,13 +1887,136 @@ func (c Component) ValidatorsByPubKey(ctx context.Context, stateID string, pubsh
 		pubkeys = append(pubkeys, pubkey)
 	}
 
-	valMap, err := c.eth2Cl.ValidatorsByPubKey(ctx, stateID, pubkeys)
+	key := validatorsKey{StateID: stateID, Query: pubsharesQuery(pubshares)}
+	if cached, ok := c.cachedValidators(key); ok {
+		return cached, nil
+	}
+
+	valMap, err := withRateLimitRetry(ctx, "validators_by_pubkey", func() (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+		return c.eth2Cl.ValidatorsByPubKey(ctx, stateID, pubkeys)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	// Then convert back.
-	return c.convertValidators(valMap)
+	return c.convertAndCacheValidators(ctx, key, valMap)
+}
+
+// cachedValidators returns the cached converted validators of the key and true or false if not cached.
+func (c Component) cachedValidators(key validatorsKey) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, bool) {
+	if c.validators == nil {
+		return nil, false
+	}
+
+	return c.validators.Get(key)
+}
+
+// convertAndCacheValidators converts the beacon node validators to public shares and caches the result by key.
+func (c Component) convertAndCacheValidators(ctx context.Context, key validatorsKey, vals map[eth2p0.ValidatorIndex]*eth2v1.Validator) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+	resp, err := c.convertValidators(ctx, vals)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.validators != nil {
+		c.validators.Set(key, resp)
+	}
+
+	return resp, nil
+}
+
+// ValidatorBalances returns the balances of the validators served by this cluster for the given state.
+// Balances of any other validators are filtered out, including when no indices are provided.
+func (c Component) ValidatorBalances(ctx context.Context, stateID string, validatorIndices []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]eth2p0.Gwei, error) {
+	if c.insecureTest {
+		return c.eth2Cl.ValidatorBalances(ctx, stateID, validatorIndices)
+	}
+
+	served, err := c.servedIndices(ctx, stateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var indices []eth2p0.ValidatorIndex
+	if len(validatorIndices) == 0 {
+		for vIdx := range served {
+			indices = append(indices, vIdx)
+		}
+	} else {
+		for _, vIdx := range validatorIndices {
+			if served[vIdx] {
+				indices = append(indices, vIdx)
+			}
+		}
+	}
+
+	resp := make(map[eth2p0.ValidatorIndex]eth2p0.Gwei)
+	if len(indices) == 0 {
+		return resp, nil // Do not query the beacon node without indices, since that returns all balances.
+	}
+
+	balances, err := c.eth2Cl.ValidatorBalances(ctx, stateID, indices)
+	if err != nil {
+		return nil, err
+	}
+
+	for vIdx, balance := range balances {
+		if served[vIdx] {
+			resp[vIdx] = balance
+		}
+	}
+
+	return resp, nil
+}
+
+// IndexFromPubKey returns the validator index of the DV root public key and true or false if the validator
+// is pending (not yet assigned an index) or not served by this cluster. The mapping is cached per epoch.
+func (c Component) IndexFromPubKey(ctx context.Context, pubkey core.PubKey) (eth2p0.ValidatorIndex, bool, error) {
+	if c.valIndices == nil {
+		return 0, false, errors.New("validator index cache not supported")
+	}
+
+	return c.valIndices.IndexFromPubKey(ctx, pubkey)
+}
+
+// PubKeyFromIndex returns the DV root public key of the validator index and true or false
+// if the validator index is not served by this cluster. The mapping is cached per epoch.
+func (c Component) PubKeyFromIndex(ctx context.Context, vIdx eth2p0.ValidatorIndex) (core.PubKey, bool, error) {
+	if c.valIndices == nil {
+		return "", false, errors.New("validator index cache not supported")
+	}
+
+	return c.valIndices.PubKeyFromIndex(ctx, vIdx)
+}
+
+// servedIndices returns the set of validator indices of the validators served by this cluster.
+func (c Component) servedIndices(ctx context.Context, stateID string) (map[eth2p0.ValidatorIndex]bool, error) {
+	var pubkeys []eth2p0.BLSPubKey
+	for corePubkey := range c.sharesByKey {
+		pubkey, err := corePubkey.ToETH2()
+		if err != nil {
+			return nil, err
+		}
+
+		pubkeys = append(pubkeys, pubkey)
+	}
+
+	resp := make(map[eth2p0.ValidatorIndex]bool)
+	if len(pubkeys) == 0 {
+		return resp, nil
+	}
+
+	vals, err := c.eth2Cl.ValidatorsByPubKey(ctx, stateID, pubkeys)
+	if err != nil {
+		return nil, err
+	}
+
+	for vIdx := range vals {
+		resp[vIdx] = true
+	}
+
+	return resp, nil
 }
 
 // NodeVersion returns the current version of charon.
@@ -1006,21 +2026,65 @@ func (Component) NodeVersion(context.Context) (string, error) {
 	return fmt.Sprintf("obolnetwork/charon/%s-%s/%s-%s", version.Version, commitSHA, runtime.GOARCH, runtime.GOOS), nil
 }
 
-// convertValidators returns the validator map with all root public keys replaced by public shares.
-func (c Component) convertValidators(vals map[eth2p0.ValidatorIndex]*eth2v1.Validator) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+// convertValidators returns copies of the validators with root public keys replaced by public shares valid at the current epoch.
+// The mapping is by public key, so it is independent of the state the validators were queried at.
+// Validators that do not exist at the queried state (nil entries) are omitted.
+func (c Component) convertValidators(ctx context.Context, vals map[eth2p0.ValidatorIndex]*eth2v1.Validator) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+	epoch, err := c.currentEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	resp := make(map[eth2p0.ValidatorIndex]*eth2v1.Validator)
 	for vIdx, val := range vals {
-		var ok bool
-		val.Validator.PublicKey, ok = c.getPubShareFunc(val.Validator.PublicKey)
+		if val == nil || val.Validator == nil {
+			continue
+		}
+
+		pubshare, ok := c.getPubShareFunc(val.Validator.PublicKey, epoch)
 		if !ok {
-			return nil, errors.New("pubshare not found")
+			return nil, errors.New("pubshare not found", z.U64("validator_index", uint64(vIdx)))
 		}
-		resp[vIdx] = val
+
+		// Copy the validator to avoid mutating the beacon node response.
+		validator := *val.Validator
+		validator.PublicKey = pubshare
+		converted := *val
+		converted.Validator = &validator
+
+		resp[vIdx] = &converted
 	}
 
 	return resp, nil
 }
 
+// currentSlot returns the current slot computed from the clock and the beacon node genesis time.
+func (c Component) currentSlot(ctx context.Context) (eth2p0.Slot, error) {
+	return c.slotFromTimestamp(ctx, c.clock.Now())
+}
+
+// currentEpoch returns the current epoch computed from the clock and the beacon node genesis time, zero before genesis.
+func (c Component) currentEpoch(ctx context.Context) (eth2p0.Epoch, error) {
+	genesis, err := c.eth2Cl.GenesisTime(ctx)
+	if err != nil {
+		return 0, err
+	} else if c.clock.Now().Before(genesis) {
+		return 0, nil
+	}
+
+	slot, err := c.currentSlot(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	p, err := c.presets.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return p.EpochFromSlot(slot), nil
+}
+
 func (c Component) slotFromTimestamp(ctx context.Context, timestamp time.Time) (eth2p0.Slot, error) {
 	genesis, err := c.eth2Cl.GenesisTime(ctx)
 	if err != nil {
@@ -1039,6 +2103,39 @@ func (c Component) slotFromTimestamp(ctx context.Context, timestamp time.Time) (
 	return eth2p0.Slot(delta / slotDuration), nil
 }
 
+// classifyStoreErr returns the store error converted to an API error based on its class.
+func (c Component) classifyStoreErr(err error) error {
+	if c.storeErrClassifier == nil {
+		return err
+	}
+
+	switch c.storeErrClassifier(err) {
+	case StoreErrTransient:
+		return apiError{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "temporary failure storing partial signature, please retry",
+			Err:        err,
+		}
+	case StoreErrPermanent:
+		return apiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "invalid partial signature",
+			Err:        err,
+		}
+	default:
+		return err
+	}
+}
+
+// shareIdxByPubKey returns this node's share index for the provided DV root public key.
+func (c Component) shareIdxByPubKey(pubkey core.PubKey) int {
+	if shareIdx, ok := c.shareIdxByKey[pubkey]; ok {
+		return shareIdx
+	}
+
+	return c.shareIdx
+}
+
 func (c Component) getProposerPubkey(ctx context.Context, duty core.Duty) (core.PubKey, error) {
 	// Get proposer pubkey (this is a blocking query).
 	defSet, err := c.dutyDefFunc(ctx, duty)
@@ -1057,14 +2154,32 @@ func (c Component) getProposerPubkey(ctx context.Context, duty core.Duty) (core.
 	return pubkey, nil
 }
 
-func (c Component) verifyPartialSig(ctx context.Context, parSig core.ParSignedData, pubkey core.PubKey) error {
-	if c.insecureTest {
-		return nil
+func (c Component) verifyPartialSig(ctx context.Context, duty core.Duty, parSig core.ParSignedData, pubkey core.PubKey) error {
+	return c.verifyPartialSigFunc(ctx, duty, parSig, pubkey, c.verifyEth2SignedData)
+}
+
+// verifyEth2SignedData returns an error if the eth2 signed data signature doesn't match the public share.
+func (c Component) verifyEth2SignedData(ctx context.Context, eth2Signed core.Eth2SignedData, pubshare tblsv2.PublicKey) error {
+	if randao, ok := eth2Signed.(core.SignedRandao); ok && c.randaoRoots != nil {
+		return c.randaoRoots.verifyRandao(ctx, randao, pubshare)
 	}
 
-	pubshare, err := c.getVerifyShareFunc(pubkey)
-	if err != nil {
+	if domain, ok, err := c.genesis.LocalDomain(eth2Signed.DomainName()); err != nil {
 		return err
+	} else if ok {
+		return verifyWithLocalDomain(eth2Signed, domain, pubshare)
+	}
+
+	return core.VerifyEth2SignedData(ctx, c.eth2Cl, eth2Signed, pubshare)
+}
+
+// verifyPartialSigFunc verifies the partial signature of the duty against the public share of the public key
+// valid at the duty's epoch using the verify function.
+func (c Component) verifyPartialSigFunc(ctx context.Context, duty core.Duty, parSig core.ParSignedData, pubkey core.PubKey,
+	verifyFunc func(context.Context, core.Eth2SignedData, tblsv2.PublicKey) error,
+) error {
+	if c.insecureTest {
+		return nil
 	}
 
 	eth2Signed, ok := parSig.SignedData.(core.Eth2SignedData)
@@ -1072,13 +2187,139 @@ func (c Component) verifyPartialSig(ctx context.Context, parSig core.ParSignedDa
 		return errors.New("invalid eth2 signed data")
 	}
 
-	return core.VerifyEth2SignedData(ctx, c.eth2Cl, eth2Signed, pubshare)
+	release, err := c.verifyPools[verifyClassOf(eth2Signed.DomainName())].Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	p, err := c.presets.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	pubshare, err := c.getVerifyShareFunc(pubkey, p.EpochFromSlot(eth2p0.Slot(duty.Slot)))
+	if err != nil {
+		return err
+	}
+
+	if err := verifyFunc(ctx, eth2Signed, pubshare); err != nil {
+		domain := string(eth2Signed.DomainName())
+		if !c.verifyReportOnly {
+			parSigVerifyFailures.WithLabelValues(domain).Inc()
+		}
+
+		fields := []z.Field{pubkeyField("pubkey", pubkey), z.Str("domain", domain)}
+		if !c.redactSigs {
+			if sigRoot, err := eth2Signed.MessageRoot(); err == nil {
+				fields = append(fields, z.Hex("sig_root", sigRoot[:]))
+			}
+			fields = append(fields, z.Hex("signature", eth2Signed.Signature()))
+		}
+
+		err = errors.Wrap(err, "verify partial signature", fields...)
+		if c.verifyReportOnly {
+			vapiVerifyWouldReject.WithLabelValues(domain).Inc()
+			log.Warn(ctx, "Partial signature verification failed, not rejecting in report-only mode", err)
+
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// partialSig is a partial signature of a duty by the public share of a validator.
+type partialSig struct {
+	Duty   core.Duty
+	ParSig core.ParSignedData
+	Pubkey core.PubKey
+}
+
+// verifyPartialSigBatch verifies the partial signatures at once against the public shares valid at their duty's epoch
+// and their cached signing data. It returns an error if any signature is invalid, without reporting or identifying it,
+// so callers must verify each signature via verifyPartialSigFunc on error.
+func (c Component) verifyPartialSigBatch(ctx context.Context, batch []partialSig, signingData *signingDataCache) error {
+	if c.insecureTest || len(batch) == 0 {
+		return nil
+	}
+
+	p, err := c.presets.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		datas     []core.Eth2SignedData
+		pubshares []tblsv2.PublicKey
+	)
+	for _, sig := range batch {
+		eth2Signed, ok := sig.ParSig.SignedData.(core.Eth2SignedData)
+		if !ok {
+			return errors.New("invalid eth2 signed data")
+		}
+
+		pubshare, err := c.getVerifyShareFunc(sig.Pubkey, p.EpochFromSlot(eth2p0.Slot(sig.Duty.Slot)))
+		if err != nil {
+			return err
+		}
+
+		datas = append(datas, eth2Signed)
+		pubshares = append(pubshares, pubshare)
+	}
+
+	release, err := c.verifyPools[verifyClassOf(datas[0].DomainName())].Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return signingData.VerifyBatch(ctx, datas, pubshares)
+}
+
+// conflictError returns the partial signed data conflict error as a bad request API error.
+func conflictError(err error) error {
+	return apiError{
+		StatusCode: http.StatusBadRequest,
+		Message:    "conflicting partial signed data",
+		Err:        err,
+	}
+}
+
+// verifyAttIndices returns an error if the attestation committee index or validator committee index
+// exceeds the bounds of the active preset.
+func verifyAttIndices(p preset, commIdx eth2p0.CommitteeIndex, valCommIdx int) error {
+	if uint64(commIdx) >= p.MaxCommitteesPerSlot {
+		invalidAttIndices.WithLabelValues("committee").Inc()
+
+		return apiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "attestation committee index out of range",
+			Err: errors.New("attestation committee index out of range",
+				z.U64("committee_index", uint64(commIdx)), z.U64("max", p.MaxCommitteesPerSlot), z.Str("preset", p.Name)),
+		}
+	}
+
+	if uint64(valCommIdx) >= p.MaxValidatorsPerCommittee {
+		invalidAttIndices.WithLabelValues("validator_committee").Inc()
+
+		return apiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "attestation validator committee index out of range",
+			Err: errors.New("attestation validator committee index out of range",
+				z.Int("validator_committee_index", valCommIdx), z.U64("max", p.MaxValidatorsPerCommittee), z.Str("preset", p.Name)),
+		}
+	}
+
+	return nil
 }
 
-func (c Component) getAggregateBeaconCommSelection(ctx context.Context, psigsBySlot map[eth2p0.Slot]core.ParSignedDataSet) ([]*eth2exp.BeaconCommitteeSelection, error) {
+func (c Component) getAggregateBeaconCommSelection(ctx context.Context, psigsBySlot core.ParSignedDataSetsBySlot) ([]*eth2exp.BeaconCommitteeSelection, error) {
 	var resp []*eth2exp.BeaconCommitteeSelection
 	for slot, data := range psigsBySlot {
-		duty := core.NewPrepareAggregatorDuty(int64(slot))
+		duty := core.NewPrepareAggregatorDuty(slot)
 		for pk := range data {
 			// Query aggregated subscription from aggsigdb for each duty and public key (this is blocking).
 			s, err := c.awaitAggSigDBFunc(ctx, duty, pk)
@@ -1098,10 +2339,10 @@ func (c Component) getAggregateBeaconCommSelection(ctx context.Context, psigsByS
 	return resp, nil
 }
 
-func (c Component) getAggregateSyncCommSelection(ctx context.Context, psigsBySlot map[eth2p0.Slot]core.ParSignedDataSet) ([]*eth2exp.SyncCommitteeSelection, error) {
+func (c Component) getAggregateSyncCommSelection(ctx context.Context, psigsBySlot core.ParSignedDataSetsBySlot) ([]*eth2exp.SyncCommitteeSelection, error) {
 	var resp []*eth2exp.SyncCommitteeSelection
 	for slot, data := range psigsBySlot {
-		duty := core.NewPrepareSyncContributionDuty(int64(slot))
+		duty := core.NewPrepareSyncContributionDuty(slot)
 		for pk := range data {
 			// Query aggregated sync committee selection from aggsigdb for each duty and public key (this is blocking).
 			s, err := c.awaitAggSigDBFunc(ctx, duty, pk)
@@ -1120,3 +2361,135 @@ func (c Component) getAggregateSyncCommSelection(ctx context.Context, psigsBySlo
 
 	return resp, nil
 }
+
+// attDataKey is a comparable representation of attestation data.
+type attDataKey struct {
+	Slot            eth2p0.Slot
+	Index           eth2p0.CommitteeIndex
+	BeaconBlockRoot eth2p0.Root
+	Source          eth2p0.Checkpoint
+	Target          eth2p0.Checkpoint
+}
+
+// newAttDataRootFunc returns a function that returns attestation data hash tree roots
+// while memoizing the roots of identical attestation data.
+func newAttDataRootFunc() func(*eth2p0.AttestationData) (eth2p0.Root, error) {
+	roots := make(map[attDataKey]eth2p0.Root)
+
+	return func(data *eth2p0.AttestationData) (eth2p0.Root, error) {
+		if data.Source == nil || data.Target == nil {
+			// Do not memoize invalid data.
+			root, err := data.HashTreeRoot()
+			if err != nil {
+				return eth2p0.Root{}, errors.Wrap(err, "hash attestation data")
+			}
+
+			return root, nil
+		}
+
+		key := attDataKey{
+			Slot:            data.Slot,
+			Index:           data.Index,
+			BeaconBlockRoot: data.BeaconBlockRoot,
+			Source:          *data.Source,
+			Target:          *data.Target,
+		}
+
+		if root, ok := roots[key]; ok {
+			return root, nil
+		}
+
+		root, err := data.HashTreeRoot()
+		if err != nil {
+			return eth2p0.Root{}, errors.Wrap(err, "hash attestation data")
+		}
+
+		roots[key] = root
+
+		return root, nil
+	}
+}
+
+// rootedEth2SignedData wraps eth2 signed data returning a precomputed message root.
+type rootedEth2SignedData struct {
+	core.Eth2SignedData
+	root eth2p0.Root
+}
+
+func (d rootedEth2SignedData) MessageRoot() ([32]byte, error) {
+	return d.root, nil
+}
+
+// withMessageRoot returns a copy of the partial signed data with a precomputed message root.
+// It must only be used for verification, not for storing.
+func withMessageRoot(parSig core.ParSignedData, root eth2p0.Root) core.ParSignedData {
+	eth2Signed, ok := parSig.SignedData.(core.Eth2SignedData)
+	if !ok {
+		return parSig
+	}
+
+	return core.ParSignedData{
+		SignedData: rootedEth2SignedData{Eth2SignedData: eth2Signed, root: root},
+		ShareIdx:   parSig.ShareIdx,
+	}
+}
+
+// background manages the lifecycle of component background goroutines.
+type background struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newBackground() *background {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &background{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Go calls the function in a new goroutine with a context that is cancelled on Close.
+// The function is not called and false is returned if the background is already closed.
+func (b *background) Go(fn func(ctx context.Context)) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return false
+	}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		fn(b.ctx)
+	}()
+
+	return true
+}
+
+// Close cancels all goroutines and blocks until they exit or the context is closed.
+func (b *background) Close(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	b.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "background goroutines did not exit")
+	case <-done:
+		return nil
+	}
+}
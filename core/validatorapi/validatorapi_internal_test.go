@@ -3,12 +3,32 @@
 package validatorapi
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	eth2v1 "github.com/attestantio/go-eth2-client/api/v1"
 	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap/zapcore"
 
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/app/log"
+	"github.com/obolnetwork/charon/app/promauto"
 	"github.com/obolnetwork/charon/core"
+	"github.com/obolnetwork/charon/eth2util"
+	"github.com/obolnetwork/charon/eth2util/signing"
 	tblsv2 "github.com/obolnetwork/charon/tbls/v2"
 	tblsconv2 "github.com/obolnetwork/charon/tbls/v2/tblsconv"
 	"github.com/obolnetwork/charon/testutil"
@@ -73,3 +93,1019 @@ func TestMismatchKeysFunc(t *testing.T) {
 		require.ErrorContains(t, err, "unknown public key")
 	})
 }
+
+func TestComponentClose(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	vapi, err := NewComponentInsecure(t, nil, 0)
+	require.NoError(t, err)
+
+	const n = 3
+	started := make(chan struct{}, n)
+	for i := 0; i < n; i++ {
+		vapi.bg.Go(func(ctx context.Context) {
+			started <- struct{}{}
+			<-ctx.Done()
+		})
+	}
+
+	for i := 0; i < n; i++ {
+		<-started
+	}
+
+	require.NoError(t, vapi.Close(context.Background()))
+
+	// Background functions are not started after close.
+	vapi.bg.Go(func(context.Context) {
+		require.Fail(t, "unexpected call after close")
+	})
+}
+
+func TestTrimState(t *testing.T) {
+	ctx := context.Background()
+
+	vapi, err := NewComponentInsecure(t, nil, 0)
+	require.NoError(t, err)
+
+	protector := NewMemSlashingProtector()
+	vapi.RegisterSlashingProtector(protector)
+
+	const slotsPerEpoch = 4
+	pubkey := testutil.RandomCorePubKey(t)
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_slot_gauge"}, []string{"slot"})
+
+	// attData returns attestation data voting for the epoch.
+	attData := func(epoch eth2p0.Epoch) *eth2p0.AttestationData {
+		return &eth2p0.AttestationData{
+			Slot:   eth2p0.Slot(epoch * slotsPerEpoch),
+			Source: &eth2p0.Checkpoint{Epoch: epoch - 1},
+			Target: &eth2p0.Checkpoint{Epoch: epoch},
+		}
+	}
+
+	// Advance slots, storing per-slot state and trimming state older than two epochs.
+	for slot := eth2p0.Slot(0); slot < 10*slotsPerEpoch; slot++ {
+		epoch := eth2p0.Epoch(slot / slotsPerEpoch)
+		vapi.slotGauges.Set(gauge, slot, 1)
+
+		if slot%slotsPerEpoch == 0 {
+			require.NoError(t, protector.RecordAttestation(ctx, pubkey, attData(epoch+1)))
+		}
+
+		if epoch >= 2 {
+			vapi.trimState(eth2p0.Slot((epoch-2)*slotsPerEpoch), epoch-2)
+		}
+	}
+
+	// Only the slots of the last three epochs are retained.
+	require.Equal(t, 3*slotsPerEpoch, vapi.slotGauges.Len())
+	require.Equal(t, 3*slotsPerEpoch, promtestutil.CollectAndCount(gauge))
+
+	protector.mu.Lock()
+	require.Len(t, protector.records[pubkey], 4) // Target epochs 7 to 10.
+	protector.mu.Unlock()
+
+	// Attestations conflicting with trimmed records are rejected.
+	err = protector.CheckAttestation(ctx, pubkey, attData(5))
+	require.ErrorContains(t, err, "attestation conflicts with trimmed slashing history")
+
+	// The number of tracked slots is bounded.
+	for slot := eth2p0.Slot(0); slot < maxTrackedSlots+10; slot++ {
+		vapi.slotGauges.Set(gauge, slot+1000, 1)
+	}
+	require.Equal(t, maxTrackedSlots, vapi.slotGauges.Len())
+	require.Equal(t, maxTrackedSlots, promtestutil.CollectAndCount(gauge))
+}
+
+func BenchmarkAttDataRoot(b *testing.B) {
+	const numAtts = 64
+
+	// All attestations in the same committee share identical attestation data.
+	var atts []*eth2p0.Attestation
+	for i := 0; i < numAtts; i++ {
+		atts = append(atts, &eth2p0.Attestation{
+			Data: &eth2p0.AttestationData{
+				Slot:            99,
+				Index:           1,
+				BeaconBlockRoot: eth2p0.Root{1},
+				Source:          &eth2p0.Checkpoint{Epoch: 2, Root: eth2p0.Root{2}},
+				Target:          &eth2p0.Checkpoint{Epoch: 3, Root: eth2p0.Root{3}},
+			},
+		})
+	}
+
+	b.Run("unmemoized", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for _, att := range atts {
+				_, err := att.Data.HashTreeRoot()
+				require.NoError(b, err)
+			}
+		}
+	})
+
+	b.Run("memoized", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			attDataRoot := newAttDataRootFunc() // New function per SubmitAttestations call.
+			for _, att := range atts {
+				_, err := attDataRoot(att.Data)
+				require.NoError(b, err)
+			}
+		}
+	})
+}
+
+func BenchmarkAttSigningData(b *testing.B) {
+	const (
+		numAtts = 64
+		epoch   = 3
+	)
+
+	ctx := context.Background()
+	eth2Cl := domainClient{}
+
+	// All attestations of the batch share one epoch, but differ in committee and therefore root.
+	var roots []eth2p0.Root
+	for i := 0; i < numAtts; i++ {
+		roots = append(roots, eth2p0.Root{byte(i % 4)})
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for _, root := range roots {
+				_, err := signing.GetDataRoot(ctx, eth2Cl, signing.DomainBeaconAttester, epoch, root)
+				require.NoError(b, err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			cache := newSigningDataCache(eth2Cl, newDomainCache(), nil) // New cache per SubmitAttestations call.
+			for _, root := range roots {
+				_, err := cache.SigningData(ctx, signing.DomainBeaconAttester, epoch, root)
+				require.NoError(b, err)
+			}
+		}
+	})
+}
+
+func BenchmarkAttVerifyBatch(b *testing.B) {
+	const numAtts = 64
+
+	ctx := context.Background()
+	eth2Cl := domainClient{}
+
+	var (
+		datas     []core.Eth2SignedData
+		pubshares []tblsv2.PublicKey
+	)
+	for i := 0; i < numAtts; i++ {
+		secret, err := tblsv2.GenerateSecretKey()
+		require.NoError(b, err)
+		pubshare, err := tblsv2.SecretToPublicKey(secret)
+		require.NoError(b, err)
+
+		att := testutil.RandomAttestation()
+		att.Data.Index = eth2p0.CommitteeIndex(i % 4)
+		root, err := att.Data.HashTreeRoot()
+		require.NoError(b, err)
+		msg, err := newSigningDataCache(eth2Cl, newDomainCache(), nil).SigningData(ctx, signing.DomainBeaconAttester, att.Data.Target.Epoch, root)
+		require.NoError(b, err)
+		sig, err := tblsv2.Sign(secret, msg[:])
+		require.NoError(b, err)
+		att.Signature = eth2p0.BLSSignature(sig)
+
+		datas = append(datas, core.NewAttestation(att))
+		pubshares = append(pubshares, pubshare)
+	}
+
+	b.Run("individual", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			cache := newSigningDataCache(eth2Cl, newDomainCache(), nil)
+			for i, data := range datas {
+				require.NoError(b, cache.Verify(ctx, data, pubshares[i]))
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			require.NoError(b, newSigningDataCache(eth2Cl, newDomainCache(), nil).VerifyBatch(ctx, datas, pubshares))
+		}
+	})
+}
+
+// domainClient is an eth2wrap.Client that computes signing domains from a fixed spec and fork version.
+type domainClient struct {
+	eth2wrap.Client
+}
+
+func (domainClient) Spec(context.Context) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		string(signing.DomainBeaconAttester): eth2p0.DomainType{1},
+	}, nil
+}
+
+func (domainClient) Domain(_ context.Context, domainType eth2p0.DomainType, _ eth2p0.Epoch) (eth2p0.Domain, error) {
+	root, err := (&eth2p0.ForkData{CurrentVersion: eth2p0.Version{1}}).HashTreeRoot()
+	if err != nil {
+		return eth2p0.Domain{}, err
+	}
+
+	var domain eth2p0.Domain
+	copy(domain[:], domainType[:])
+	copy(domain[4:], root[:])
+
+	return domain, nil
+}
+
+func BenchmarkRandaoSigningRoot(b *testing.B) {
+	const (
+		numProposers = 64
+		epoch        = 3
+	)
+
+	domain := eth2p0.Domain{1}
+
+	// signingRoot computes the domain-wrapped randao signing root of the epoch.
+	signingRoot := func(_ context.Context, epoch eth2p0.Epoch) ([32]byte, error) {
+		root, err := eth2util.SignedEpoch{Epoch: epoch}.HashTreeRoot()
+		if err != nil {
+			return [32]byte{}, err
+		}
+
+		return (&eth2p0.SigningData{ObjectRoot: root, Domain: domain}).HashTreeRoot()
+	}
+
+	ctx := context.Background()
+
+	b.Run("uncached", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for i := 0; i < numProposers; i++ {
+				_, err := signingRoot(ctx, epoch)
+				require.NoError(b, err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		cache := newRandaoRootCache(signingRoot)
+		for n := 0; n < b.N; n++ {
+			for i := 0; i < numProposers; i++ {
+				_, err := cache.SigningRoot(ctx, epoch)
+				require.NoError(b, err)
+			}
+		}
+	})
+}
+
+func TestStoreErrClassifier(t *testing.T) {
+	var (
+		errTransient = errors.New("transient")
+		errPermanent = errors.New("permanent")
+		errOther     = errors.New("other")
+	)
+
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{name: "transient", err: errTransient, statusCode: http.StatusServiceUnavailable},
+		{name: "permanent", err: errPermanent, statusCode: http.StatusBadRequest},
+		{name: "unknown", err: errOther},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vapi, err := NewComponentInsecure(t, nil, 0)
+			require.NoError(t, err)
+
+			vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+				return testutil.RandomCorePubKey(t), nil
+			})
+			vapi.Subscribe(func(context.Context, core.Duty, core.ParSignedDataSet) error {
+				return test.err
+			})
+			vapi.RegisterStoreErrClassifier(func(err error) StoreErrClass {
+				switch {
+				case errors.Is(err, errTransient):
+					return StoreErrTransient
+				case errors.Is(err, errPermanent):
+					return StoreErrPermanent
+				default:
+					return StoreErrUnknown
+				}
+			})
+
+			aggBits := bitfield.NewBitlist(8)
+			aggBits.SetBitAt(1, true)
+			att := &eth2p0.Attestation{
+				AggregationBits: aggBits,
+				Data: &eth2p0.AttestationData{
+					Source: &eth2p0.Checkpoint{},
+					Target: &eth2p0.Checkpoint{},
+				},
+			}
+
+			err = vapi.SubmitAttestations(context.Background(), []*eth2p0.Attestation{att})
+			require.Error(t, err)
+
+			var aerr apiError
+			if test.statusCode == 0 {
+				require.False(t, errors.As(err, &aerr))
+				require.ErrorIs(t, err, test.err)
+
+				return
+			}
+
+			require.True(t, errors.As(err, &aerr))
+			require.Equal(t, test.statusCode, aerr.StatusCode)
+			require.ErrorIs(t, aerr.Err, test.err)
+		})
+	}
+}
+
+func TestAwaitTimeout(t *testing.T) {
+	vapi, err := NewComponentInsecure(t, nil, 0)
+	require.NoError(t, err)
+
+	vapi.SetAwaitTimeout(time.Millisecond * 10)
+	vapi.RegisterAwaitAttestation(func(ctx context.Context, _, _ int64) (*eth2p0.AttestationData, error) {
+		<-ctx.Done() // Never returns before the context is closed.
+		return nil, ctx.Err()
+	})
+
+	_, err = vapi.AttestationData(context.Background(), 1, 2)
+	require.Error(t, err)
+
+	var aerr apiError
+	require.True(t, errors.As(err, &aerr))
+	require.Equal(t, http.StatusGatewayTimeout, aerr.StatusCode)
+
+	// Parent context cancellation is not reported as an await timeout.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = vapi.AttestationData(ctx, 1, 2)
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, errors.As(err, &aerr))
+
+	// The blocking proposer duty definition query is also covered by the await timeout.
+	vapi.RegisterGetDutyDefinition(func(ctx context.Context, _ core.Duty) (core.DutyDefinitionSet, error) {
+		<-ctx.Done() // Never returns before the context is closed.
+		return nil, ctx.Err()
+	})
+
+	_, err = vapi.BeaconBlockProposal(context.Background(), 1, eth2p0.BLSSignature{}, nil)
+	require.True(t, errors.As(err, &aerr))
+	require.Equal(t, http.StatusGatewayTimeout, aerr.StatusCode)
+
+	_, err = vapi.BlindedBeaconBlockProposal(context.Background(), 1, eth2p0.BLSSignature{}, nil)
+	require.True(t, errors.As(err, &aerr))
+	require.Equal(t, http.StatusGatewayTimeout, aerr.StatusCode)
+}
+
+func TestPubkeyLookupMetric(t *testing.T) {
+	// sampleCount returns the number of pubkey lookup latency samples.
+	sampleCount := func(t *testing.T) uint64 {
+		t.Helper()
+
+		registry, err := promauto.NewRegistry(nil)
+		require.NoError(t, err)
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+
+		for _, family := range families {
+			if family.GetName() == "core_validatorapi_attestation_pubkey_lookup_seconds" {
+				return family.GetMetric()[0].GetHistogram().GetSampleCount()
+			}
+		}
+
+		return 0
+	}
+
+	vapi, err := NewComponentInsecure(t, nil, 0)
+	require.NoError(t, err)
+
+	vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+		return testutil.RandomCorePubKey(t), nil
+	})
+
+	var atts []*eth2p0.Attestation
+	for i := 0; i < 2; i++ {
+		aggBits := bitfield.NewBitlist(8)
+		aggBits.SetBitAt(uint64(i), true)
+		atts = append(atts, &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Source: &eth2p0.Checkpoint{},
+				Target: &eth2p0.Checkpoint{},
+			},
+		})
+	}
+
+	before := sampleCount(t)
+
+	err = vapi.SubmitAttestations(context.Background(), atts)
+	require.NoError(t, err)
+
+	require.EqualValues(t, before+uint64(len(atts)), sampleCount(t))
+}
+
+func TestValidatorSubmissionMetrics(t *testing.T) {
+	pubkey := testutil.RandomCorePubKey(t)
+	counter := vapiValidatorSubmissions.WithLabelValues(core.DutyAttester.String(), pubkey.String())
+
+	vapi, err := NewComponentInsecure(t, nil, 0)
+	require.NoError(t, err)
+
+	vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+		return pubkey, nil
+	})
+	// Multiple subscribers must not result in multiple counts.
+	for i := 0; i < 2; i++ {
+		vapi.Subscribe(func(context.Context, core.Duty, core.ParSignedDataSet) error {
+			return nil
+		})
+	}
+
+	submit := func(t *testing.T, slot eth2p0.Slot) {
+		t.Helper()
+
+		aggBits := bitfield.NewBitlist(8)
+		aggBits.SetBitAt(1, true)
+		att := &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Slot:   slot,
+				Source: &eth2p0.Checkpoint{},
+				Target: &eth2p0.Checkpoint{},
+			},
+		}
+
+		err := vapi.SubmitAttestations(context.Background(), []*eth2p0.Attestation{att})
+		require.NoError(t, err)
+	}
+
+	before := promtestutil.ToFloat64(counter)
+
+	// Disabled by default.
+	submit(t, 1)
+	require.Equal(t, before, promtestutil.ToFloat64(counter))
+
+	vapi.SetValidatorSubmissionMetrics(true)
+	submit(t, 2)
+	submit(t, 3)
+	require.Equal(t, before+2, promtestutil.ToFloat64(counter))
+}
+
+func TestPubKeyLogFormat(t *testing.T) {
+	t.Cleanup(func() {
+		SetPubKeyLogFormat(PubKeyLogAbbreviated)
+	})
+
+	pubkey := testutil.RandomCorePubKey(t)
+	b, err := pubkey.Bytes()
+	require.NoError(t, err)
+
+	hash := sha256.Sum256(b)
+
+	tests := []struct {
+		name   string
+		format PubKeyLogFormat
+		expect string
+	}{
+		{name: "abbreviated", format: PubKeyLogAbbreviated, expect: "0x" + hex.EncodeToString(b[:4])},
+		{name: "hashed", format: PubKeyLogHashed, expect: "sha256:" + hex.EncodeToString(hash[:4])},
+		{name: "full", format: PubKeyLogFull, expect: string(pubkey)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			SetPubKeyLogFormat(test.format)
+
+			var buf bytes.Buffer
+			log.InitLogfmtForT(t, zapcore.AddSync(&buf))
+
+			allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{pubkey: {1: {}}}
+			err := VerifyShareAssignment(allPubSharesByKey, 1, nil)
+			require.Error(t, err)
+
+			log.Error(context.Background(), "Share assignment", err)
+
+			require.Contains(t, buf.String(), "pubkey="+test.expect)
+			if test.format != PubKeyLogFull {
+				require.NotContains(t, buf.String(), string(pubkey))
+			}
+		})
+	}
+}
+
+func TestWarnSmallCluster(t *testing.T) {
+	// newShares returns public shares of a cluster of the number of nodes.
+	newShares := func(nodes int) map[core.PubKey]map[int]tblsv2.PublicKey {
+		shares := make(map[int]tblsv2.PublicKey)
+		for i := 1; i <= nodes; i++ {
+			shares[i] = tblsv2.PublicKey{byte(i)}
+		}
+
+		return map[core.PubKey]map[int]tblsv2.PublicKey{testutil.RandomCorePubKey(t): shares}
+	}
+
+	tests := []struct {
+		name  string
+		nodes int
+		warn  bool
+	}{
+		{name: "1-of-1", nodes: 1, warn: true},
+		{name: "3-of-4", nodes: 4, warn: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log.InitLogfmtForT(t, zapcore.AddSync(&buf))
+
+			require.Equal(t, test.warn, warnSmallCluster(context.Background(), newShares(test.nodes)))
+
+			if test.warn {
+				require.Contains(t, buf.String(), "Cluster too small to tolerate any faulty node")
+				require.Contains(t, buf.String(), "threshold=1")
+				require.EqualValues(t, 1, promtestutil.ToFloat64(vapiSmallCluster))
+			} else {
+				require.Empty(t, buf.String())
+				require.EqualValues(t, 0, promtestutil.ToFloat64(vapiSmallCluster))
+			}
+		})
+	}
+}
+
+func TestCurrentSlot(t *testing.T) {
+	const slotDuration = 12 * time.Second
+
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	genesis := clock.Now().Add(-10 * slotDuration)
+
+	vapi, err := NewComponentInsecure(t, genesisClient{genesis: genesis, slotDuration: slotDuration}, 0)
+	require.NoError(t, err)
+	vapi.SetClock(clock)
+
+	slot, err := vapi.currentSlot(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, slot)
+
+	clock.Advance(slotDuration / 2)
+	slot, err = vapi.currentSlot(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, slot)
+
+	clock.Advance(slotDuration / 2)
+	slot, err = vapi.currentSlot(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 11, slot)
+
+	// Before genesis.
+	vapi.SetClock(clockwork.NewFakeClockAt(genesis.Add(-time.Second)))
+	_, err = vapi.currentSlot(ctx)
+	require.ErrorContains(t, err, "before genesis")
+}
+
+// genesisClient is an eth2wrap.Client with a fixed genesis time and slot duration.
+type genesisClient struct {
+	eth2wrap.Client
+	genesis      time.Time
+	slotDuration time.Duration
+}
+
+func (c genesisClient) GenesisTime(context.Context) (time.Time, error) {
+	return c.genesis, nil
+}
+
+func (c genesisClient) SlotDuration(context.Context) (time.Duration, error) {
+	return c.slotDuration, nil
+}
+
+func TestEpochFromSlot(t *testing.T) {
+	ctx := context.Background()
+	eth2Cl := &specClient{slotsPerEpoch: 8}
+
+	vapi, err := NewComponentInsecure(t, eth2Cl, 0)
+	require.NoError(t, err)
+
+	for slot := eth2p0.Slot(0); slot < 20; slot++ {
+		epoch, err := vapi.epochFromSlot(ctx, slot)
+		require.NoError(t, err)
+		require.EqualValues(t, uint64(slot)/8, epoch)
+	}
+
+	slotsPerEpoch, err := vapi.slotsPerEpoch(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 8, slotsPerEpoch)
+
+	// The spec is only fetched once.
+	require.EqualValues(t, 1, eth2Cl.specCalls.Load())
+	require.EqualValues(t, 1, eth2Cl.slotsPerEpochCalls.Load())
+}
+
+// specClient is an eth2wrap.Client with a fixed spec counting spec queries.
+type specClient struct {
+	eth2wrap.Client
+	slotsPerEpoch      uint64
+	specCalls          atomic.Int64
+	slotsPerEpochCalls atomic.Int64
+}
+
+func (c *specClient) Spec(context.Context) (map[string]interface{}, error) {
+	c.specCalls.Add(1)
+	return map[string]interface{}{"PRESET_BASE": "minimal"}, nil
+}
+
+func (c *specClient) SlotsPerEpoch(context.Context) (uint64, error) {
+	c.slotsPerEpochCalls.Add(1)
+	return c.slotsPerEpoch, nil
+}
+
+func TestAttestationDataWindow(t *testing.T) {
+	const slotDuration = 12 * time.Second
+
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+	genesis := clock.Now().Add(-10 * slotDuration)
+
+	vapi, err := NewComponentInsecure(t, genesisClient{genesis: genesis, slotDuration: slotDuration}, 0)
+	require.NoError(t, err)
+	vapi.SetClock(clock)
+	vapi.SetAwaitTimeout(time.Hour)
+
+	// Await blocks until the context is cancelled, simulating slow consensus.
+	vapi.RegisterAwaitAttestation(func(ctx context.Context, slot, commIdx int64) (*eth2p0.AttestationData, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	t.Run("closes after deadline", func(t *testing.T) {
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := vapi.AttestationData(ctx, 10, 0)
+			errCh <- err
+		}()
+
+		clock.BlockUntil(1)
+		clock.Advance(slotDuration / 3)
+
+		select {
+		case err := <-errCh:
+			require.ErrorContains(t, err, "attestation window closed")
+			require.Equal(t, http.StatusGatewayTimeout, ErrorToHTTPStatus(err))
+		case <-time.After(time.Second):
+			require.Fail(t, "attestation data not returned promptly")
+		}
+	})
+
+	t.Run("already closed", func(t *testing.T) {
+		_, err := vapi.AttestationData(ctx, 5, 0)
+		require.ErrorContains(t, err, "attestation window closed")
+	})
+}
+
+func TestAttestationSubmissionLimit(t *testing.T) {
+	const limit = 2
+
+	newAtt := func() *eth2p0.Attestation {
+		aggBits := bitfield.NewBitlist(8)
+		aggBits.SetBitAt(1, true)
+
+		return &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Source: &eth2p0.Checkpoint{},
+				Target: &eth2p0.Checkpoint{},
+			},
+		}
+	}
+
+	// newComponent returns a component whose submissions block in the subscriber until unblocked.
+	newComponent := func(t *testing.T, queueTimeout time.Duration) (*Component, *atomic.Int32, chan struct{}, chan struct{}) {
+		t.Helper()
+
+		vapi, err := NewComponentInsecure(t, nil, 0)
+		require.NoError(t, err)
+		vapi.SetAttestationSubmissionLimit(limit, queueTimeout)
+
+		vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+			return testutil.RandomCorePubKey(t), nil
+		})
+
+		var (
+			active  = new(atomic.Int32)
+			entered = make(chan struct{}, 10)
+			unblock = make(chan struct{})
+		)
+		vapi.Subscribe(func(context.Context, core.Duty, core.ParSignedDataSet) error {
+			active.Add(1)
+			defer active.Add(-1)
+
+			entered <- struct{}{}
+			<-unblock
+
+			return nil
+		})
+
+		return vapi, active, entered, unblock
+	}
+
+	t.Run("excess calls wait", func(t *testing.T) {
+		vapi, active, entered, unblock := newComponent(t, time.Minute)
+
+		const total = limit + 2
+		errs := make(chan error, total)
+		for i := 0; i < total; i++ {
+			go func() {
+				errs <- vapi.SubmitAttestations(context.Background(), []*eth2p0.Attestation{newAtt()})
+			}()
+		}
+
+		for i := 0; i < limit; i++ {
+			<-entered
+		}
+
+		// Excess calls are queued and do not start processing.
+		select {
+		case <-entered:
+			require.Fail(t, "concurrency limit exceeded")
+		case <-time.After(50 * time.Millisecond):
+		}
+		require.EqualValues(t, limit, active.Load())
+
+		close(unblock)
+		for i := 0; i < total; i++ {
+			require.NoError(t, <-errs)
+		}
+	})
+
+	t.Run("queue timeout", func(t *testing.T) {
+		vapi, _, entered, unblock := newComponent(t, 10*time.Millisecond)
+		defer close(unblock)
+
+		for i := 0; i < limit; i++ {
+			go func() {
+				_ = vapi.SubmitAttestations(context.Background(), []*eth2p0.Attestation{newAtt()})
+			}()
+			<-entered
+		}
+
+		err := vapi.SubmitAttestations(context.Background(), []*eth2p0.Attestation{newAtt()})
+		require.ErrorContains(t, err, "submission queue timeout")
+		require.Equal(t, http.StatusServiceUnavailable, ErrorToHTTPStatus(err))
+	})
+}
+
+func TestCacheBudget(t *testing.T) {
+	budget := newCacheBudget(100)
+
+	var evicted []string
+	newEntry := func(name, cache string, size int64) *cacheEntry {
+		return budget.Entry(cache, size, func() { evicted = append(evicted, name) })
+	}
+
+	a := newEntry("a", "x", 40)
+	b := newEntry("b", "y", 40)
+	budget.Track(a)
+	budget.Track(b)
+	require.EqualValues(t, 80, budget.Size())
+	require.Empty(t, evicted)
+
+	// Exceeding the budget evicts the least recently used entry across caches.
+	budget.Touch(a)
+	budget.Track(newEntry("c", "x", 40))
+	require.Equal(t, []string{"b"}, evicted)
+	require.EqualValues(t, 80, budget.Size())
+
+	// Removed entries are not tracked anymore.
+	budget.Remove(a)
+	budget.Track(a)
+	require.EqualValues(t, 40, budget.Size())
+
+	// The most recently used entry is never evicted, even if it exceeds the budget by itself.
+	budget.Track(newEntry("d", "y", 200))
+	require.Equal(t, []string{"b", "c"}, evicted)
+	require.EqualValues(t, 200, budget.Size())
+
+	// A nil budget doesn't track anything.
+	var nilBudget *cacheBudget
+	entry := nilBudget.Entry("x", 1, func() {})
+	nilBudget.Track(entry)
+	nilBudget.Touch(entry)
+	nilBudget.Remove(entry)
+	require.Zero(t, nilBudget.Size())
+}
+
+func TestComponentCacheBudget(t *testing.T) {
+	const maxEntries = 3
+
+	vapi, err := NewComponentInsecure(t, nil, 0)
+	require.NoError(t, err)
+	vapi.SetCacheBudget(maxEntries * attDataEntrySize)
+
+	evictions := func() float64 {
+		return promtestutil.ToFloat64(vapiCacheEvictions.WithLabelValues(cacheAttConsistency))
+	}
+	before := evictions()
+
+	for slot := eth2p0.Slot(0); slot < maxEntries; slot++ {
+		vapi.attConsistency.Check(&eth2p0.AttestationData{Slot: slot})
+	}
+	require.Len(t, vapi.attConsistency.firsts, maxEntries)
+	require.Zero(t, evictions()-before)
+
+	// Eviction kicks in once the budget is exceeded, evicting the least recently used slots.
+	vapi.attConsistency.Check(&eth2p0.AttestationData{Slot: 0}) // Touch slot 0.
+	vapi.attConsistency.Check(&eth2p0.AttestationData{Slot: maxEntries})
+	vapi.attConsistency.Check(&eth2p0.AttestationData{Slot: maxEntries + 1})
+
+	require.EqualValues(t, 2, evictions()-before)
+	require.EqualValues(t, maxEntries*attDataEntrySize, vapi.cacheBudget.Size())
+	require.Len(t, vapi.attConsistency.firsts, maxEntries)
+	require.Contains(t, vapi.attConsistency.firsts, eth2p0.Slot(0))
+	require.NotContains(t, vapi.attConsistency.firsts, eth2p0.Slot(1))
+	require.NotContains(t, vapi.attConsistency.firsts, eth2p0.Slot(2))
+
+	// Trimmed entries are released from the budget.
+	vapi.attConsistency.Trim(maxEntries + 1)
+	require.EqualValues(t, attDataEntrySize, vapi.cacheBudget.Size())
+}
+
+func TestComponentCacheBudgetCaches(t *testing.T) {
+	ctx := context.Background()
+
+	vapi, err := NewComponentInsecure(t, nil, 0)
+	require.NoError(t, err)
+	vapi.verified = newVerifiedCache()
+	committees := NewCommitteeCache(committeeClient{})
+	vapi.RegisterCommitteeCache(committees)
+	vapi.SetCacheBudget(2 * domainEntrySize)
+
+	evictions := func(cache string) float64 {
+		return promtestutil.ToFloat64(vapiCacheEvictions.WithLabelValues(cache))
+	}
+	domainsBefore, verifiedBefore := evictions(cacheDomains), evictions(cacheVerified)
+
+	for epoch := eth2p0.Epoch(1); epoch <= 2; epoch++ {
+		_, err := vapi.domains.Get(ctx, domainClient{}, domainKey{Name: signing.DomainBeaconAttester, Epoch: epoch})
+		require.NoError(t, err)
+	}
+	require.Len(t, vapi.domains.domains, 2)
+	require.EqualValues(t, 2*domainEntrySize, vapi.cacheBudget.Size())
+
+	// Caching a verified signature evicts the least recently used domain.
+	key := [32]byte{1}
+	vapi.verified.Add(2, key)
+	require.True(t, vapi.verified.Contains(2, key))
+	require.EqualValues(t, 1, evictions(cacheDomains)-domainsBefore)
+	require.NotContains(t, vapi.domains.domains, domainKey{Name: signing.DomainBeaconAttester, Epoch: 1})
+
+	// Caching committees evicts all other entries, but never the most recently used.
+	_, err = committees.Committee(ctx, 0, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, evictions(cacheDomains)-domainsBefore)
+	require.EqualValues(t, 1, evictions(cacheVerified)-verifiedBefore)
+	require.Empty(t, vapi.domains.domains)
+	require.False(t, vapi.verified.Contains(2, key))
+	require.Contains(t, committees.committees, eth2p0.Epoch(0))
+	require.EqualValues(t, committeeEntrySize+8*committeeClientSize, vapi.cacheBudget.Size())
+}
+
+func TestVerifiedCache(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+	pubshare, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	verified := newVerifiedCache()
+	cache := newSigningDataCache(domainClient{}, newDomainCache(), verified)
+
+	att := testutil.RandomAttestation()
+	root, err := att.Data.HashTreeRoot()
+	require.NoError(t, err)
+	msg, err := cache.SigningData(ctx, signing.DomainBeaconAttester, att.Data.Target.Epoch, root)
+	require.NoError(t, err)
+	sig, err := tblsv2.Sign(secret, msg[:])
+	require.NoError(t, err)
+	att.Signature = eth2p0.BLSSignature(sig)
+	data := core.NewAttestation(att)
+
+	// Invalid signatures aren't cached.
+	invalid := core.NewAttestation(testutil.RandomAttestation())
+	require.Error(t, cache.Verify(ctx, invalid, pubshare))
+	require.Empty(t, verified.epochs)
+
+	require.NoError(t, cache.Verify(ctx, data, pubshare))
+	signed, err := cache.message(ctx, data, pubshare)
+	require.NoError(t, err)
+	require.True(t, verified.Contains(att.Data.Target.Epoch, signed.Key))
+
+	// Verified signatures are skipped by batches, so the remaining batch is empty.
+	require.NoError(t, cache.VerifyBatch(ctx, []core.Eth2SignedData{data}, []tblsv2.PublicKey{pubshare}))
+
+	// Verified signatures of older epochs are pruned.
+	verified.Add(att.Data.Target.Epoch+2, [32]byte{})
+	require.False(t, verified.Contains(att.Data.Target.Epoch, signed.Key))
+}
+
+// committeeClientSize is the size of the single beacon committee per slot of committeeClient.
+const committeeClientSize = 8
+
+// committeeClient is an eth2wrap.Client with one slot per epoch and a single beacon committee per slot.
+type committeeClient struct {
+	eth2wrap.Client
+}
+
+func (committeeClient) SlotsPerEpoch(context.Context) (uint64, error) {
+	return 1, nil
+}
+
+func (committeeClient) BeaconCommitteesAtEpoch(_ context.Context, _ string, epoch eth2p0.Epoch) ([]*eth2v1.BeaconCommittee, error) {
+	return []*eth2v1.BeaconCommittee{{
+		Slot:       eth2p0.Slot(epoch),
+		Validators: make([]eth2p0.ValidatorIndex, committeeClientSize),
+	}}, nil
+}
+
+func TestAttBatcher(t *testing.T) {
+	ctx := context.Background()
+	clock := clockwork.NewFakeClock()
+
+	const window = time.Second
+
+	bg := newBackground()
+	flushed := make(chan core.ParSignedDataSet, 1)
+	b := newAttBatcher(window, clock.After, bg.Go, func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Equal(t, core.NewAttesterDuty(1), duty)
+		flushed <- set
+
+		return nil
+	})
+
+	newSet := func(pubkey core.PubKey) core.ParSignedDataSet {
+		att := testutil.RandomAttestation()
+		att.Data.Slot = 1
+
+		return core.ParSignedDataSet{pubkey: core.NewPartialAttestation(att, 1)}
+	}
+
+	pubkey1, pubkey2 := testutil.RandomCorePubKey(t), testutil.RandomCorePubKey(t)
+
+	// Submissions within the window are coalesced into a single batch.
+	batch1, err := b.add(ctx, 1, newSet(pubkey1))
+	require.NoError(t, err)
+	batch2, err := b.add(ctx, 1, newSet(pubkey2))
+	require.NoError(t, err)
+	require.Same(t, batch1, batch2)
+
+	// Different attestations of the same validator and slot conflict.
+	_, err = b.add(ctx, 1, newSet(pubkey1))
+	require.ErrorContains(t, err, "conflicting batched attestation")
+
+	clock.BlockUntil(1)
+	select {
+	case <-batch1.done:
+		require.Fail(t, "flushed before window elapsed")
+	default:
+	}
+
+	clock.Advance(window)
+	<-batch1.done
+	require.NoError(t, batch1.err)
+
+	set := <-flushed
+	require.Len(t, set, 2)
+	require.Contains(t, set, pubkey1)
+	require.Contains(t, set, pubkey2)
+
+	// Later submissions start a new batch.
+	batch3, err := b.add(ctx, 1, newSet(pubkey1))
+	require.NoError(t, err)
+	require.NotSame(t, batch1, batch3)
+
+	clock.BlockUntil(1)
+	clock.Advance(window)
+	<-batch3.done
+	<-flushed
+
+	// Pending batches fail without flushing when closed.
+	batch4, err := b.add(ctx, 1, newSet(pubkey1))
+	require.NoError(t, err)
+
+	clock.BlockUntil(1)
+	require.NoError(t, bg.Close(ctx))
+	<-batch4.done
+	require.ErrorContains(t, batch4.err, "attestation batch not flushed since validator api closed")
+	require.Empty(t, flushed)
+
+	// New batches fail once closed.
+	_, err = b.add(ctx, 1, newSet(pubkey1))
+	require.ErrorContains(t, err, "validator api closed")
+}
@@ -3,27 +3,38 @@
 package validatorapi_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"sort"
 	"sync"
 	"testing"
+	"time"
 
 	eth2api "github.com/attestantio/go-eth2-client/api"
 	eth2v1 "github.com/attestantio/go-eth2-client/api/v1"
 	eth2bellatrix "github.com/attestantio/go-eth2-client/api/v1/bellatrix"
 	eth2capella "github.com/attestantio/go-eth2-client/api/v1/capella"
+	eth2http "github.com/attestantio/go-eth2-client/http"
 	eth2spec "github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/altair"
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
 	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/jonboulle/clockwork"
 	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/obolnetwork/charon/app/errors"
 	"github.com/obolnetwork/charon/app/eth2wrap"
+	"github.com/obolnetwork/charon/app/log"
+	"github.com/obolnetwork/charon/app/promauto"
 	"github.com/obolnetwork/charon/core"
+	"github.com/obolnetwork/charon/core/parsigdb"
 	"github.com/obolnetwork/charon/core/validatorapi"
 	"github.com/obolnetwork/charon/eth2util"
 	"github.com/obolnetwork/charon/eth2util/eth2exp"
@@ -46,7 +57,7 @@ func TestComponent_ValidSubmitAttestations(t *testing.T) {
 
 	const (
 		slot        = 123
-		commIdx     = 456
+		commIdx     = 45
 		vIdxA       = 1
 		vIdxB       = 2
 		valCommIdxA = vIdxA
@@ -124,7 +135,7 @@ func TestComponent_InvalidSubmitAttestations(t *testing.T) {
 
 	const (
 		slot       = 123
-		commIdx    = 456
+		commIdx    = 45
 		vIdx       = 1
 		valCommIdx = vIdx
 		commLen    = 8
@@ -154,6 +165,219 @@ func TestComponent_InvalidSubmitAttestations(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestComponent_AggBitsResolver(t *testing.T) {
+	ctx := context.Background()
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	const (
+		slot     = 123
+		commIdx  = 4
+		commLen  = 8
+		shareIdx = 1
+	)
+
+	// Create two distributed validators (just use normal keys, not split tbls).
+	var (
+		secrets           []tblsv2.PrivateKey
+		pubkeys           []core.PubKey
+		allPubSharesByKey = make(map[core.PubKey]map[int]tblsv2.PublicKey)
+	)
+	for i := 0; i < 2; i++ {
+		secret, err := tblsv2.GenerateSecretKey()
+		require.NoError(t, err)
+		pubkey, err := tblsv2.SecretToPublicKey(secret)
+		require.NoError(t, err)
+		corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+		require.NoError(t, err)
+
+		secrets = append(secrets, secret)
+		pubkeys = append(pubkeys, corePubKey)
+		allPubSharesByKey[corePubKey] = map[int]tblsv2.PublicKey{shareIdx: pubkey} // Maps self to self since not tbls
+	}
+
+	// newAtt returns an attestation with the validator committee indices' bits set, signed by the provided secrets.
+	newAtt := func(t *testing.T, valCommIdxs []uint64, secrets ...tblsv2.PrivateKey) *eth2p0.Attestation {
+		t.Helper()
+
+		aggBits := bitfield.NewBitlist(commLen)
+		for _, idx := range valCommIdxs {
+			aggBits.SetBitAt(idx, true)
+		}
+
+		att := &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Slot:   slot,
+				Index:  commIdx,
+				Source: &eth2p0.Checkpoint{},
+				Target: &eth2p0.Checkpoint{Epoch: 3},
+			},
+		}
+
+		root, err := att.Data.HashTreeRoot()
+		require.NoError(t, err)
+		sigData, err := signing.GetDataRoot(ctx, bmock, signing.DomainBeaconAttester, att.Data.Target.Epoch, root)
+		require.NoError(t, err)
+
+		var sigs []tblsv2.Signature
+		for _, secret := range secrets {
+			sig, err := tblsv2.Sign(secret, sigData[:])
+			require.NoError(t, err)
+			sigs = append(sigs, sig)
+		}
+
+		aggSig, err := tblsv2.Aggregate(sigs)
+		require.NoError(t, err)
+		att.Signature = eth2p0.BLSSignature(aggSig)
+
+		return att
+	}
+
+	// newVAPI returns a validator API component with the aggregation bits resolver and mapping
+	// validator committee indices 0 and 1 to the two validators.
+	newVAPI := func(t *testing.T, resolver validatorapi.AggBitsResolver, pubkeyByIdx map[int64]core.PubKey) (*validatorapi.Component, *core.ParSignedDataSet) {
+		t.Helper()
+
+		vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+		require.NoError(t, err)
+
+		if resolver != nil {
+			vapi.RegisterAggBitsResolver(resolver)
+		}
+
+		vapi.RegisterPubKeyByAttestation(func(_ context.Context, _, _, valCommIdx int64) (core.PubKey, error) {
+			pubkey, ok := pubkeyByIdx[valCommIdx]
+			if !ok {
+				return "", errors.New("unknown validator committee index")
+			}
+
+			return pubkey, nil
+		})
+
+		var stored core.ParSignedDataSet
+		vapi.Subscribe(func(_ context.Context, _ core.Duty, set core.ParSignedDataSet) error {
+			stored = set
+			return nil
+		})
+
+		return vapi, &stored
+	}
+
+	pubkeyByIdx := map[int64]core.PubKey{0: pubkeys[0], 1: pubkeys[1]}
+
+	t.Run("single bit", func(t *testing.T) {
+		vapi, stored := newVAPI(t, nil, pubkeyByIdx)
+
+		att := newAtt(t, []uint64{1}, secrets[1])
+		require.NoError(t, vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att}))
+		require.Len(t, *stored, 1)
+		require.Contains(t, *stored, pubkeys[1])
+
+		// Multi-bit attestations are rejected by default.
+		att = newAtt(t, []uint64{0, 1}, secrets...)
+		require.ErrorContains(t, vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att}), "unexpected number of aggregation bits")
+	})
+
+	t.Run("custom resolver", func(t *testing.T) {
+		// Resolve the set bit to the next validator committee index.
+		shifted := func(att *eth2p0.Attestation) ([]int, error) {
+			indices, err := validatorapi.SingleBitResolver(att)
+			if err != nil {
+				return nil, err
+			}
+
+			return []int{indices[0] + 1}, nil
+		}
+
+		vapi, stored := newVAPI(t, shifted, pubkeyByIdx)
+
+		att := newAtt(t, []uint64{0}, secrets[1])
+		require.NoError(t, vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att}))
+		require.Len(t, *stored, 1)
+		require.Contains(t, *stored, pubkeys[1])
+
+		// Bits not resolving to a validator are rejected.
+		att = newAtt(t, []uint64{1}, secrets[1])
+		require.ErrorContains(t, vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att}), "unknown validator committee index")
+	})
+
+	t.Run("multiple signers", func(t *testing.T) {
+		// Attestations resolving to multiple validators are rejected, since the aggregate signature
+		// isn't a partial signature of each validator.
+		allBits := func(att *eth2p0.Attestation) ([]int, error) {
+			return att.AggregationBits.BitIndices(), nil
+		}
+
+		vapi, stored := newVAPI(t, allBits, pubkeyByIdx)
+
+		att := newAtt(t, []uint64{0, 1}, secrets...)
+		require.ErrorContains(t, vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att}), "unexpected number of attestation signers")
+		require.Empty(t, *stored)
+
+		att = newAtt(t, nil, secrets[0])
+		require.ErrorContains(t, vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att}), "unexpected number of attestation signers")
+		require.Empty(t, *stored)
+	})
+}
+
+func TestComponent_SubmitAttestationsIndexBounds(t *testing.T) {
+	ctx := context.Background()
+	eth2Cl, err := beaconmock.New()
+	require.NoError(t, err)
+
+	component, err := validatorapi.NewComponentInsecure(t, eth2Cl, 1)
+	require.NoError(t, err)
+
+	component.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+		require.Fail(t, "pubkey lookup not expected")
+		return "", nil
+	})
+
+	tests := []struct {
+		name       string
+		commIdx    eth2p0.CommitteeIndex
+		valCommIdx uint64
+		commLen    uint64
+		errMsg     string
+	}{
+		{
+			name:       "absurd committee index",
+			commIdx:    1 << 62,
+			valCommIdx: 1,
+			commLen:    8,
+			errMsg:     "attestation committee index out of range",
+		},
+		{
+			name:       "absurd validator committee index",
+			commIdx:    1,
+			valCommIdx: 1 << 16,
+			commLen:    1<<16 + 1,
+			errMsg:     "attestation validator committee index out of range",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggBits := bitfield.NewBitlist(test.commLen)
+			aggBits.SetBitAt(test.valCommIdx, true)
+
+			att := &eth2p0.Attestation{
+				AggregationBits: aggBits,
+				Data: &eth2p0.AttestationData{
+					Slot:   123,
+					Index:  test.commIdx,
+					Source: &eth2p0.Checkpoint{},
+					Target: &eth2p0.Checkpoint{},
+				},
+			}
+
+			err := component.SubmitAttestations(ctx, []*eth2p0.Attestation{att})
+			require.ErrorContains(t, err, test.errMsg)
+		})
+	}
+}
+
 func TestSubmitAttestations_Verify(t *testing.T) {
 	ctx := context.Background()
 
@@ -228,428 +452,627 @@ func TestSubmitAttestations_Verify(t *testing.T) {
 	require.NoError(t, attester.Attest(ctx))
 }
 
-// TestSignAndVerify signs and verifies the signature.
-// Test input and output obtained from prysm/validator/client/attest_test.go#TestSignAttestation.
-func TestSignAndVerify(t *testing.T) {
+func TestComponent_SubmitAttestationsWithReceipts(t *testing.T) {
 	ctx := context.Background()
-
-	// Create key pair
-	secretKey := *(*tblsv2.PrivateKey)(padTo([]byte{1}, 32))
-
-	// Setup beaconmock
-	forkSchedule := `{"data": [{
-        	"previous_version": "0x61626364",
-			"current_version": "0x64656666",
-        	"epoch": "0"
-      	}]}`
-	bmock, err := beaconmock.New(
-		beaconmock.WithEndpoint("/eth/v1/config/fork_schedule", forkSchedule),
-		beaconmock.WithGenesisValidatorsRoot([32]byte{0x01, 0x02}))
+	bmock, err := beaconmock.New()
 	require.NoError(t, err)
 
-	// Get and assert domain
-	domain, err := signing.GetDomain(ctx, bmock, signing.DomainBeaconAttester, 0)
-	require.NoError(t, err)
-	require.Equal(t, "0x0100000011b4296f38fa573d05f00854d452e120725b4d24b5587a472c6c4258", fmt.Sprintf("%#x", domain))
+	const (
+		commIdx  = 4
+		commLen  = 8
+		shareIdx = 1
+	)
 
-	// Define attestation data to sign
-	blockRoot := padTo([]byte("blockRoot"), 32)
-	var eth2Root eth2p0.Root
-	copy(eth2Root[:], blockRoot)
-	attData := eth2p0.AttestationData{
-		Slot:            999,
-		Index:           0,
-		BeaconBlockRoot: eth2Root,
-		Source:          &eth2p0.Checkpoint{Epoch: 100},
-		Target:          &eth2p0.Checkpoint{Epoch: 200},
+	// Create three distributed validators (just use normal keys, not split tbls).
+	var (
+		secrets           []tblsv2.PrivateKey
+		pubkeys           []core.PubKey
+		allPubSharesByKey = make(map[core.PubKey]map[int]tblsv2.PublicKey)
+	)
+	for i := 0; i < 3; i++ {
+		secret, err := tblsv2.GenerateSecretKey()
+		require.NoError(t, err)
+		pubkey, err := tblsv2.SecretToPublicKey(secret)
+		require.NoError(t, err)
+		corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+		require.NoError(t, err)
+
+		secrets = append(secrets, secret)
+		pubkeys = append(pubkeys, corePubKey)
+		allPubSharesByKey[corePubKey] = map[int]tblsv2.PublicKey{shareIdx: pubkey} // Maps self to self since not tbls
 	}
 
-	// Assert attestation data
-	attRoot, err := attData.HashTreeRoot()
+	genesis, err := bmock.GenesisTime(ctx)
 	require.NoError(t, err)
-	require.Equal(t, "0xeee68bd8e94662122695d04afa5fd5c30ae385c9f39d98aa840062f43221d0d0", fmt.Sprintf("%#x", attRoot))
-
-	// Create and assert signing data
-	sigData := eth2p0.SigningData{ObjectRoot: attRoot, Domain: domain}
-	sigDataBytes, err := sigData.HashTreeRoot()
+	slotDuration, err := bmock.SlotDuration(ctx)
 	require.NoError(t, err)
-	require.Equal(t, "0x02bbdb88056d6cbafd6e94575540e74b8cf2c0f2c1b79b8e17e7b21ed1694305", fmt.Sprintf("%#x", sigDataBytes))
+	nextSlot := eth2p0.Slot(time.Since(genesis)/slotDuration) + 1
 
-	// Get pubkey
-	pubkey, err := tblsv2.SecretToPublicKey(secretKey)
-	require.NoError(t, err)
-	eth2Pubkey := eth2p0.BLSPubKey(pubkey)
+	// newAtt returns an attestation of the slot and committee index with the validator committee index bit set,
+	// signed by the secret.
+	newAtt := func(slot eth2p0.Slot, commIdx eth2p0.CommitteeIndex, valCommIdx uint64, secret tblsv2.PrivateKey) *eth2p0.Attestation {
+		aggBits := bitfield.NewBitlist(commLen)
+		aggBits.SetBitAt(valCommIdx, true)
 
-	signer, err := validatormock.NewSigner(secretKey)
-	require.NoError(t, err)
+		att := &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Slot:   slot,
+				Index:  commIdx,
+				Source: &eth2p0.Checkpoint{},
+				Target: &eth2p0.Checkpoint{},
+			},
+		}
 
-	// Sign
-	sig, err := signer(eth2Pubkey, sigDataBytes[:])
-	require.NoError(t, err)
+		root, err := att.Data.HashTreeRoot()
+		require.NoError(t, err)
+		sigData, err := signing.GetDataRoot(ctx, bmock, signing.DomainBeaconAttester, att.Data.Target.Epoch, root)
+		require.NoError(t, err)
 
-	// Assert signature
-	require.Equal(t, "0xb6a60f8497bd328908be83634d045dd7a32f5e246b2c4031fc2f316983f362e36fc27fd3d6d5a2b15b4dbff38804ffb10b1719b7ebc54e9cbf3293fd37082bc0fc91f79d70ce5b04ff13de3c8e10bb41305bfdbe921a43792c12624f225ee865",
-		fmt.Sprintf("%#x", sig))
+		sig, err := tblsv2.Sign(secret, sigData[:])
+		require.NoError(t, err)
+		att.Signature = eth2p0.BLSSignature(sig)
 
-	// Convert pubkey
-	shareIdx := 1
-	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
-	require.NoError(t, err)
-	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+		return att
+	}
 
-	// Setup validatorapi component.
 	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
 	require.NoError(t, err)
-	vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
-		return core.PubKeyFromBytes(pubkey[:])
+
+	vapi.RegisterPubKeyByAttestation(func(_ context.Context, _, _, valCommIdx int64) (core.PubKey, error) {
+		return pubkeys[valCommIdx], nil
 	})
 
-	// Assert output
-	var wg sync.WaitGroup
-	wg.Add(1)
-	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		require.Equal(t, core.DutyAttester, duty.Type)
-		require.Len(t, set, 1)
-		wg.Done()
+	submitted := make(core.ParSignedDataSet)
+	vapi.Subscribe(func(_ context.Context, _ core.Duty, set core.ParSignedDataSet) error {
+		for pubkey, data := range set {
+			submitted[pubkey] = data
+		}
 
 		return nil
 	})
 
-	// Create and submit attestation.
-	aggBits := bitfield.NewBitlist(1)
-	aggBits.SetBitAt(0, true)
-	att := eth2p0.Attestation{
-		AggregationBits: aggBits,
-		Data:            &attData,
-		Signature:       sig,
+	accepted := newAtt(nextSlot, commIdx, 0, secrets[0])
+	atts := []*eth2p0.Attestation{
+		accepted,
+		accepted,                                 // Duplicate
+		newAtt(nextSlot, commIdx, 1, secrets[2]), // Signed by another validator
+		newAtt(1, commIdx, 2, secrets[2]),        // Attestation window of slot 1 closed long ago
+		newAtt(nextSlot, 64, 0, secrets[0]),      // Committee index out of range
 	}
-	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{&att})
+
+	// The batch fails without receipts.
+	err = vapi.SubmitAttestations(ctx, atts)
+	require.Error(t, err)
+
+	receipts, err := vapi.SubmitAttestationsWithReceipts(ctx, atts)
 	require.NoError(t, err)
-	wg.Wait()
-}
+	require.Len(t, receipts, len(atts))
 
-// padTo pads a byte slice to the given size.
-// It was copied from prysm/encoding/bytesutil/bytes.go.
-func padTo(b []byte, size int) []byte {
-	if len(b) > size {
-		return b
+	expect := []validatorapi.AttestationReceiptStatus{
+		validatorapi.AttestationAccepted,
+		validatorapi.AttestationDuplicate,
+		validatorapi.AttestationVerificationFailed,
+		validatorapi.AttestationLate,
+		validatorapi.AttestationRejected,
 	}
-
-	return append(b, make([]byte, size-len(b))...)
+	for i, receipt := range receipts {
+		require.Equal(t, i, receipt.Index)
+		require.Equal(t, expect[i], receipt.Status, "index %d", i)
+	}
+	require.Empty(t, receipts[0].Message)
+	require.Contains(t, receipts[2].Message, "verify partial signature")
+	require.Contains(t, receipts[4].Message, "committee index out of range")
+
+	// Only accepted (including late) attestations are submitted.
+	require.Len(t, submitted, 2)
+	require.Contains(t, submitted, pubkeys[0])
+	require.Contains(t, submitted, pubkeys[2])
+
+	// A valid batch of different signing data is accepted.
+	atts = []*eth2p0.Attestation{
+		newAtt(nextSlot+1, commIdx, 0, secrets[0]),
+		newAtt(nextSlot+1, commIdx, 1, secrets[1]),
+		newAtt(nextSlot+2, commIdx, 2, secrets[2]),
+	}
+	receipts, err = vapi.SubmitAttestationsWithReceipts(ctx, atts)
+	require.NoError(t, err)
+	for i, receipt := range receipts {
+		require.Equal(t, validatorapi.AttestationAccepted, receipt.Status, "index %d", i)
+	}
+	require.Len(t, submitted, 3)
 }
 
-func TestComponent_BeaconBlockProposal(t *testing.T) {
+func TestComponent_SlashingProtection(t *testing.T) {
 	ctx := context.Background()
-	eth2Cl, err := beaconmock.New()
-	require.NoError(t, err)
+	pubkey := testutil.RandomCorePubKey(t)
 
-	const (
-		slot = 123
-		vIdx = 1
-	)
+	// rejectedCount returns the number of attestations rejected by slashing protection.
+	rejectedCount := func(t *testing.T) float64 {
+		t.Helper()
 
-	slotsPerEpoch, err := eth2Cl.SlotsPerEpoch(context.Background())
-	require.NoError(t, err)
+		registry, err := promauto.NewRegistry(nil)
+		require.NoError(t, err)
 
-	epoch := eth2p0.Epoch(uint64(slot) / slotsPerEpoch)
+		families, err := registry.Gather()
+		require.NoError(t, err)
 
-	component, err := validatorapi.NewComponentInsecure(t, eth2Cl, vIdx)
-	require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "core_validatorapi_slashing_rejected_total" {
+				return family.GetMetric()[0].GetCounter().GetValue()
+			}
+		}
 
-	secret, err := tblsv2.GenerateSecretKey()
-	require.NoError(t, err)
+		return 0
+	}
 
-	pk, err := tblsv2.SecretToPublicKey(secret)
+	vapi, err := validatorapi.NewComponentInsecure(t, nil, 0)
 	require.NoError(t, err)
 
-	msg := []byte("randao reveal")
-	sig, err := tblsv2.Sign(secret, msg)
-	require.NoError(t, err)
+	vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+		return pubkey, nil
+	})
+	vapi.RegisterSlashingProtector(validatorapi.NewMemSlashingProtector())
 
-	randao := eth2p0.BLSSignature(sig)
-	pubkey, err := core.PubKeyFromBytes(pk[:])
-	require.NoError(t, err)
+	var submitted int
+	vapi.Subscribe(func(context.Context, core.Duty, core.ParSignedDataSet) error {
+		submitted++
+		return nil
+	})
 
-	block1 := &eth2spec.VersionedBeaconBlock{
-		Version: eth2spec.DataVersionPhase0,
-		Phase0:  testutil.RandomPhase0BeaconBlock(),
+	newAtt := func(slot eth2p0.Slot, source, target eth2p0.Epoch, root eth2p0.Root) *eth2p0.Attestation {
+		aggBits := bitfield.NewBitlist(8)
+		aggBits.SetBitAt(0, true)
+
+		return &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Slot:            slot,
+				BeaconBlockRoot: root,
+				Source:          &eth2p0.Checkpoint{Epoch: source},
+				Target:          &eth2p0.Checkpoint{Epoch: target},
+			},
+			Signature: testutil.RandomEth2Signature(),
+		}
 	}
-	block1.Phase0.Slot = slot
-	block1.Phase0.ProposerIndex = vIdx
-	block1.Phase0.Body.RANDAOReveal = randao
 
-	component.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
-		return core.DutyDefinitionSet{pubkey: nil}, nil
-	})
+	att := newAtt(64, 1, 2, testutil.RandomRoot())
+	require.NoError(t, vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att}))
 
-	component.RegisterAwaitBeaconBlock(func(ctx context.Context, slot int64) (*eth2spec.VersionedBeaconBlock, error) {
-		return block1, nil
-	})
+	// Resubmitting identical attestation data is allowed.
+	require.NoError(t, vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att}))
+	require.Equal(t, 2, submitted)
 
-	component.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		require.Equal(t, set, core.ParSignedDataSet{
-			pubkey: core.NewPartialSignedRandao(epoch, randao, vIdx),
-		})
-		require.Equal(t, duty, core.NewRandaoDuty(slot))
+	before := rejectedCount(t)
 
-		return nil
-	})
+	// Double vote: same target epoch, different data.
+	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(65, 1, 2, testutil.RandomRoot())})
+	require.ErrorContains(t, err, "slashable double vote")
 
-	block2, err := component.BeaconBlockProposal(ctx, slot, randao, []byte{})
-	require.NoError(t, err)
-	require.Equal(t, block1, block2)
+	// Surround vote: surrounds the first attestation.
+	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(96, 0, 3, testutil.RandomRoot())})
+	require.ErrorContains(t, err, "slashable surround vote")
+
+	require.Equal(t, 2, submitted)
+	require.EqualValues(t, before+2, rejectedCount(t))
 }
 
-func TestComponent_SubmitBeaconBlock(t *testing.T) {
+func TestComponent_SubmitAttestationsSlotsError(t *testing.T) {
 	ctx := context.Background()
 
-	// Create keys (just use normal keys, not split tbls)
-	secret, err := tblsv2.GenerateSecretKey()
-	require.NoError(t, err)
-
-	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	vapi, err := validatorapi.NewComponentInsecure(t, nil, 0)
 	require.NoError(t, err)
 
-	const (
-		vIdx     = 1
-		shareIdx = 1
-		slot     = 123
-		epoch    = eth2p0.Epoch(3)
-	)
+	vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+		return testutil.RandomCorePubKey(t), nil
+	})
 
-	// Convert pubkey
-	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
-	require.NoError(t, err)
-	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+	const failedSlot = 2
 
-	// Configure beacon mock
-	bmock, err := beaconmock.New()
-	require.NoError(t, err)
+	var stored []int64
+	vapi.Subscribe(func(_ context.Context, duty core.Duty, _ core.ParSignedDataSet) error {
+		if duty.Slot == failedSlot {
+			return errors.New("store failed")
+		}
+		stored = append(stored, duty.Slot)
 
-	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
-	require.NoError(t, err)
+		return nil
+	})
 
-	// Prepare unsigned beacon block
-	msg := []byte("randao reveal")
-	sig, err := tblsv2.Sign(secret, msg)
-	require.NoError(t, err)
+	newAtt := func(slot eth2p0.Slot) *eth2p0.Attestation {
+		aggBits := bitfield.NewBitlist(8)
+		aggBits.SetBitAt(1, true)
 
-	randao := eth2p0.BLSSignature(sig)
-	unsignedBlock := &eth2spec.VersionedBeaconBlock{
-		Version: eth2spec.DataVersionPhase0,
-		Phase0:  testutil.RandomPhase0BeaconBlock(),
+		return &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Slot:   slot,
+				Source: &eth2p0.Checkpoint{},
+				Target: &eth2p0.Checkpoint{},
+			},
+		}
 	}
-	unsignedBlock.Phase0.Body.RANDAOReveal = randao
-	unsignedBlock.Phase0.Slot = slot
-	unsignedBlock.Phase0.ProposerIndex = vIdx
 
-	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
-		return core.DutyDefinitionSet{corePubKey: nil}, nil
-	})
+	// Submit out-of-order slots, the later slot fails to be stored.
+	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(failedSlot), newAtt(1)})
+	require.ErrorContains(t, err, "store failed")
 
-	// Sign beacon block
-	sigRoot, err := unsignedBlock.Root()
-	require.NoError(t, err)
+	var slotsErr validatorapi.AttestationSlotsError
+	require.True(t, errors.As(err, &slotsErr))
+	require.Equal(t, []int64{1}, slotsErr.Stored)
+	require.Equal(t, []int64{failedSlot}, slotsErr.Failed)
+	require.Equal(t, []int64{1}, stored)
 
-	domain, err := signing.GetDomain(ctx, bmock, signing.DomainBeaconProposer, epoch)
-	require.NoError(t, err)
+	// Single slot failures return the error as is.
+	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(failedSlot)})
+	require.ErrorContains(t, err, "store failed")
+	require.False(t, errors.As(err, &slotsErr))
+}
 
-	sigData, err := (&eth2p0.SigningData{ObjectRoot: sigRoot, Domain: domain}).HashTreeRoot()
-	require.NoError(t, err)
+func TestComponent_AttestationBatching(t *testing.T) {
+	ctx := context.Background()
+	pubkeys := []core.PubKey{testutil.RandomCorePubKey(t), testutil.RandomCorePubKey(t)}
 
-	s, err := tblsv2.Sign(secret, sigData[:])
+	vapi, err := validatorapi.NewComponentInsecure(t, nil, 0)
 	require.NoError(t, err)
 
-	signedBlock := &eth2spec.VersionedSignedBeaconBlock{
-		Version: eth2spec.DataVersionPhase0,
-		Phase0: &eth2p0.SignedBeaconBlock{
-			Message:   unsignedBlock.Phase0,
-			Signature: eth2p0.BLSSignature(s),
-		},
-	}
+	// Map validator committee index to pubkey.
+	vapi.RegisterPubKeyByAttestation(func(_ context.Context, _, _, valCommIdx int64) (core.PubKey, error) {
+		return pubkeys[valCommIdx], nil
+	})
 
-	// Register subscriber
-	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		block, ok := set[corePubKey].SignedData.(core.VersionedSignedBeaconBlock)
-		require.True(t, ok)
-		require.Equal(t, *signedBlock, block.VersionedSignedBeaconBlock)
+	var (
+		mu   sync.Mutex
+		sets []core.ParSignedDataSet
+	)
+	vapi.Subscribe(func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		require.Equal(t, core.NewAttesterDuty(1), duty)
+		sets = append(sets, set)
 
 		return nil
 	})
 
-	err = vapi.SubmitBeaconBlock(ctx, signedBlock)
+	clock := clockwork.NewFakeClock()
+	vapi.SetClock(clock)
+	vapi.SetAttestationBatchWindow(100 * time.Millisecond)
+
+	aggBits := bitfield.NewBitlist(8)
+	aggBits.SetBitAt(0, true)
+	att := &eth2p0.Attestation{
+		AggregationBits: aggBits,
+		Data: &eth2p0.AttestationData{
+			Slot:   1,
+			Source: &eth2p0.Checkpoint{},
+			Target: &eth2p0.Checkpoint{},
+		},
+		Signature: testutil.RandomEth2Signature(),
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att})
+	}()
+
+	// The submission is only stored once the batch window elapsed.
+	clock.BlockUntil(1)
+	mu.Lock()
+	require.Empty(t, sets)
+	mu.Unlock()
+
+	clock.Advance(100 * time.Millisecond)
+	require.NoError(t, <-errCh)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, sets, 1)
+	require.Len(t, sets[0], 1)
+}
+
+func TestComponent_InspectRegistrations(t *testing.T) {
+	component, err := validatorapi.NewComponentInsecure(t, nil, 1)
 	require.NoError(t, err)
+
+	require.Equal(t, validatorapi.RegistrationStatus{}, component.InspectRegistrations())
+
+	component.RegisterAwaitAttestation(func(context.Context, int64, int64) (*eth2p0.AttestationData, error) {
+		return nil, nil
+	})
+	component.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+		return "", nil
+	})
+	component.RegisterAwaitAggSigDB(func(context.Context, core.Duty, core.PubKey) (core.SignedData, error) {
+		return nil, nil
+	})
+	for i := 0; i < 2; i++ {
+		component.Subscribe(func(context.Context, core.Duty, core.ParSignedDataSet) error {
+			return nil
+		})
+	}
+
+	require.Equal(t, validatorapi.RegistrationStatus{
+		PubKeyByAttestation: true,
+		AwaitAttestation:    true,
+		AwaitAggSigDB:       true,
+		Subscriptions:       2,
+	}, component.InspectRegistrations())
 }
 
-func TestComponent_SubmitBeaconBlockInvalidSignature(t *testing.T) {
+func TestNewWithOptions(t *testing.T) {
 	ctx := context.Background()
 
-	// Create keys (just use normal keys, not split tbls)
+	const vIdx = 1
+
 	secret, err := tblsv2.GenerateSecretKey()
 	require.NoError(t, err)
-
 	pubkey, err := tblsv2.SecretToPublicKey(secret)
 	require.NoError(t, err)
 
-	const (
-		vIdx     = 1
-		shareIdx = 1
-		slot     = 123
-	)
-
-	// Convert pubkey
 	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
-	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	// Configure beacon mock
-	bmock, err := beaconmock.New()
-	require.NoError(t, err)
+	// This node holds share index 2 of the DV.
+	pubshare := tblsv2.PublicKey(testutil.RandomEth2PubKey(t))
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {1: pubkey, 2: pubshare}}
+	shareIdxByKey := map[core.PubKey]int{corePubKey: 2}
 
-	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
-	require.NoError(t, err)
+	validator := beaconmock.ValidatorSetA[vIdx]
+	validator.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
 
-	// Prepare unsigned beacon block
-	msg := []byte("randao reveal")
-	sig, err := tblsv2.Sign(secret, msg)
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSet{vIdx: validator}))
 	require.NoError(t, err)
 
-	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
-		return core.DutyDefinitionSet{corePubKey: nil}, nil
+	bmock.ProposerDutiesFunc = func(context.Context, eth2p0.Epoch, []eth2p0.ValidatorIndex) ([]*eth2v1.ProposerDuty, error) {
+		return []*eth2v1.ProposerDuty{{PubKey: eth2p0.BLSPubKey(pubkey), ValidatorIndex: vIdx}}, nil
+	}
+
+	var seen []core.PubKey
+	newVAPI := func(t *testing.T, opts ...validatorapi.Option) *validatorapi.Component {
+		t.Helper()
+
+		opts = append(opts,
+			validatorapi.WithShareIndices(shareIdxByKey),
+			validatorapi.WithBuilderEnabled(func(int64) bool { return true }),
+			validatorapi.WithSeenPubkeys(func(pk core.PubKey) { seen = append(seen, pk) }),
+		)
+
+		vapi, err := validatorapi.New(bmock, allPubSharesByKey, 1, opts...)
+		require.NoError(t, err)
+
+		vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+			return corePubKey, nil
+		})
+		vapi.Subscribe(func(_ context.Context, _ core.Duty, set core.ParSignedDataSet) error {
+			require.Equal(t, 2, set[corePubKey].ShareIdx)
+			return nil
+		})
+
+		return vapi
+	}
+
+	aggBits := bitfield.NewBitlist(8)
+	aggBits.SetBitAt(0, true)
+	att := &eth2p0.Attestation{
+		AggregationBits: aggBits,
+		Data: &eth2p0.AttestationData{
+			Source: &eth2p0.Checkpoint{},
+			Target: &eth2p0.Checkpoint{},
+		},
+		Signature: testutil.RandomEth2Signature(),
+	}
+
+	t.Run("share indices", func(t *testing.T) {
+		duties, err := newVAPI(t).ProposerDuties(ctx, 0, nil)
+		require.NoError(t, err)
+		require.Len(t, duties, 1)
+		require.Equal(t, eth2p0.BLSPubKey(pubshare), duties[0].PubKey)
+		require.Contains(t, seen, corePubKey)
 	})
 
-	// Add invalid Signature to beacon block
-	s, err := tblsv2.Sign(secret, []byte("invalid msg"))
+	t.Run("verify", func(t *testing.T) {
+		err := newVAPI(t).SubmitAttestations(ctx, []*eth2p0.Attestation{att})
+		require.ErrorContains(t, err, "verify partial signature")
+	})
+
+	t.Run("insecure skip verify", func(t *testing.T) {
+		err := newVAPI(t, validatorapi.WithInsecureSkipVerify()).SubmitAttestations(ctx, []*eth2p0.Attestation{att})
+		require.NoError(t, err)
+	})
+}
+
+func TestComponent_MultipleShareIndices(t *testing.T) {
+	ctx := context.Background()
+
+	bmock, err := beaconmock.New()
 	require.NoError(t, err)
 
-	unsignedBlock := testutil.RandomPhase0BeaconBlock()
-	unsignedBlock.Body.RANDAOReveal = eth2p0.BLSSignature(sig)
-	unsignedBlock.Slot = slot
-	unsignedBlock.ProposerIndex = vIdx
+	// This node holds share index 1 of DV A and share index 2 of DV B.
+	shareIdxByKey := make(map[core.PubKey]int)
+	allPubSharesByKey := make(map[core.PubKey]map[int]tblsv2.PublicKey)
+	secretsByKey := make(map[core.PubKey]tblsv2.PrivateKey)
+	var pubkeys []core.PubKey
+	for _, shareIdx := range []int{1, 2} {
+		pubkey := testutil.RandomCorePubKey(t)
+		pubkeys = append(pubkeys, pubkey)
 
-	signedBlock := &eth2spec.VersionedSignedBeaconBlock{
-		Version: eth2spec.DataVersionPhase0,
-		Phase0: &eth2p0.SignedBeaconBlock{
-			Message:   unsignedBlock,
-			Signature: eth2p0.BLSSignature(s),
-		},
+		shares := make(map[int]tblsv2.PublicKey)
+		for i := 1; i <= 3; i++ {
+			secret, err := tblsv2.GenerateSecretKey()
+			require.NoError(t, err)
+			pubshare, err := tblsv2.SecretToPublicKey(secret)
+			require.NoError(t, err)
+
+			shares[i] = pubshare
+			if i == shareIdx {
+				secretsByKey[pubkey] = secret
+			}
+		}
+
+		allPubSharesByKey[pubkey] = shares
+		shareIdxByKey[pubkey] = shareIdx
 	}
 
-	// Register subscriber
-	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		block, ok := set[corePubKey].SignedData.(core.VersionedSignedBeaconBlock)
-		require.True(t, ok)
-		require.Equal(t, signedBlock, block)
+	vapi, err := validatorapi.NewComponentWithShareIndices(bmock, allPubSharesByKey, shareIdxByKey, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
 
+	vapi.RegisterPubKeyByAttestation(func(_ context.Context, _, _, valCommIdx int64) (core.PubKey, error) {
+		return pubkeys[valCommIdx], nil
+	})
+
+	var stored core.ParSignedDataSet
+	vapi.Subscribe(func(_ context.Context, _ core.Duty, set core.ParSignedDataSet) error {
+		stored = set
 		return nil
 	})
 
-	err = vapi.SubmitBeaconBlock(ctx, signedBlock)
-	require.ErrorContains(t, err, "signature not verified")
+	var atts []*eth2p0.Attestation
+	for valCommIdx, pubkey := range pubkeys {
+		aggBits := bitfield.NewBitlist(uint64(len(pubkeys)))
+		aggBits.SetBitAt(uint64(valCommIdx), true)
+
+		attData := &eth2p0.AttestationData{
+			Slot:   1,
+			Index:  1,
+			Source: &eth2p0.Checkpoint{},
+			Target: &eth2p0.Checkpoint{},
+		}
+
+		root, err := attData.HashTreeRoot()
+		require.NoError(t, err)
+		sigData, err := signing.GetDataRoot(ctx, bmock, signing.DomainBeaconAttester, 0, root)
+		require.NoError(t, err)
+		sig, err := tblsv2.Sign(secretsByKey[pubkey], sigData[:])
+		require.NoError(t, err)
+
+		atts = append(atts, &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data:            attData,
+			Signature:       eth2p0.BLSSignature(sig),
+		})
+	}
+
+	err = vapi.SubmitAttestations(ctx, atts)
+	require.NoError(t, err)
+
+	require.Len(t, stored, 2)
+	for _, pubkey := range pubkeys {
+		require.Equal(t, shareIdxByKey[pubkey], stored[pubkey].ShareIdx)
+	}
 }
 
-func TestComponent_SubmitBeaconBlockInvalidBlock(t *testing.T) {
+// TestSignAndVerify signs and verifies the signature.
+// Test input and output obtained from prysm/validator/client/attest_test.go#TestSignAttestation.
+func TestSignAndVerify(t *testing.T) {
 	ctx := context.Background()
-	shareIdx := 1
-	// Create keys (just use normal keys, not split tbls)
-	pubkey := testutil.RandomCorePubKey(t)
 
-	pkb, err := pubkey.Bytes()
+	// Create key pair
+	secretKey := *(*tblsv2.PrivateKey)(padTo([]byte{1}, 32))
+
+	// Setup beaconmock
+	forkSchedule := `{"data": [{
+        	"previous_version": "0x61626364",
+			"current_version": "0x64656666",
+        	"epoch": "0"
+      	}]}`
+	bmock, err := beaconmock.New(
+		beaconmock.WithEndpoint("/eth/v1/config/fork_schedule", forkSchedule),
+		beaconmock.WithGenesisValidatorsRoot([32]byte{0x01, 0x02}))
 	require.NoError(t, err)
 
-	tblsPubkey := *(*tblsv2.PublicKey)(pkb)
+	// Get and assert domain
+	domain, err := signing.GetDomain(ctx, bmock, signing.DomainBeaconAttester, 0)
 	require.NoError(t, err)
-	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{pubkey: {shareIdx: tblsPubkey}} // Maps self to self since not tbls
+	require.Equal(t, "0x0100000011b4296f38fa573d05f00854d452e120725b4d24b5587a472c6c4258", fmt.Sprintf("%#x", domain))
 
-	// Configure beacon mock
-	bmock, err := beaconmock.New()
+	// Define attestation data to sign
+	blockRoot := padTo([]byte("blockRoot"), 32)
+	var eth2Root eth2p0.Root
+	copy(eth2Root[:], blockRoot)
+	attData := eth2p0.AttestationData{
+		Slot:            999,
+		Index:           0,
+		BeaconBlockRoot: eth2Root,
+		Source:          &eth2p0.Checkpoint{Epoch: 100},
+		Target:          &eth2p0.Checkpoint{Epoch: 200},
+	}
+
+	// Assert attestation data
+	attRoot, err := attData.HashTreeRoot()
 	require.NoError(t, err)
+	require.Equal(t, "0xeee68bd8e94662122695d04afa5fd5c30ae385c9f39d98aa840062f43221d0d0", fmt.Sprintf("%#x", attRoot))
 
-	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	// Create and assert signing data
+	sigData := eth2p0.SigningData{ObjectRoot: attRoot, Domain: domain}
+	sigDataBytes, err := sigData.HashTreeRoot()
 	require.NoError(t, err)
+	require.Equal(t, "0x02bbdb88056d6cbafd6e94575540e74b8cf2c0f2c1b79b8e17e7b21ed1694305", fmt.Sprintf("%#x", sigDataBytes))
 
-	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
-		return core.DutyDefinitionSet{pubkey: nil}, nil
-	})
+	// Get pubkey
+	pubkey, err := tblsv2.SecretToPublicKey(secretKey)
+	require.NoError(t, err)
+	eth2Pubkey := eth2p0.BLSPubKey(pubkey)
 
-	// invalid block scenarios
-	tests := []struct {
-		name   string
-		block  *eth2spec.VersionedSignedBeaconBlock
-		errMsg string
-	}{
-		{
-			name:   "no phase 0 block",
-			block:  &eth2spec.VersionedSignedBeaconBlock{Version: eth2spec.DataVersionPhase0},
-			errMsg: "no phase0 block",
-		},
-		{
-			name:   "no altair block",
-			block:  &eth2spec.VersionedSignedBeaconBlock{Version: eth2spec.DataVersionAltair},
-			errMsg: "no altair block",
-		},
-		{
-			name:   "no bellatrix block",
-			block:  &eth2spec.VersionedSignedBeaconBlock{Version: eth2spec.DataVersionBellatrix},
-			errMsg: "no bellatrix block",
-		},
-		{
-			name:   "no capella block",
-			block:  &eth2spec.VersionedSignedBeaconBlock{Version: eth2spec.DataVersionCapella},
-			errMsg: "no capella block",
-		},
-		{
-			name:   "none",
-			block:  &eth2spec.VersionedSignedBeaconBlock{Version: eth2spec.DataVersion(4)},
-			errMsg: "unknown version",
-		},
-		{
-			name: "no phase 0 sig",
-			block: &eth2spec.VersionedSignedBeaconBlock{
-				Version: eth2spec.DataVersionPhase0,
-				Phase0: &eth2p0.SignedBeaconBlock{
-					Message:   &eth2p0.BeaconBlock{Slot: eth2p0.Slot(123), Body: testutil.RandomPhase0BeaconBlockBody()},
-					Signature: eth2p0.BLSSignature{},
-				},
-			},
-			errMsg: "no signature found",
-		},
-		{
-			name: "no altair sig",
-			block: &eth2spec.VersionedSignedBeaconBlock{
-				Version: eth2spec.DataVersionAltair,
-				Altair: &altair.SignedBeaconBlock{
-					Message:   &altair.BeaconBlock{Slot: eth2p0.Slot(123), Body: testutil.RandomAltairBeaconBlockBody()},
-					Signature: eth2p0.BLSSignature{},
-				},
-			},
-			errMsg: "no signature found",
-		},
-		{
-			name: "no bellatrix sig",
-			block: &eth2spec.VersionedSignedBeaconBlock{
-				Version: eth2spec.DataVersionBellatrix,
-				Bellatrix: &bellatrix.SignedBeaconBlock{
-					Message:   &bellatrix.BeaconBlock{Slot: eth2p0.Slot(123), Body: testutil.RandomBellatrixBeaconBlockBody()},
-					Signature: eth2p0.BLSSignature{},
-				},
-			},
-			errMsg: "no signature found",
-		},
+	signer, err := validatormock.NewSigner(secretKey)
+	require.NoError(t, err)
+
+	// Sign
+	sig, err := signer(eth2Pubkey, sigDataBytes[:])
+	require.NoError(t, err)
+
+	// Assert signature
+	require.Equal(t, "0xb6a60f8497bd328908be83634d045dd7a32f5e246b2c4031fc2f316983f362e36fc27fd3d6d5a2b15b4dbff38804ffb10b1719b7ebc54e9cbf3293fd37082bc0fc91f79d70ce5b04ff13de3c8e10bb41305bfdbe921a43792c12624f225ee865",
+		fmt.Sprintf("%#x", sig))
+
+	// Convert pubkey
+	shareIdx := 1
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	// Setup validatorapi component.
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+	vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+		return core.PubKeyFromBytes(pubkey[:])
+	})
+
+	// Assert output
+	var wg sync.WaitGroup
+	wg.Add(1)
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Equal(t, core.DutyAttester, duty.Type)
+		require.Len(t, set, 1)
+		wg.Done()
+
+		return nil
+	})
+
+	// Create and submit attestation.
+	aggBits := bitfield.NewBitlist(1)
+	aggBits.SetBitAt(0, true)
+	att := eth2p0.Attestation{
+		AggregationBits: aggBits,
+		Data:            &attData,
+		Signature:       sig,
 	}
+	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{&att})
+	require.NoError(t, err)
+	wg.Wait()
+}
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			err = vapi.SubmitBeaconBlock(ctx, test.block)
-			require.ErrorContains(t, err, test.errMsg)
-		})
+// padTo pads a byte slice to the given size.
+// It was copied from prysm/encoding/bytesutil/bytes.go.
+func padTo(b []byte, size int) []byte {
+	if len(b) > size {
+		return b
 	}
+
+	return append(b, make([]byte, size-len(b))...)
 }
 
-func TestComponent_BlindedBeaconBlockProposal(t *testing.T) {
+func TestComponent_BeaconBlockProposal(t *testing.T) {
 	ctx := context.Background()
 	eth2Cl, err := beaconmock.New()
 	require.NoError(t, err)
@@ -681,19 +1104,19 @@ func TestComponent_BlindedBeaconBlockProposal(t *testing.T) {
 	pubkey, err := core.PubKeyFromBytes(pk[:])
 	require.NoError(t, err)
 
-	block1 := &eth2api.VersionedBlindedBeaconBlock{
-		Version:   eth2spec.DataVersionPhase0,
-		Bellatrix: testutil.RandomBellatrixBlindedBeaconBlock(),
+	block1 := &eth2spec.VersionedBeaconBlock{
+		Version: eth2spec.DataVersionPhase0,
+		Phase0:  testutil.RandomPhase0BeaconBlock(),
 	}
-	block1.Bellatrix.Slot = slot
-	block1.Bellatrix.ProposerIndex = vIdx
-	block1.Bellatrix.Body.RANDAOReveal = randao
+	block1.Phase0.Slot = slot
+	block1.Phase0.ProposerIndex = vIdx
+	block1.Phase0.Body.RANDAOReveal = randao
 
 	component.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
 		return core.DutyDefinitionSet{pubkey: nil}, nil
 	})
 
-	component.RegisterAwaitBlindedBeaconBlock(func(ctx context.Context, slot int64) (*eth2api.VersionedBlindedBeaconBlock, error) {
+	component.RegisterAwaitBeaconBlock(func(ctx context.Context, slot int64) (*eth2spec.VersionedBeaconBlock, error) {
 		return block1, nil
 	})
 
@@ -706,233 +1129,287 @@ func TestComponent_BlindedBeaconBlockProposal(t *testing.T) {
 		return nil
 	})
 
-	block2, err := component.BlindedBeaconBlockProposal(ctx, slot, randao, []byte{})
+	block2, err := component.BeaconBlockProposal(ctx, slot, randao, []byte{})
 	require.NoError(t, err)
 	require.Equal(t, block1, block2)
 }
 
-func TestComponent_SubmitBlindedBeaconBlock(t *testing.T) {
+func TestComponent_MinimalPreset(t *testing.T) {
 	ctx := context.Background()
 
-	// Create keys (just use normal keys, not split tbls)
-	secret, err := tblsv2.GenerateSecretKey()
-	require.NoError(t, err)
-
-	pubkey, err := tblsv2.SecretToPublicKey(secret)
-	require.NoError(t, err)
-
 	const (
-		vIdx     = 1
 		shareIdx = 1
-		slot     = 123
-		epoch    = eth2p0.Epoch(3)
+		slot     = 20 // Epoch 2 in the minimal preset with 8 slots per epoch.
 	)
 
-	// Convert pubkey
-	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	bmock, err := beaconmock.New(beaconmock.WithMinimalPreset())
 	require.NoError(t, err)
-	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	// Configure beacon mock
-	bmock, err := beaconmock.New()
+	secret, err := tblsv2.GenerateSecretKey()
 	require.NoError(t, err)
-
-	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
 	require.NoError(t, err)
-
-	// Prepare unsigned beacon block
-	msg := []byte("randao reveal")
-	sig, err := tblsv2.Sign(secret, msg)
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	unsignedBlindedBlock := testutil.RandomCapellaBlindedBeaconBlock()
-	unsignedBlindedBlock.Body.RANDAOReveal = eth2p0.BLSSignature(sig)
-	unsignedBlindedBlock.Slot = slot
-	unsignedBlindedBlock.ProposerIndex = vIdx
-
-	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
-		return core.DutyDefinitionSet{corePubKey: nil}, nil
-	})
-
-	// Sign blinded beacon block
-	sigRoot, err := unsignedBlindedBlock.HashTreeRoot()
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
 	require.NoError(t, err)
 
-	domain, err := signing.GetDomain(ctx, bmock, signing.DomainBeaconProposer, epoch)
-	require.NoError(t, err)
+	t.Run("randao epoch", func(t *testing.T) {
+		const epoch = 2
 
-	sigData, err := (&eth2p0.SigningData{ObjectRoot: sigRoot, Domain: domain}).HashTreeRoot()
-	require.NoError(t, err)
+		msg, err := eth2util.SignedEpoch{Epoch: epoch}.HashTreeRoot()
+		require.NoError(t, err)
+		sigData, err := signing.GetDataRoot(ctx, bmock, signing.DomainRandao, epoch, msg)
+		require.NoError(t, err)
+		sig, err := tblsv2.Sign(secret, sigData[:])
+		require.NoError(t, err)
+		randao := eth2p0.BLSSignature(sig)
 
-	s, err := tblsv2.Sign(secret, sigData[:])
-	require.NoError(t, err)
+		block := &eth2spec.VersionedBeaconBlock{
+			Version: eth2spec.DataVersionPhase0,
+			Phase0:  testutil.RandomPhase0BeaconBlock(),
+		}
+		block.Phase0.Slot = slot
+		block.Phase0.Body.RANDAOReveal = randao
 
-	signedBlindedBlock := &eth2api.VersionedSignedBlindedBeaconBlock{
-		Version: eth2spec.DataVersionCapella,
-		Capella: &eth2capella.SignedBlindedBeaconBlock{
-			Message:   unsignedBlindedBlock,
-			Signature: eth2p0.BLSSignature(s),
-		},
-	}
+		vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+			return core.DutyDefinitionSet{corePubKey: nil}, nil
+		})
+		vapi.RegisterAwaitBeaconBlock(func(ctx context.Context, slot int64) (*eth2spec.VersionedBeaconBlock, error) {
+			return block, nil
+		})
 
-	// Register subscriber
-	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		block, ok := set[corePubKey].SignedData.(core.VersionedSignedBlindedBeaconBlock)
-		require.True(t, ok)
-		require.Equal(t, *signedBlindedBlock, block.VersionedSignedBlindedBeaconBlock)
+		var stored core.ParSignedDataSet
+		vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+			stored = set
+			return nil
+		})
 
-		return nil
+		resp, err := vapi.BeaconBlockProposal(ctx, slot, randao, []byte{})
+		require.NoError(t, err)
+		require.Equal(t, block, resp)
+		require.Equal(t, core.ParSignedDataSet{corePubKey: core.NewPartialSignedRandao(epoch, randao, shareIdx)}, stored)
 	})
 
-	err = vapi.SubmitBlindedBeaconBlock(ctx, signedBlindedBlock)
-	require.NoError(t, err)
+	t.Run("committee index", func(t *testing.T) {
+		aggBits := bitfield.NewBitlist(8)
+		aggBits.SetBitAt(1, true)
+
+		att := &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Slot:   slot,
+				Index:  4, // MAX_COMMITTEES_PER_SLOT is 4 in the minimal preset.
+				Source: &eth2p0.Checkpoint{},
+				Target: &eth2p0.Checkpoint{},
+			},
+		}
+
+		err := vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att})
+		require.ErrorContains(t, err, "attestation committee index out of range")
+	})
 }
 
-func TestComponent_SubmitBlindedBeaconBlockInvalidSignature(t *testing.T) {
+func TestComponent_VerificationPoolIsolation(t *testing.T) {
 	ctx := context.Background()
 
-	// Create keys (just use normal keys, not split tbls)
-	secret, err := tblsv2.GenerateSecretKey()
-	require.NoError(t, err)
-
-	pubkey, err := tblsv2.SecretToPublicKey(secret)
-	require.NoError(t, err)
-
 	const (
-		vIdx     = 1
 		shareIdx = 1
 		slot     = 123
 	)
 
-	// Convert pubkey
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
 	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
 	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	// Configure beacon mock
 	bmock, err := beaconmock.New()
 	require.NoError(t, err)
-
-	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+	spec, err := bmock.Spec(ctx)
 	require.NoError(t, err)
 
-	// Prepare unsigned beacon block
-	msg := []byte("randao reveal")
-	sig, err := tblsv2.Sign(secret, msg)
-	require.NoError(t, err)
+	// Attestation verification blocks on attester domain queries until unblocked.
+	eth2Cl := gatedDomainClient{
+		Client:     bmock,
+		domainType: spec[string(signing.DomainBeaconAttester)].(eth2p0.DomainType),
+		entered:    make(chan struct{}, 2),
+		unblock:    make(chan struct{}),
+	}
 
-	unsignedBlindedBlock := testutil.RandomCapellaBlindedBeaconBlock()
-	unsignedBlindedBlock.Body.RANDAOReveal = eth2p0.BLSSignature(sig)
-	unsignedBlindedBlock.Slot = slot
-	unsignedBlindedBlock.ProposerIndex = vIdx
+	vapi, err := validatorapi.New(eth2Cl, allPubSharesByKey, shareIdx,
+		validatorapi.WithAsyncVerify(true),
+		validatorapi.WithVerificationWorkers(validatorapi.VerifyClassOther, 1),
+	)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, vapi.Close(ctx))
+	}()
 
-	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+	vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+		return corePubKey, nil
+	})
+	vapi.RegisterGetDutyDefinition(func(context.Context, core.Duty) (core.DutyDefinitionSet, error) {
 		return core.DutyDefinitionSet{corePubKey: nil}, nil
 	})
+	block := &eth2spec.VersionedBeaconBlock{
+		Version: eth2spec.DataVersionPhase0,
+		Phase0:  testutil.RandomPhase0BeaconBlock(),
+	}
+	vapi.RegisterAwaitBeaconBlock(func(context.Context, int64) (*eth2spec.VersionedBeaconBlock, error) {
+		return block, nil
+	})
+	vapi.Subscribe(func(context.Context, core.Duty, core.ParSignedDataSet) error {
+		return nil
+	})
 
-	// Add invalid Signature to blinded beacon block
+	// newAtt returns an attestation targeting a different epoch per slot, so its verification
+	// queries an uncached signing domain.
+	newAtt := func(slot eth2p0.Slot) *eth2p0.Attestation {
+		aggBits := bitfield.NewBitlist(8)
+		aggBits.SetBitAt(1, true)
+
+		return &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Slot:   slot,
+				Source: &eth2p0.Checkpoint{},
+				Target: &eth2p0.Checkpoint{Epoch: eth2p0.Epoch(slot)},
+			},
+			Signature: testutil.RandomEth2Signature(),
+		}
+	}
 
-	s, err := tblsv2.Sign(secret, []byte("invalid msg"))
-	require.NoError(t, err)
+	verifyQueued := func() float64 {
+		registry, err := promauto.NewRegistry(nil)
+		require.NoError(t, err)
 
-	signedBlindedBlock := &eth2api.VersionedSignedBlindedBeaconBlock{
-		Version: eth2spec.DataVersionCapella,
-		Capella: &eth2capella.SignedBlindedBeaconBlock{
-			Message:   unsignedBlindedBlock,
-			Signature: eth2p0.BLSSignature(s),
-		},
+		families, err := registry.Gather()
+		require.NoError(t, err)
+
+		for _, family := range families {
+			if family.GetName() != "core_validatorapi_verification_queued_total" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				if metric.GetLabel()[0].GetValue() == string(validatorapi.VerifyClassOther) {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+
+		return 0
 	}
 
-	// Register subscriber
-	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		block, ok := set[corePubKey].SignedData.(core.VersionedSignedBlindedBeaconBlock)
-		require.True(t, ok)
-		require.Equal(t, signedBlindedBlock, block)
+	queued := verifyQueued()
 
-		return nil
-	})
+	// The first attestation verification occupies the only worker.
+	require.NoError(t, vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(1)}))
+	<-eth2Cl.entered
 
-	err = vapi.SubmitBlindedBeaconBlock(ctx, signedBlindedBlock)
-	require.ErrorContains(t, err, "signature not verified")
-}
+	// The backlog of attestation verifications queues for the worker.
+	require.NoError(t, vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(2)}))
+	require.Eventually(t, func() bool {
+		return verifyQueued() == queued+1
+	}, time.Second, time.Millisecond)
+	require.Empty(t, eth2Cl.entered)
 
-func TestComponent_SubmitBlindedBeaconBlockInvalidBlock(t *testing.T) {
-	ctx := context.Background()
-	shareIdx := 1
-	// Create keys (just use normal keys, not split tbls)
-	pubkey := testutil.RandomCorePubKey(t)
+	// Randao verification is processed by the separate proposal pool and completes promptly.
+	epoch := eth2p0.Epoch(slot / spec["SLOTS_PER_EPOCH"].(uint64))
+	msg, err := eth2util.SignedEpoch{Epoch: epoch}.HashTreeRoot()
+	require.NoError(t, err)
+	sigData, err := signing.GetDataRoot(ctx, bmock, signing.DomainRandao, epoch, msg)
+	require.NoError(t, err)
+	sig, err := tblsv2.Sign(secret, sigData[:])
+	require.NoError(t, err)
 
-	// Convert pubkey
-	pkb, err := pubkey.Bytes()
+	proposalCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	resp, err := vapi.BeaconBlockProposal(proposalCtx, slot, eth2p0.BLSSignature(sig), nil)
 	require.NoError(t, err)
+	require.Equal(t, block, resp)
 
-	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{pubkey: {shareIdx: *(*tblsv2.PublicKey)(pkb)}} // Maps self to self since not tbls
+	// The queued attestation verification proceeds once the worker is available.
+	close(eth2Cl.unblock)
+	<-eth2Cl.entered
+}
 
-	// Configure beacon mock
-	bmock, err := beaconmock.New()
+// gatedDomainClient is an eth2 client that signals and blocks queries of the domain type until unblocked.
+type gatedDomainClient struct {
+	eth2wrap.Client
+	domainType eth2p0.DomainType
+	entered    chan struct{}
+	unblock    chan struct{}
+}
+
+func (c gatedDomainClient) Domain(ctx context.Context, domainType eth2p0.DomainType, epoch eth2p0.Epoch) (eth2p0.Domain, error) {
+	if domainType != c.domainType {
+		return c.Client.Domain(ctx, domainType, epoch)
+	}
+
+	c.entered <- struct{}{}
+
+	select {
+	case <-ctx.Done():
+		return eth2p0.Domain{}, ctx.Err()
+	case <-c.unblock:
+	}
+
+	return c.Client.Domain(ctx, domainType, epoch)
+}
+
+func TestComponent_Maintenance(t *testing.T) {
+	ctx := context.Background()
+	eth2Cl, err := beaconmock.New()
 	require.NoError(t, err)
 
-	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+	component, err := validatorapi.NewComponentInsecure(t, eth2Cl, 0)
 	require.NoError(t, err)
 
-	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+	pubkey := testutil.RandomCorePubKey(t)
+	component.RegisterGetDutyDefinition(func(context.Context, core.Duty) (core.DutyDefinitionSet, error) {
 		return core.DutyDefinitionSet{pubkey: nil}, nil
 	})
 
-	// invalid block scenarios
-	tests := []struct {
-		name   string
-		block  *eth2api.VersionedSignedBlindedBeaconBlock
-		errMsg string
-	}{
-		{
-			name:   "no bellatrix block",
-			block:  &eth2api.VersionedSignedBlindedBeaconBlock{Version: eth2spec.DataVersionBellatrix},
-			errMsg: "no bellatrix block",
-		},
-		{
-			name:   "none",
-			block:  &eth2api.VersionedSignedBlindedBeaconBlock{Version: eth2spec.DataVersion(4)},
-			errMsg: "unsupported version",
-		},
-		{
-			name: "no bellatrix sig",
-			block: &eth2api.VersionedSignedBlindedBeaconBlock{
-				Version: eth2spec.DataVersionBellatrix,
-				Bellatrix: &eth2bellatrix.SignedBlindedBeaconBlock{
-					Message:   &eth2bellatrix.BlindedBeaconBlock{Slot: eth2p0.Slot(123), Body: testutil.RandomBellatrixBlindedBeaconBlockBody()},
-					Signature: eth2p0.BLSSignature{},
-				},
-			},
-			errMsg: "no signature found",
-		},
-		{
-			name: "no capella sig",
-			block: &eth2api.VersionedSignedBlindedBeaconBlock{
-				Version: eth2spec.DataVersionCapella,
-				Capella: &eth2capella.SignedBlindedBeaconBlock{
-					Message:   &eth2capella.BlindedBeaconBlock{Slot: eth2p0.Slot(123), Body: testutil.RandomCapellaBlindedBeaconBlockBody()},
-					Signature: eth2p0.BLSSignature{},
-				},
-			},
-			errMsg: "no signature found",
-		},
+	requireMaintenance := func(t *testing.T, err error) {
+		t.Helper()
+		require.ErrorContains(t, err, "maintenance mode")
+		require.Equal(t, http.StatusServiceUnavailable, validatorapi.ErrorToHTTPStatus(err))
 	}
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			err = vapi.SubmitBlindedBeaconBlock(ctx, test.block)
-			require.ErrorContains(t, err, test.errMsg)
-		})
-	}
+	component.SetMaintenance(true)
+
+	// Submissions are rejected.
+	requireMaintenance(t, component.SubmitAttestations(ctx, nil))
+	requireMaintenance(t, component.SubmitAggregateAttestations(ctx, nil))
+	requireMaintenance(t, component.SubmitSyncCommitteeMessages(ctx, nil))
+	requireMaintenance(t, component.SubmitSyncCommitteeContributions(ctx, nil))
+	requireMaintenance(t, component.SubmitValidatorRegistrations(ctx, nil))
+	requireMaintenance(t, component.SubmitVoluntaryExit(ctx, testutil.RandomExit()))
+	requireMaintenance(t, component.SubmitBeaconBlock(ctx, testutil.RandomCapellaVersionedSignedBeaconBlock()))
+	_, err = component.BeaconBlockProposal(ctx, 1, testutil.RandomEth2Signature(), nil)
+	requireMaintenance(t, err)
+	_, err = component.AggregateBeaconCommitteeSelections(ctx, nil)
+	requireMaintenance(t, err)
+
+	// Read queries are served.
+	_, err = component.AttesterDuties(ctx, 0, []eth2p0.ValidatorIndex{1})
+	require.NoError(t, err)
+	_, err = component.ProposerDuties(ctx, 0, []eth2p0.ValidatorIndex{1})
+	require.NoError(t, err)
+
+	// Submissions are accepted again after maintenance.
+	component.SetMaintenance(false)
+	require.NoError(t, component.SubmitAttestations(ctx, nil))
 }
 
-func TestComponent_SubmitVoluntaryExit(t *testing.T) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+func TestComponent_SubmitBeaconBlock(t *testing.T) {
+	ctx := context.Background()
 
 	// Create keys (just use normal keys, not split tbls)
 	secret, err := tblsv2.GenerateSecretKey()
@@ -942,9 +1419,10 @@ func TestComponent_SubmitVoluntaryExit(t *testing.T) {
 	require.NoError(t, err)
 
 	const (
-		vIdx     = 2
+		vIdx     = 1
 		shareIdx = 1
-		epoch    = 10
+		slot     = 123
+		epoch    = eth2p0.Epoch(3)
 	)
 
 	// Convert pubkey
@@ -952,65 +1430,68 @@ func TestComponent_SubmitVoluntaryExit(t *testing.T) {
 	require.NoError(t, err)
 	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	// Prep beacon mock validators
-	validator := beaconmock.ValidatorSetA[vIdx]
-	validator.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
-	require.NoError(t, err)
-
 	// Configure beacon mock
-	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	bmock, err := beaconmock.New()
 	require.NoError(t, err)
 
 	// Construct the validator api component
 	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
 	require.NoError(t, err)
 
-	// Prepare unsigned voluntary exit
-	exit := &eth2p0.VoluntaryExit{
-		Epoch:          epoch,
-		ValidatorIndex: vIdx,
+	// Prepare unsigned beacon block
+	msg := []byte("randao reveal")
+	sig, err := tblsv2.Sign(secret, msg)
+	require.NoError(t, err)
+
+	randao := eth2p0.BLSSignature(sig)
+	unsignedBlock := &eth2spec.VersionedBeaconBlock{
+		Version: eth2spec.DataVersionPhase0,
+		Phase0:  testutil.RandomPhase0BeaconBlock(),
 	}
+	unsignedBlock.Phase0.Body.RANDAOReveal = randao
+	unsignedBlock.Phase0.Slot = slot
+	unsignedBlock.Phase0.ProposerIndex = vIdx
 
-	// sign voluntary exit
-	sigRoot, err := exit.HashTreeRoot()
+	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+		return core.DutyDefinitionSet{corePubKey: nil}, nil
+	})
+
+	// Sign beacon block
+	sigRoot, err := unsignedBlock.Root()
 	require.NoError(t, err)
 
-	domain, err := signing.GetDomain(ctx, bmock, signing.DomainExit, epoch)
+	domain, err := signing.GetDomain(ctx, bmock, signing.DomainBeaconProposer, epoch)
 	require.NoError(t, err)
 
 	sigData, err := (&eth2p0.SigningData{ObjectRoot: sigRoot, Domain: domain}).HashTreeRoot()
 	require.NoError(t, err)
 
-	sig, err := tblsv2.Sign(secret, sigData[:])
+	s, err := tblsv2.Sign(secret, sigData[:])
 	require.NoError(t, err)
 
-	signedExit := &eth2p0.SignedVoluntaryExit{
-		Message:   exit,
-		Signature: eth2p0.BLSSignature(sig),
+	signedBlock := &eth2spec.VersionedSignedBeaconBlock{
+		Version: eth2spec.DataVersionPhase0,
+		Phase0: &eth2p0.SignedBeaconBlock{
+			Message:   unsignedBlock.Phase0,
+			Signature: eth2p0.BLSSignature(s),
+		},
 	}
 
 	// Register subscriber
 	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		signedExit2, ok := set[corePubKey].SignedData.(core.SignedVoluntaryExit)
+		block, ok := set[corePubKey].SignedData.(core.VersionedSignedBeaconBlock)
 		require.True(t, ok)
-		require.Equal(t, *signedExit, signedExit2.SignedVoluntaryExit)
-		cancel()
+		require.Equal(t, *signedBlock, block.VersionedSignedBeaconBlock)
 
-		return ctx.Err()
+		return nil
 	})
 
-	err = vapi.SubmitVoluntaryExit(ctx, signedExit)
-	require.ErrorIs(t, err, context.Canceled)
+	err = vapi.SubmitBeaconBlock(ctx, signedBlock)
+	require.NoError(t, err)
 }
 
-func TestComponent_SubmitVoluntaryExitInvalidSignature(t *testing.T) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	const (
-		vIdx     = 2
-		shareIdx = 1
-	)
+func TestComponent_SubmitBeaconBlockForks(t *testing.T) {
+	ctx := context.Background()
 
 	// Create keys (just use normal keys, not split tbls)
 	secret, err := tblsv2.GenerateSecretKey()
@@ -1019,127 +1500,146 @@ func TestComponent_SubmitVoluntaryExitInvalidSignature(t *testing.T) {
 	pubkey, err := tblsv2.SecretToPublicKey(secret)
 	require.NoError(t, err)
 
+	const (
+		vIdx     = 1
+		shareIdx = 1
+		slot     = 123
+		epoch    = eth2p0.Epoch(3)
+	)
+
+	// Convert pubkey
 	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
 	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	validator := beaconmock.ValidatorSetA[vIdx]
-	validator.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
-	require.NoError(t, err)
-
 	// Configure beacon mock
-	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	bmock, err := beaconmock.New()
 	require.NoError(t, err)
 
-	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	domain, err := signing.GetDomain(ctx, bmock, signing.DomainBeaconProposer, epoch)
 	require.NoError(t, err)
 
-	// Register subscriber
-	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		cancel()
-		return ctx.Err()
-	})
+	// sign returns the signed block after setting the slot, proposer index and signature.
+	sign := func(t *testing.T, block *eth2spec.VersionedSignedBeaconBlock) *eth2spec.VersionedSignedBeaconBlock {
+		t.Helper()
+
+		var (
+			root eth2p0.Root
+			err  error
+		)
+		switch block.Version {
+		case eth2spec.DataVersionPhase0:
+			block.Phase0.Message.Slot = slot
+			block.Phase0.Message.ProposerIndex = vIdx
+			root, err = block.Phase0.Message.HashTreeRoot()
+		case eth2spec.DataVersionAltair:
+			block.Altair.Message.Slot = slot
+			block.Altair.Message.ProposerIndex = vIdx
+			root, err = block.Altair.Message.HashTreeRoot()
+		case eth2spec.DataVersionBellatrix:
+			block.Bellatrix.Message.Slot = slot
+			block.Bellatrix.Message.ProposerIndex = vIdx
+			root, err = block.Bellatrix.Message.HashTreeRoot()
+		case eth2spec.DataVersionCapella:
+			block.Capella.Message.Slot = slot
+			block.Capella.Message.ProposerIndex = vIdx
+			root, err = block.Capella.Message.HashTreeRoot()
+		}
+		require.NoError(t, err)
 
-	sig, err := tblsv2.Sign(secret, []byte("invalid message"))
-	require.NoError(t, err)
+		sigData, err := (&eth2p0.SigningData{ObjectRoot: root, Domain: domain}).HashTreeRoot()
+		require.NoError(t, err)
 
-	exit := testutil.RandomExit()
-	exit.Message.ValidatorIndex = vIdx
-	exit.Signature = eth2p0.BLSSignature(sig)
+		s, err := tblsv2.Sign(secret, sigData[:])
+		require.NoError(t, err)
 
-	err = vapi.SubmitVoluntaryExit(ctx, exit)
-	require.ErrorContains(t, err, "signature not verified")
-}
+		switch block.Version {
+		case eth2spec.DataVersionPhase0:
+			block.Phase0.Signature = eth2p0.BLSSignature(s)
+		case eth2spec.DataVersionAltair:
+			block.Altair.Signature = eth2p0.BLSSignature(s)
+		case eth2spec.DataVersionBellatrix:
+			block.Bellatrix.Signature = eth2p0.BLSSignature(s)
+		case eth2spec.DataVersionCapella:
+			block.Capella.Signature = eth2p0.BLSSignature(s)
+		}
 
-func TestComponent_Duties(t *testing.T) {
-	ctx := context.Background()
+		return block
+	}
 
-	// Configure validator
-	const (
-		vIdx     = 123
-		shareIdx = 1
-		epch     = 456
-	)
+	blocks := []*eth2spec.VersionedSignedBeaconBlock{
+		{
+			Version: eth2spec.DataVersionPhase0,
+			Phase0:  &eth2p0.SignedBeaconBlock{Message: testutil.RandomPhase0BeaconBlock()},
+		},
+		{
+			Version: eth2spec.DataVersionAltair,
+			Altair:  &altair.SignedBeaconBlock{Message: testutil.RandomAltairBeaconBlock()},
+		},
+		{
+			Version:   eth2spec.DataVersionBellatrix,
+			Bellatrix: &bellatrix.SignedBeaconBlock{Message: testutil.RandomBellatrixBeaconBlock()},
+		},
+		{
+			Version: eth2spec.DataVersionCapella,
+			Capella: &capella.SignedBeaconBlock{Message: testutil.RandomCapellaBeaconBlock()},
+		},
+	}
 
-	// Create pubkey and pubshare
-	eth2Pubkey := testutil.RandomEth2PubKey(t)
-	eth2Share := testutil.RandomEth2PubKey(t)
+	for _, block := range blocks {
+		t.Run(block.Version.String(), func(t *testing.T) {
+			// Construct the validator api component
+			vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+			require.NoError(t, err)
 
-	pubshare := tblsv2.PublicKey(eth2Share)
-	pubkey := tblsv2.PublicKey(eth2Pubkey)
-	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
-	require.NoError(t, err)
+			vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+				if duty.Slot != slot {
+					return nil, errors.New("duty not scheduled")
+				}
 
-	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubshare}}
-	// Configure beacon mock
-	bmock, err := beaconmock.New()
-	require.NoError(t, err)
+				return core.DutyDefinitionSet{corePubKey: nil}, nil
+			})
 
-	t.Run("proposer_duties", func(t *testing.T) {
-		bmock.ProposerDutiesFunc = func(ctx context.Context, epoch eth2p0.Epoch, indices []eth2p0.ValidatorIndex) ([]*eth2v1.ProposerDuty, error) {
-			require.Equal(t, epoch, eth2p0.Epoch(epch))
-			require.Equal(t, []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)}, indices)
+			signedBlock := sign(t, block)
 
-			return []*eth2v1.ProposerDuty{{
-				PubKey:         eth2Pubkey,
-				ValidatorIndex: vIdx,
-			}}, nil
-		}
+			var stored int
+			vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+				require.Equal(t, core.NewProposerDuty(slot), duty)
 
-		// Construct the validator api component
-		vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
-		require.NoError(t, err)
-		duties, err := vapi.ProposerDuties(ctx, eth2p0.Epoch(epch), []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)})
-		require.NoError(t, err)
-		require.Len(t, duties, 1)
-		require.Equal(t, duties[0].PubKey, eth2Share)
-	})
-
-	t.Run("attester_duties", func(t *testing.T) {
-		bmock.AttesterDutiesFunc = func(_ context.Context, epoch eth2p0.Epoch, indices []eth2p0.ValidatorIndex) ([]*eth2v1.AttesterDuty, error) {
-			require.Equal(t, epoch, eth2p0.Epoch(epch))
-			require.Equal(t, []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)}, indices)
-
-			return []*eth2v1.AttesterDuty{{
-				PubKey:         eth2Pubkey,
-				ValidatorIndex: vIdx,
-			}}, nil
-		}
-
-		// Construct the validator api component
-		vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
-		require.NoError(t, err)
-		duties, err := vapi.AttesterDuties(ctx, eth2p0.Epoch(epch), []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)})
-		require.NoError(t, err)
-		require.Len(t, duties, 1)
-		require.Equal(t, duties[0].PubKey, eth2Share)
-	})
+				block, ok := set[corePubKey].SignedData.(core.VersionedSignedBeaconBlock)
+				require.True(t, ok)
+				require.Equal(t, *signedBlock, block.VersionedSignedBeaconBlock)
+				stored++
 
-	t.Run("sync_committee_duties", func(t *testing.T) {
-		bmock.SyncCommitteeDutiesFunc = func(ctx context.Context, epoch eth2p0.Epoch, indices []eth2p0.ValidatorIndex) ([]*eth2v1.SyncCommitteeDuty, error) {
-			require.Equal(t, epoch, eth2p0.Epoch(epch))
-			require.Equal(t, []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)}, indices)
+				return nil
+			})
 
-			return []*eth2v1.SyncCommitteeDuty{{
-				PubKey:         eth2Pubkey,
-				ValidatorIndex: vIdx,
-			}}, nil
-		}
+			err = vapi.SubmitBeaconBlock(ctx, signedBlock)
+			require.NoError(t, err)
+			require.Equal(t, 1, stored)
+
+			// Blocks for slots without a scheduled proposal are rejected.
+			switch signedBlock.Version {
+			case eth2spec.DataVersionPhase0:
+				signedBlock.Phase0.Message.Slot++
+			case eth2spec.DataVersionAltair:
+				signedBlock.Altair.Message.Slot++
+			case eth2spec.DataVersionBellatrix:
+				signedBlock.Bellatrix.Message.Slot++
+			case eth2spec.DataVersionCapella:
+				signedBlock.Capella.Message.Slot++
+			}
 
-		// Construct the validator api component
-		vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
-		require.NoError(t, err)
-		duties, err := vapi.SyncCommitteeDuties(ctx, eth2p0.Epoch(epch), []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)})
-		require.NoError(t, err)
-		require.Len(t, duties, 1)
-		require.Equal(t, duties[0].PubKey, eth2Share)
-	})
+			err = vapi.SubmitBeaconBlock(ctx, signedBlock)
+			require.ErrorContains(t, err, "duty not scheduled")
+			require.Equal(t, 1, stored)
+		})
+	}
 }
 
-func TestComponent_SubmitValidatorRegistration(t *testing.T) {
+func TestComponent_SubmitBeaconBlockInvalidSignature(t *testing.T) {
 	ctx := context.Background()
-	shareIdx := 1
+
 	// Create keys (just use normal keys, not split tbls)
 	secret, err := tblsv2.GenerateSecretKey()
 	require.NoError(t, err)
@@ -1147,8 +1647,13 @@ func TestComponent_SubmitValidatorRegistration(t *testing.T) {
 	pubkey, err := tblsv2.SecretToPublicKey(secret)
 	require.NoError(t, err)
 
+	const (
+		vIdx     = 1
+		shareIdx = 1
+		slot     = 123
+	)
+
 	// Convert pubkey
-	eth2Pubkey := eth2p0.BLSPubKey(pubkey)
 	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
 	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
@@ -1158,629 +1663,3127 @@ func TestComponent_SubmitValidatorRegistration(t *testing.T) {
 	require.NoError(t, err)
 
 	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
 	require.NoError(t, err)
 
-	unsigned := testutil.RandomValidatorRegistration(t)
-	unsigned.Pubkey = eth2Pubkey
-	unsigned.Timestamp, err = bmock.GenesisTime(ctx) // Set timestamp to genesis which should result in epoch 0 and slot 0.
+	// Prepare unsigned beacon block
+	msg := []byte("randao reveal")
+	sig, err := tblsv2.Sign(secret, msg)
 	require.NoError(t, err)
 
-	// Sign validator (builder) registration
-	sigRoot, err := unsigned.HashTreeRoot()
-	require.NoError(t, err)
+	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+		return core.DutyDefinitionSet{corePubKey: nil}, nil
+	})
 
-	sigData, err := signing.GetDataRoot(ctx, bmock, signing.DomainApplicationBuilder, 0, sigRoot)
+	// Add invalid Signature to beacon block
+	s, err := tblsv2.Sign(secret, []byte("invalid msg"))
 	require.NoError(t, err)
 
-	s, err := tblsv2.Sign(secret, sigData[:])
-	require.NoError(t, err)
+	unsignedBlock := testutil.RandomPhase0BeaconBlock()
+	unsignedBlock.Body.RANDAOReveal = eth2p0.BLSSignature(sig)
+	unsignedBlock.Slot = slot
+	unsignedBlock.ProposerIndex = vIdx
 
-	signed := &eth2api.VersionedSignedValidatorRegistration{
-		Version: eth2spec.BuilderVersionV1,
-		V1: &eth2v1.SignedValidatorRegistration{
-			Message:   unsigned,
+	signedBlock := &eth2spec.VersionedSignedBeaconBlock{
+		Version: eth2spec.DataVersionPhase0,
+		Phase0: &eth2p0.SignedBeaconBlock{
+			Message:   unsignedBlock,
 			Signature: eth2p0.BLSSignature(s),
 		},
 	}
 
-	output := make(chan core.ParSignedDataSet)
-
 	// Register subscriber
 	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		require.Equal(t, core.NewBuilderRegistrationDuty(0), duty)
-
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case output <- set:
-		}
+		block, ok := set[corePubKey].SignedData.(core.VersionedSignedBeaconBlock)
+		require.True(t, ok)
+		require.Equal(t, signedBlock, block)
 
 		return nil
 	})
 
-	go func() {
-		err = vapi.SubmitValidatorRegistrations(ctx, []*eth2api.VersionedSignedValidatorRegistration{signed})
-		require.NoError(t, err)
-	}()
-
-	// Assert output, this is blocking since it requires subscribe block to execute.
-	actualData := <-output
-	registration, ok := actualData[corePubKey].SignedData.(core.VersionedSignedValidatorRegistration)
-	require.True(t, ok)
-	require.Equal(t, *signed, registration.VersionedSignedValidatorRegistration)
+	err = vapi.SubmitBeaconBlock(ctx, signedBlock)
+	require.ErrorContains(t, err, "signature not verified")
 }
 
-func TestComponent_SubmitValidatorRegistrationInvalidSignature(t *testing.T) {
+func TestComponent_SubmitBeaconBlockInvalidBlock(t *testing.T) {
 	ctx := context.Background()
 	shareIdx := 1
 	// Create keys (just use normal keys, not split tbls)
-	secret, err := tblsv2.GenerateSecretKey()
-	require.NoError(t, err)
+	pubkey := testutil.RandomCorePubKey(t)
 
-	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	pkb, err := pubkey.Bytes()
 	require.NoError(t, err)
 
-	// Convert pubkey
-	eth2Pubkey := eth2p0.BLSPubKey(pubkey)
-	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	tblsPubkey := *(*tblsv2.PublicKey)(pkb)
 	require.NoError(t, err)
-	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{pubkey: {shareIdx: tblsPubkey}} // Maps self to self since not tbls
 
 	// Configure beacon mock
 	bmock, err := beaconmock.New()
 	require.NoError(t, err)
 
 	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
-	require.NoError(t, err)
-
-	unsigned := testutil.RandomValidatorRegistration(t)
-	unsigned.Pubkey = eth2Pubkey
-	unsigned.Timestamp, err = bmock.GenesisTime(ctx) // Set timestamp to genesis which should result in epoch 0 and slot 0.
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
 	require.NoError(t, err)
 
 	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
-		return core.DutyDefinitionSet{corePubKey: nil}, nil
+		return core.DutyDefinitionSet{pubkey: nil}, nil
 	})
 
-	// Add invalid Signature to validator (builder) registration
+	// invalid block scenarios
+	tests := []struct {
+		name   string
+		block  *eth2spec.VersionedSignedBeaconBlock
+		errMsg string
+	}{
+		{
+			name:   "no phase 0 block",
+			block:  &eth2spec.VersionedSignedBeaconBlock{Version: eth2spec.DataVersionPhase0},
+			errMsg: "no phase0 block",
+		},
+		{
+			name:   "no altair block",
+			block:  &eth2spec.VersionedSignedBeaconBlock{Version: eth2spec.DataVersionAltair},
+			errMsg: "no altair block",
+		},
+		{
+			name:   "no bellatrix block",
+			block:  &eth2spec.VersionedSignedBeaconBlock{Version: eth2spec.DataVersionBellatrix},
+			errMsg: "no bellatrix block",
+		},
+		{
+			name:   "no capella block",
+			block:  &eth2spec.VersionedSignedBeaconBlock{Version: eth2spec.DataVersionCapella},
+			errMsg: "no capella block",
+		},
+		{
+			name:   "none",
+			block:  &eth2spec.VersionedSignedBeaconBlock{Version: eth2spec.DataVersion(4)},
+			errMsg: "unknown version",
+		},
+		{
+			name: "no phase 0 sig",
+			block: &eth2spec.VersionedSignedBeaconBlock{
+				Version: eth2spec.DataVersionPhase0,
+				Phase0: &eth2p0.SignedBeaconBlock{
+					Message:   &eth2p0.BeaconBlock{Slot: eth2p0.Slot(123), Body: testutil.RandomPhase0BeaconBlockBody()},
+					Signature: eth2p0.BLSSignature{},
+				},
+			},
+			errMsg: "no signature found",
+		},
+		{
+			name: "no altair sig",
+			block: &eth2spec.VersionedSignedBeaconBlock{
+				Version: eth2spec.DataVersionAltair,
+				Altair: &altair.SignedBeaconBlock{
+					Message:   &altair.BeaconBlock{Slot: eth2p0.Slot(123), Body: testutil.RandomAltairBeaconBlockBody()},
+					Signature: eth2p0.BLSSignature{},
+				},
+			},
+			errMsg: "no signature found",
+		},
+		{
+			name: "no bellatrix sig",
+			block: &eth2spec.VersionedSignedBeaconBlock{
+				Version: eth2spec.DataVersionBellatrix,
+				Bellatrix: &bellatrix.SignedBeaconBlock{
+					Message:   &bellatrix.BeaconBlock{Slot: eth2p0.Slot(123), Body: testutil.RandomBellatrixBeaconBlockBody()},
+					Signature: eth2p0.BLSSignature{},
+				},
+			},
+			errMsg: "no signature found",
+		},
+	}
 
-	s, err := tblsv2.Sign(secret, []byte("invalid msg"))
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err = vapi.SubmitBeaconBlock(ctx, test.block)
+			require.ErrorContains(t, err, test.errMsg)
+		})
+	}
+}
+
+func TestComponent_BlindedBeaconBlockProposal(t *testing.T) {
+	ctx := context.Background()
+	eth2Cl, err := beaconmock.New()
+	require.NoError(t, err)
+
+	const (
+		slot = 123
+		vIdx = 1
+	)
+
+	slotsPerEpoch, err := eth2Cl.SlotsPerEpoch(context.Background())
+	require.NoError(t, err)
+
+	epoch := eth2p0.Epoch(uint64(slot) / slotsPerEpoch)
+
+	component, err := validatorapi.NewComponentInsecure(t, eth2Cl, vIdx)
+	require.NoError(t, err)
+
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pk, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	msg := []byte("randao reveal")
+	sig, err := tblsv2.Sign(secret, msg)
+	require.NoError(t, err)
+
+	randao := eth2p0.BLSSignature(sig)
+	pubkey, err := core.PubKeyFromBytes(pk[:])
 	require.NoError(t, err)
 
-	signed := &eth2api.VersionedSignedValidatorRegistration{
-		Version: eth2spec.BuilderVersionV1,
-		V1: &eth2v1.SignedValidatorRegistration{
-			Message:   unsigned,
-			Signature: eth2p0.BLSSignature(s),
-		},
-	}
+	block1 := &eth2api.VersionedBlindedBeaconBlock{
+		Version:   eth2spec.DataVersionPhase0,
+		Bellatrix: testutil.RandomBellatrixBlindedBeaconBlock(),
+	}
+	block1.Bellatrix.Slot = slot
+	block1.Bellatrix.ProposerIndex = vIdx
+	block1.Bellatrix.Body.RANDAOReveal = randao
+
+	component.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+		return core.DutyDefinitionSet{pubkey: nil}, nil
+	})
+
+	component.RegisterAwaitBlindedBeaconBlock(func(ctx context.Context, slot int64) (*eth2api.VersionedBlindedBeaconBlock, error) {
+		return block1, nil
+	})
+
+	component.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Equal(t, set, core.ParSignedDataSet{
+			pubkey: core.NewPartialSignedRandao(epoch, randao, vIdx),
+		})
+		require.Equal(t, duty, core.NewRandaoDuty(slot))
+
+		return nil
+	})
+
+	block2, err := component.BlindedBeaconBlockProposal(ctx, slot, randao, []byte{})
+	require.NoError(t, err)
+	require.Equal(t, block1, block2)
+}
+
+func TestComponent_SubmitBlindedBeaconBlock(t *testing.T) {
+	ctx := context.Background()
+
+	// Create keys (just use normal keys, not split tbls)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	const (
+		vIdx     = 1
+		shareIdx = 1
+		slot     = 123
+		epoch    = eth2p0.Epoch(3)
+	)
+
+	// Convert pubkey
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	// Configure beacon mock
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	// Construct the validator api component
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+	require.NoError(t, err)
+
+	// Prepare unsigned beacon block
+	msg := []byte("randao reveal")
+	sig, err := tblsv2.Sign(secret, msg)
+	require.NoError(t, err)
+
+	unsignedBlindedBlock := testutil.RandomCapellaBlindedBeaconBlock()
+	unsignedBlindedBlock.Body.RANDAOReveal = eth2p0.BLSSignature(sig)
+	unsignedBlindedBlock.Slot = slot
+	unsignedBlindedBlock.ProposerIndex = vIdx
+
+	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+		return core.DutyDefinitionSet{corePubKey: nil}, nil
+	})
+
+	// Sign blinded beacon block
+	sigRoot, err := unsignedBlindedBlock.HashTreeRoot()
+	require.NoError(t, err)
+
+	domain, err := signing.GetDomain(ctx, bmock, signing.DomainBeaconProposer, epoch)
+	require.NoError(t, err)
+
+	sigData, err := (&eth2p0.SigningData{ObjectRoot: sigRoot, Domain: domain}).HashTreeRoot()
+	require.NoError(t, err)
+
+	s, err := tblsv2.Sign(secret, sigData[:])
+	require.NoError(t, err)
+
+	signedBlindedBlock := &eth2api.VersionedSignedBlindedBeaconBlock{
+		Version: eth2spec.DataVersionCapella,
+		Capella: &eth2capella.SignedBlindedBeaconBlock{
+			Message:   unsignedBlindedBlock,
+			Signature: eth2p0.BLSSignature(s),
+		},
+	}
+
+	// Register subscriber
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		block, ok := set[corePubKey].SignedData.(core.VersionedSignedBlindedBeaconBlock)
+		require.True(t, ok)
+		require.Equal(t, *signedBlindedBlock, block.VersionedSignedBlindedBeaconBlock)
+
+		return nil
+	})
+
+	err = vapi.SubmitBlindedBeaconBlock(ctx, signedBlindedBlock)
+	require.NoError(t, err)
+}
+
+func TestComponent_SubmitBlindedBeaconBlockForks(t *testing.T) {
+	ctx := context.Background()
+
+	// Create keys (just use normal keys, not split tbls)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	const (
+		vIdx     = 1
+		shareIdx = 1
+		slot     = 123
+		epoch    = eth2p0.Epoch(3)
+	)
+
+	// Convert pubkey
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	// Configure beacon mock
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	domain, err := signing.GetDomain(ctx, bmock, signing.DomainBeaconProposer, epoch)
+	require.NoError(t, err)
+
+	sign := func(t *testing.T, root eth2p0.Root) eth2p0.BLSSignature {
+		t.Helper()
+
+		sigData, err := (&eth2p0.SigningData{ObjectRoot: root, Domain: domain}).HashTreeRoot()
+		require.NoError(t, err)
+
+		s, err := tblsv2.Sign(secret, sigData[:])
+		require.NoError(t, err)
+
+		return eth2p0.BLSSignature(s)
+	}
+
+	tests := []struct {
+		name  string
+		block func(t *testing.T) *eth2api.VersionedSignedBlindedBeaconBlock
+	}{
+		{
+			name: "bellatrix",
+			block: func(t *testing.T) *eth2api.VersionedSignedBlindedBeaconBlock {
+				t.Helper()
+
+				block := testutil.RandomBellatrixBlindedBeaconBlock()
+				block.Slot = slot
+				block.ProposerIndex = vIdx
+
+				root, err := block.HashTreeRoot()
+				require.NoError(t, err)
+
+				return &eth2api.VersionedSignedBlindedBeaconBlock{
+					Version: eth2spec.DataVersionBellatrix,
+					Bellatrix: &eth2bellatrix.SignedBlindedBeaconBlock{
+						Message:   block,
+						Signature: sign(t, root),
+					},
+				}
+			},
+		},
+		{
+			name: "capella",
+			block: func(t *testing.T) *eth2api.VersionedSignedBlindedBeaconBlock {
+				t.Helper()
+
+				block := testutil.RandomCapellaBlindedBeaconBlock()
+				block.Slot = slot
+				block.ProposerIndex = vIdx
+
+				root, err := block.HashTreeRoot()
+				require.NoError(t, err)
+
+				return &eth2api.VersionedSignedBlindedBeaconBlock{
+					Version: eth2spec.DataVersionCapella,
+					Capella: &eth2capella.SignedBlindedBeaconBlock{
+						Message:   block,
+						Signature: sign(t, root),
+					},
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Construct the validator api component
+			vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+			require.NoError(t, err)
+
+			vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+				return core.DutyDefinitionSet{corePubKey: nil}, nil
+			})
+
+			signedBlindedBlock := test.block(t)
+
+			var stored bool
+			vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+				require.Equal(t, core.NewBuilderProposerDuty(slot), duty)
+
+				block, ok := set[corePubKey].SignedData.(core.VersionedSignedBlindedBeaconBlock)
+				require.True(t, ok)
+				require.Equal(t, *signedBlindedBlock, block.VersionedSignedBlindedBeaconBlock)
+				require.Equal(t, shareIdx, set[corePubKey].ShareIdx)
+				stored = true
+
+				return nil
+			})
+
+			err = vapi.SubmitBlindedBeaconBlock(ctx, signedBlindedBlock)
+			require.NoError(t, err)
+			require.True(t, stored)
+
+			// Tampering with the block invalidates the signature.
+			invalid := test.block(t)
+			switch invalid.Version {
+			case eth2spec.DataVersionBellatrix:
+				invalid.Bellatrix.Signature = signedBlindedBlock.Bellatrix.Signature
+			case eth2spec.DataVersionCapella:
+				invalid.Capella.Signature = signedBlindedBlock.Capella.Signature
+			}
+
+			err = vapi.SubmitBlindedBeaconBlock(ctx, invalid)
+			require.ErrorContains(t, err, "signature not verified")
+		})
+	}
+}
+
+func TestComponent_SubmitBlindedBeaconBlockInvalidSignature(t *testing.T) {
+	ctx := context.Background()
+
+	// Create keys (just use normal keys, not split tbls)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	const (
+		vIdx     = 1
+		shareIdx = 1
+		slot     = 123
+	)
+
+	// Convert pubkey
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	// Configure beacon mock
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	// Construct the validator api component
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+	require.NoError(t, err)
+
+	// Prepare unsigned beacon block
+	msg := []byte("randao reveal")
+	sig, err := tblsv2.Sign(secret, msg)
+	require.NoError(t, err)
+
+	unsignedBlindedBlock := testutil.RandomCapellaBlindedBeaconBlock()
+	unsignedBlindedBlock.Body.RANDAOReveal = eth2p0.BLSSignature(sig)
+	unsignedBlindedBlock.Slot = slot
+	unsignedBlindedBlock.ProposerIndex = vIdx
+
+	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+		return core.DutyDefinitionSet{corePubKey: nil}, nil
+	})
+
+	// Add invalid Signature to blinded beacon block
+
+	s, err := tblsv2.Sign(secret, []byte("invalid msg"))
+	require.NoError(t, err)
+
+	signedBlindedBlock := &eth2api.VersionedSignedBlindedBeaconBlock{
+		Version: eth2spec.DataVersionCapella,
+		Capella: &eth2capella.SignedBlindedBeaconBlock{
+			Message:   unsignedBlindedBlock,
+			Signature: eth2p0.BLSSignature(s),
+		},
+	}
+
+	// Register subscriber
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		block, ok := set[corePubKey].SignedData.(core.VersionedSignedBlindedBeaconBlock)
+		require.True(t, ok)
+		require.Equal(t, signedBlindedBlock, block)
+
+		return nil
+	})
+
+	err = vapi.SubmitBlindedBeaconBlock(ctx, signedBlindedBlock)
+	require.ErrorContains(t, err, "signature not verified")
+}
+
+func TestComponent_SubmitBlindedBeaconBlockInvalidBlock(t *testing.T) {
+	ctx := context.Background()
+	shareIdx := 1
+	// Create keys (just use normal keys, not split tbls)
+	pubkey := testutil.RandomCorePubKey(t)
+
+	// Convert pubkey
+	pkb, err := pubkey.Bytes()
+	require.NoError(t, err)
+
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{pubkey: {shareIdx: *(*tblsv2.PublicKey)(pkb)}} // Maps self to self since not tbls
+
+	// Configure beacon mock
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	// Construct the validator api component
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+	require.NoError(t, err)
+
+	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+		return core.DutyDefinitionSet{pubkey: nil}, nil
+	})
+
+	// invalid block scenarios
+	tests := []struct {
+		name   string
+		block  *eth2api.VersionedSignedBlindedBeaconBlock
+		errMsg string
+	}{
+		{
+			name:   "no bellatrix block",
+			block:  &eth2api.VersionedSignedBlindedBeaconBlock{Version: eth2spec.DataVersionBellatrix},
+			errMsg: "no bellatrix block",
+		},
+		{
+			name:   "none",
+			block:  &eth2api.VersionedSignedBlindedBeaconBlock{Version: eth2spec.DataVersion(4)},
+			errMsg: "unsupported version",
+		},
+		{
+			name: "no bellatrix sig",
+			block: &eth2api.VersionedSignedBlindedBeaconBlock{
+				Version: eth2spec.DataVersionBellatrix,
+				Bellatrix: &eth2bellatrix.SignedBlindedBeaconBlock{
+					Message:   &eth2bellatrix.BlindedBeaconBlock{Slot: eth2p0.Slot(123), Body: testutil.RandomBellatrixBlindedBeaconBlockBody()},
+					Signature: eth2p0.BLSSignature{},
+				},
+			},
+			errMsg: "no signature found",
+		},
+		{
+			name: "no capella sig",
+			block: &eth2api.VersionedSignedBlindedBeaconBlock{
+				Version: eth2spec.DataVersionCapella,
+				Capella: &eth2capella.SignedBlindedBeaconBlock{
+					Message:   &eth2capella.BlindedBeaconBlock{Slot: eth2p0.Slot(123), Body: testutil.RandomCapellaBlindedBeaconBlockBody()},
+					Signature: eth2p0.BLSSignature{},
+				},
+			},
+			errMsg: "no signature found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err = vapi.SubmitBlindedBeaconBlock(ctx, test.block)
+			require.ErrorContains(t, err, test.errMsg)
+		})
+	}
+}
+
+func TestComponent_SubmitVoluntaryExit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Create keys (just use normal keys, not split tbls)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	const (
+		vIdx     = 2
+		shareIdx = 1
+		epoch    = 10
+	)
+
+	// Convert pubkey
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	// Prep beacon mock validators
+	validator := beaconmock.ValidatorSetA[vIdx]
+	validator.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
+	require.NoError(t, err)
+
+	// Configure beacon mock
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	require.NoError(t, err)
+
+	// Construct the validator api component
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	// Prepare unsigned voluntary exit
+	exit := &eth2p0.VoluntaryExit{
+		Epoch:          epoch,
+		ValidatorIndex: vIdx,
+	}
+
+	// sign voluntary exit
+	sigRoot, err := exit.HashTreeRoot()
+	require.NoError(t, err)
+
+	domain, err := signing.GetDomain(ctx, bmock, signing.DomainExit, epoch)
+	require.NoError(t, err)
+
+	sigData, err := (&eth2p0.SigningData{ObjectRoot: sigRoot, Domain: domain}).HashTreeRoot()
+	require.NoError(t, err)
+
+	sig, err := tblsv2.Sign(secret, sigData[:])
+	require.NoError(t, err)
+
+	signedExit := &eth2p0.SignedVoluntaryExit{
+		Message:   exit,
+		Signature: eth2p0.BLSSignature(sig),
+	}
+
+	// Register subscriber
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		signedExit2, ok := set[corePubKey].SignedData.(core.SignedVoluntaryExit)
+		require.True(t, ok)
+		require.Equal(t, *signedExit, signedExit2.SignedVoluntaryExit)
+		cancel()
+
+		return ctx.Err()
+	})
+
+	err = vapi.SubmitVoluntaryExit(ctx, signedExit)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestComponent_ShareRotation(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		vIdx          = 2
+		shareIdx      = 1
+		rotationEpoch = 10
+	)
+
+	// Create keys (just use normal keys, not split tbls)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+	rotatedSecret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+	rotatedPubshare, err := tblsv2.SecretToPublicKey(rotatedSecret)
+	require.NoError(t, err)
+
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	validator := beaconmock.ValidatorSetA[vIdx]
+	validator.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
+
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	require.NoError(t, err)
+	bmock.AttesterDutiesFunc = func(context.Context, eth2p0.Epoch, []eth2p0.ValidatorIndex) ([]*eth2v1.AttesterDuty, error) {
+		return []*eth2v1.AttesterDuty{{PubKey: eth2p0.BLSPubKey(pubkey), ValidatorIndex: vIdx}}, nil
+	}
+
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+	require.NoError(t, err)
+	require.NoError(t, vapi.SetShareRotation(rotationEpoch, map[core.PubKey]tblsv2.PublicKey{corePubKey: rotatedPubshare}))
+
+	vapi.Subscribe(func(context.Context, core.Duty, core.ParSignedDataSet) error {
+		return nil
+	})
+
+	// signExit returns a voluntary exit of the epoch signed by the secret.
+	signExit := func(t *testing.T, epoch eth2p0.Epoch, secret tblsv2.PrivateKey) *eth2p0.SignedVoluntaryExit {
+		t.Helper()
+
+		exit := &eth2p0.VoluntaryExit{Epoch: epoch, ValidatorIndex: vIdx}
+		sigRoot, err := exit.HashTreeRoot()
+		require.NoError(t, err)
+
+		sigData, err := signing.GetDataRoot(ctx, bmock, signing.DomainExit, epoch, sigRoot)
+		require.NoError(t, err)
+
+		sig, err := tblsv2.Sign(secret, sigData[:])
+		require.NoError(t, err)
+
+		return &eth2p0.SignedVoluntaryExit{Message: exit, Signature: eth2p0.BLSSignature(sig)}
+	}
+
+	tests := []struct {
+		name   string
+		epoch  eth2p0.Epoch
+		secret tblsv2.PrivateKey
+		valid  bool
+	}{
+		{name: "current share before rotation", epoch: rotationEpoch - 1, secret: secret, valid: true},
+		{name: "rotated share before rotation", epoch: rotationEpoch - 1, secret: rotatedSecret, valid: false},
+		{name: "current share at rotation", epoch: rotationEpoch, secret: secret, valid: false},
+		{name: "rotated share at rotation", epoch: rotationEpoch, secret: rotatedSecret, valid: true},
+		{name: "rotated share after rotation", epoch: rotationEpoch + 1, secret: rotatedSecret, valid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := vapi.SubmitVoluntaryExit(ctx, signExit(t, test.epoch, test.secret))
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, "signature not verified")
+			}
+		})
+	}
+
+	// signRegistration returns a builder registration timestamped at the start of the epoch signed by the secret.
+	signRegistration := func(t *testing.T, epoch eth2p0.Epoch, secret tblsv2.PrivateKey) *eth2api.VersionedSignedValidatorRegistration {
+		t.Helper()
+
+		genesis, err := bmock.GenesisTime(ctx)
+		require.NoError(t, err)
+		slotDuration, err := bmock.SlotDuration(ctx)
+		require.NoError(t, err)
+		slotsPerEpoch, err := bmock.SlotsPerEpoch(ctx)
+		require.NoError(t, err)
+
+		unsigned := testutil.RandomValidatorRegistration(t)
+		unsigned.Pubkey = eth2p0.BLSPubKey(pubkey)
+		unsigned.Timestamp = genesis.Add(slotDuration * time.Duration(slotsPerEpoch*uint64(epoch)))
+
+		sigRoot, err := unsigned.HashTreeRoot()
+		require.NoError(t, err)
+
+		// Registrations are always signed with epoch 0 domains, so only the duty's epoch identifies the valid share.
+		sigData, err := signing.GetDataRoot(ctx, bmock, signing.DomainApplicationBuilder, 0, sigRoot)
+		require.NoError(t, err)
+
+		sig, err := tblsv2.Sign(secret, sigData[:])
+		require.NoError(t, err)
+
+		return &eth2api.VersionedSignedValidatorRegistration{
+			Version: eth2spec.BuilderVersionV1,
+			V1: &eth2v1.SignedValidatorRegistration{
+				Message:   unsigned,
+				Signature: eth2p0.BLSSignature(sig),
+			},
+		}
+	}
+
+	for _, test := range tests {
+		t.Run("registration "+test.name, func(t *testing.T) {
+			reg := signRegistration(t, test.epoch, test.secret)
+			err := vapi.SubmitValidatorRegistrations(ctx, []*eth2api.VersionedSignedValidatorRegistration{reg})
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, "signature not verified")
+			}
+		})
+	}
+
+	// Duties contain the public shares valid at their epoch.
+	for epoch, pubshare := range map[eth2p0.Epoch]tblsv2.PublicKey{
+		rotationEpoch - 1: pubkey,
+		rotationEpoch:     rotatedPubshare,
+	} {
+		duties, err := vapi.AttesterDuties(ctx, epoch, []eth2p0.ValidatorIndex{vIdx})
+		require.NoError(t, err)
+		require.Len(t, duties, 1)
+		require.Equal(t, eth2p0.BLSPubKey(pubshare), duties[0].PubKey)
+	}
+}
+
+func TestComponent_SubmitVoluntaryExitInvalidSignature(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const (
+		vIdx     = 2
+		shareIdx = 1
+	)
+
+	// Create keys (just use normal keys, not split tbls)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	validator := beaconmock.ValidatorSetA[vIdx]
+	validator.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
+	require.NoError(t, err)
+
+	// Configure beacon mock
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	require.NoError(t, err)
+
+	// Construct the validator api component
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	// Register subscriber
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		cancel()
+		return ctx.Err()
+	})
+
+	sig, err := tblsv2.Sign(secret, []byte("invalid message"))
+	require.NoError(t, err)
+
+	exit := testutil.RandomExit()
+	exit.Message.ValidatorIndex = vIdx
+	exit.Signature = eth2p0.BLSSignature(sig)
+
+	err = vapi.SubmitVoluntaryExit(ctx, exit)
+	require.ErrorContains(t, err, "signature not verified")
+}
+
+func TestComponent_ProposerRandaoReady(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		shareIdx     = 1
+		readySlot    = 100
+		notReadySlot = 101
+	)
+
+	eth2Pubkey := testutil.RandomEth2PubKey(t)
+	corePubKey := core.PubKeyFrom48Bytes(eth2Pubkey)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{
+		corePubKey: {shareIdx: tblsv2.PublicKey(testutil.RandomEth2PubKey(t))},
+	}
+
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	bmock.ProposerDutiesFunc = func(context.Context, eth2p0.Epoch, []eth2p0.ValidatorIndex) ([]*eth2v1.ProposerDuty, error) {
+		return []*eth2v1.ProposerDuty{
+			{PubKey: eth2Pubkey, Slot: readySlot},
+			{PubKey: eth2Pubkey, Slot: notReadySlot},
+		}, nil
+	}
+
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	// Only the randao of readySlot is stored.
+	vapi.RegisterAwaitAggSigDB(func(ctx context.Context, duty core.Duty, pubkey core.PubKey) (core.SignedData, error) {
+		require.Equal(t, core.DutyRandao, duty.Type)
+		require.Equal(t, corePubKey, pubkey)
+
+		if duty.Slot == readySlot {
+			return core.NewPartialSignedRandao(0, testutil.RandomEth2Signature(), shareIdx).SignedData, nil
+		}
+
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	})
+
+	_, err = vapi.ProposerDuties(ctx, 0, nil)
+	require.NoError(t, err)
+
+	// The readiness is reported in the background.
+	require.Eventually(t, func() bool {
+		registry, err := promauto.NewRegistry(nil)
+		require.NoError(t, err)
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+
+		ready := make(map[string]float64)
+		for _, family := range families {
+			if family.GetName() != "core_validatorapi_proposer_randao_ready" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				ready[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+			}
+		}
+
+		notReady, ok := ready[fmt.Sprint(notReadySlot)]
+
+		return len(ready) == 2 && ready[fmt.Sprint(readySlot)] == 1 && ok && notReady == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestComponent_Duties(t *testing.T) {
+	ctx := context.Background()
+
+	// Configure validator
+	const (
+		vIdx     = 123
+		shareIdx = 1
+		epch     = 456
+	)
+
+	// Create pubkey and pubshare
+	eth2Pubkey := testutil.RandomEth2PubKey(t)
+	eth2Share := testutil.RandomEth2PubKey(t)
+
+	pubshare := tblsv2.PublicKey(eth2Share)
+	pubkey := tblsv2.PublicKey(eth2Pubkey)
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubshare}}
+	// Configure beacon mock
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	t.Run("proposer_duties", func(t *testing.T) {
+		bmock.ProposerDutiesFunc = func(ctx context.Context, epoch eth2p0.Epoch, indices []eth2p0.ValidatorIndex) ([]*eth2v1.ProposerDuty, error) {
+			require.Equal(t, epoch, eth2p0.Epoch(epch))
+			require.Equal(t, []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)}, indices)
+
+			return []*eth2v1.ProposerDuty{{
+				PubKey:         eth2Pubkey,
+				ValidatorIndex: vIdx,
+			}}, nil
+		}
+
+		// Construct the validator api component
+		vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+		require.NoError(t, err)
+		duties, err := vapi.ProposerDuties(ctx, eth2p0.Epoch(epch), []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)})
+		require.NoError(t, err)
+		require.Len(t, duties, 1)
+		require.Equal(t, duties[0].PubKey, eth2Share)
+	})
+
+	t.Run("attester_duties", func(t *testing.T) {
+		bmock.AttesterDutiesFunc = func(_ context.Context, epoch eth2p0.Epoch, indices []eth2p0.ValidatorIndex) ([]*eth2v1.AttesterDuty, error) {
+			require.Equal(t, epoch, eth2p0.Epoch(epch))
+			require.Equal(t, []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)}, indices)
+
+			return []*eth2v1.AttesterDuty{{
+				PubKey:         eth2Pubkey,
+				ValidatorIndex: vIdx,
+			}}, nil
+		}
+
+		// Construct the validator api component
+		vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+		require.NoError(t, err)
+		duties, err := vapi.AttesterDuties(ctx, eth2p0.Epoch(epch), []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)})
+		require.NoError(t, err)
+		require.Len(t, duties, 1)
+		require.Equal(t, duties[0].PubKey, eth2Share)
+	})
+
+	t.Run("sync_committee_duties", func(t *testing.T) {
+		bmock.SyncCommitteeDutiesFunc = func(ctx context.Context, epoch eth2p0.Epoch, indices []eth2p0.ValidatorIndex) ([]*eth2v1.SyncCommitteeDuty, error) {
+			require.Equal(t, epoch, eth2p0.Epoch(epch))
+			require.Equal(t, []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)}, indices)
+
+			return []*eth2v1.SyncCommitteeDuty{{
+				PubKey:         eth2Pubkey,
+				ValidatorIndex: vIdx,
+			}}, nil
+		}
+
+		// Construct the validator api component
+		vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+		require.NoError(t, err)
+		duties, err := vapi.SyncCommitteeDuties(ctx, eth2p0.Epoch(epch), []eth2p0.ValidatorIndex{eth2p0.ValidatorIndex(vIdx)})
+		require.NoError(t, err)
+		require.Len(t, duties, 1)
+		require.Equal(t, duties[0].PubKey, eth2Share)
+	})
+}
+
+func TestComponent_RateLimitRetry(t *testing.T) {
+	ctx := context.Background()
+
+	// newComponent returns a component using a beacon mock with the provided attester duties function.
+	newComponent := func(t *testing.T, fn func(context.Context, eth2p0.Epoch, []eth2p0.ValidatorIndex) ([]*eth2v1.AttesterDuty, error)) *validatorapi.Component {
+		t.Helper()
+
+		bmock, err := beaconmock.New()
+		require.NoError(t, err)
+		bmock.AttesterDutiesFunc = fn
+
+		vapi, err := validatorapi.NewComponentInsecure(t, bmock, 0)
+		require.NoError(t, err)
+
+		return vapi
+	}
+
+	rateLimited := func() float64 {
+		registry, err := promauto.NewRegistry(nil)
+		require.NoError(t, err)
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+
+		for _, family := range families {
+			if family.GetName() != "core_validatorapi_beacon_rate_limited_total" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				if metric.GetLabel()[0].GetValue() == "attester_duties" {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+
+		return 0
+	}
+
+	// newAttesterDutiesFunc returns a function that is rate limited for the first n calls.
+	newAttesterDutiesFunc := func(n int, calls *int) func(context.Context, eth2p0.Epoch, []eth2p0.ValidatorIndex) ([]*eth2v1.AttesterDuty, error) {
+		return func(context.Context, eth2p0.Epoch, []eth2p0.ValidatorIndex) ([]*eth2v1.AttesterDuty, error) {
+			*calls++
+			if *calls <= n {
+				return nil, eth2http.Error{Method: "POST", StatusCode: http.StatusTooManyRequests}
+			}
+
+			return []*eth2v1.AttesterDuty{}, nil
+		}
+	}
+
+	t.Run("retried", func(t *testing.T) {
+		before := rateLimited()
+
+		var calls int
+		vapi := newComponent(t, newAttesterDutiesFunc(2, &calls))
+
+		duties, err := vapi.AttesterDuties(ctx, 0, nil)
+		require.NoError(t, err)
+		require.Empty(t, duties)
+		require.Equal(t, 3, calls)
+		require.Equal(t, before+2, rateLimited())
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		before := rateLimited()
+
+		var calls int
+		vapi := newComponent(t, newAttesterDutiesFunc(100, &calls))
+
+		_, err := vapi.AttesterDuties(ctx, 0, nil)
+		require.Error(t, err)
+		require.True(t, eth2wrap.IsRateLimited(err))
+		require.Equal(t, 4, calls) // Bounded number of attempts.
+		require.Equal(t, before+4, rateLimited())
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		var calls int
+		vapi := newComponent(t, func(context.Context, eth2p0.Epoch, []eth2p0.ValidatorIndex) ([]*eth2v1.AttesterDuty, error) {
+			calls++
+			return nil, errors.New("other error")
+		})
+
+		_, err := vapi.AttesterDuties(ctx, 0, nil)
+		require.ErrorContains(t, err, "other error")
+		require.Equal(t, 1, calls)
+	})
+}
+
+func TestComponent_ValidatorsHistoricalState(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		oldIdx   = 1
+		newIdx   = 2
+		shareIdx = 1
+	)
+
+	oldPubkey := testutil.RandomEth2PubKey(t)
+	newPubkey := testutil.RandomEth2PubKey(t)
+	oldPubshare := testutil.RandomEth2PubKey(t)
+	newPubshare := testutil.RandomEth2PubKey(t)
+
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{
+		core.PubKeyFrom48Bytes(oldPubkey): {shareIdx: tblsv2.PublicKey(oldPubshare)},
+		core.PubKeyFrom48Bytes(newPubkey): {shareIdx: tblsv2.PublicKey(newPubshare)},
+	}
+
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	// The new validator only exists at head, not at the historical (finalized or slot) states.
+	bmock.ValidatorsFunc = func(_ context.Context, state string, indices []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+		require.Equal(t, []eth2p0.ValidatorIndex{oldIdx, newIdx}, indices)
+
+		resp := map[eth2p0.ValidatorIndex]*eth2v1.Validator{
+			oldIdx: {Index: oldIdx, Validator: &eth2p0.Validator{PublicKey: oldPubkey}},
+		}
+		if state == "head" {
+			resp[newIdx] = &eth2v1.Validator{Index: newIdx, Validator: &eth2p0.Validator{PublicKey: newPubkey}}
+		}
+
+		return resp, nil
+	}
+
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	for _, stateID := range []string{"head", "finalized", "justified", "100"} {
+		t.Run(stateID, func(t *testing.T) {
+			vals, err := vapi.Validators(ctx, stateID, []eth2p0.ValidatorIndex{oldIdx, newIdx})
+			require.NoError(t, err)
+
+			expect := map[eth2p0.ValidatorIndex]eth2p0.BLSPubKey{oldIdx: oldPubshare}
+			if stateID == "head" {
+				expect[newIdx] = newPubshare
+			}
+
+			actual := make(map[eth2p0.ValidatorIndex]eth2p0.BLSPubKey)
+			for vIdx, val := range vals {
+				require.Equal(t, vIdx, val.Index)
+				actual[vIdx] = val.Validator.PublicKey
+			}
+			require.Equal(t, expect, actual)
+		})
+	}
+}
+
+func TestComponent_ValidatorsPartialFailure(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		okIdx     = 1
+		failedIdx = 2
+		shareIdx  = 1
+	)
+
+	okPubkey := testutil.RandomEth2PubKey(t)
+	okPubshare := testutil.RandomEth2PubKey(t)
+	failedPubkey := testutil.RandomEth2PubKey(t)
+
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{
+		core.PubKeyFrom48Bytes(okPubkey):     {shareIdx: tblsv2.PublicKey(okPubshare)},
+		core.PubKeyFrom48Bytes(failedPubkey): {shareIdx: tblsv2.PublicKey(testutil.RandomEth2PubKey(t))},
+	}
+
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	partial := true
+	bmock.ValidatorsFunc = func(context.Context, string, []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+		if !partial {
+			return nil, errors.New("validators failed")
+		}
+
+		return map[eth2p0.ValidatorIndex]*eth2v1.Validator{
+			okIdx: {Index: okIdx, Validator: &eth2p0.Validator{PublicKey: okPubkey}},
+		}, errors.New("validator failed")
+	}
+
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	// Partial results return the resolved subset with public shares.
+	vals, err := vapi.Validators(ctx, "head", []eth2p0.ValidatorIndex{okIdx, failedIdx})
+	require.NoError(t, err)
+	require.Len(t, vals, 1)
+	require.Equal(t, okPubshare, vals[okIdx].Validator.PublicKey)
+
+	// Complete failures still return the error.
+	partial = false
+	_, err = vapi.Validators(ctx, "head", []eth2p0.ValidatorIndex{okIdx, failedIdx})
+	require.ErrorContains(t, err, "validators failed")
+}
+
+func TestComponent_ValidatorsCache(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		vIdx     = 1
+		shareIdx = 1
+	)
+
+	pubkey := testutil.RandomEth2PubKey(t)
+	pubshare := testutil.RandomEth2PubKey(t)
+
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{
+		core.PubKeyFrom48Bytes(pubkey): {shareIdx: tblsv2.PublicKey(pubshare)},
+	}
+
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	queries := make(map[string]int)
+	bmock.ValidatorsFunc = func(_ context.Context, stateID string, _ []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+		queries[stateID]++
+		return map[eth2p0.ValidatorIndex]*eth2v1.Validator{
+			vIdx: {Index: vIdx, Validator: &eth2p0.Validator{PublicKey: pubkey}},
+		}, nil
+	}
+	bmock.ValidatorsByPubKeyFunc = func(_ context.Context, stateID string, _ []eth2p0.BLSPubKey) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+		queries["pubkeys_"+stateID]++
+		return map[eth2p0.ValidatorIndex]*eth2v1.Validator{
+			vIdx: {Index: vIdx, Validator: &eth2p0.Validator{PublicKey: pubkey}},
+		}, nil
+	}
+
+	clock := clockwork.NewFakeClock()
+	vapi, err := validatorapi.New(bmock, allPubSharesByKey, shareIdx, validatorapi.WithClock(clock))
+	require.NoError(t, err)
+
+	query := func(stateID string) {
+		t.Helper()
+
+		vals, err := vapi.Validators(ctx, stateID, []eth2p0.ValidatorIndex{vIdx})
+		require.NoError(t, err)
+		require.Equal(t, pubshare, vals[vIdx].Validator.PublicKey)
+
+		vals, err = vapi.ValidatorsByPubKey(ctx, stateID, []eth2p0.BLSPubKey{pubshare})
+		require.NoError(t, err)
+		require.Equal(t, pubshare, vals[vIdx].Validator.PublicKey)
+	}
+
+	query("finalized")
+	query("head")
+	query("finalized")
+	query("head")
+	require.Equal(t, map[string]int{"finalized": 1, "head": 1, "pubkeys_finalized": 1, "pubkeys_head": 1}, queries)
+
+	// Head queries expire after a few seconds, while finalized queries are still cached.
+	clock.Advance(time.Minute)
+	query("finalized")
+	query("head")
+	require.Equal(t, map[string]int{"finalized": 1, "head": 2, "pubkeys_finalized": 1, "pubkeys_head": 2}, queries)
+
+	// Finalized queries expire eventually.
+	clock.Advance(time.Hour)
+	query("finalized")
+	require.Equal(t, 2, queries["finalized"])
+	require.Equal(t, 2, queries["pubkeys_finalized"])
+}
+
+func TestComponent_ValidatorIndexCache(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		activeIdx  = 1
+		pendingIdx = 2
+		shareIdx   = 1
+	)
+
+	activePubkey := testutil.RandomEth2PubKey(t)
+	pendingPubkey := testutil.RandomEth2PubKey(t)
+
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{
+		core.PubKeyFrom48Bytes(activePubkey):  {shareIdx: tblsv2.PublicKey(testutil.RandomEth2PubKey(t))},
+		core.PubKeyFrom48Bytes(pendingPubkey): {shareIdx: tblsv2.PublicKey(testutil.RandomEth2PubKey(t))},
+	}
+
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	slotDuration, err := bmock.SlotDuration(ctx)
+	require.NoError(t, err)
+	slotsPerEpoch, err := bmock.SlotsPerEpoch(ctx)
+	require.NoError(t, err)
+	epochDuration := slotDuration * time.Duration(slotsPerEpoch)
+
+	// Start at the beginning of an epoch to avoid crossing an epoch boundary during the test.
+	var (
+		mu      sync.Mutex
+		genesis = time.Now().Add(-10 * epochDuration)
+		pending = true
+		queries int
+	)
+	bmock.GenesisTimeFunc = func(context.Context) (time.Time, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return genesis, nil
+	}
+	bmock.ValidatorsByPubKeyFunc = func(_ context.Context, stateID string, pubkeys []eth2p0.BLSPubKey) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		require.Equal(t, "head", stateID)
+		require.Len(t, pubkeys, 2)
+		queries++
+
+		resp := map[eth2p0.ValidatorIndex]*eth2v1.Validator{
+			activeIdx: {Index: activeIdx, Validator: &eth2p0.Validator{PublicKey: activePubkey}},
+		}
+		if !pending {
+			resp[pendingIdx] = &eth2v1.Validator{Index: pendingIdx, Validator: &eth2p0.Validator{PublicKey: pendingPubkey}}
+		}
+
+		return resp, nil
+	}
+
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	// Pubkey to index.
+	vIdx, ok, err := vapi.IndexFromPubKey(ctx, core.PubKeyFrom48Bytes(activePubkey))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, activeIdx, vIdx)
+
+	// Index to pubkey.
+	pubkey, ok, err := vapi.PubKeyFromIndex(ctx, activeIdx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, core.PubKeyFrom48Bytes(activePubkey), pubkey)
+
+	// Pending validator is not assigned an index yet.
+	_, ok, err = vapi.IndexFromPubKey(ctx, core.PubKeyFrom48Bytes(pendingPubkey))
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, ok, err = vapi.PubKeyFromIndex(ctx, pendingIdx)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, 1, queries) // Cached within the epoch.
+
+	// Pending validator is assigned an index, which is picked up in the next epoch.
+	mu.Lock()
+	pending = false
+	genesis = genesis.Add(-epochDuration)
+	mu.Unlock()
+
+	vIdx, ok, err = vapi.IndexFromPubKey(ctx, core.PubKeyFrom48Bytes(pendingPubkey))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, pendingIdx, vIdx)
+	require.Equal(t, 2, queries)
+}
+
+func TestComponent_ValidatorBalances(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		servedIdx   = 123
+		unservedIdx = 456
+		shareIdx    = 1
+		stateID     = "head"
+	)
+
+	eth2Pubkey := testutil.RandomEth2PubKey(t)
+	pubkey := tblsv2.PublicKey(eth2Pubkey)
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{
+		corePubKey: {shareIdx: tblsv2.PublicKey(testutil.RandomEth2PubKey(t))},
+	}
+
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	bmock.ValidatorsByPubKeyFunc = func(_ context.Context, state string, pubkeys []eth2p0.BLSPubKey) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
+		require.Equal(t, stateID, state)
+		require.Equal(t, []eth2p0.BLSPubKey{eth2Pubkey}, pubkeys)
+
+		return map[eth2p0.ValidatorIndex]*eth2v1.Validator{
+			servedIdx: {Index: servedIdx, Validator: &eth2p0.Validator{PublicKey: eth2Pubkey}},
+		}, nil
+	}
+
+	var requested []eth2p0.ValidatorIndex
+	bmock.ValidatorBalancesFunc = func(_ context.Context, state string, indices []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]eth2p0.Gwei, error) {
+		require.Equal(t, stateID, state)
+		requested = indices
+
+		return map[eth2p0.ValidatorIndex]eth2p0.Gwei{
+			servedIdx:   32e9,
+			unservedIdx: 31e9,
+		}, nil
+	}
+
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	expect := map[eth2p0.ValidatorIndex]eth2p0.Gwei{servedIdx: 32e9}
+
+	t.Run("filtered indices", func(t *testing.T) {
+		balances, err := vapi.ValidatorBalances(ctx, stateID, []eth2p0.ValidatorIndex{servedIdx, unservedIdx})
+		require.NoError(t, err)
+		require.Equal(t, expect, balances)
+		require.Equal(t, []eth2p0.ValidatorIndex{servedIdx}, requested)
+	})
+
+	t.Run("no indices", func(t *testing.T) {
+		balances, err := vapi.ValidatorBalances(ctx, stateID, nil)
+		require.NoError(t, err)
+		require.Equal(t, expect, balances)
+		require.Equal(t, []eth2p0.ValidatorIndex{servedIdx}, requested)
+	})
+
+	t.Run("only unserved indices", func(t *testing.T) {
+		requested = nil
+		balances, err := vapi.ValidatorBalances(ctx, stateID, []eth2p0.ValidatorIndex{unservedIdx})
+		require.NoError(t, err)
+		require.Empty(t, balances)
+		require.Nil(t, requested)
+	})
+}
+
+func TestComponent_SubmitValidatorRegistration(t *testing.T) {
+	ctx := context.Background()
+	shareIdx := 1
+	// Create keys (just use normal keys, not split tbls)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	// Convert pubkey
+	eth2Pubkey := eth2p0.BLSPubKey(pubkey)
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	// Configure beacon mock
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	// Construct the validator api component
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+	require.NoError(t, err)
+
+	unsigned := testutil.RandomValidatorRegistration(t)
+	unsigned.Pubkey = eth2Pubkey
+	unsigned.Timestamp, err = bmock.GenesisTime(ctx) // Set timestamp to genesis which should result in epoch 0 and slot 0.
+	require.NoError(t, err)
+
+	// Sign validator (builder) registration
+	sigRoot, err := unsigned.HashTreeRoot()
+	require.NoError(t, err)
+
+	sigData, err := signing.GetDataRoot(ctx, bmock, signing.DomainApplicationBuilder, 0, sigRoot)
+	require.NoError(t, err)
+
+	s, err := tblsv2.Sign(secret, sigData[:])
+	require.NoError(t, err)
+
+	signed := &eth2api.VersionedSignedValidatorRegistration{
+		Version: eth2spec.BuilderVersionV1,
+		V1: &eth2v1.SignedValidatorRegistration{
+			Message:   unsigned,
+			Signature: eth2p0.BLSSignature(s),
+		},
+	}
+
+	output := make(chan core.ParSignedDataSet)
+
+	// Register subscriber
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Equal(t, core.NewBuilderRegistrationDuty(0), duty)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case output <- set:
+		}
+
+		return nil
+	})
+
+	go func() {
+		err = vapi.SubmitValidatorRegistrations(ctx, []*eth2api.VersionedSignedValidatorRegistration{signed})
+		require.NoError(t, err)
+	}()
+
+	// Assert output, this is blocking since it requires subscribe block to execute.
+	actualData := <-output
+	registration, ok := actualData[corePubKey].SignedData.(core.VersionedSignedValidatorRegistration)
+	require.True(t, ok)
+	require.Equal(t, *signed, registration.VersionedSignedValidatorRegistration)
+}
+
+func TestComponent_SubmitValidatorRegistrationInvalidSignature(t *testing.T) {
+	ctx := context.Background()
+	shareIdx := 1
+	// Create keys (just use normal keys, not split tbls)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	// Convert pubkey
+	eth2Pubkey := eth2p0.BLSPubKey(pubkey)
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	// Configure beacon mock
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	// Construct the validator api component
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderTrue, nil)
+	require.NoError(t, err)
+
+	unsigned := testutil.RandomValidatorRegistration(t)
+	unsigned.Pubkey = eth2Pubkey
+	unsigned.Timestamp, err = bmock.GenesisTime(ctx) // Set timestamp to genesis which should result in epoch 0 and slot 0.
+	require.NoError(t, err)
+
+	vapi.RegisterGetDutyDefinition(func(ctx context.Context, duty core.Duty) (core.DutyDefinitionSet, error) {
+		return core.DutyDefinitionSet{corePubKey: nil}, nil
+	})
+
+	// Add invalid Signature to validator (builder) registration
+
+	s, err := tblsv2.Sign(secret, []byte("invalid msg"))
+	require.NoError(t, err)
+
+	signed := &eth2api.VersionedSignedValidatorRegistration{
+		Version: eth2spec.BuilderVersionV1,
+		V1: &eth2v1.SignedValidatorRegistration{
+			Message:   unsigned,
+			Signature: eth2p0.BLSSignature(s),
+		},
+	}
+
+	err = vapi.SubmitValidatorRegistrations(ctx, []*eth2api.VersionedSignedValidatorRegistration{signed})
+	require.ErrorContains(t, err, "signature not verified")
+}
+
+// noDomainClient is a beacon client that fails signing domain queries.
+type noDomainClient struct {
+	beaconmock.Mock
+}
+
+func (noDomainClient) Domain(context.Context, eth2p0.DomainType, eth2p0.Epoch) (eth2p0.Domain, error) {
+	return eth2p0.Domain{}, errors.New("unexpected domain query")
+}
+
+func TestComponent_SubmitValidatorRegistrationLocalDomain(t *testing.T) {
+	ctx := context.Background()
+	const shareIdx = 1
+
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	forks, err := bmock.ForkSchedule(ctx)
+	require.NoError(t, err)
+	genesis, err := bmock.Genesis(ctx)
+	require.NoError(t, err)
+
+	unsigned := testutil.RandomValidatorRegistration(t)
+	unsigned.Pubkey = eth2p0.BLSPubKey(pubkey)
+	unsigned.Timestamp = genesis.GenesisTime
+
+	sigRoot, err := unsigned.HashTreeRoot()
+	require.NoError(t, err)
+	sigData, err := signing.GetDataRoot(ctx, bmock, signing.DomainApplicationBuilder, 0, sigRoot)
+	require.NoError(t, err)
+	sig, err := tblsv2.Sign(secret, sigData[:])
+	require.NoError(t, err)
+
+	signed := &eth2api.VersionedSignedValidatorRegistration{
+		Version: eth2spec.BuilderVersionV1,
+		V1: &eth2v1.SignedValidatorRegistration{
+			Message:   unsigned,
+			Signature: eth2p0.BLSSignature(sig),
+		},
+	}
+
+	eth2Cl := noDomainClient{Mock: bmock}
+
+	// Verification queries the beacon node domain by default.
+	vapi, err := validatorapi.New(eth2Cl, allPubSharesByKey, shareIdx, validatorapi.WithBuilderEnabled(testutil.BuilderTrue))
+	require.NoError(t, err)
+	err = vapi.SubmitValidatorRegistrations(ctx, []*eth2api.VersionedSignedValidatorRegistration{signed})
+	require.ErrorContains(t, err, "unexpected domain query")
+
+	// Verification computes the builder domain locally from the configured genesis data.
+	vapi, err = validatorapi.New(eth2Cl, allPubSharesByKey, shareIdx,
+		validatorapi.WithBuilderEnabled(testutil.BuilderTrue),
+		validatorapi.WithGenesisData(forks[0].CurrentVersion, genesis.GenesisValidatorsRoot),
+	)
+	require.NoError(t, err)
+
+	var submitted bool
+	vapi.Subscribe(func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Equal(t, core.NewBuilderRegistrationDuty(0), duty)
+		require.Contains(t, set, corePubKey)
+		submitted = true
+
+		return nil
+	})
+
+	err = vapi.SubmitValidatorRegistrations(ctx, []*eth2api.VersionedSignedValidatorRegistration{signed})
+	require.NoError(t, err)
+	require.True(t, submitted)
+}
+
+func TestComponent_TekuProposerConfig(t *testing.T) {
+	ctx := context.Background()
+	const (
+		zeroAddr     = "0x0000000000000000000000000000000000000000"
+		feeRecipient = "0x123456"
+		shareIdx     = 1
+	)
+	// Create keys (just use normal keys, not split tbls)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	// Convert pubkey
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	// Configure beacon mock
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	// Construct the validator api component
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, func(core.PubKey) string {
+		return feeRecipient
+	}, testutil.BuilderTrue, nil)
+	require.NoError(t, err)
+
+	resp, err := vapi.TekuProposerConfig(ctx)
+	require.NoError(t, err)
+
+	pk, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+
+	genesis, err := bmock.GenesisTime(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, validatorapi.TekuProposerConfigResponse{
+		Proposers: map[string]validatorapi.TekuProposerConfig{
+			string(pk): {
+				FeeRecipient: feeRecipient,
+				Builder: validatorapi.TekuBuilder{
+					Enabled:  true,
+					GasLimit: 30000000,
+					Overrides: map[string]string{
+						"timestamp":  fmt.Sprint(genesis.Unix()),
+						"public_key": string(pk),
+					},
+				},
+			},
+		},
+		Default: validatorapi.TekuProposerConfig{
+			FeeRecipient: zeroAddr,
+			Builder: validatorapi.TekuBuilder{
+				Enabled:  false,
+				GasLimit: 30000000,
+			},
+		},
+	}, resp)
+}
+
+func TestComponent_AggregateBeaconCommitteeSelections(t *testing.T) {
+	ctx := context.Background()
+
+	const slot = 99
+
+	valSet := beaconmock.ValidatorSetA
+	eth2Cl, err := beaconmock.New(beaconmock.WithValidatorSet(valSet))
+	require.NoError(t, err)
+
+	vapi, err := validatorapi.NewComponentInsecure(t, eth2Cl, 0)
+	require.NoError(t, err)
+
+	selections := []*eth2exp.BeaconCommitteeSelection{
+		{
+			ValidatorIndex: valSet[1].Index,
+			Slot:           slot,
+			SelectionProof: testutil.RandomEth2Signature(),
+		}, {
+			ValidatorIndex: valSet[2].Index,
+			Slot:           slot,
+			SelectionProof: testutil.RandomEth2Signature(),
+		},
+	}
+
+	vapi.RegisterAwaitAggSigDB(func(_ context.Context, duty core.Duty, pk core.PubKey) (core.SignedData, error) {
+		require.Equal(t, core.NewPrepareAggregatorDuty(slot), duty)
+		for _, val := range valSet {
+			pkEth2, err := pk.ToETH2()
+			require.NoError(t, err)
+			if pkEth2 != val.Validator.PublicKey {
+				continue
+			}
+			for _, selection := range selections {
+				if selection.ValidatorIndex == val.Index {
+					return core.NewBeaconCommitteeSelection(selection), nil
+				}
+			}
+		}
+
+		return nil, errors.New("unknown public key")
+	})
+
+	actual, err := vapi.AggregateBeaconCommitteeSelections(ctx, selections)
+	require.NoError(t, err)
+
+	// Sort by VIdx before comparing
+	sort.Slice(actual, func(i, j int) bool {
+		return actual[i].ValidatorIndex < actual[j].ValidatorIndex
+	})
+	require.Equal(t, selections, actual)
+}
+
+func TestComponent_SubmitAggregateAttestations(t *testing.T) {
+	ctx := context.Background()
+
+	const vIdx = 1
+
+	agg := &eth2p0.SignedAggregateAndProof{
+		Message: &eth2p0.AggregateAndProof{
+			AggregatorIndex: vIdx,
+			Aggregate:       testutil.RandomAttestation(),
+			SelectionProof:  testutil.RandomEth2Signature(),
+		},
+		Signature: testutil.RandomEth2Signature(),
+	}
+
+	slot := agg.Message.Aggregate.Data.Slot
+	pubkey := beaconmock.ValidatorSetA[vIdx].Validator.PublicKey
+
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	require.NoError(t, err)
+
+	vapi, err := validatorapi.NewComponentInsecure(t, bmock, 0)
+	require.NoError(t, err)
+
+	vapi.Subscribe(func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Equal(t, core.NewAggregatorDuty(int64(slot)), duty)
+
+		pk, err := core.PubKeyFromBytes(pubkey[:])
+		require.NoError(t, err)
+
+		data, ok := set[pk]
+		require.True(t, ok)
+		require.Equal(t, core.NewPartialSignedAggregateAndProof(agg, 0), data)
+
+		return nil
+	})
+
+	require.NoError(t, vapi.SubmitAggregateAttestations(ctx, []*eth2p0.SignedAggregateAndProof{agg}))
+}
+
+func TestComponent_SubmitAggregateAttestationVerify(t *testing.T) {
+	const shareIdx = 1
+	var (
+		ctx = context.Background()
+		val = testutil.RandomValidator(t)
+	)
+
+	// Create keys (just use normal keys, not split tbls)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	val.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
+
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSet{val.Index: val}))
+	require.NoError(t, err)
+
+	slot := eth2p0.Slot(99)
+	aggProof := &eth2p0.AggregateAndProof{
+		AggregatorIndex: val.Index,
+		Aggregate:       testutil.RandomAttestation(),
+	}
+	aggProof.Aggregate.Data.Slot = slot
+	aggProof.SelectionProof = signBeaconSelection(t, bmock, secret, slot)
+	signedAggProof := &eth2p0.SignedAggregateAndProof{
+		Message:   aggProof,
+		Signature: signAggregationAndProof(t, bmock, secret, aggProof),
+	}
+
+	// Construct the validator api component
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	// Collect submitted partial signature.
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Len(t, set, 1)
+		_, ok := set[core.PubKeyFrom48Bytes(val.Validator.PublicKey)]
+		require.True(t, ok)
+		close(done)
+
+		return nil
+	})
+
+	err = vapi.SubmitAggregateAttestations(ctx, []*eth2p0.SignedAggregateAndProof{signedAggProof})
+	require.NoError(t, err)
+	<-done
+}
+
+func TestComponent_SubmitAggregateAttestationVerifyAggregate(t *testing.T) {
+	const (
+		shareIdx = 1
+		slot     = 99
+		commIdx  = 1
+		n        = 3 // Number of participating committee validators.
+	)
+	ctx := context.Background()
+
+	var (
+		secrets   []tblsv2.PrivateKey
+		valSet    = make(beaconmock.ValidatorSet)
+		committee []eth2p0.ValidatorIndex
+	)
+	for i := 0; i < n; i++ {
+		secret, err := tblsv2.GenerateSecretKey()
+		require.NoError(t, err)
+		pubkey, err := tblsv2.SecretToPublicKey(secret)
+		require.NoError(t, err)
+
+		val := testutil.RandomValidator(t)
+		val.Index = eth2p0.ValidatorIndex(i + 1)
+		val.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
+
+		secrets = append(secrets, secret)
+		valSet[val.Index] = val
+		committee = append(committee, val.Index)
+	}
+	committee = append(committee, 100) // Non-participating committee validator.
+
+	aggregator := valSet[1]
+	corePubKey := core.PubKeyFrom48Bytes(aggregator.Validator.PublicKey)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{
+		corePubKey: {shareIdx: tblsv2.PublicKey(aggregator.Validator.PublicKey)}, // Maps self to self since not tbls
+	}
+
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(valSet))
+	require.NoError(t, err)
+
+	attData := testutil.RandomAttestation().Data
+	attData.Slot = slot
+	attData.Index = commIdx
+	attRoot, err := attData.HashTreeRoot()
+	require.NoError(t, err)
+
+	aggBits := bitfield.NewBitlist(uint64(len(committee)))
+	var sigs []tblsv2.Signature
+	for i, secret := range secrets {
+		aggBits.SetBitAt(uint64(i), true)
+		sigs = append(sigs, tblsv2.Signature(sign(t, bmock, secret, signing.DomainBeaconAttester, attData.Target.Epoch, attRoot)))
+	}
+
+	newAggProof := func(sigs []tblsv2.Signature) *eth2p0.SignedAggregateAndProof {
+		aggSig, err := tblsv2.Aggregate(sigs)
+		require.NoError(t, err)
+
+		aggProof := &eth2p0.AggregateAndProof{
+			AggregatorIndex: aggregator.Index,
+			Aggregate: &eth2p0.Attestation{
+				AggregationBits: aggBits,
+				Data:            attData,
+				Signature:       eth2p0.BLSSignature(aggSig),
+			},
+			SelectionProof: signBeaconSelection(t, bmock, secrets[0], slot),
+		}
+
+		return &eth2p0.SignedAggregateAndProof{
+			Message:   aggProof,
+			Signature: signAggregationAndProof(t, bmock, secrets[0], aggProof),
+		}
+	}
+
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	vapi.RegisterBeaconCommittee(func(_ context.Context, s, c int64) ([]eth2p0.ValidatorIndex, error) {
+		require.EqualValues(t, slot, s)
+		require.EqualValues(t, commIdx, c)
+
+		return committee, nil
+	})
+
+	var stored int
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Contains(t, set, corePubKey)
+		stored++
+
+		return nil
+	})
+
+	t.Run("valid aggregate", func(t *testing.T) {
+		err := vapi.SubmitAggregateAttestations(ctx, []*eth2p0.SignedAggregateAndProof{newAggProof(sigs)})
+		require.NoError(t, err)
+		require.Equal(t, 1, stored)
+	})
+
+	t.Run("corrupted aggregate", func(t *testing.T) {
+		// Omitting a participant's signature corrupts the aggregate signature.
+		err := vapi.SubmitAggregateAttestations(ctx, []*eth2p0.SignedAggregateAndProof{newAggProof(sigs[:n-1])})
+		require.ErrorContains(t, err, "verify aggregate attestation signature")
+		require.Equal(t, 1, stored)
+	})
+}
+
+func TestComponent_SubmitSyncCommitteeMessages(t *testing.T) {
+	const vIdx = 1
+
+	var (
+		ctx    = context.Background()
+		msg    = testutil.RandomSyncCommitteeMessage()
+		pubkey = beaconmock.ValidatorSetA[vIdx].Validator.PublicKey
+		count  = 0 // No of times the subscription function is called.
+	)
+
+	msg.ValidatorIndex = vIdx
+
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	require.NoError(t, err)
+
+	vapi, err := validatorapi.NewComponentInsecure(t, bmock, 0)
+	require.NoError(t, err)
+
+	vapi.Subscribe(func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Equal(t, core.NewSyncMessageDuty(int64(msg.Slot)), duty)
+
+		pk, err := core.PubKeyFromBytes(pubkey[:])
+		require.NoError(t, err)
+
+		data, ok := set[pk]
+		require.True(t, ok)
+		require.Equal(t, core.NewPartialSignedSyncMessage(msg, 0), data)
+		count++
+
+		return nil
+	})
+
+	require.NoError(t, vapi.SubmitSyncCommitteeMessages(ctx, []*altair.SyncCommitteeMessage{msg}))
+	require.Equal(t, count, 1)
+}
+
+func TestComponent_SyncContributionReady(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		threshold  = 3
+		slot       = 100
+		subcommIdx = 1
+	)
+
+	pubkey := testutil.RandomCorePubKey(t)
+	blockRoot := testutil.RandomRoot()
+
+	component, err := validatorapi.NewComponentInsecure(t, nil, 0)
+	require.NoError(t, err)
+
+	// The contribution is available once threshold partial signed sync messages are stored.
+	var (
+		mu       sync.Mutex
+		aggSlots = make(map[int64]bool)
+		parSigDB = parsigdb.NewMemDB(threshold, noopDeadliner{})
+	)
+	parSigDB.SubscribeThreshold(func(_ context.Context, duty core.Duty, _ core.PubKey, _ []core.ParSignedData) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		aggSlots[duty.Slot] = true
+
+		return nil
+	})
+
+	component.RegisterAwaitSyncContribution(func(ctx context.Context, slot, subcommIdx int64, root eth2p0.Root) (*altair.SyncCommitteeContribution, error) {
+		mu.Lock()
+		ok := aggSlots[slot]
+		mu.Unlock()
+
+		if !ok {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		return &altair.SyncCommitteeContribution{
+			Slot:              eth2p0.Slot(slot),
+			SubcommitteeIndex: uint64(subcommIdx),
+			BeaconBlockRoot:   root,
+		}, nil
+	})
+
+	// requireReady queries the contribution like a validator client and asserts the readiness reported in the background.
+	requireReady := func(expect float64) {
+		t.Helper()
+
+		ctx, cancel := context.WithTimeout(ctx, time.Millisecond*100)
+		defer cancel()
+
+		_, _ = component.SyncCommitteeContribution(ctx, slot, subcommIdx, blockRoot)
+
+		require.Eventually(t, func() bool {
+			registry, err := promauto.NewRegistry(nil)
+			require.NoError(t, err)
+
+			families, err := registry.Gather()
+			require.NoError(t, err)
+
+			for _, family := range families {
+				if family.GetName() != "core_validatorapi_sync_contribution_ready" {
+					continue
+				}
+				for _, metric := range family.GetMetric() {
+					return metric.GetGauge().GetValue() == expect
+				}
+			}
+
+			return false
+		}, time.Second, time.Millisecond)
+	}
+
+	msg := &altair.SyncCommitteeMessage{
+		Slot:            slot,
+		BeaconBlockRoot: blockRoot,
+		ValidatorIndex:  1,
+		Signature:       testutil.RandomEth2Signature(),
+	}
+
+	for shareIdx := 1; shareIdx <= threshold; shareIdx++ {
+		requireReady(0)
+
+		err := parSigDB.StoreExternal(ctx, core.NewSyncMessageDuty(slot), core.ParSignedDataSet{
+			pubkey: core.NewPartialSignedSyncMessage(msg, shareIdx),
+		})
+		require.NoError(t, err)
+	}
+
+	// The contribution is returned and reported ready once aggregated.
+	contrib, err := component.SyncCommitteeContribution(ctx, slot, subcommIdx, blockRoot)
+	require.NoError(t, err)
+	require.EqualValues(t, subcommIdx, contrib.SubcommitteeIndex)
+	requireReady(1)
+}
+
+// noopDeadliner is a core.Deadliner that never expires duties.
+type noopDeadliner struct{}
+
+func (noopDeadliner) Add(core.Duty) bool {
+	return true
+}
+
+func (noopDeadliner) C() <-chan core.Duty {
+	return nil
+}
+
+func TestComponent_SubmitSyncCommitteeContributions(t *testing.T) {
+	const vIdx = 1
+
+	var (
+		count        = 0 // No of times the subscription function is called.
+		ctx          = context.Background()
+		contrib      = testutil.RandomSignedSyncContributionAndProof()
+		pubkey       = beaconmock.ValidatorSetA[vIdx].Validator.PublicKey
+		expectedDuty = core.NewSyncContributionDuty(int64(contrib.Message.Contribution.Slot))
+	)
+
+	contrib.Message.AggregatorIndex = vIdx
+
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	require.NoError(t, err)
+
+	vapi, err := validatorapi.NewComponentInsecure(t, bmock, 0)
+	require.NoError(t, err)
+
+	vapi.Subscribe(func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Equal(t, expectedDuty, duty)
+
+		pk, err := core.PubKeyFromBytes(pubkey[:])
+		require.NoError(t, err)
+
+		data, ok := set[pk]
+		require.True(t, ok)
+		require.Equal(t, core.NewPartialSignedSyncContributionAndProof(contrib, 0), data)
+		count++
+
+		return nil
+	})
+
+	require.NoError(t, vapi.SubmitSyncCommitteeContributions(ctx, []*altair.SignedContributionAndProof{contrib}))
+	require.Equal(t, count, 1)
+}
+
+func TestComponent_SyncMultipleSubcommittees(t *testing.T) {
+	const vIdx = 1
+
+	ctx := context.Background()
+	slot := eth2p0.Slot(99)
+
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	require.NoError(t, err)
+
+	vapi, err := validatorapi.NewComponentInsecure(t, bmock, 0)
+	require.NoError(t, err)
+
+	var sets []core.ParSignedDataSet
+	vapi.Subscribe(func(_ context.Context, _ core.Duty, set core.ParSignedDataSet) error {
+		sets = append(sets, set)
+		return nil
+	})
+
+	// A validator in two sync subcommittees submits a contribution for each in the same slot.
+	var contribs []*altair.SignedContributionAndProof
+	for subcommIdx := uint64(0); subcommIdx < 2; subcommIdx++ {
+		contrib := testutil.RandomSignedSyncContributionAndProof()
+		contrib.Message.AggregatorIndex = vIdx
+		contrib.Message.Contribution.Slot = slot
+		contrib.Message.Contribution.SubcommitteeIndex = subcommIdx
+		contribs = append(contribs, contrib)
+	}
+
+	require.NoError(t, vapi.SubmitSyncCommitteeContributions(ctx, contribs))
+	require.Len(t, sets, 1)
+	require.Len(t, sets[0], 1)
+
+	// And a selection for each.
+	var selections []*eth2exp.SyncCommitteeSelection
+	for subcommIdx := eth2p0.CommitteeIndex(0); subcommIdx < 2; subcommIdx++ {
+		selection := testutil.RandomSyncCommitteeSelection()
+		selection.ValidatorIndex = vIdx
+		selection.Slot = slot
+		selection.SubcommitteeIndex = subcommIdx
+		selections = append(selections, selection)
+	}
+
+	vapi.RegisterAwaitAggSigDB(func(context.Context, core.Duty, core.PubKey) (core.SignedData, error) {
+		return core.NewSyncCommitteeSelection(selections[1]), nil
+	})
+
+	resp, err := vapi.AggregateSyncCommitteeSelections(ctx, selections)
+	require.NoError(t, err)
+	require.Len(t, resp, 1)
+	require.Len(t, sets, 2)
+}
+
+func TestComponent_SubmitSyncCommitteeContributionsVerify(t *testing.T) {
+	const shareIdx = 1
+	var (
+		ctx        = context.Background()
+		val        = testutil.RandomValidator(t)
+		slot       = eth2p0.Slot(50)
+		subcommIdx = eth2p0.CommitteeIndex(1)
+	)
+
+	// Create keys (just use normal keys, not split tbls).
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
+
+	val.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
+
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSet{val.Index: val}))
+	require.NoError(t, err)
+
+	// Create contribution and proof.
+	contribAndProof := &altair.ContributionAndProof{
+		AggregatorIndex: val.Index,
+		Contribution:    testutil.RandomSyncCommitteeContribution(),
+	}
+	contribAndProof.Contribution.Slot = slot
+	contribAndProof.Contribution.SubcommitteeIndex = uint64(subcommIdx)
+	contribAndProof.SelectionProof = syncCommSelectionProof(t, bmock, secret, slot, subcommIdx)
+
+	signedContribAndProof := &altair.SignedContributionAndProof{
+		Message:   contribAndProof,
+		Signature: signContributionAndProof(t, bmock, secret, contribAndProof),
+	}
+
+	// Construct validatorapi component.
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	// Collect submitted partial signature.
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Len(t, set, 1)
+		_, ok := set[core.PubKeyFrom48Bytes(val.Validator.PublicKey)]
+		require.True(t, ok)
+		close(done)
+
+		return nil
+	})
+
+	err = vapi.SubmitSyncCommitteeContributions(ctx, []*altair.SignedContributionAndProof{signedContribAndProof})
+	require.NoError(t, err)
+	<-done
+}
+
+func TestComponent_AggregateSyncCommitteeSelectionsVerify(t *testing.T) {
+	const (
+		slot     = 0
+		shareIdx = 1
+		vIdxA    = 1
+		vIdxB    = 2
+	)
+
+	var (
+		ctx    = context.Background()
+		valSet = beaconmock.ValidatorSetA
+	)
+
+	// Construct beaconmock.
+	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(valSet))
+	require.NoError(t, err)
+
+	// Sync committee selection 1.
+	secret1, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey1, err := tblsv2.SecretToPublicKey(secret1)
+	require.NoError(t, err)
+
+	pk1, err := core.PubKeyFromBytes(pubkey1[:])
+	require.NoError(t, err)
+
+	valSet[vIdxA].Validator.PublicKey = eth2p0.BLSPubKey(pubkey1)
+
+	selection1 := testutil.RandomSyncCommitteeSelection()
+	selection1.ValidatorIndex = valSet[1].Index
+	selection1.Slot = slot
+	selection1.SelectionProof = syncCommSelectionProof(t, bmock, secret1, slot, selection1.SubcommitteeIndex)
+
+	// Sync committee selection 2.
+	secret2, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	pubkey2, err := tblsv2.SecretToPublicKey(secret2)
+	require.NoError(t, err)
+
+	pk2, err := core.PubKeyFromBytes(pubkey2[:])
+	require.NoError(t, err)
+
+	valSet[vIdxB].Validator.PublicKey = eth2p0.BLSPubKey(pubkey2)
+
+	selection2 := testutil.RandomSyncCommitteeSelection()
+	selection2.ValidatorIndex = valSet[2].Index
+	selection2.Slot = slot
+	selection2.SelectionProof = syncCommSelectionProof(t, bmock, secret2, slot, selection2.SubcommitteeIndex)
+
+	selections := []*eth2exp.SyncCommitteeSelection{selection1, selection2}
+
+	// Populate all pubshares map.
+	corePubKey1, err := core.PubKeyFromBytes(pubkey1[:])
+	require.NoError(t, err)
+	corePubKey2, err := core.PubKeyFromBytes(pubkey2[:])
+	require.NoError(t, err)
+
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{
+		corePubKey1: {shareIdx: pubkey1},
+		corePubKey2: {shareIdx: pubkey2},
+	}
+
+	// Construct the validator api component.
+	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	require.NoError(t, err)
+
+	vapi.RegisterAwaitAggSigDB(func(ctx context.Context, duty core.Duty, pubkey core.PubKey) (core.SignedData, error) {
+		require.Equal(t, core.NewPrepareSyncContributionDuty(slot), duty)
+		for _, val := range valSet {
+			pkEth2, err := pubkey.ToETH2()
+			require.NoError(t, err)
+			if pkEth2 != val.Validator.PublicKey {
+				continue
+			}
+
+			for _, selection := range selections {
+				if selection.ValidatorIndex == val.Index {
+					require.Equal(t, eth2p0.Slot(slot), selection.Slot)
+
+					return core.NewSyncCommitteeSelection(selection), nil
+				}
+			}
+		}
+
+		return nil, errors.New("unknown public key")
+	})
+
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Equal(t, duty, core.NewPrepareSyncContributionDuty(slot))
+
+		expect := core.ParSignedDataSet{
+			pk1: core.NewPartialSignedSyncCommitteeSelection(selection1, shareIdx),
+			pk2: core.NewPartialSignedSyncCommitteeSelection(selection2, shareIdx),
+		}
+
+		require.Equal(t, expect, set)
+
+		return nil
+	})
+
+	got, err := vapi.AggregateSyncCommitteeSelections(ctx, selections)
+	require.NoError(t, err)
+
+	// Sort by VIdx before comparing.
+	sort.Slice(got, func(i, j int) bool {
+		return got[i].ValidatorIndex < got[j].ValidatorIndex
+	})
+
+	require.Equal(t, selections, got)
+}
+
+func signAggregationAndProof(t *testing.T, eth2Cl eth2wrap.Client, secret tblsv2.PrivateKey, aggProof *eth2p0.AggregateAndProof) eth2p0.BLSSignature {
+	t.Helper()
+
+	epoch, err := eth2util.EpochFromSlot(context.Background(), eth2Cl, aggProof.Aggregate.Data.Slot)
+	require.NoError(t, err)
+
+	dataRoot, err := aggProof.HashTreeRoot()
+	require.NoError(t, err)
+
+	return sign(t, eth2Cl, secret, signing.DomainAggregateAndProof, epoch, dataRoot)
+}
+
+// syncCommSelectionProof returns the selection_proof corresponding to the provided altair.ContributionAndProof.
+// Refer get_sync_committee_selection_proof from https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/validator.md#aggregation-selection.
+func syncCommSelectionProof(t *testing.T, eth2Cl eth2wrap.Client, secret tblsv2.PrivateKey, slot eth2p0.Slot, subcommIdx eth2p0.CommitteeIndex) eth2p0.BLSSignature {
+	t.Helper()
+
+	epoch, err := eth2util.EpochFromSlot(context.Background(), eth2Cl, slot)
+	require.NoError(t, err)
+
+	data := altair.SyncAggregatorSelectionData{
+		Slot:              slot,
+		SubcommitteeIndex: uint64(subcommIdx),
+	}
+
+	sigRoot, err := data.HashTreeRoot()
+	require.NoError(t, err)
+
+	return sign(t, eth2Cl, secret, signing.DomainSyncCommitteeSelectionProof, epoch, sigRoot)
+}
+
+// signContributionAndProof signs the provided altair.SignedContributionAndProof and returns the signature.
+// Refer get_contribution_and_proof_signature from https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/validator.md#broadcast-sync-committee-contribution
+func signContributionAndProof(t *testing.T, eth2Cl eth2wrap.Client, secret tblsv2.PrivateKey, contrib *altair.ContributionAndProof) eth2p0.BLSSignature {
+	t.Helper()
+
+	epoch, err := eth2util.EpochFromSlot(context.Background(), eth2Cl, contrib.Contribution.Slot)
+	require.NoError(t, err)
+
+	sigRoot, err := contrib.HashTreeRoot()
+	require.NoError(t, err)
+
+	return sign(t, eth2Cl, secret, signing.DomainContributionAndProof, epoch, sigRoot)
+}
+
+func signBeaconSelection(t *testing.T, eth2Cl eth2wrap.Client, secret tblsv2.PrivateKey, slot eth2p0.Slot) eth2p0.BLSSignature {
+	t.Helper()
+
+	epoch, err := eth2util.EpochFromSlot(context.Background(), eth2Cl, slot)
+	require.NoError(t, err)
+
+	dataRoot, err := eth2util.SlotHashRoot(slot)
+	require.NoError(t, err)
+
+	return sign(t, eth2Cl, secret, signing.DomainSelectionProof, epoch, dataRoot)
+}
+
+func sign(t *testing.T, eth2Cl eth2wrap.Client, secret tblsv2.PrivateKey, domain signing.DomainName, epoch eth2p0.Epoch, dataRoot eth2p0.Root) eth2p0.BLSSignature {
+	t.Helper()
+	ctx := context.Background()
+
+	signingRoot, err := signing.GetDataRoot(ctx, eth2Cl, domain, epoch, dataRoot)
+	require.NoError(t, err)
+
+	sig, err := tblsv2.Sign(secret, signingRoot[:])
+	require.NoError(t, err)
+
+	return eth2p0.BLSSignature(sig)
+}
+
+func TestAggregateSyncContributions(t *testing.T) {
+	msg := []byte("beacon block root")
+
+	newContrib := func(t *testing.T, subIdx uint64, bit uint64) (*altair.SyncCommitteeContribution, tblsv2.Signature) {
+		t.Helper()
+
+		secret, err := tblsv2.GenerateSecretKey()
+		require.NoError(t, err)
+		sig, err := tblsv2.Sign(secret, msg)
+		require.NoError(t, err)
+
+		bits := bitfield.NewBitvector128()
+		bits.SetBitAt(bit, true)
+
+		return &altair.SyncCommitteeContribution{
+			Slot:              1,
+			SubcommitteeIndex: subIdx,
+			AggregationBits:   bits,
+			Signature:         eth2p0.BLSSignature(sig),
+		}, sig
+	}
+
+	contrib0, sig0 := newContrib(t, 0, 1)
+	contrib1, sig1 := newContrib(t, 1, 2)
+
+	t.Run("disjoint", func(t *testing.T) {
+		agg, err := validatorapi.AggregateSyncContributions([]*altair.SyncCommitteeContribution{contrib0, contrib1})
+		require.NoError(t, err)
+
+		expectSig, err := tblsv2.Aggregate([]tblsv2.Signature{sig0, sig1})
+		require.NoError(t, err)
+		require.Equal(t, eth2p0.BLSSignature(expectSig), agg.SyncCommitteeSignature)
+		require.Equal(t, []int{1, 128 + 2}, agg.SyncCommitteeBits.BitIndices())
+	})
+
+	t.Run("overlapping", func(t *testing.T) {
+		overlap, _ := newContrib(t, 1, 2)
+		_, err := validatorapi.AggregateSyncContributions([]*altair.SyncCommitteeContribution{contrib0, contrib1, overlap})
+		require.ErrorContains(t, err, "overlapping sync committee participation bits")
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		invalid, _ := newContrib(t, 4, 0)
+		_, err := validatorapi.AggregateSyncContributions([]*altair.SyncCommitteeContribution{invalid})
+		require.ErrorContains(t, err, "subcommittee index out of range")
+	})
+}
+
+func TestCommitteeCache(t *testing.T) {
+	ctx := context.Background()
+
+	const slotsPerEpoch = 4
+
+	bmock, err := beaconmock.New(beaconmock.WithSlotsPerEpoch(slotsPerEpoch))
+	require.NoError(t, err)
+
+	fetched := make(map[eth2p0.Epoch]int)
+	bmock.BeaconCommitteesAtEpochFunc = func(_ context.Context, _ string, epoch eth2p0.Epoch) ([]*eth2v1.BeaconCommittee, error) {
+		fetched[epoch]++
+
+		var resp []*eth2v1.BeaconCommittee
+		for i := 0; i < slotsPerEpoch; i++ {
+			slot := eth2p0.Slot(uint64(epoch)*slotsPerEpoch + uint64(i))
+			resp = append(resp, &eth2v1.BeaconCommittee{
+				Slot:       slot,
+				Index:      1,
+				Validators: make([]eth2p0.ValidatorIndex, 8+int(slot)),
+			})
+		}
+
+		return resp, nil
+	}
+
+	cache := validatorapi.NewCommitteeCache(bmock)
+
+	// Query all slots of two epochs, crossing the epoch boundary.
+	for slot := int64(0); slot < 2*slotsPerEpoch; slot++ {
+		for i := 0; i < 2; i++ {
+			size, err := cache.CommitteeSize(ctx, slot, 1)
+			require.NoError(t, err)
+			require.Equal(t, 8+int(slot), size)
+		}
+	}
+
+	require.Equal(t, map[eth2p0.Epoch]int{0: 1, 1: 1}, fetched)
+
+	_, err = cache.CommitteeSize(ctx, 0, 2)
+	require.ErrorContains(t, err, "beacon committee not found")
+
+	t.Run("submit attestations", func(t *testing.T) {
+		vapi, err := validatorapi.NewComponentInsecure(t, bmock, 1)
+		require.NoError(t, err)
+
+		vapi.RegisterCommitteeSize(cache.CommitteeSize)
+		vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+			return testutil.RandomCorePubKey(t), nil
+		})
+
+		newAtt := func(bitsLen uint64) *eth2p0.Attestation {
+			aggBits := bitfield.NewBitlist(bitsLen)
+			aggBits.SetBitAt(1, true)
+
+			return &eth2p0.Attestation{
+				AggregationBits: aggBits,
+				Data: &eth2p0.AttestationData{
+					Slot:   2,
+					Index:  1,
+					Source: &eth2p0.Checkpoint{},
+					Target: &eth2p0.Checkpoint{},
+				},
+			}
+		}
+
+		err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(10), newAtt(10)})
+		require.NoError(t, err)
 
-	err = vapi.SubmitValidatorRegistrations(ctx, []*eth2api.VersionedSignedValidatorRegistration{signed})
-	require.ErrorContains(t, err, "signature not verified")
+		err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(9)})
+		require.ErrorContains(t, err, "attestation aggregation bits length mismatches committee size")
+
+		require.Equal(t, map[eth2p0.Epoch]int{0: 1, 1: 1}, fetched)
+	})
 }
 
-func TestComponent_TekuProposerConfig(t *testing.T) {
+func TestCommitteeCacheConcurrent(t *testing.T) {
 	ctx := context.Background()
-	const (
-		zeroAddr     = "0x0000000000000000000000000000000000000000"
-		feeRecipient = "0x123456"
-		shareIdx     = 1
-	)
-	// Create keys (just use normal keys, not split tbls)
-	secret, err := tblsv2.GenerateSecretKey()
-	require.NoError(t, err)
 
-	pubkey, err := tblsv2.SecretToPublicKey(secret)
-	require.NoError(t, err)
+	const slotsPerEpoch = 4
 
-	// Convert pubkey
-	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
+	bmock, err := beaconmock.New(beaconmock.WithSlotsPerEpoch(slotsPerEpoch))
 	require.NoError(t, err)
-	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	// Configure beacon mock
-	bmock, err := beaconmock.New()
-	require.NoError(t, err)
+	var (
+		mu      sync.Mutex
+		fetched = make(map[eth2p0.Epoch]int)
+		release = make(chan struct{})
+	)
+	bmock.BeaconCommitteesAtEpochFunc = func(_ context.Context, _ string, epoch eth2p0.Epoch) ([]*eth2v1.BeaconCommittee, error) {
+		mu.Lock()
+		fetched[epoch]++
+		mu.Unlock()
 
-	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, func(core.PubKey) string {
-		return feeRecipient
-	}, testutil.BuilderTrue, nil)
-	require.NoError(t, err)
+		if epoch == 1 {
+			<-release // Block fetching the second epoch until released.
+		}
 
-	resp, err := vapi.TekuProposerConfig(ctx)
-	require.NoError(t, err)
+		return []*eth2v1.BeaconCommittee{{
+			Slot:       eth2p0.Slot(uint64(epoch) * slotsPerEpoch),
+			Index:      1,
+			Validators: make([]eth2p0.ValidatorIndex, 8),
+		}}, nil
+	}
 
-	pk, err := core.PubKeyFromBytes(pubkey[:])
+	cache := validatorapi.NewCommitteeCache(bmock)
+
+	_, err = cache.CommitteeSize(ctx, 0, 1)
 	require.NoError(t, err)
 
-	genesis, err := bmock.GenesisTime(ctx)
+	// Concurrently look up the blocked epoch.
+	const n = 4
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			size, err := cache.CommitteeSize(ctx, slotsPerEpoch, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, 8, size)
+		}()
+	}
+
+	// Lookups of the cached epoch don't wait for the blocked fetch.
+	size, err := cache.CommitteeSize(ctx, 0, 1)
 	require.NoError(t, err)
+	require.Equal(t, 8, size)
 
-	require.Equal(t, validatorapi.TekuProposerConfigResponse{
-		Proposers: map[string]validatorapi.TekuProposerConfig{
-			string(pk): {
-				FeeRecipient: feeRecipient,
-				Builder: validatorapi.TekuBuilder{
-					Enabled:  true,
-					GasLimit: 30000000,
-					Overrides: map[string]string{
-						"timestamp":  fmt.Sprint(genesis.Unix()),
-						"public_key": string(pk),
-					},
-				},
-			},
-		},
-		Default: validatorapi.TekuProposerConfig{
-			FeeRecipient: zeroAddr,
-			Builder: validatorapi.TekuBuilder{
-				Enabled:  false,
-				GasLimit: 30000000,
-			},
-		},
-	}, resp)
+	close(release)
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, map[eth2p0.Epoch]int{0: 1, 1: 1}, fetched)
 }
 
-func TestComponent_AggregateBeaconCommitteeSelections(t *testing.T) {
+func TestComponent_RedactSignatures(t *testing.T) {
 	ctx := context.Background()
 
-	const slot = 99
+	const shareIdx = 1
 
-	valSet := beaconmock.ValidatorSetA
-	eth2Cl, err := beaconmock.New(beaconmock.WithValidatorSet(valSet))
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	vapi, err := validatorapi.NewComponentInsecure(t, eth2Cl, 0)
+	bmock, err := beaconmock.New()
 	require.NoError(t, err)
 
-	selections := []*eth2exp.BeaconCommitteeSelection{
-		{
-			ValidatorIndex: valSet[1].Index,
-			Slot:           slot,
-			SelectionProof: testutil.RandomEth2Signature(),
-		}, {
-			ValidatorIndex: valSet[2].Index,
-			Slot:           slot,
-			SelectionProof: testutil.RandomEth2Signature(),
-		},
-	}
+	// Sign the wrong message, so verification fails.
+	sig, err := tblsv2.Sign(secret, []byte("invalid msg"))
+	require.NoError(t, err)
+	sigHex := fmt.Sprintf("%x", sig[:])
 
-	vapi.RegisterAwaitAggSigDB(func(_ context.Context, duty core.Duty, pk core.PubKey) (core.SignedData, error) {
-		require.Equal(t, core.NewPrepareAggregatorDuty(slot), duty)
-		for _, val := range valSet {
-			pkEth2, err := pk.ToETH2()
+	for _, redact := range []bool{false, true} {
+		t.Run(fmt.Sprint("redact=", redact), func(t *testing.T) {
+			vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
 			require.NoError(t, err)
-			if pkEth2 != val.Validator.PublicKey {
-				continue
-			}
-			for _, selection := range selections {
-				if selection.ValidatorIndex == val.Index {
-					return core.NewBeaconCommitteeSelection(selection), nil
-				}
+			vapi.SetRedactSignatures(redact)
+			vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+				return corePubKey, nil
+			})
+
+			aggBits := bitfield.NewBitlist(8)
+			aggBits.SetBitAt(1, true)
+			att := &eth2p0.Attestation{
+				AggregationBits: aggBits,
+				Data: &eth2p0.AttestationData{
+					Slot:   1,
+					Source: &eth2p0.Checkpoint{},
+					Target: &eth2p0.Checkpoint{},
+				},
+				Signature: eth2p0.BLSSignature(sig),
 			}
-		}
 
-		return nil, errors.New("unknown public key")
-	})
+			err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att})
+			require.ErrorContains(t, err, "signature not verified")
 
-	actual, err := vapi.AggregateBeaconCommitteeSelections(ctx, selections)
-	require.NoError(t, err)
+			var buf bytes.Buffer
+			log.InitLogfmtForT(t, zapcore.AddSync(&buf))
+			log.Error(ctx, "Verification failed", err)
 
-	// Sort by VIdx before comparing
-	sort.Slice(actual, func(i, j int) bool {
-		return actual[i].ValidatorIndex < actual[j].ValidatorIndex
-	})
-	require.Equal(t, selections, actual)
+			require.Contains(t, buf.String(), "DOMAIN_BEACON_ATTESTER")
+			if redact {
+				require.NotContains(t, buf.String(), sigHex)
+				require.NotContains(t, buf.String(), "sig_root")
+			} else {
+				require.Contains(t, buf.String(), sigHex)
+				require.Contains(t, buf.String(), "sig_root")
+			}
+		})
+	}
 }
 
-func TestComponent_SubmitAggregateAttestations(t *testing.T) {
+func TestComponent_VerifyReportOnly(t *testing.T) {
 	ctx := context.Background()
 
-	const vIdx = 1
-
-	agg := &eth2p0.SignedAggregateAndProof{
-		Message: &eth2p0.AggregateAndProof{
-			AggregatorIndex: vIdx,
-			Aggregate:       testutil.RandomAttestation(),
-			SelectionProof:  testutil.RandomEth2Signature(),
-		},
-		Signature: testutil.RandomEth2Signature(),
-	}
-
-	slot := agg.Message.Aggregate.Data.Slot
-	pubkey := beaconmock.ValidatorSetA[vIdx].Validator.PublicKey
-
-	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
-	require.NoError(t, err)
-
-	vapi, err := validatorapi.NewComponentInsecure(t, bmock, 0)
-	require.NoError(t, err)
+	const shareIdx = 1
 
-	vapi.Subscribe(func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		require.Equal(t, core.NewAggregatorDuty(int64(slot)), duty)
+	// wouldRejectCount returns the number of partial signatures that would have been rejected.
+	wouldRejectCount := func(t *testing.T) float64 {
+		t.Helper()
 
-		pk, err := core.PubKeyFromBytes(pubkey[:])
+		registry, err := promauto.NewRegistry(nil)
 		require.NoError(t, err)
 
-		data, ok := set[pk]
-		require.True(t, ok)
-		require.Equal(t, core.NewPartialSignedAggregateAndProof(agg, 0), data)
-
-		return nil
-	})
+		families, err := registry.Gather()
+		require.NoError(t, err)
 
-	require.NoError(t, vapi.SubmitAggregateAttestations(ctx, []*eth2p0.SignedAggregateAndProof{agg}))
-}
+		for _, family := range families {
+			if family.GetName() == "core_validatorapi_verify_would_reject_total" {
+				return family.GetMetric()[0].GetCounter().GetValue()
+			}
+		}
 
-func TestComponent_SubmitAggregateAttestationVerify(t *testing.T) {
-	const shareIdx = 1
-	var (
-		ctx = context.Background()
-		val = testutil.RandomValidator(t)
-	)
+		return 0
+	}
 
-	// Create keys (just use normal keys, not split tbls)
 	secret, err := tblsv2.GenerateSecretKey()
 	require.NoError(t, err)
-
 	pubkey, err := tblsv2.SecretToPublicKey(secret)
 	require.NoError(t, err)
-
 	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
-
 	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	val.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
 
-	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSet{val.Index: val}))
+	// Sign the wrong message, so verification fails.
+	sig, err := tblsv2.Sign(secret, []byte("invalid msg"))
 	require.NoError(t, err)
 
-	slot := eth2p0.Slot(99)
-	aggProof := &eth2p0.AggregateAndProof{
-		AggregatorIndex: val.Index,
-		Aggregate:       testutil.RandomAttestation(),
-	}
-	aggProof.Aggregate.Data.Slot = slot
-	aggProof.SelectionProof = signBeaconSelection(t, bmock, secret, slot)
-	signedAggProof := &eth2p0.SignedAggregateAndProof{
-		Message:   aggProof,
-		Signature: signAggregationAndProof(t, bmock, secret, aggProof),
+	for _, reportOnly := range []bool{false, true} {
+		t.Run(fmt.Sprint("report_only=", reportOnly), func(t *testing.T) {
+			vapi, err := validatorapi.New(bmock, allPubSharesByKey, shareIdx, validatorapi.WithVerifyReportOnly(reportOnly))
+			require.NoError(t, err)
+			vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+				return corePubKey, nil
+			})
+
+			var submitted int
+			vapi.Subscribe(func(context.Context, core.Duty, core.ParSignedDataSet) error {
+				submitted++
+				return nil
+			})
+
+			aggBits := bitfield.NewBitlist(8)
+			aggBits.SetBitAt(1, true)
+			att := &eth2p0.Attestation{
+				AggregationBits: aggBits,
+				Data: &eth2p0.AttestationData{
+					Slot:   1,
+					Source: &eth2p0.Checkpoint{},
+					Target: &eth2p0.Checkpoint{},
+				},
+				Signature: eth2p0.BLSSignature(sig),
+			}
+
+			before := wouldRejectCount(t)
+
+			err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{att})
+			if reportOnly {
+				// Failures are reported but not returned.
+				require.NoError(t, err)
+				require.Equal(t, 1, submitted)
+				require.Equal(t, before+1, wouldRejectCount(t))
+			} else {
+				require.ErrorContains(t, err, "signature not verified")
+				require.Zero(t, submitted)
+				require.Equal(t, before, wouldRejectCount(t))
+			}
+		})
 	}
+}
 
-	// Construct the validator api component
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
-	require.NoError(t, err)
+// blockingDomainClient is an eth2 client that blocks domain queries, and therefore signature verification, until unblocked.
+// Unblocked queries return the error if not nil.
+type blockingDomainClient struct {
+	eth2wrap.Client
+	unblock chan struct{}
+	err     error
+}
 
-	done := make(chan struct{})
-	// Collect submitted partial signature.
-	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		require.Len(t, set, 1)
-		_, ok := set[core.PubKeyFrom48Bytes(val.Validator.PublicKey)]
-		require.True(t, ok)
-		close(done)
+func (c blockingDomainClient) Domain(ctx context.Context, domainType eth2p0.DomainType, epoch eth2p0.Epoch) (eth2p0.Domain, error) {
+	select {
+	case <-ctx.Done():
+		return eth2p0.Domain{}, ctx.Err()
+	case <-c.unblock:
+	}
 
-		return nil
-	})
+	if c.err != nil {
+		return eth2p0.Domain{}, c.err
+	}
 
-	err = vapi.SubmitAggregateAttestations(ctx, []*eth2p0.SignedAggregateAndProof{signedAggProof})
-	require.NoError(t, err)
-	<-done
+	return c.Client.Domain(ctx, domainType, epoch)
 }
 
-func TestComponent_SubmitSyncCommitteeMessages(t *testing.T) {
-	const vIdx = 1
-
-	var (
-		ctx    = context.Background()
-		msg    = testutil.RandomSyncCommitteeMessage()
-		pubkey = beaconmock.ValidatorSetA[vIdx].Validator.PublicKey
-		count  = 0 // No of times the subscription function is called.
-	)
+func TestComponent_AsyncVerify(t *testing.T) {
+	ctx := context.Background()
 
-	msg.ValidatorIndex = vIdx
+	const shareIdx = 1
 
-	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	secret, err := tblsv2.GenerateSecretKey()
 	require.NoError(t, err)
-
-	vapi, err := validatorapi.NewComponentInsecure(t, bmock, 0)
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	vapi.Subscribe(func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		require.Equal(t, core.NewSyncMessageDuty(int64(msg.Slot)), duty)
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
 
-		pk, err := core.PubKeyFromBytes(pubkey[:])
-		require.NoError(t, err)
+	eth2Cl := blockingDomainClient{Client: bmock, unblock: make(chan struct{})}
+	clock := clockwork.NewFakeClock()
 
-		data, ok := set[pk]
-		require.True(t, ok)
-		require.Equal(t, core.NewPartialSignedSyncMessage(msg, 0), data)
-		count++
+	vapi, err := validatorapi.New(eth2Cl, allPubSharesByKey, shareIdx, validatorapi.WithAsyncVerify(true))
+	require.NoError(t, err)
+	vapi.SetClock(clock)
+	defer func() {
+		require.NoError(t, vapi.Close(ctx))
+	}()
 
-		return nil
+	vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+		return corePubKey, nil
 	})
 
-	require.NoError(t, vapi.SubmitSyncCommitteeMessages(ctx, []*altair.SyncCommitteeMessage{msg}))
-	require.Equal(t, count, 1)
-}
+	protector := validatorapi.NewMemSlashingProtector()
+	vapi.RegisterSlashingProtector(protector)
 
-func TestComponent_SubmitSyncCommitteeContributions(t *testing.T) {
-	const vIdx = 1
+	const failedSlot = 3
 
-	var (
-		count        = 0 // No of times the subscription function is called.
-		ctx          = context.Background()
-		contrib      = testutil.RandomSignedSyncContributionAndProof()
-		pubkey       = beaconmock.ValidatorSetA[vIdx].Validator.PublicKey
-		expectedDuty = core.NewSyncContributionDuty(int64(contrib.Message.Contribution.Slot))
-	)
+	var stored int
+	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
+		require.Contains(t, set, corePubKey)
+		if duty.Slot == failedSlot {
+			return errors.New("store failed")
+		}
+		stored++
 
-	contrib.Message.AggregatorIndex = vIdx
+		return nil
+	})
 
-	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSetA))
+	// Sign the wrong message, so verification fails.
+	sig, err := tblsv2.Sign(secret, []byte("invalid msg"))
 	require.NoError(t, err)
 
-	vapi, err := validatorapi.NewComponentInsecure(t, bmock, 0)
-	require.NoError(t, err)
+	newAtt := func(slot eth2p0.Slot) *eth2p0.Attestation {
+		aggBits := bitfield.NewBitlist(8)
+		aggBits.SetBitAt(1, true)
 
-	vapi.Subscribe(func(_ context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		require.Equal(t, expectedDuty, duty)
+		return &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Slot:   slot,
+				Source: &eth2p0.Checkpoint{},
+				Target: &eth2p0.Checkpoint{},
+			},
+			Signature: eth2p0.BLSSignature(sig),
+		}
+	}
 
-		pk, err := core.PubKeyFromBytes(pubkey[:])
+	asyncVerifyFailures := func() float64 {
+		registry, err := promauto.NewRegistry(nil)
 		require.NoError(t, err)
 
-		data, ok := set[pk]
-		require.True(t, ok)
-		require.Equal(t, core.NewPartialSignedSyncContributionAndProof(contrib, 0), data)
-		count++
+		families, err := registry.Gather()
+		require.NoError(t, err)
 
-		return nil
-	})
+		for _, family := range families {
+			if family.GetName() == "core_validatorapi_async_verification_failure_total" {
+				return family.GetMetric()[0].GetCounter().GetValue()
+			}
+		}
 
-	require.NoError(t, vapi.SubmitSyncCommitteeContributions(ctx, []*altair.SignedContributionAndProof{contrib}))
-	require.Equal(t, count, 1)
+		return 0
+	}
+
+	failures := asyncVerifyFailures()
+
+	// The partial signature is stored while verification is still blocked, even if storing another slot fails.
+	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(1), newAtt(failedSlot)})
+	require.ErrorContains(t, err, "store failed")
+	require.Equal(t, 1, stored)
+	require.Equal(t, failures, asyncVerifyFailures())
+
+	// Failed asynchronous verification of all attestations raises the metric and quarantines the validator.
+	close(eth2Cl.unblock)
+	require.Eventually(t, func() bool {
+		return asyncVerifyFailures() == failures+2
+	}, time.Second, time.Millisecond)
+
+	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(2)})
+	require.ErrorContains(t, err, "quarantined validator")
+	require.Equal(t, 1, stored)
+
+	// The unverified attestation wasn't recorded for slashing protection, so a double vote isn't slashable.
+	require.NoError(t, protector.CheckAttestation(ctx, corePubKey, newAtt(2).Data))
+
+	// The quarantine expires.
+	clock.Advance(10 * time.Minute)
+	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(2)})
+	require.NoError(t, err)
+	require.Equal(t, 2, stored)
 }
 
-func TestComponent_SubmitSyncCommitteeContributionsVerify(t *testing.T) {
+func TestComponent_AsyncVerifyBeaconError(t *testing.T) {
+	ctx := context.Background()
+
 	const shareIdx = 1
-	var (
-		ctx        = context.Background()
-		val        = testutil.RandomValidator(t)
-		slot       = eth2p0.Slot(50)
-		subcommIdx = eth2p0.CommitteeIndex(1)
-	)
 
-	// Create keys (just use normal keys, not split tbls).
 	secret, err := tblsv2.GenerateSecretKey()
 	require.NoError(t, err)
-
 	pubkey, err := tblsv2.SecretToPublicKey(secret)
 	require.NoError(t, err)
-
 	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
 	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: {shareIdx: pubkey}} // Maps self to self since not tbls
 
-	val.Validator.PublicKey = eth2p0.BLSPubKey(pubkey)
-
-	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(beaconmock.ValidatorSet{val.Index: val}))
+	bmock, err := beaconmock.New()
 	require.NoError(t, err)
 
-	// Create contribution and proof.
-	contribAndProof := &altair.ContributionAndProof{
-		AggregatorIndex: val.Index,
-		Contribution:    testutil.RandomSyncCommitteeContribution(),
-	}
-	contribAndProof.Contribution.Slot = slot
-	contribAndProof.Contribution.SubcommitteeIndex = uint64(subcommIdx)
-	contribAndProof.SelectionProof = syncCommSelectionProof(t, bmock, secret, slot, subcommIdx)
-
-	signedContribAndProof := &altair.SignedContributionAndProof{
-		Message:   contribAndProof,
-		Signature: signContributionAndProof(t, bmock, secret, contribAndProof),
-	}
+	// Verification fails due to beacon node errors.
+	eth2Cl := blockingDomainClient{Client: bmock, unblock: make(chan struct{}), err: errors.New("beacon node error")}
+	close(eth2Cl.unblock)
 
-	// Construct validatorapi component.
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
+	vapi, err := validatorapi.New(eth2Cl, allPubSharesByKey, shareIdx, validatorapi.WithAsyncVerify(true))
 	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, vapi.Close(ctx))
+	}()
 
-	done := make(chan struct{})
-	// Collect submitted partial signature.
-	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		require.Len(t, set, 1)
-		_, ok := set[core.PubKeyFrom48Bytes(val.Validator.PublicKey)]
-		require.True(t, ok)
-		close(done)
+	vapi.RegisterPubKeyByAttestation(func(context.Context, int64, int64, int64) (core.PubKey, error) {
+		return corePubKey, nil
+	})
 
+	var stored int
+	vapi.Subscribe(func(context.Context, core.Duty, core.ParSignedDataSet) error {
+		stored++
 		return nil
 	})
 
-	err = vapi.SubmitSyncCommitteeContributions(ctx, []*altair.SignedContributionAndProof{signedContribAndProof})
-	require.NoError(t, err)
-	<-done
-}
+	newAtt := func(slot eth2p0.Slot) *eth2p0.Attestation {
+		aggBits := bitfield.NewBitlist(8)
+		aggBits.SetBitAt(1, true)
 
-func TestComponent_AggregateSyncCommitteeSelectionsVerify(t *testing.T) {
-	const (
-		slot     = 0
-		shareIdx = 1
-		vIdxA    = 1
-		vIdxB    = 2
-	)
+		return &eth2p0.Attestation{
+			AggregationBits: aggBits,
+			Data: &eth2p0.AttestationData{
+				Slot:   slot,
+				Source: &eth2p0.Checkpoint{},
+				Target: &eth2p0.Checkpoint{},
+			},
+			Signature: testutil.RandomEth2Signature(),
+		}
+	}
 
-	var (
-		ctx    = context.Background()
-		valSet = beaconmock.ValidatorSetA
-	)
+	asyncVerifyErrors := func() float64 {
+		registry, err := promauto.NewRegistry(nil)
+		require.NoError(t, err)
 
-	// Construct beaconmock.
-	bmock, err := beaconmock.New(beaconmock.WithValidatorSet(valSet))
-	require.NoError(t, err)
+		families, err := registry.Gather()
+		require.NoError(t, err)
 
-	// Sync committee selection 1.
-	secret1, err := tblsv2.GenerateSecretKey()
-	require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "core_validatorapi_async_verification_error_total" {
+				return family.GetMetric()[0].GetCounter().GetValue()
+			}
+		}
 
-	pubkey1, err := tblsv2.SecretToPublicKey(secret1)
-	require.NoError(t, err)
+		return 0
+	}
 
-	pk1, err := core.PubKeyFromBytes(pubkey1[:])
+	errs := asyncVerifyErrors()
+
+	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(1)})
 	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return asyncVerifyErrors() == errs+1
+	}, time.Second, time.Millisecond)
 
-	valSet[vIdxA].Validator.PublicKey = eth2p0.BLSPubKey(pubkey1)
+	// The validator isn't quarantined.
+	err = vapi.SubmitAttestations(ctx, []*eth2p0.Attestation{newAtt(1)})
+	require.NoError(t, err)
+	require.Equal(t, 2, stored)
+}
 
-	selection1 := testutil.RandomSyncCommitteeSelection()
-	selection1.ValidatorIndex = valSet[1].Index
-	selection1.Slot = slot
-	selection1.SelectionProof = syncCommSelectionProof(t, bmock, secret1, slot, selection1.SubcommitteeIndex)
+func TestVerifyShareAssignment(t *testing.T) {
+	const (
+		shareIdx  = 2
+		total     = 4
+		threshold = 3
+	)
 
-	// Sync committee selection 2.
-	secret2, err := tblsv2.GenerateSecretKey()
+	secret, err := tblsv2.GenerateSecretKey()
 	require.NoError(t, err)
 
-	pubkey2, err := tblsv2.SecretToPublicKey(secret2)
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
 	require.NoError(t, err)
 
-	pk2, err := core.PubKeyFromBytes(pubkey2[:])
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
 
-	valSet[vIdxB].Validator.PublicKey = eth2p0.BLSPubKey(pubkey2)
-
-	selection2 := testutil.RandomSyncCommitteeSelection()
-	selection2.ValidatorIndex = valSet[2].Index
-	selection2.Slot = slot
-	selection2.SelectionProof = syncCommSelectionProof(t, bmock, secret2, slot, selection2.SubcommitteeIndex)
-
-	selections := []*eth2exp.SyncCommitteeSelection{selection1, selection2}
-
-	// Populate all pubshares map.
-	corePubKey1, err := core.PubKeyFromBytes(pubkey1[:])
-	require.NoError(t, err)
-	corePubKey2, err := core.PubKeyFromBytes(pubkey2[:])
+	shares, err := tblsv2.ThresholdSplit(secret, total, threshold)
 	require.NoError(t, err)
 
-	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{
-		corePubKey1: {shareIdx: pubkey1},
-		corePubKey2: {shareIdx: pubkey2},
+	pubShares := make(map[int]tblsv2.PublicKey)
+	for idx, share := range shares {
+		pubShares[idx], err = tblsv2.SecretToPublicKey(share)
+		require.NoError(t, err)
 	}
 
-	// Construct the validator api component.
-	vapi, err := validatorapi.NewComponent(bmock, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
-	require.NoError(t, err)
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: pubShares}
 
-	vapi.RegisterAwaitAggSigDB(func(ctx context.Context, duty core.Duty, pubkey core.PubKey) (core.SignedData, error) {
-		require.Equal(t, core.NewPrepareSyncContributionDuty(slot), duty)
-		for _, val := range valSet {
-			pkEth2, err := pubkey.ToETH2()
-			require.NoError(t, err)
-			if pkEth2 != val.Validator.PublicKey {
-				continue
-			}
+	t.Run("correctly assigned", func(t *testing.T) {
+		err := validatorapi.VerifyShareAssignment(allPubSharesByKey, shareIdx, []tblsv2.PrivateKey{shares[shareIdx]})
+		require.NoError(t, err)
+	})
 
-			for _, selection := range selections {
-				if selection.ValidatorIndex == val.Index {
-					require.Equal(t, eth2p0.Slot(slot), selection.Slot)
+	t.Run("incorrectly assigned", func(t *testing.T) {
+		err := validatorapi.VerifyShareAssignment(allPubSharesByKey, shareIdx, []tblsv2.PrivateKey{shares[shareIdx+1]})
+		require.ErrorContains(t, err, "mismatching key share index, Mth key share assigned to Nth charon peer")
+	})
 
-					return core.NewSyncCommitteeSelection(selection), nil
-				}
-			}
-		}
+	t.Run("missing share", func(t *testing.T) {
+		other, err := tblsv2.GenerateSecretKey()
+		require.NoError(t, err)
 
-		return nil, errors.New("unknown public key")
+		err = validatorapi.VerifyShareAssignment(allPubSharesByKey, shareIdx, []tblsv2.PrivateKey{other})
+		require.ErrorContains(t, err, "key share not found")
 	})
+}
 
-	vapi.Subscribe(func(ctx context.Context, duty core.Duty, set core.ParSignedDataSet) error {
-		require.Equal(t, duty, core.NewPrepareSyncContributionDuty(slot))
-
-		expect := core.ParSignedDataSet{
-			pk1: core.NewPartialSignedSyncCommitteeSelection(selection1, shareIdx),
-			pk2: core.NewPartialSignedSyncCommitteeSelection(selection2, shareIdx),
-		}
+func TestComponent_GroupPubKey(t *testing.T) {
+	const (
+		shareIdx  = 1
+		total     = 4
+		threshold = 3
+	)
 
-		require.Equal(t, expect, set)
+	secret, err := tblsv2.GenerateSecretKey()
+	require.NoError(t, err)
 
-		return nil
-	})
+	pubkey, err := tblsv2.SecretToPublicKey(secret)
+	require.NoError(t, err)
 
-	got, err := vapi.AggregateSyncCommitteeSelections(ctx, selections)
+	corePubKey, err := core.PubKeyFromBytes(pubkey[:])
 	require.NoError(t, err)
 
-	// Sort by VIdx before comparing.
-	sort.Slice(got, func(i, j int) bool {
-		return got[i].ValidatorIndex < got[j].ValidatorIndex
-	})
+	shares, err := tblsv2.ThresholdSplit(secret, total, threshold)
+	require.NoError(t, err)
 
-	require.Equal(t, selections, got)
-}
+	pubShares := make(map[int]tblsv2.PublicKey)
+	for idx, share := range shares {
+		pubShares[idx], err = tblsv2.SecretToPublicKey(share)
+		require.NoError(t, err)
+	}
 
-func signAggregationAndProof(t *testing.T, eth2Cl eth2wrap.Client, secret tblsv2.PrivateKey, aggProof *eth2p0.AggregateAndProof) eth2p0.BLSSignature {
-	t.Helper()
+	allPubSharesByKey := map[core.PubKey]map[int]tblsv2.PublicKey{corePubKey: pubShares}
 
-	epoch, err := eth2util.EpochFromSlot(context.Background(), eth2Cl, aggProof.Aggregate.Data.Slot)
+	vapi, err := validatorapi.NewComponent(nil, allPubSharesByKey, shareIdx, nil, testutil.BuilderFalse, nil)
 	require.NoError(t, err)
 
-	dataRoot, err := aggProof.HashTreeRoot()
+	// Query by DV root public key.
+	groupKey, err := vapi.GroupPubKey(corePubKey)
 	require.NoError(t, err)
+	require.Equal(t, pubkey, groupKey)
 
-	return sign(t, eth2Cl, secret, signing.DomainAggregateAndProof, epoch, dataRoot)
-}
-
-// syncCommSelectionProof returns the selection_proof corresponding to the provided altair.ContributionAndProof.
-// Refer get_sync_committee_selection_proof from https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/validator.md#aggregation-selection.
-func syncCommSelectionProof(t *testing.T, eth2Cl eth2wrap.Client, secret tblsv2.PrivateKey, slot eth2p0.Slot, subcommIdx eth2p0.CommitteeIndex) eth2p0.BLSSignature {
-	t.Helper()
+	// Query by this node's public share.
+	nodePubShare := pubShares[shareIdx]
+	pubShare, err := core.PubKeyFromBytes(nodePubShare[:])
+	require.NoError(t, err)
 
-	epoch, err := eth2util.EpochFromSlot(context.Background(), eth2Cl, slot)
+	groupKey, err = vapi.GroupPubKey(pubShare)
 	require.NoError(t, err)
+	require.Equal(t, pubkey, groupKey)
 
-	data := altair.SyncAggregatorSelectionData{
-		Slot:              slot,
-		SubcommitteeIndex: uint64(subcommIdx),
+	// The group key verifies the final aggregated signature.
+	msg := []byte("message")
+	partials := make(map[int]tblsv2.Signature)
+	for idx, share := range shares {
+		partials[idx], err = tblsv2.Sign(share, msg)
+		require.NoError(t, err)
 	}
-
-	sigRoot, err := data.HashTreeRoot()
+	sig, err := tblsv2.ThresholdAggregate(partials)
 	require.NoError(t, err)
+	require.NoError(t, tblsv2.Verify(groupKey, msg, sig))
 
-	return sign(t, eth2Cl, secret, signing.DomainSyncCommitteeSelectionProof, epoch, sigRoot)
+	// Unknown public key.
+	_, err = vapi.GroupPubKey(testutil.RandomCorePubKey(t))
+	require.ErrorContains(t, err, "unknown public key")
 }
 
-// signContributionAndProof signs the provided altair.SignedContributionAndProof and returns the signature.
-// Refer get_contribution_and_proof_signature from https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/validator.md#broadcast-sync-committee-contribution
-func signContributionAndProof(t *testing.T, eth2Cl eth2wrap.Client, secret tblsv2.PrivateKey, contrib *altair.ContributionAndProof) eth2p0.BLSSignature {
-	t.Helper()
+func TestComponent_AttestationDataConsistency(t *testing.T) {
+	ctx := context.Background()
 
-	epoch, err := eth2util.EpochFromSlot(context.Background(), eth2Cl, contrib.Contribution.Slot)
-	require.NoError(t, err)
+	inconsistent := func(field string) float64 {
+		registry, err := promauto.NewRegistry(nil)
+		require.NoError(t, err)
 
-	sigRoot, err := contrib.HashTreeRoot()
-	require.NoError(t, err)
+		families, err := registry.Gather()
+		require.NoError(t, err)
 
-	return sign(t, eth2Cl, secret, signing.DomainContributionAndProof, epoch, sigRoot)
-}
+		for _, family := range families {
+			if family.GetName() != "core_validatorapi_attestation_data_inconsistent_total" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				if metric.GetLabel()[0].GetValue() == field {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
 
-func signBeaconSelection(t *testing.T, eth2Cl eth2wrap.Client, secret tblsv2.PrivateKey, slot eth2p0.Slot) eth2p0.BLSSignature {
-	t.Helper()
+		return 0
+	}
 
-	epoch, err := eth2util.EpochFromSlot(context.Background(), eth2Cl, slot)
-	require.NoError(t, err)
+	// awaitAtt returns attestation data per committee, with committee 2 disagreeing on the target.
+	awaitAtt := func(_ context.Context, slot, commIdx int64) (*eth2p0.AttestationData, error) {
+		data := &eth2p0.AttestationData{
+			Slot:            eth2p0.Slot(slot),
+			Index:           eth2p0.CommitteeIndex(commIdx),
+			BeaconBlockRoot: eth2p0.Root{1},
+			Source:          &eth2p0.Checkpoint{Epoch: 1, Root: eth2p0.Root{2}},
+			Target:          &eth2p0.Checkpoint{Epoch: 2, Root: eth2p0.Root{3}},
+		}
+		if commIdx == 2 {
+			data.Target = &eth2p0.Checkpoint{Epoch: 2, Root: eth2p0.Root{4}}
+		}
 
-	dataRoot, err := eth2util.SlotHashRoot(slot)
-	require.NoError(t, err)
+		return data, nil
+	}
 
-	return sign(t, eth2Cl, secret, signing.DomainSelectionProof, epoch, dataRoot)
+	for _, reject := range []bool{false, true} {
+		t.Run(fmt.Sprintf("reject=%v", reject), func(t *testing.T) {
+			vapi, err := validatorapi.NewComponentInsecure(t, nil, 0)
+			require.NoError(t, err)
+			vapi.RegisterAwaitAttestation(awaitAtt)
+			vapi.SetRejectInconsistentAttestationData(reject)
+
+			beforeTarget := inconsistent("target")
+			beforeSource := inconsistent("source")
+
+			_, err = vapi.AttestationData(ctx, 1, 0)
+			require.NoError(t, err)
+
+			// Consistent committee in the same slot.
+			_, err = vapi.AttestationData(ctx, 1, 1)
+			require.NoError(t, err)
+			require.Equal(t, beforeTarget, inconsistent("target"))
+
+			// Conflicting committee in the same slot.
+			data, err := vapi.AttestationData(ctx, 1, 2)
+			if reject {
+				require.ErrorContains(t, err, "attestation data disagrees with other committee in same slot")
+			} else {
+				require.NoError(t, err)
+				require.EqualValues(t, 2, data.Index)
+			}
+			require.Equal(t, beforeTarget+1, inconsistent("target"))
+			require.Equal(t, beforeSource, inconsistent("source"))
+
+			// The first-seen attestation data is per slot.
+			_, err = vapi.AttestationData(ctx, 2, 2)
+			require.NoError(t, err)
+			require.Equal(t, beforeTarget+1, inconsistent("target"))
+		})
+	}
 }
 
-func sign(t *testing.T, eth2Cl eth2wrap.Client, secret tblsv2.PrivateKey, domain signing.DomainName, epoch eth2p0.Epoch, dataRoot eth2p0.Root) eth2p0.BLSSignature {
-	t.Helper()
+func TestVerifyGenesisValidatorsRoot(t *testing.T) {
 	ctx := context.Background()
+	root := testutil.RandomRoot()
 
-	signingRoot, err := signing.GetDataRoot(ctx, eth2Cl, domain, epoch, dataRoot)
+	bmock, err := beaconmock.New(beaconmock.WithGenesisValidatorsRoot(root))
 	require.NoError(t, err)
 
-	sig, err := tblsv2.Sign(secret, signingRoot[:])
-	require.NoError(t, err)
+	t.Run("matching", func(t *testing.T) {
+		require.NoError(t, validatorapi.VerifyGenesisValidatorsRoot(ctx, bmock, root))
+	})
 
-	return eth2p0.BLSSignature(sig)
+	t.Run("mismatching", func(t *testing.T) {
+		err := validatorapi.VerifyGenesisValidatorsRoot(ctx, bmock, testutil.RandomRoot())
+		require.ErrorContains(t, err, "mismatching beacon node genesis validators root")
+	})
 }
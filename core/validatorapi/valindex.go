@@ -0,0 +1,192 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"sync"
+
+	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/obolnetwork/charon/core"
+)
+
+// newValIndexCache returns a new validator index cache of the DV root public keys that determines the
+// current epoch via epochFunc and queries the validator indices via indicesFunc.
+func newValIndexCache(pubkeys []core.PubKey, epochFunc func(context.Context) (eth2p0.Epoch, error),
+	indicesFunc func(context.Context, []core.PubKey) (map[core.PubKey]eth2p0.ValidatorIndex, error),
+) *valIndexCache {
+	return &valIndexCache{
+		pubkeys:     pubkeys,
+		epochFunc:   epochFunc,
+		indicesFunc: indicesFunc,
+	}
+}
+
+// valIndexCache caches the bidirectional mapping between the validator indices and DV root public keys
+// served by this cluster. It is refreshed every epoch so that pending validators are picked up once
+// they are assigned an index.
+type valIndexCache struct {
+	pubkeys     []core.PubKey
+	epochFunc   func(context.Context) (eth2p0.Epoch, error)
+	indicesFunc func(context.Context, []core.PubKey) (map[core.PubKey]eth2p0.ValidatorIndex, error)
+	// budget bounds the memory of the cache together with other caches, it is nil if unbounded.
+	budget *cacheBudget
+
+	mu           sync.Mutex
+	entry        *cacheEntry
+	fetched      bool
+	epoch        eth2p0.Epoch
+	indices      map[core.PubKey]eth2p0.ValidatorIndex
+	pubkeysByIdx map[eth2p0.ValidatorIndex]core.PubKey
+}
+
+// IndexFromPubKey returns the validator index of the DV root public key and true or false
+// if the validator is pending (not yet assigned an index) or not served by this cluster.
+func (c *valIndexCache) IndexFromPubKey(ctx context.Context, pubkey core.PubKey) (eth2p0.ValidatorIndex, bool, error) {
+	indices, _, err := c.get(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	vIdx, ok := indices[pubkey]
+
+	return vIdx, ok, nil
+}
+
+// PubKeyFromIndex returns the DV root public key of the validator index and true or false
+// if the validator index is not served by this cluster.
+func (c *valIndexCache) PubKeyFromIndex(ctx context.Context, vIdx eth2p0.ValidatorIndex) (core.PubKey, bool, error) {
+	_, pubkeysByIdx, err := c.get(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	pubkey, ok := pubkeysByIdx[vIdx]
+
+	return pubkey, ok, nil
+}
+
+// get returns the bidirectional mapping, refreshing it if not yet fetched in the current epoch.
+func (c *valIndexCache) get(ctx context.Context) (map[core.PubKey]eth2p0.ValidatorIndex, map[eth2p0.ValidatorIndex]core.PubKey, error) {
+	c.mu.Lock()
+	entry, err := c.maybeRefresh(ctx)
+	indices, pubkeysByIdx := c.indices, c.pubkeysByIdx
+	c.mu.Unlock()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.budget.Track(entry) // Track without holding the lock, since it may evict this cache.
+
+	return indices, pubkeysByIdx, nil
+}
+
+// maybeRefresh queries the validator indices if not yet fetched in the current epoch,
+// returning the new cache entry to track if refreshed. It must be called with the mutex held.
+func (c *valIndexCache) maybeRefresh(ctx context.Context) (*cacheEntry, error) {
+	epoch, err := c.epochFunc(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.fetched && c.epoch == epoch {
+		c.budget.Touch(c.entry)
+		return nil, nil
+	}
+
+	indices, err := c.indicesFunc(ctx, c.pubkeys)
+	if err != nil {
+		return nil, err
+	}
+
+	pubkeysByIdx := make(map[eth2p0.ValidatorIndex]core.PubKey)
+	for pubkey, vIdx := range indices {
+		pubkeysByIdx[vIdx] = pubkey
+	}
+
+	c.fetched = true
+	c.epoch = epoch
+	c.indices = indices
+	c.pubkeysByIdx = pubkeysByIdx
+
+	c.budget.Remove(c.entry)
+	var entry *cacheEntry
+	entry = c.budget.Entry(cacheValIndices, int64(len(indices))*2*valIndexEntrySize, func() { c.evict(entry) })
+	c.entry = entry
+
+	return entry, nil
+}
+
+// valIndexEntrySize is the approximate size in bytes of a cached mapping between a validator index
+// and a hex encoded public key.
+const valIndexEntrySize = 8 + 2 + 2*48
+
+// evict drops the mapping evicted by the cache budget, unless it was refreshed since, so it is fetched again when next queried.
+func (c *valIndexCache) evict(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entry != entry {
+		return
+	}
+
+	c.entry = nil
+	c.fetched = false
+	c.indices = nil
+	c.pubkeysByIdx = nil
+}
+
+// newEth2ValIndexCache returns a new validator index cache of the DV root public keys served by the component
+// that queries the validator indices from the beacon node at the head state.
+func newEth2ValIndexCache(c *Component) *valIndexCache {
+	var pubkeys []core.PubKey
+	for pubkey := range c.sharesByKey {
+		pubkeys = append(pubkeys, pubkey)
+	}
+
+	epochFunc := func(ctx context.Context) (eth2p0.Epoch, error) {
+		slot, err := c.currentSlot(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		return c.epochFromSlot(ctx, slot)
+	}
+
+	indicesFunc := func(ctx context.Context, pubkeys []core.PubKey) (map[core.PubKey]eth2p0.ValidatorIndex, error) {
+		resp := make(map[core.PubKey]eth2p0.ValidatorIndex)
+		if len(pubkeys) == 0 {
+			return resp, nil
+		}
+
+		var eth2Pubkeys []eth2p0.BLSPubKey
+		for _, pubkey := range pubkeys {
+			eth2Pubkey, err := pubkey.ToETH2()
+			if err != nil {
+				return nil, err
+			}
+
+			eth2Pubkeys = append(eth2Pubkeys, eth2Pubkey)
+		}
+
+		vals, err := c.eth2Cl.ValidatorsByPubKey(ctx, "head", eth2Pubkeys)
+		if err != nil {
+			return nil, err
+		}
+
+		// Pending validators without an index are not included in the response.
+		for vIdx, val := range vals {
+			if val == nil || val.Validator == nil {
+				continue
+			}
+
+			resp[core.PubKeyFrom48Bytes(val.Validator.PublicKey)] = vIdx
+		}
+
+		return resp, nil
+	}
+
+	return newValIndexCache(pubkeys, epochFunc, indicesFunc)
+}
@@ -0,0 +1,75 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package validatorapi
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/obolnetwork/charon/eth2util/signing"
+)
+
+// VerifyClass is a class of partial signature verifications processed by a separate pool of workers,
+// see Component.SetVerificationWorkers.
+type VerifyClass string
+
+const (
+	// VerifyClassProposal is the class of latency-critical verifications of block proposal duties,
+	// i.e., randao reveals and signed (blinded) beacon blocks.
+	VerifyClassProposal VerifyClass = "proposal"
+	// VerifyClassOther is the class of verifications of all other duties, e.g. attestations.
+	VerifyClassOther VerifyClass = "other"
+)
+
+// defaultVerifyWorkers is the default number of concurrent partial signature verifications per class.
+var defaultVerifyWorkers = runtime.NumCPU()
+
+// verifyClassOf returns the verification class of signed data of the domain.
+func verifyClassOf(domain signing.DomainName) VerifyClass {
+	switch domain {
+	case signing.DomainRandao, signing.DomainBeaconProposer:
+		return VerifyClassProposal
+	default:
+		return VerifyClassOther
+	}
+}
+
+// newVerifyPool returns a pool of the number of workers of the verification class.
+func newVerifyPool(class VerifyClass, workers int) *verifyPool {
+	return &verifyPool{
+		class: class,
+		sem:   make(chan struct{}, workers),
+	}
+}
+
+// verifyPool is a semaphore bounding the number of concurrent partial signature verifications of a class,
+// since verification is CPU intensive and may query the beacon node for signing domains.
+type verifyPool struct {
+	class VerifyClass
+	sem   chan struct{}
+}
+
+// Acquire blocks until a worker is available or the context is closed and returns a function that must be called when done.
+// A nil pool doesn't bound verifications.
+func (p *verifyPool) Acquire(ctx context.Context) (func(), error) {
+	if p == nil {
+		return func() {}, nil
+	}
+
+	release := func() { <-p.sem }
+
+	select {
+	case p.sem <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	vapiVerifyQueuedTotal.WithLabelValues(string(p.class)).Inc()
+
+	select {
+	case p.sem <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
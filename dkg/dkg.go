@@ -254,7 +254,7 @@ func setupP2P(ctx context.Context, key *k1.PrivateKey, p2pConf p2p.Config, peers
 
 	for _, relay := range relays {
 		go func(relay *p2p.MutablePeer) {
-			err := p2p.NewRelayReserver(tcpNode, relay)(ctx)
+			err := p2p.NewRelayReserver(tcpNode, relay, p2p.WithReserveWhenPeersUnreachable(peerIDs))(ctx)
 			if err != nil {
 				log.Error(ctx, "Reserve relay error", err)
 			}
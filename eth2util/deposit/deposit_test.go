@@ -4,6 +4,7 @@ package deposit_test
 
 import (
 	"encoding/hex"
+	"os"
 	"testing"
 
 	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
@@ -16,6 +17,10 @@ import (
 	"github.com/obolnetwork/charon/testutil"
 )
 
+func TestMain(m *testing.M) {
+	os.Exit(testutil.MainWithGoldens(m))
+}
+
 //go:generate go test . -run=TestMarshalDepositData -update -clean
 
 func TestMarshalDepositData(t *testing.T) {
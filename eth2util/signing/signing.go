@@ -30,8 +30,43 @@ const (
 	DomainSyncCommitteeSelectionProof DomainName = "DOMAIN_SYNC_COMMITTEE_SELECTION_PROOF"
 	DomainContributionAndProof        DomainName = "DOMAIN_CONTRIBUTION_AND_PROOF"
 	DomainDeposit                     DomainName = "DOMAIN_DEPOSIT"
+	DomainBLSToExecutionChange        DomainName = "DOMAIN_BLS_TO_EXECUTION_CHANGE"
 )
 
+// forkIndependentDomains are the spec domain types of domain names whose signing domain doesn't depend on
+// the fork at an epoch, but only on the genesis fork version and, except DomainApplicationBuilder, the
+// genesis validators root. See "compute_domain" usages in the consensus and builder specs.
+var forkIndependentDomains = map[DomainName]eth2p0.DomainType{
+	DomainApplicationBuilder:   {0x00, 0x00, 0x00, 0x01},
+	DomainBLSToExecutionChange: {0x0a, 0x00, 0x00, 0x00},
+}
+
+// LocalDomain returns the signing domain of a fork-independent domain name computed locally from
+// the genesis fork version and genesis validators root and true, without querying a beacon node.
+// It returns false if the domain depends on the fork at an epoch and must be obtained via GetDomain.
+func LocalDomain(name DomainName, genesisForkVersion eth2p0.Version, genesisValidatorsRoot eth2p0.Root) (eth2p0.Domain, bool, error) {
+	domainType, ok := forkIndependentDomains[name]
+	if !ok {
+		return eth2p0.Domain{}, false, nil
+	}
+
+	forkData := &eth2p0.ForkData{CurrentVersion: genesisForkVersion}
+	if name != DomainApplicationBuilder { // Builder domains always use the zero genesis validators root.
+		forkData.GenesisValidatorsRoot = genesisValidatorsRoot
+	}
+
+	root, err := forkData.HashTreeRoot()
+	if err != nil {
+		return eth2p0.Domain{}, false, errors.Wrap(err, "hash fork data")
+	}
+
+	var domain eth2p0.Domain
+	copy(domain[:], domainType[:])
+	copy(domain[4:], root[:])
+
+	return domain, true, nil
+}
+
 // GetDomain returns the beacon domain for the provided type.
 func GetDomain(ctx context.Context, eth2Cl eth2wrap.Client, name DomainName, epoch eth2p0.Epoch) (eth2p0.Domain, error) {
 	spec, err := eth2Cl.Spec(ctx)
@@ -85,23 +120,34 @@ func VerifyAggregateAndProofSelection(ctx context.Context, eth2Cl eth2wrap.Clien
 }
 
 // Verify returns an error if the signature doesn't match the eth2 domain signed root.
-func Verify(ctx context.Context, eth2Cl eth2wrap.Client, domain DomainName, epoch eth2p0.Epoch, sigRoot eth2p0.Root,
+func Verify(ctx context.Context, eth2Cl eth2wrap.Client, domainName DomainName, epoch eth2p0.Epoch, sigRoot eth2p0.Root,
 	signature eth2p0.BLSSignature, pubkey tblsv2.PublicKey,
 ) error {
 	ctx, span := tracer.Start(ctx, "eth2util.Verify")
 	defer span.End()
 
-	sigData, err := GetDataRoot(ctx, eth2Cl, domain, epoch, sigRoot)
+	domain, err := GetDomain(ctx, eth2Cl, domainName, epoch)
 	if err != nil {
 		return err
 	}
 
+	span.AddEvent("tbls.Verify")
+
+	return VerifyWithDomain(domain, sigRoot, signature, pubkey)
+}
+
+// VerifyWithDomain returns an error if the signature doesn't match the signed root wrapped with the provided
+// precomputed domain. Unlike Verify, it doesn't query the beacon node, which supports offline verification.
+func VerifyWithDomain(domain eth2p0.Domain, sigRoot eth2p0.Root, signature eth2p0.BLSSignature, pubkey tblsv2.PublicKey) error {
 	var zeroSig eth2p0.BLSSignature
 	if signature == zeroSig {
 		return errors.New("no signature found")
 	}
 
-	span.AddEvent("tbls.Verify")
+	sigData, err := (&eth2p0.SigningData{ObjectRoot: sigRoot, Domain: domain}).HashTreeRoot()
+	if err != nil {
+		return errors.Wrap(err, "marshal signing data")
+	}
 
 	return tblsv2.Verify(pubkey, sigData[:], tblsv2.Signature(signature))
 }
@@ -73,3 +73,102 @@ func TestVerifyRegistrationReference(t *testing.T) {
 	err = signing.Verify(context.Background(), bmock, signing.DomainApplicationBuilder, 0, sigRoot, eth2p0.BLSSignature(sig), pubkey)
 	require.NoError(t, err)
 }
+
+func TestVerifyWithDomain(t *testing.T) {
+	// Test data obtained from teku, see TestVerifyRegistrationReference.
+	secretShareBytes, err := hex.DecodeString("345768c0245f1dc702df9e50e811002f61ebb2680b3d5931527ef59f96cbaf9b")
+	require.NoError(t, err)
+	secretShare, err := tblsconv2.PrivkeyFromBytes(secretShareBytes)
+	require.NoError(t, err)
+	pubkey, err := tblsv2.SecretToPublicKey(secretShare)
+	require.NoError(t, err)
+
+	sigRootBytes, err := hex.DecodeString("2c231b16a80337212ab1decde301bdb4383e74c0bf2f3439cc82542bf0f90fdd")
+	require.NoError(t, err)
+
+	sigBytes, err := hex.DecodeString("b101da0fc08addcc5d010ee569f6bbbdca049a5cb27efad231565bff2e3af504ec2bb87b11ed22843e9c1094f1dfe51a0b2a5ad1808df18530a2f59f004032dbf6281ecf0fc3df86d032da5b9d32a3d282c05923de491381f8f28c2863a00180")
+	require.NoError(t, err)
+
+	// Builder domain as returned by the beacon mock at epoch 0.
+	domainBytes, err := hex.DecodeString("00000001e4be9393b074ca1f3e4aabd585ca4bea101170ccfaf71b89ce5c5c38")
+	require.NoError(t, err)
+
+	var (
+		sigRoot eth2p0.Root
+		sig     eth2p0.BLSSignature
+		domain  eth2p0.Domain
+	)
+	copy(sigRoot[:], sigRootBytes)
+	copy(sig[:], sigBytes)
+	copy(domain[:], domainBytes)
+
+	err = signing.VerifyWithDomain(domain, sigRoot, sig, pubkey)
+	require.NoError(t, err)
+
+	domain[0] = 0xff
+	err = signing.VerifyWithDomain(domain, sigRoot, sig, pubkey)
+	require.Error(t, err)
+}
+
+func TestLocalDomain(t *testing.T) {
+	ctx := context.Background()
+
+	bmock, err := beaconmock.New()
+	require.NoError(t, err)
+
+	forks, err := bmock.ForkSchedule(ctx)
+	require.NoError(t, err)
+	genesisForkVersion := forks[0].CurrentVersion
+
+	genesis, err := bmock.Genesis(ctx)
+	require.NoError(t, err)
+
+	// Locally computed domains match the beacon node's genesis domains.
+	for name, domainType := range map[signing.DomainName]eth2p0.DomainType{
+		signing.DomainApplicationBuilder:   {0x00, 0x00, 0x00, 0x01},
+		signing.DomainBLSToExecutionChange: {0x0a, 0x00, 0x00, 0x00},
+	} {
+		expect, err := bmock.GenesisDomain(ctx, domainType)
+		require.NoError(t, err)
+
+		domain, ok, err := signing.LocalDomain(name, genesisForkVersion, genesis.GenesisValidatorsRoot)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, expect, domain, name)
+	}
+
+	// Fork-dependent domains aren't computed locally.
+	_, ok, err := signing.LocalDomain(signing.DomainExit, genesisForkVersion, genesis.GenesisValidatorsRoot)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestVerifyRegistrationLocalDomain(t *testing.T) {
+	// Test data obtained from teku, see TestVerifyRegistrationReference.
+	secretShareBytes, err := hex.DecodeString("345768c0245f1dc702df9e50e811002f61ebb2680b3d5931527ef59f96cbaf9b")
+	require.NoError(t, err)
+	secretShare, err := tblsconv2.PrivkeyFromBytes(secretShareBytes)
+	require.NoError(t, err)
+	pubkey, err := tblsv2.SecretToPublicKey(secretShare)
+	require.NoError(t, err)
+
+	sigRootBytes, err := hex.DecodeString("2c231b16a80337212ab1decde301bdb4383e74c0bf2f3439cc82542bf0f90fdd")
+	require.NoError(t, err)
+
+	sigBytes, err := hex.DecodeString("b101da0fc08addcc5d010ee569f6bbbdca049a5cb27efad231565bff2e3af504ec2bb87b11ed22843e9c1094f1dfe51a0b2a5ad1808df18530a2f59f004032dbf6281ecf0fc3df86d032da5b9d32a3d282c05923de491381f8f28c2863a00180")
+	require.NoError(t, err)
+
+	var (
+		sigRoot eth2p0.Root
+		sig     eth2p0.BLSSignature
+	)
+	copy(sigRoot[:], sigRootBytes)
+	copy(sig[:], sigBytes)
+
+	// Beacon mock genesis fork version, the genesis validators root is ignored for builder domains.
+	domain, ok, err := signing.LocalDomain(signing.DomainApplicationBuilder, eth2p0.Version{0x00, 0x00, 0x10, 0x20}, eth2p0.Root{0xff})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, signing.VerifyWithDomain(domain, sigRoot, sig, pubkey))
+}
@@ -0,0 +1,88 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/obolnetwork/charon/app/errors"
+	pbv1 "github.com/obolnetwork/charon/p2p/p2ppb/v1"
+)
+
+// authToken returns the HMAC-SHA256 token of the payload sent by the peer using the shared cluster key.
+// Binding the sender peer ID prevents other nodes from replaying the token as their own.
+func authToken(key []byte, sender peer.ID, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(sender))
+	_, _ = mac.Write(payload)
+
+	return mac.Sum(nil)
+}
+
+// wrapAuth returns the message wrapped in an envelope authenticated by the shared cluster key.
+func wrapAuth(key []byte, sender peer.ID, msg proto.Message) (*pbv1.AuthEnvelope, error) {
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal proto")
+	}
+
+	return &pbv1.AuthEnvelope{
+		Payload: payload,
+		Token:   authToken(key, sender, payload),
+	}, nil
+}
+
+// unwrapAuth returns the payload of the marshalled authenticated envelope sent by the peer.
+// It returns an error if the envelope is malformed or not authenticated by the shared cluster key.
+func unwrapAuth(key []byte, sender peer.ID, b []byte) ([]byte, error) {
+	env := new(pbv1.AuthEnvelope)
+	if err := proto.Unmarshal(b, env); err != nil {
+		return nil, errors.Wrap(err, "unmarshal auth envelope")
+	}
+
+	if !hmac.Equal(env.Token, authToken(key, sender, env.Payload)) {
+		return nil, errors.New("invalid auth token")
+	}
+
+	return env.Payload, nil
+}
+
+// WithHandlerAuthToken returns an option for RegisterHandler that only handles requests authenticated
+// by the shared cluster key, e.g. as sent by peers configured via Sender.SetAuthToken. Unauthenticated requests
+// are rejected before being dispatched to the handler. This adds defense-in-depth to peer ID allowlists.
+func WithHandlerAuthToken(key []byte) func(*registerHandlerOpts) {
+	return func(opts *registerHandlerOpts) {
+		opts.authKey = key
+	}
+}
+
+// WithSendReceiveAuthToken returns an option for SendReceive that authenticates the request by the shared cluster key,
+// required by handlers registered with WithHandlerAuthToken.
+func WithSendReceiveAuthToken(key []byte) func(*sendRecvOpts) {
+	return func(opts *sendRecvOpts) {
+		opts.authKey = key
+	}
+}
+
+// SetAuthToken configures the sender to authenticate requests of the protocols by the shared cluster key,
+// required by handlers registered with WithHandlerAuthToken.
+func (s *Sender) SetAuthToken(key []byte, pids ...protocol.ID) {
+	for _, pid := range pids {
+		s.authKeys.Store(pid, key)
+	}
+}
+
+// authKey returns the shared cluster key of the protocol if configured via SetAuthToken.
+func (s *Sender) authKey(pid protocol.ID) ([]byte, bool) {
+	val, ok := s.authKeys.Load(pid)
+	if !ok {
+		return nil, false
+	}
+
+	return val.([]byte), true
+}
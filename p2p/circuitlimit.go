@@ -0,0 +1,91 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	circuit "github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/client"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/z"
+)
+
+// RelayCircuitDataLimit is the maximum number of bytes relayed per direction of a relay circuit connection
+// by charon relays, after which the relay resets the connection.
+const RelayCircuitDataLimit = 32 << 20 // 32MB
+
+// circuitBudgets tracks the bytes sent over the relay circuit connections of all hosts, since all messages
+// sent over a circuit connection, requests and responses of all protocols, share its data limit.
+var circuitBudgets = newCircuitBudget()
+
+// newCircuitBudget returns a new relay circuit data budget.
+func newCircuitBudget() *circuitBudget {
+	return &circuitBudget{
+		sent: make(map[network.Conn]int64),
+	}
+}
+
+// circuitBudget tracks the number of bytes sent over relay circuit connections against their circuit data limit,
+// failing fast with a clear error when a message would exceed the remaining budget, instead of the relay
+// resetting the connection mid-transfer. Note the budget is approximate since it excludes length prefixes,
+// stream multiplexing and encryption overhead as well as libp2p's own protocols like identify and ping.
+type circuitBudget struct {
+	mu   sync.Mutex
+	sent map[network.Conn]int64 // Bytes sent by connection.
+}
+
+// Reserve reserves size bytes from the remaining data budget of the connection if it is a data limited relay circuit.
+// It returns an error without reserving if the size exceeds the remaining budget.
+func (b *circuitBudget) Reserve(tcpNode host.Host, conn network.Conn, size int) error {
+	limit, ok := circuitDataLimit(conn)
+	if !ok {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.prune(tcpNode)
+
+	remaining := limit - b.sent[conn]
+	if int64(size) > remaining {
+		return errors.New("message exceeds remaining relay circuit data limit",
+			z.Int("bytes", size),
+			z.I64("remaining", remaining),
+			z.I64("limit", limit),
+			z.Str("peer", PeerName(conn.RemotePeer())),
+		)
+	}
+
+	b.sent[conn] += int64(size)
+
+	return nil
+}
+
+// prune removes the budgets of closed connections of the host, it must be called with the lock held.
+func (b *circuitBudget) prune(tcpNode host.Host) {
+	open := make(map[network.Conn]bool)
+	for _, conn := range tcpNode.Network().Conns() {
+		open[conn] = true
+	}
+
+	for conn := range b.sent {
+		if conn.LocalPeer() == tcpNode.ID() && !open[conn] {
+			delete(b.sent, conn)
+		}
+	}
+}
+
+// circuitDataLimit returns the data limit per direction of the relay circuit connection as communicated by the relay
+// when establishing the circuit, or false if the connection isn't a relay circuit or not data limited.
+func circuitDataLimit(conn network.Conn) (int64, bool) {
+	limit, ok := conn.Stat().Extra[circuit.StatLimitData].(uint64)
+	if !ok || limit == 0 {
+		return 0, false
+	}
+
+	return int64(limit), true
+}
@@ -0,0 +1,142 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/client"
+	"github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/relay"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	pbv1 "github.com/obolnetwork/charon/core/corepb/v1"
+	"github.com/obolnetwork/charon/p2p"
+	"github.com/obolnetwork/charon/testutil"
+)
+
+func TestCircuitDataLimit(t *testing.T) {
+	const (
+		limit = 1 << 12 // 4KB
+		pID   = "/charon/test/circuit/1.0.0"
+	)
+
+	ctx := context.Background()
+
+	relayHost := testutil.CreateHost(t, testutil.AvailableAddr(t))
+	resources := relay.DefaultResources()
+	resources.Limit.Data = limit
+	relaySvc, err := relay.New(relayHost, relay.WithResources(resources))
+	require.NoError(t, err)
+	defer relaySvc.Close()
+
+	server := testutil.CreateHost(t, testutil.AvailableAddr(t))
+	relayInfo := peer.AddrInfo{ID: relayHost.ID(), Addrs: relayHost.Addrs()}
+	require.NoError(t, server.Connect(ctx, relayInfo))
+	_, err = client.Reserve(ctx, server, relayInfo)
+	require.NoError(t, err)
+
+	var handled atomic.Int32
+	p2p.RegisterHandler("test", server, pID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(ctx context.Context, peerID peer.ID, req proto.Message) (proto.Message, bool, error) {
+			handled.Add(1)
+			if req.(*pbv1.Duty).Type == largeResp {
+				return padded(limit), true, nil
+			}
+
+			return req, true, nil
+		},
+	)
+
+	// Only route to the server via the relay circuit.
+	clientHost := testutil.CreateHost(t, testutil.AvailableAddr(t))
+	circuitAddr, err := ma.NewMultiaddr(relayHost.Addrs()[0].String() + "/p2p/" + relayHost.ID().String() + "/p2p-circuit")
+	require.NoError(t, err)
+	clientHost.Peerstore().AddAddr(server.ID(), circuitAddr, peerstore.PermanentAddrTTL)
+
+	// The circuit data limit is communicated by the relay.
+	sender := new(p2p.Sender)
+
+	small := &pbv1.Duty{Type: 1}
+	err = sender.SendReceive(ctx, clientHost, server.ID(), small, new(pbv1.Duty), pID)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, handled.Load())
+
+	large := padded(limit)
+	err = sender.SendReceive(ctx, clientHost, server.ID(), large, new(pbv1.Duty), pID)
+	require.ErrorContains(t, err, "message exceeds remaining relay circuit data limit")
+	require.EqualValues(t, 1, handled.Load(), "request not sent")
+
+	// Sending without a response is also limited.
+	err = p2p.Send(ctx, clientHost, pID, server.ID(), large)
+	require.ErrorContains(t, err, "message exceeds remaining relay circuit data limit")
+	require.EqualValues(t, 1, handled.Load(), "message not sent")
+
+	// Responses are limited by the responding peer.
+	err = sender.SendReceive(ctx, clientHost, server.ID(), &pbv1.Duty{Type: largeResp}, new(pbv1.Duty), pID)
+	require.Error(t, err)
+	require.Greater(t, handled.Load(), int32(1))
+	handledBefore := handled.Load()
+
+	// The circuit connection wasn't reset and remains usable.
+	err = sender.SendReceive(ctx, clientHost, server.ID(), small, new(pbv1.Duty), pID)
+	require.NoError(t, err)
+	require.Equal(t, handledBefore+1, handled.Load())
+}
+
+func TestCircuitNoDataLimit(t *testing.T) {
+	const pID = "/charon/test/circuit/1.0.0"
+
+	ctx := context.Background()
+
+	relayHost := testutil.CreateHost(t, testutil.AvailableAddr(t))
+	resources := relay.DefaultResources()
+	resources.Limit = nil // Relay circuits without limits.
+	relaySvc, err := relay.New(relayHost, relay.WithResources(resources))
+	require.NoError(t, err)
+	defer relaySvc.Close()
+
+	server := testutil.CreateHost(t, testutil.AvailableAddr(t))
+	relayInfo := peer.AddrInfo{ID: relayHost.ID(), Addrs: relayHost.Addrs()}
+	require.NoError(t, server.Connect(ctx, relayInfo))
+	_, err = client.Reserve(ctx, server, relayInfo)
+	require.NoError(t, err)
+
+	p2p.RegisterHandler("test", server, pID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(ctx context.Context, peerID peer.ID, req proto.Message) (proto.Message, bool, error) {
+			return req, true, nil
+		},
+	)
+
+	clientHost := testutil.CreateHost(t, testutil.AvailableAddr(t))
+	circuitAddr, err := ma.NewMultiaddr(relayHost.Addrs()[0].String() + "/p2p/" + relayHost.ID().String() + "/p2p-circuit")
+	require.NoError(t, err)
+	clientHost.Peerstore().AddAddr(server.ID(), circuitAddr, peerstore.PermanentAddrTTL)
+
+	// Messages larger than charon relays' circuit data limit are sent.
+	large := padded(p2p.RelayCircuitDataLimit)
+	resp := new(pbv1.Duty)
+	err = new(p2p.Sender).SendReceive(ctx, clientHost, server.ID(), large, resp, pID)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(large, resp))
+}
+
+// largeResp is the duty type of requests the test handler responds to with a message exceeding the circuit data limit.
+const largeResp = 2
+
+// padded returns a message padded beyond the size with an unknown field.
+func padded(size int) *pbv1.Duty {
+	msg := &pbv1.Duty{Type: 1, Slot: 1}
+	unknown := protowire.AppendTag(nil, 100, protowire.BytesType)
+	msg.ProtoReflect().SetUnknown(protowire.AppendBytes(unknown, make([]byte, size)))
+
+	return msg
+}
@@ -0,0 +1,40 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"encoding/hex"
+
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// ForkDigest identifies the network fork of a node. Peers on different forks may run
+// incompatible versions of the same protocol.
+type ForkDigest [4]byte
+
+// String returns the fork digest as hex.
+func (d ForkDigest) String() string {
+	return hex.EncodeToString(d[:])
+}
+
+// ForkProtocolID returns the protocol ID suffixed with the fork digest, e.g. "/charon/parsigex/1.0.0/01020304",
+// so that only peers on the same fork negotiate the protocol.
+func ForkProtocolID(pID protocol.ID, digest ForkDigest) protocol.ID {
+	return protocol.ID(string(pID) + "/" + digest.String())
+}
+
+// WithForkDigest returns an option for RegisterHandler that only serves the fork digest suffixed protocol ID,
+// see ForkProtocolID. Streams of peers on other forks are rejected during protocol negotiation.
+func WithForkDigest(digest ForkDigest) func(*registerHandlerOpts) {
+	return func(opts *registerHandlerOpts) {
+		opts.forkDigest = &digest
+	}
+}
+
+// WithSendReceiveForkDigest returns an option for SendReceive that suffixes all protocol IDs with the fork digest,
+// see ForkProtocolID. Sending to peers on other forks fails during protocol negotiation.
+func WithSendReceiveForkDigest(digest ForkDigest) func(*sendRecvOpts) {
+	return func(opts *sendRecvOpts) {
+		opts.forkDigest = &digest
+	}
+}
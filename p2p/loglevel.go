@@ -0,0 +1,102 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/log"
+	"github.com/obolnetwork/charon/app/z"
+)
+
+// LogSubsystem identifies a p2p subsystem with an individually configurable log level.
+type LogSubsystem string
+
+const (
+	LogSubsystemRelay   LogSubsystem = "relay"
+	LogSubsystemRouter  LogSubsystem = "router"
+	LogSubsystemReceive LogSubsystem = "receive"
+	LogSubsystemSender  LogSubsystem = "sender"
+)
+
+var (
+	logLevelsMu sync.RWMutex
+	logLevels   = make(map[LogSubsystem]zapcore.Level)
+)
+
+// SetLogLevel sets the minimum log level of the p2p subsystem, e.g. "debug", "info", "warn" or "error".
+// It can be called at runtime. Note that the global log level still applies, so a subsystem level
+// can only further suppress logs, not enable logs suppressed by the global level.
+func SetLogLevel(sub LogSubsystem, level string) error {
+	switch sub {
+	case LogSubsystemRelay, LogSubsystemRouter, LogSubsystemReceive, LogSubsystemSender:
+	default:
+		return errors.New("unknown p2p log subsystem", z.Str("subsystem", string(sub)))
+	}
+
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return errors.Wrap(err, "parse level")
+	}
+
+	logLevelsMu.Lock()
+	defer logLevelsMu.Unlock()
+
+	logLevels[sub] = lvl
+
+	return nil
+}
+
+// logEnabled returns true if the subsystem is configured to log at the provided level.
+// Subsystems without a configured level log at all levels.
+func logEnabled(sub LogSubsystem, level zapcore.Level) bool {
+	logLevelsMu.RLock()
+	defer logLevelsMu.RUnlock()
+
+	minLevel, ok := logLevels[sub]
+	if !ok {
+		return true
+	}
+
+	return level >= minLevel
+}
+
+// logDebug calls log.Debug if enabled for the subsystem.
+func logDebug(ctx context.Context, sub LogSubsystem, msg string, fields ...z.Field) {
+	if !logEnabled(sub, zapcore.DebugLevel) {
+		return
+	}
+
+	log.Debug(ctx, msg, fields...)
+}
+
+// logInfo calls log.Info if enabled for the subsystem.
+func logInfo(ctx context.Context, sub LogSubsystem, msg string, fields ...z.Field) {
+	if !logEnabled(sub, zapcore.InfoLevel) {
+		return
+	}
+
+	log.Info(ctx, msg, fields...)
+}
+
+// logWarn calls log.Warn if enabled for the subsystem.
+func logWarn(ctx context.Context, sub LogSubsystem, msg string, err error, fields ...z.Field) {
+	if !logEnabled(sub, zapcore.WarnLevel) {
+		return
+	}
+
+	log.Warn(ctx, msg, err, fields...)
+}
+
+// logError calls log.Error if enabled for the subsystem.
+func logError(ctx context.Context, sub LogSubsystem, msg string, err error, fields ...z.Field) {
+	if !logEnabled(sub, zapcore.ErrorLevel) {
+		return
+	}
+
+	log.Error(ctx, msg, err, fields...)
+}
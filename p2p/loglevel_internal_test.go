@@ -0,0 +1,44 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/log"
+)
+
+func TestSubsystemLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log.InitConsoleForT(t, zapcore.AddSync(&buf))
+
+	ctx := context.Background()
+
+	require.NoError(t, SetLogLevel(LogSubsystemRelay, "error"))
+	t.Cleanup(func() {
+		logLevelsMu.Lock()
+		defer logLevelsMu.Unlock()
+		delete(logLevels, LogSubsystemRelay)
+	})
+
+	logDebug(ctx, LogSubsystemRelay, "relay debug")
+	logWarn(ctx, LogSubsystemRelay, "relay warn", errors.New("boom"))
+	require.Empty(t, buf.String())
+
+	logError(ctx, LogSubsystemRelay, "relay error", nil)
+	require.Contains(t, buf.String(), "relay error")
+
+	// Unconfigured subsystems log at all levels.
+	buf.Reset()
+	logDebug(ctx, LogSubsystemSender, "sender debug")
+	require.Contains(t, buf.String(), "sender debug")
+
+	require.Error(t, SetLogLevel(LogSubsystemReceive, "invalid"))
+	require.Error(t, SetLogLevel("unknown", "info"))
+}
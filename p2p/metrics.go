@@ -47,6 +47,36 @@ var (
 		Help:      "Connected relays by name",
 	}, []string{"peer"})
 
+	relayReservationAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "p2p",
+		Name:      "relay_reservation_attempts_total",
+		Help:      "Total number of relay circuit reservation attempts by relay",
+	}, []string{"peer"})
+
+	relayReservationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "p2p",
+		Name:      "relay_reservation_failures_total",
+		Help:      "Total number of failed relay circuit reservation attempts by relay",
+	}, []string{"peer"})
+
+	relayReservationRefreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "p2p",
+		Name:      "relay_reservation_refresh_total",
+		Help:      "Total number of periodic relay circuit reservation refreshes by relay",
+	}, []string{"peer"})
+
+	relayReservationReleases = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "p2p",
+		Name:      "relay_reservation_releases_total",
+		Help:      "Total number of relay circuit reservations released since all peers are directly connected by relay",
+	}, []string{"peer"})
+
+	relayAddrRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "p2p",
+		Name:      "relay_addr_rejected_total",
+		Help:      "Total number of rejected malformed or mismatching relay addresses by relay",
+	}, []string{"peer"})
+
 	peerConnGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "p2p",
 		Name:      "peer_connection_types",
@@ -70,6 +100,32 @@ var (
 		Name:      "peer_network_sent_bytes_total",
 		Help:      "Total number of network bytes sent to the peer by protocol.",
 	}, []string{"peer", "protocol"})
+
+	handlerStreamsShed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "p2p",
+		Name:      "handler_streams_shed_total",
+		Help:      "Total number of received streams reset since the worker pool queue of the protocol was full.",
+	}, []string{"protocol"})
+
+	handlerUnauthenticated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "p2p",
+		Name:      "handler_unauthenticated_requests_total",
+		Help:      "Total number of received requests rejected since not authenticated by the cluster auth token.",
+	}, []string{"protocol"})
+
+	networkRXSizeBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "p2p",
+		Name:      "network_receive_message_size_bytes",
+		Help:      "Size distribution of received network messages in bytes by protocol.",
+		Buckets:   prometheus.ExponentialBuckets(64, 4, 8),
+	}, []string{"protocol"})
+
+	networkTXSizeBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "p2p",
+		Name:      "network_sent_message_size_bytes",
+		Help:      "Size distribution of sent network messages in bytes by protocol.",
+		Buckets:   prometheus.ExponentialBuckets(64, 4, 8),
+	}, []string{"protocol"})
 )
 
 func observePing(p peer.ID, d time.Duration) {
@@ -81,3 +137,28 @@ func incPingError(p peer.ID) {
 	pingErrors.WithLabelValues(PeerName(p)).Inc()
 	pingSuccess.WithLabelValues(PeerName(p)).Set(0)
 }
+
+// Collectors returns the Prometheus collectors of the package, including the relay state collectors,
+// for registering with a custom registry instead of the global one, e.g. when embedding charon in other binaries.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		pingLatencies,
+		pingErrors,
+		pingSuccess,
+		reachableGauge,
+		relayConnGauge,
+		relayReservationAttempts,
+		relayReservationFailures,
+		relayReservationRefreshes,
+		relayReservationReleases,
+		relayAddrRejected,
+		peerConnGauge,
+		peerConnCounter,
+		networkRXCounter,
+		networkTXCounter,
+		handlerStreamsShed,
+		handlerUnauthenticated,
+		networkRXSizeBytes,
+		networkTXSizeBytes,
+	}
+}
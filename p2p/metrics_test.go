@@ -0,0 +1,47 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obolnetwork/charon/p2p"
+)
+
+func TestCollectors(t *testing.T) {
+	// Collectors register cleanly into multiple fresh registries.
+	for i := 0; i < 2; i++ {
+		registry := prometheus.NewRegistry()
+		for _, collector := range p2p.Collectors() {
+			require.NoError(t, registry.Register(collector))
+		}
+	}
+
+	descs := make(chan *prometheus.Desc, 100)
+	for _, collector := range p2p.Collectors() {
+		collector.Describe(descs)
+	}
+	close(descs)
+
+	var names []string
+	for desc := range descs {
+		names = append(names, desc.String())
+	}
+
+	joined := strings.Join(names, "\n")
+	for _, name := range []string{
+		"p2p_relay_connections",
+		"p2p_relay_reservation_attempts_total",
+		"p2p_relay_reservation_failures_total",
+		"p2p_relay_reservation_refresh_total",
+		"p2p_relay_reservation_releases_total",
+		"p2p_relay_addr_rejected_total",
+		"p2p_handler_unauthenticated_requests_total",
+	} {
+		require.Contains(t, joined, `"`+name+`"`)
+	}
+}
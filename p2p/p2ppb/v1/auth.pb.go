@@ -0,0 +1,155 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.29.0
+// 	protoc        (unknown)
+// source: p2p/p2ppb/v1/auth.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AuthEnvelope wraps a request of a protocol requiring authentication with a shared cluster token.
+type AuthEnvelope struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Payload []byte `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"` // Marshalled request
+	Token   []byte `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`     // HMAC-SHA256 of sender peer ID and payload
+}
+
+func (x *AuthEnvelope) Reset() {
+	*x = AuthEnvelope{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_p2p_p2ppb_v1_auth_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthEnvelope) ProtoMessage() {}
+
+func (x *AuthEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_p2p_p2ppb_v1_auth_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthEnvelope.ProtoReflect.Descriptor instead.
+func (*AuthEnvelope) Descriptor() ([]byte, []int) {
+	return file_p2p_p2ppb_v1_auth_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuthEnvelope) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *AuthEnvelope) GetToken() []byte {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+var File_p2p_p2ppb_v1_auth_proto protoreflect.FileDescriptor
+
+var file_p2p_p2ppb_v1_auth_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x70, 0x32, 0x70, 0x2f, 0x70, 0x32, 0x70, 0x70, 0x62, 0x2f, 0x76, 0x31, 0x2f, 0x61,
+	0x75, 0x74, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x70, 0x32, 0x70, 0x2e, 0x70,
+	0x32, 0x70, 0x70, 0x62, 0x2e, 0x76, 0x31, 0x22, 0x3e, 0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x45,
+	0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x62, 0x6f, 0x6c, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x2f, 0x63, 0x68, 0x61, 0x72, 0x6f, 0x6e, 0x2f, 0x70, 0x32, 0x70, 0x2f, 0x70, 0x32, 0x70,
+	0x70, 0x62, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_p2p_p2ppb_v1_auth_proto_rawDescOnce sync.Once
+	file_p2p_p2ppb_v1_auth_proto_rawDescData = file_p2p_p2ppb_v1_auth_proto_rawDesc
+)
+
+func file_p2p_p2ppb_v1_auth_proto_rawDescGZIP() []byte {
+	file_p2p_p2ppb_v1_auth_proto_rawDescOnce.Do(func() {
+		file_p2p_p2ppb_v1_auth_proto_rawDescData = protoimpl.X.CompressGZIP(file_p2p_p2ppb_v1_auth_proto_rawDescData)
+	})
+	return file_p2p_p2ppb_v1_auth_proto_rawDescData
+}
+
+var file_p2p_p2ppb_v1_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_p2p_p2ppb_v1_auth_proto_goTypes = []interface{}{
+	(*AuthEnvelope)(nil), // 0: p2p.p2ppb.v1.AuthEnvelope
+}
+var file_p2p_p2ppb_v1_auth_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_p2p_p2ppb_v1_auth_proto_init() }
+func file_p2p_p2ppb_v1_auth_proto_init() {
+	if File_p2p_p2ppb_v1_auth_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_p2p_p2ppb_v1_auth_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthEnvelope); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_p2p_p2ppb_v1_auth_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_p2p_p2ppb_v1_auth_proto_goTypes,
+		DependencyIndexes: file_p2p_p2ppb_v1_auth_proto_depIdxs,
+		MessageInfos:      file_p2p_p2ppb_v1_auth_proto_msgTypes,
+	}.Build()
+	File_p2p_p2ppb_v1_auth_proto = out.File
+	file_p2p_p2ppb_v1_auth_proto_rawDesc = nil
+	file_p2p_p2ppb_v1_auth_proto_goTypes = nil
+	file_p2p_p2ppb_v1_auth_proto_depIdxs = nil
+}
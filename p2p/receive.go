@@ -6,6 +6,7 @@ import (
 	"context"
 	"io"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/host"
@@ -19,6 +20,39 @@ import (
 	"github.com/obolnetwork/charon/app/z"
 )
 
+// defaultUnmarshalLogPrefix is the default number of bytes of a malformed request logged as hex.
+const defaultUnmarshalLogPrefix = 16
+
+var (
+	unmarshalLogPrefixMu sync.RWMutex
+	unmarshalLogPrefix   = defaultUnmarshalLogPrefix
+)
+
+// SetUnmarshalLogPrefix sets the maximum number of bytes of a malformed request payload
+// logged as hex when unmarshalling fails, zero disables logging the payload.
+func SetUnmarshalLogPrefix(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	unmarshalLogPrefixMu.Lock()
+	defer unmarshalLogPrefixMu.Unlock()
+
+	unmarshalLogPrefix = n
+}
+
+// payloadPrefix returns the bounded prefix of the payload to log.
+func payloadPrefix(b []byte) []byte {
+	unmarshalLogPrefixMu.RLock()
+	defer unmarshalLogPrefixMu.RUnlock()
+
+	if len(b) > unmarshalLogPrefix {
+		return b[:unmarshalLogPrefix]
+	}
+
+	return b
+}
+
 // HandlerFunc abstracts the handler logic that processes a p2p received proto message
 // and returns a response or false or an error.
 type HandlerFunc func(ctx context.Context, peerID peer.ID, req proto.Message) (proto.Message, bool, error)
@@ -26,18 +60,124 @@ type HandlerFunc func(ctx context.Context, peerID peer.ID, req proto.Message) (p
 // RegisterHandlerFunc abstracts a function that registers a libp2p stream handler
 // that reads a single protobuf request and returns an optional response.
 type RegisterHandlerFunc func(logTopic string, tcpNode host.Host, protocol protocol.ID,
-	zeroReq func() proto.Message, handlerFunc HandlerFunc,
+	zeroReq func() proto.Message, handlerFunc HandlerFunc, opts ...func(*registerHandlerOpts),
 )
 
+type registerHandlerOpts struct {
+	recorder   *Recorder
+	forkDigest *ForkDigest
+	poolCtx    context.Context
+	workers    int
+	queueSize  int
+	delimited  bool
+	authKey    []byte
+}
+
+// WithHandlerRecorder returns an option for RegisterHandler that records the raw request
+// and response bytes of each handled stream, see Replay.
+func WithHandlerRecorder(recorder *Recorder) func(*registerHandlerOpts) {
+	return func(opts *registerHandlerOpts) {
+		opts.recorder = recorder
+	}
+}
+
+// WithWorkerPool returns an option for RegisterHandler that processes the protocol's streams by a fixed pool of workers
+// instead of a goroutine per stream, queueing up to queueSize incoming streams. Streams received while the queue is full
+// are reset (shed). This bounds concurrency and tail latency of high-QPS protocols.
+// The workers are stopped when the context is closed, after which queued and incoming streams are reset.
+func WithWorkerPool(ctx context.Context, workers, queueSize int) func(*registerHandlerOpts) {
+	return func(opts *registerHandlerOpts) {
+		opts.poolCtx = ctx
+		opts.workers = workers
+		opts.queueSize = queueSize
+	}
+}
+
+// WithDelimitedMessages returns an option for RegisterHandler that handles multiple length-delimited requests
+// per stream, responding to each with a length-delimited response, empty if there is no response.
+// This is required by protocols that are pooled by senders, see Sender.SetStreamPool. Streams are closed
+// after being idle for delimitedIdleTimeout, which Sender.SetStreamPool requires pool idle timeouts to be shorter than.
+// If a worker pool is configured, each request rather than each stream is processed by a worker,
+// so idle pooled streams don't occupy workers.
+func WithDelimitedMessages() func(*registerHandlerOpts) {
+	return func(opts *registerHandlerOpts) {
+		opts.delimited = true
+	}
+}
+
 // RegisterHandler registers a canonical proto request and response handler for the provided protocol.
 // - The zeroReq function returns a zero request to unmarshal.
 // - The handlerFunc is called with the unmarshalled request and returns either a response or false or an error.
 // - The marshalled response is sent back if present.
 // - The stream is always closed before returning.
+// - Multiple length-delimited requests are handled per stream if configured.
+// - Unauthenticated requests are rejected if an auth token is configured.
+// - The request and response bytes are recorded if a recorder is configured.
+// - The streams are processed by a worker pool if configured.
 func RegisterHandler(logTopic string, tcpNode host.Host, protocol protocol.ID,
-	zeroReq func() proto.Message, handlerFunc HandlerFunc,
+	zeroReq func() proto.Message, handlerFunc HandlerFunc, opts ...func(*registerHandlerOpts),
 ) {
-	tcpNode.SetStreamHandler(protocol, func(s network.Stream) {
+	var o registerHandlerOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.forkDigest != nil {
+		protocol = ForkProtocolID(protocol, *o.forkDigest)
+	}
+
+	// process unmarshals and handles the request, returning the marshalled response if any.
+	process := func(ctx context.Context, s network.Stream, t0 time.Time, b []byte) ([]byte, bool) {
+		name := PeerName(s.Conn().RemotePeer())
+
+		if o.authKey != nil {
+			payload, err := unwrapAuth(o.authKey, s.Conn().RemotePeer(), b)
+			if err != nil {
+				handlerUnauthenticated.WithLabelValues(string(protocol)).Inc()
+				logWarn(ctx, LogSubsystemReceive, "LibP2P rejecting unauthenticated request", err)
+
+				return nil, false
+			}
+			b = payload
+		}
+
+		req := zeroReq()
+		if err := proto.Unmarshal(b, req); err != nil {
+			// Log the payload prefix to help identify version mismatches or corruption.
+			logError(ctx, LogSubsystemReceive, "LibP2P unmarshal request", err,
+				z.I64("bytes", int64(len(b))),
+				z.Hex("payload_prefix", payloadPrefix(b)),
+			)
+			return nil, false
+		}
+
+		networkRXCounter.WithLabelValues(name, string(s.Protocol())).Add(float64(len(b)))
+		networkRXSizeBytes.WithLabelValues(string(s.Protocol())).Observe(float64(len(b)))
+
+		resp, ok, err := handlerFunc(ctx, s.Conn().RemotePeer(), req)
+		if err != nil {
+			logError(ctx, LogSubsystemReceive, "LibP2P handle stream error", err, z.Any("duration", time.Since(t0)))
+			return nil, false
+		}
+
+		reqBytes := b
+
+		if !ok {
+			record(ctx, LogSubsystemReceive, o.recorder, s, reqBytes, nil)
+			return nil, false
+		}
+
+		b, err = proto.Marshal(resp)
+		if err != nil {
+			logError(ctx, LogSubsystemReceive, "LibP2P marshall response", err)
+			return nil, false
+		}
+
+		record(ctx, LogSubsystemReceive, o.recorder, s, reqBytes, b)
+
+		return b, true
+	}
+
+	handle := func(s network.Stream) {
 		t0 := time.Now()
 		name := PeerName(s.Conn().RemotePeer())
 
@@ -57,7 +197,7 @@ func RegisterHandler(logTopic string, tcpNode host.Host, protocol protocol.ID,
 			return // Ignore relay errors.
 		} else if netErr := net.Error(nil); errors.As(err, &netErr) && netErr.Timeout() {
 			validPB := proto.Unmarshal(b, zeroReq()) == nil
-			log.Error(ctx, "LibP2P read timeout", err,
+			logError(ctx, LogSubsystemReceive, "LibP2P read timeout", err,
 				z.Any("duration", time.Since(t0)),
 				z.I64("bytes", int64(len(b))),
 				z.Bool("valid_proto", validPB),
@@ -65,7 +205,7 @@ func RegisterHandler(logTopic string, tcpNode host.Host, protocol protocol.ID,
 
 			return
 		} else if err != nil {
-			log.Error(ctx, "LibP2P read request", err,
+			logError(ctx, LogSubsystemReceive, "LibP2P read request", err,
 				z.Any("duration", time.Since(t0)),
 				z.I64("bytes", int64(len(b))),
 			)
@@ -73,37 +213,146 @@ func RegisterHandler(logTopic string, tcpNode host.Host, protocol protocol.ID,
 			return
 		}
 
-		req := zeroReq()
-		if err := proto.Unmarshal(b, req); err != nil {
-			log.Error(ctx, "LibP2P unmarshal request", err)
+		b, ok := process(ctx, s, t0, b)
+		if !ok {
 			return
 		}
 
-		networkRXCounter.WithLabelValues(name, string(s.Protocol())).Add(float64(len(b)))
+		if err := circuitBudgets.Reserve(tcpNode, s.Conn(), len(b)); err != nil {
+			logError(ctx, LogSubsystemReceive, "LibP2P response exceeds relay circuit data limit", err)
+			_ = s.Reset()
 
-		resp, ok, err := handlerFunc(ctx, s.Conn().RemotePeer(), req)
-		if err != nil {
-			log.Error(ctx, "LibP2P handle stream error", err, z.Any("duration", time.Since(t0)))
 			return
 		}
 
-		if !ok {
-			return
-		}
-
-		b, err = proto.Marshal(resp)
-		if err != nil {
-			log.Error(ctx, "LibP2P marshall response", err)
-			return
-		}
+		// Observe the response size before writing it, so it is observed by the time the peer received it.
+		networkTXSizeBytes.WithLabelValues(string(s.Protocol())).Observe(float64(len(b)))
 
 		if _, err := s.Write(b); IsRelayError(err) {
 			return // Ignore relay errors.
 		} else if err != nil {
-			log.Error(ctx, "LibP2P write response", err)
+			logError(ctx, LogSubsystemReceive, "LibP2P write response", err)
 			return
 		}
 
 		networkTXCounter.WithLabelValues(name, string(s.Protocol())).Add(float64(len(b)))
+	}
+
+	var pool *workerPool
+	if o.workers > 0 {
+		pool = newWorkerPool(o.poolCtx, logTopic, protocol, o.workers, o.queueSize)
+	}
+
+	if o.delimited {
+		handle = delimitedHandler(logTopic, tcpNode, protocol, pool, process)
+	} else if pool != nil {
+		handle = pool.Handler(handle)
+	}
+
+	tcpNode.SetStreamHandler(protocol, handle)
+}
+
+// newWorkerPool starts the workers of a new worker pool which are stopped when the context is closed.
+func newWorkerPool(ctx context.Context, logTopic string, protocol protocol.ID, workers, queueSize int) *workerPool {
+	p := &workerPool{
+		logTopic: logTopic,
+		protocol: protocol,
+		queue:    make(chan poolJob, queueSize),
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.stopped = true
+		close(p.queue)
+	}()
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range p.queue {
+				if ctx.Err() != nil {
+					job.abort() // Abort jobs still queued when stopped.
+					continue
+				}
+
+				job.run()
+			}
+		}()
+	}
+
+	return p
+}
+
+// poolJob is a job of a worker pool processing a stream.
+type poolJob struct {
+	run   func()
+	abort func()
+}
+
+// workerPool is a fixed pool of workers processing the queued jobs of a protocol's streams.
+type workerPool struct {
+	logTopic string
+	protocol protocol.ID
+
+	mu      sync.Mutex
+	stopped bool
+	queue   chan poolJob
+}
+
+// Queue queues the job processing the stream, it returns false if the queue is full (shedding the job)
+// or the pool is stopped. The abort function is called instead of run if the pool is stopped after queueing.
+func (p *workerPool) Queue(s network.Stream, run, abort func()) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		return false
+	}
+
+	select {
+	case p.queue <- poolJob{run: run, abort: abort}:
+		return true
+	default:
+		handlerStreamsShed.WithLabelValues(string(p.protocol)).Inc()
+
+		ctx := log.WithTopic(context.Background(), p.logTopic)
+		logDebug(ctx, LogSubsystemReceive, "Shedding stream since worker pool queue is full",
+			z.Str("peer", PeerName(s.Conn().RemotePeer())),
+			z.Str("protocol", string(p.protocol)),
+		)
+
+		return false
+	}
+}
+
+// Handler returns a stream handler that queues the streams to be processed by the handler,
+// resetting them if shed or the pool is stopped.
+func (p *workerPool) Handler(handler network.StreamHandler) network.StreamHandler {
+	return func(s network.Stream) {
+		reset := func() { _ = s.Reset() }
+		if !p.Queue(s, func() { handler(s) }, reset) {
+			reset()
+		}
+	}
+}
+
+// record records the request and response bytes of the stream if the recorder is not nil.
+func record(ctx context.Context, sub LogSubsystem, recorder *Recorder, s network.Stream, req, resp []byte) {
+	if recorder == nil {
+		return
+	}
+
+	err := recorder.Record(Capture{
+		Time:     time.Now(),
+		Protocol: s.Protocol(),
+		Peer:     s.Conn().RemotePeer(),
+		Request:  req,
+		Response: resp,
 	})
+	if err != nil {
+		logWarn(ctx, sub, "Failed recording p2p stream", err)
+	}
 }
@@ -0,0 +1,343 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/obolnetwork/charon/app/log"
+	pbv1 "github.com/obolnetwork/charon/core/corepb/v1"
+	charontestutil "github.com/obolnetwork/charon/testutil"
+)
+
+// logWriter is a zapcore.WriteSyncer that sends each log line to a channel.
+type logWriter chan string
+
+func (w logWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func (logWriter) Sync() error {
+	return nil
+}
+
+func TestRegisterHandlerUnmarshalLog(t *testing.T) {
+	logs := make(logWriter, 100)
+	log.InitConsoleForT(t, zapcore.Lock(logs))
+
+	SetUnmarshalLogPrefix(4)
+	t.Cleanup(func() { SetUnmarshalLogPrefix(defaultUnmarshalLogPrefix) })
+
+	var (
+		protocolID = protocol.ID("test-unmarshal-log")
+		ctx        = context.Background()
+		server     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+		client     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+	)
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	RegisterHandler("server", server, protocolID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(context.Context, peer.ID, proto.Message) (proto.Message, bool, error) {
+			require.Fail(t, "malformed request not expected to be handled")
+			return nil, false, nil
+		},
+	)
+
+	s, err := client.NewStream(ctx, server.ID(), protocolID)
+	require.NoError(t, err)
+	defer s.Close()
+
+	// Length prefix exceeding payload.
+	_, err = s.Write([]byte{0x0a, 0xff, 0xff, 0xff, 0xff, 0x0f})
+	require.NoError(t, err)
+	require.NoError(t, s.CloseWrite())
+	_, _ = io.ReadAll(s)
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case line := <-logs:
+			if !strings.Contains(line, "LibP2P unmarshal request") {
+				continue
+			}
+
+			require.Contains(t, line, `"protocol": "test-unmarshal-log"`)
+			require.Contains(t, line, `"peer": "`+PeerName(client.ID())+`"`)
+			require.Contains(t, line, `"bytes": 6`)
+			require.Contains(t, line, `"payload_prefix": "0x0affffff"`)
+
+			return
+		case <-timeout:
+			require.Fail(t, "unmarshal failure not logged")
+		}
+	}
+}
+
+func TestRegisterHandlerWorkerPool(t *testing.T) {
+	const (
+		workers   = 2
+		queueSize = 2
+		streams   = 6
+	)
+
+	var (
+		protocolID = protocol.ID("test-worker-pool")
+		ctx        = context.Background()
+		server     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+		client     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+	)
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	var (
+		mu            sync.Mutex
+		active, peak  int
+		release       = make(chan struct{})
+		handlerCalled = make(chan struct{}, streams)
+	)
+	RegisterHandler("server", server, protocolID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(_ context.Context, _ peer.ID, req proto.Message) (proto.Message, bool, error) {
+			mu.Lock()
+			active++
+			if active > peak {
+				peak = active
+			}
+			mu.Unlock()
+
+			handlerCalled <- struct{}{}
+			<-release
+
+			mu.Lock()
+			active--
+			mu.Unlock()
+
+			return req, true, nil
+		},
+		WithWorkerPool(ctx, workers, queueSize),
+	)
+
+	shed := func() float64 {
+		return testutil.ToFloat64(handlerStreamsShed.WithLabelValues(string(protocolID)))
+	}
+	shedBefore := shed()
+
+	errs := make(chan error, streams)
+	for i := 0; i < streams; i++ {
+		go func() {
+			errs <- SendReceive(ctx, client, server.ID(), &pbv1.Duty{Slot: 1}, new(pbv1.Duty), protocolID)
+		}()
+	}
+
+	// Streams beyond the busy workers and the full queue are shed.
+	require.Eventually(t, func() bool {
+		return shed()-shedBefore == streams-workers-queueSize
+	}, 5*time.Second, time.Millisecond)
+
+	for i := 0; i < streams-workers-queueSize; i++ {
+		require.Error(t, <-errs)
+	}
+
+	// Queued streams are processed once the workers are released.
+	close(release)
+	for i := 0; i < workers+queueSize; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	require.Len(t, handlerCalled, workers+queueSize)
+	require.Equal(t, workers, peak)
+	require.EqualValues(t, streams-workers-queueSize, shed()-shedBefore)
+}
+
+func TestRegisterHandlerWorkerPoolStopped(t *testing.T) {
+	var (
+		protocolID  = protocol.ID("test-worker-pool-stopped")
+		ctx, cancel = context.WithCancel(context.Background())
+		server      = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+		client      = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+	)
+	defer cancel()
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	var (
+		handled       atomic.Int32
+		release       = make(chan struct{})
+		handlerCalled = make(chan struct{}, 1)
+	)
+	RegisterHandler("server", server, protocolID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(_ context.Context, _ peer.ID, req proto.Message) (proto.Message, bool, error) {
+			handled.Add(1)
+			handlerCalled <- struct{}{}
+			<-release
+
+			return req, true, nil
+		},
+		WithWorkerPool(ctx, 1, 1),
+	)
+
+	sendReceive := func() error {
+		return SendReceive(context.Background(), client, server.ID(), &pbv1.Duty{Slot: 1}, new(pbv1.Duty), protocolID)
+	}
+
+	// Occupy the worker and queue a stream.
+	busy := make(chan error, 1)
+	go func() { busy <- sendReceive() }()
+	<-handlerCalled
+
+	queued := make(chan error, 1)
+	go func() { queued <- sendReceive() }()
+
+	// Stopping the pool resets the queued and incoming streams.
+	cancel()
+	close(release)
+
+	require.NoError(t, <-busy)
+	require.Error(t, <-queued)
+	require.Error(t, sendReceive())
+	require.EqualValues(t, 1, handled.Load())
+}
+
+func TestRegisterHandlerAuthToken(t *testing.T) {
+	var (
+		protocolID = protocol.ID("test-auth-token")
+		ctx        = context.Background()
+		server     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+		client     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+		key        = []byte("cluster-token")
+	)
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	RegisterHandler("server", server, protocolID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(_ context.Context, _ peer.ID, req proto.Message) (proto.Message, bool, error) {
+			return req, true, nil
+		},
+		WithHandlerAuthToken(key),
+	)
+
+	rejected := func() float64 {
+		return testutil.ToFloat64(handlerUnauthenticated.WithLabelValues(string(protocolID)))
+	}
+	rejectedBefore := rejected()
+
+	// Requests authenticated by the cluster token are handled.
+	sender := new(Sender)
+	sender.SetAuthToken(key, protocolID)
+
+	resp := new(pbv1.Duty)
+	err := sender.SendReceive(ctx, client, server.ID(), &pbv1.Duty{Slot: 1}, resp, protocolID)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, resp.Slot)
+
+	// Requests with an invalid or without token are rejected.
+	err = SendReceive(ctx, client, server.ID(), &pbv1.Duty{Slot: 1}, resp, protocolID,
+		WithSendReceiveAuthToken([]byte("other-token")))
+	require.ErrorContains(t, err, "peer errored, no response")
+
+	err = SendReceive(ctx, client, server.ID(), &pbv1.Duty{Slot: 1}, resp, protocolID)
+	require.ErrorContains(t, err, "peer errored, no response")
+
+	require.EqualValues(t, 2, rejected()-rejectedBefore)
+}
+
+func TestRegisterStreamingHandlerOptions(t *testing.T) {
+	var (
+		protocolID = protocol.ID("test-streaming-options")
+		ctx        = context.Background()
+		server     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+		client     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+		key        = []byte("cluster-token")
+		forkA      = ForkDigest{1, 2, 3, 4}
+		forkB      = ForkDigest{5, 6, 7, 8}
+		sink       = new(bytes.Buffer)
+	)
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	RegisterStreamingHandler("server", server, protocolID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(_ context.Context, _ peer.ID, req proto.Message, send func(proto.Message) error) error {
+			for i := 0; i < 2; i++ {
+				if err := send(req); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		WithForkDigest(forkA),
+		WithHandlerAuthToken(key),
+		WithWorkerPool(ctx, 1, 1),
+		WithHandlerRecorder(NewRecorder(sink)),
+	)
+
+	rejected := func() float64 {
+		return testutil.ToFloat64(handlerUnauthenticated.WithLabelValues(string(ForkProtocolID(protocolID, forkA))))
+	}
+	rejectedBefore := rejected()
+
+	sendReceive := func(opts ...func(*sendRecvOpts)) (int, error) {
+		var received int
+		err := SendReceiveStreaming(ctx, client, server.ID(), &pbv1.Duty{Slot: 1},
+			func() proto.Message { return new(pbv1.Duty) },
+			func(resp proto.Message) error {
+				require.EqualValues(t, 1, resp.(*pbv1.Duty).Slot)
+				received++
+
+				return nil
+			},
+			protocolID, opts...)
+
+		return received, err
+	}
+
+	// Authenticated requests of the same fork are handled.
+	received, err := sendReceive(WithSendReceiveForkDigest(forkA), WithSendReceiveAuthToken(key))
+	require.NoError(t, err)
+	require.Equal(t, 2, received)
+
+	captures, err := ReadCaptures(sink)
+	require.NoError(t, err)
+	require.Len(t, captures, 1)
+	require.Equal(t, ForkProtocolID(protocolID, forkA), captures[0].Protocol)
+	require.NotEmpty(t, captures[0].Response)
+
+	// Requests of other forks fail during protocol negotiation.
+	_, err = sendReceive(WithSendReceiveForkDigest(forkB), WithSendReceiveAuthToken(key))
+	require.ErrorContains(t, err, "protocols not supported")
+
+	_, err = sendReceive(WithSendReceiveAuthToken(key))
+	require.ErrorContains(t, err, "protocols not supported")
+
+	// Unauthenticated requests are rejected without responses.
+	received, err = sendReceive(WithSendReceiveForkDigest(forkA))
+	require.NoError(t, err)
+	require.Zero(t, received)
+
+	received, err = sendReceive(WithSendReceiveForkDigest(forkA), WithSendReceiveAuthToken([]byte("other-token")))
+	require.NoError(t, err)
+	require.Zero(t, received)
+
+	require.EqualValues(t, 2, rejected()-rejectedBefore)
+}
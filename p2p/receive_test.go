@@ -3,7 +3,9 @@
 package p2p_test
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"testing"
 
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -13,6 +15,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/promauto"
 	pbv1 "github.com/obolnetwork/charon/core/corepb/v1"
 	"github.com/obolnetwork/charon/p2p"
 	"github.com/obolnetwork/charon/testutil"
@@ -74,3 +77,209 @@ func TestSendReceive(t *testing.T) {
 		require.ErrorContains(t, err, "no response")
 	})
 }
+
+func TestForkDigest(t *testing.T) {
+	var (
+		protocolID = protocol.ID("/charon/test-fork/1.0.0")
+		forkA      = p2p.ForkDigest{1, 2, 3, 4}
+		forkB      = p2p.ForkDigest{5, 6, 7, 8}
+		ctx        = context.Background()
+		server     = testutil.CreateHost(t, testutil.AvailableAddr(t))
+		client     = testutil.CreateHost(t, testutil.AvailableAddr(t))
+	)
+
+	require.EqualValues(t, "/charon/test-fork/1.0.0/01020304", p2p.ForkProtocolID(protocolID, forkA))
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	var handled int
+	p2p.RegisterHandler("server", server, protocolID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(_ context.Context, _ peer.ID, req proto.Message) (proto.Message, bool, error) {
+			handled++
+			return req, true, nil
+		},
+		p2p.WithForkDigest(forkA),
+	)
+
+	sendReceive := func(digest *p2p.ForkDigest) error {
+		req, resp := &pbv1.Duty{Slot: 1}, new(pbv1.Duty)
+		if digest == nil {
+			return p2p.SendReceive(ctx, client, server.ID(), req, resp, protocolID)
+		}
+
+		return p2p.SendReceive(ctx, client, server.ID(), req, resp, protocolID, p2p.WithSendReceiveForkDigest(*digest))
+	}
+
+	t.Run("same fork", func(t *testing.T) {
+		require.NoError(t, sendReceive(&forkA))
+		require.Equal(t, 1, handled)
+	})
+
+	t.Run("different fork", func(t *testing.T) {
+		err := sendReceive(&forkB)
+		require.ErrorContains(t, err, "protocols not supported")
+		require.Equal(t, 1, handled)
+	})
+
+	t.Run("no fork", func(t *testing.T) {
+		err := sendReceive(nil)
+		require.ErrorContains(t, err, "protocols not supported")
+		require.Equal(t, 1, handled)
+	})
+}
+
+func TestRegisterHandlerSizeMetrics(t *testing.T) {
+	var (
+		protocolID = protocol.ID("test-size-metrics")
+		ctx        = context.Background()
+		server     = testutil.CreateHost(t, testutil.AvailableAddr(t))
+		client     = testutil.CreateHost(t, testutil.AvailableAddr(t))
+	)
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	// Register a server handler that responds with a larger duty than the request.
+	p2p.RegisterHandler("server", server, protocolID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(context.Context, peer.ID, proto.Message) (proto.Message, bool, error) {
+			return &pbv1.Duty{Slot: 1 << 40, Type: 1}, true, nil
+		},
+	)
+
+	req := &pbv1.Duty{Slot: 1}
+	resp := new(pbv1.Duty)
+	err := p2p.SendReceive(ctx, client, server.ID(), req, resp, protocolID)
+	require.NoError(t, err)
+
+	reqBytes, err := proto.Marshal(req)
+	require.NoError(t, err)
+	respBytes, err := proto.Marshal(resp)
+	require.NoError(t, err)
+
+	registry, err := promauto.NewRegistry(nil)
+	require.NoError(t, err)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	// assertHistogram asserts that the named histogram observed a single sample of the expected size.
+	assertHistogram := func(t *testing.T, name string, size int) {
+		t.Helper()
+
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+
+			for _, metric := range family.GetMetric() {
+				if metric.GetLabel()[0].GetValue() != string(protocolID) {
+					continue
+				}
+
+				require.EqualValues(t, 1, metric.GetHistogram().GetSampleCount())
+				require.EqualValues(t, size, metric.GetHistogram().GetSampleSum())
+
+				return
+			}
+		}
+
+		require.Fail(t, "histogram not found", name)
+	}
+
+	assertHistogram(t, "p2p_network_receive_message_size_bytes", len(reqBytes))
+	assertHistogram(t, "p2p_network_sent_message_size_bytes", len(respBytes))
+}
+
+// FuzzRegisterHandler ensures that malformed requests read from peers never cause the handler to panic.
+// Run it in fuzz mode with: go test -run=^$ -fuzz=FuzzRegisterHandler -fuzztime=10s ./p2p.
+func FuzzRegisterHandler(f *testing.F) {
+	var (
+		protocolID = protocol.ID("test-fuzz")
+		ctx        = context.Background()
+		server     = testutil.CreateHost(f, testutil.AvailableAddr(f))
+		client     = testutil.CreateHost(f, testutil.AvailableAddr(f))
+	)
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	// Register a trivial echo handler.
+	p2p.RegisterHandler("server", server, protocolID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(_ context.Context, _ peer.ID, req proto.Message) (proto.Message, bool, error) {
+			return req, true, nil
+		},
+	)
+
+	valid, err := proto.Marshal(&pbv1.Duty{Slot: 1 << 40, Type: 1})
+	require.NoError(f, err)
+
+	f.Add([]byte{})
+	f.Add(valid)
+	f.Add(valid[:len(valid)-1])                                // Truncated
+	f.Add([]byte{0x0a, 0xff, 0xff, 0xff, 0xff, 0x0f})          // Length prefix exceeding payload
+	f.Add(bytes.Repeat([]byte{0xff}, 1<<20))                   // Oversized garbage
+	f.Add(append(valid, bytes.Repeat([]byte{0x00}, 1<<16)...)) // Valid with trailing zeros
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s, err := client.NewStream(ctx, server.ID(), protocolID)
+		require.NoError(t, err)
+		defer s.Close()
+
+		_, err = s.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, s.CloseWrite())
+
+		// Wait for the server to finish processing the request, any panic crashes the test.
+		_, _ = io.ReadAll(s)
+	})
+}
+
+func TestSenderProtocolPreference(t *testing.T) {
+	var (
+		protocolV1 = protocol.ID("/charon/test/1.0.0")
+		protocolV2 = protocol.ID("/charon/test/2.0.0")
+		ctx        = context.Background()
+		server     = testutil.CreateHost(t, testutil.AvailableAddr(t))
+		client     = testutil.CreateHost(t, testutil.AvailableAddr(t))
+	)
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	// Register a handler per protocol version that responds with its version as the duty type.
+	for version, pid := range []protocol.ID{protocolV1, protocolV2} {
+		version := int32(version + 1)
+		p2p.RegisterHandler("server", server, pid,
+			func() proto.Message { return new(pbv1.Duty) },
+			func(ctx context.Context, peerID peer.ID, req proto.Message) (proto.Message, bool, error) {
+				return &pbv1.Duty{Type: version}, true, nil
+			},
+		)
+	}
+
+	sender := new(p2p.Sender)
+
+	// negotiated returns the protocol version negotiated when sending with the new version first.
+	negotiated := func(t *testing.T) int32 {
+		t.Helper()
+
+		resp := new(pbv1.Duty)
+		err := sender.SendReceive(ctx, client, server.ID(), new(pbv1.Duty), resp, "",
+			p2p.WithSendReceiveProtocols(protocolV2, protocolV1))
+		require.NoError(t, err)
+
+		return resp.Type
+	}
+
+	require.EqualValues(t, 2, negotiated(t))
+
+	sender.SetProtocolPreference(protocolV1, protocolV2)
+	require.EqualValues(t, 1, negotiated(t))
+
+	sender.SetProtocolPreference(protocolV2, protocolV1)
+	require.EqualValues(t, 2, negotiated(t))
+
+	// Unconfigured protocols are tried last.
+	sender.SetProtocolPreference(protocolV1)
+	require.EqualValues(t, 1, negotiated(t))
+}
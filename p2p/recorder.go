@@ -0,0 +1,145 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/z"
+)
+
+// maxCaptureField is the maximum length of a single field of a capture frame.
+const maxCaptureField = 1 << 26 // 64MB
+
+// Capture is a recorded request and response round-trip of a p2p stream.
+type Capture struct {
+	Time     time.Time
+	Protocol protocol.ID
+	Peer     peer.ID
+	Request  []byte
+	Response []byte // Empty if no response was sent.
+}
+
+// NewRecorder returns a new recorder that writes framed captures to the sink.
+func NewRecorder(sink io.Writer) *Recorder {
+	return &Recorder{sink: sink}
+}
+
+// Recorder records the raw request and response bytes of p2p streams for debugging.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu   sync.Mutex
+	sink io.Writer
+}
+
+// Record writes the capture to the sink as a single frame. A nil recorder is a no-op.
+func (r *Recorder) Record(c Capture) error {
+	if r == nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, c.Time.UnixNano())
+	for _, field := range [][]byte{[]byte(c.Protocol), []byte(c.Peer), c.Request, c.Response} {
+		_ = binary.Write(&buf, binary.BigEndian, uint32(len(field)))
+		_, _ = buf.Write(field)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.sink.Write(buf.Bytes()); err != nil {
+		return errors.Wrap(err, "write capture")
+	}
+
+	return nil
+}
+
+// ReadCaptures returns all the framed captures read from the reader until EOF.
+func ReadCaptures(r io.Reader) ([]Capture, error) {
+	var resp []Capture
+	for {
+		var nanos int64
+		if err := binary.Read(r, binary.BigEndian, &nanos); errors.Is(err, io.EOF) {
+			return resp, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "read capture timestamp")
+		}
+
+		var fields [4][]byte
+		for i := range fields {
+			field, err := readCaptureField(r)
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = field
+		}
+
+		resp = append(resp, Capture{
+			Time:     time.Unix(0, nanos),
+			Protocol: protocol.ID(fields[0]),
+			Peer:     peer.ID(fields[1]),
+			Request:  fields[2],
+			Response: fields[3],
+		})
+	}
+}
+
+// readCaptureField returns the next length prefixed field of a capture frame.
+func readCaptureField(r io.Reader) ([]byte, error) {
+	var l uint32
+	if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+		return nil, errors.Wrap(err, "read capture field length")
+	} else if l > maxCaptureField {
+		return nil, errors.New("capture field too large", z.U64("length", uint64(l)))
+	}
+
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, errors.Wrap(err, "read capture field")
+	}
+
+	return b, nil
+}
+
+// Replay replays the captured request against the handler offline and returns an error
+// if the handler fails or if its response differs from the captured response.
+func Replay(ctx context.Context, c Capture, zeroReq func() proto.Message, handlerFunc HandlerFunc) error {
+	req := zeroReq()
+	if err := proto.Unmarshal(c.Request, req); err != nil {
+		return errors.Wrap(err, "unmarshal captured request")
+	}
+
+	resp, ok, err := handlerFunc(ctx, c.Peer, req)
+	if err != nil {
+		return errors.Wrap(err, "replay handler")
+	}
+
+	var b []byte
+	if ok {
+		b, err = proto.Marshal(resp)
+		if err != nil {
+			return errors.Wrap(err, "marshal replayed response")
+		}
+	}
+
+	if !bytes.Equal(b, c.Response) {
+		return errors.New("replayed response mismatch",
+			z.Str("protocol", string(c.Protocol)),
+			z.Hex("captured", c.Response),
+			z.Hex("replayed", b),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	pbv1 "github.com/obolnetwork/charon/core/corepb/v1"
+	"github.com/obolnetwork/charon/p2p"
+	"github.com/obolnetwork/charon/testutil"
+)
+
+func TestRecordReplay(t *testing.T) {
+	var (
+		protocolID = protocol.ID("test-record")
+		ctx        = context.Background()
+		server     = testutil.CreateHost(t, testutil.AvailableAddr(t))
+		client     = testutil.CreateHost(t, testutil.AvailableAddr(t))
+		serverSink = new(bytes.Buffer)
+		clientSink = new(bytes.Buffer)
+	)
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	zeroReq := func() proto.Message { return new(pbv1.Duty) }
+
+	// Echo the duty with an incremented slot.
+	handler := func(ctx context.Context, peerID peer.ID, req proto.Message) (proto.Message, bool, error) {
+		duty, ok := req.(*pbv1.Duty)
+		require.True(t, ok)
+
+		return &pbv1.Duty{Slot: duty.Slot + 1, Type: duty.Type}, true, nil
+	}
+
+	p2p.RegisterHandler("server", server, protocolID, zeroReq, handler,
+		p2p.WithHandlerRecorder(p2p.NewRecorder(serverSink)))
+
+	resp := new(pbv1.Duty)
+	err := p2p.SendReceive(ctx, client, server.ID(), &pbv1.Duty{Slot: 99, Type: 1}, resp, protocolID,
+		p2p.WithSendReceiveRecorder(p2p.NewRecorder(clientSink)))
+	require.NoError(t, err)
+	require.EqualValues(t, 100, resp.Slot)
+
+	serverCaptures, err := p2p.ReadCaptures(serverSink)
+	require.NoError(t, err)
+	require.Len(t, serverCaptures, 1)
+
+	clientCaptures, err := p2p.ReadCaptures(clientSink)
+	require.NoError(t, err)
+	require.Len(t, clientCaptures, 1)
+
+	// Both sides captured identical round-trip bytes.
+	require.Equal(t, protocolID, serverCaptures[0].Protocol)
+	require.Equal(t, client.ID(), serverCaptures[0].Peer)
+	require.Equal(t, server.ID(), clientCaptures[0].Peer)
+	require.Equal(t, clientCaptures[0].Request, serverCaptures[0].Request)
+	require.Equal(t, clientCaptures[0].Response, serverCaptures[0].Response)
+	require.False(t, serverCaptures[0].Time.IsZero())
+
+	// Replaying against the same handler results in identical behavior.
+	require.NoError(t, p2p.Replay(ctx, serverCaptures[0], zeroReq, handler))
+
+	// Replaying against a different handler detects the mismatch.
+	other := func(ctx context.Context, peerID peer.ID, req proto.Message) (proto.Message, bool, error) {
+		return req, true, nil
+	}
+	err = p2p.Replay(ctx, serverCaptures[0], zeroReq, other)
+	require.ErrorContains(t, err, "replayed response mismatch")
+}
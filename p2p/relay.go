@@ -4,13 +4,20 @@ package p2p
 
 import (
 	"context"
+	"sync"
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/event"
 	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/peerstore"
 	circuit "github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/client"
+	ma "github.com/multiformats/go-multiaddr"
 
+	"github.com/obolnetwork/charon/app/errors"
 	"github.com/obolnetwork/charon/app/expbackoff"
+	"github.com/obolnetwork/charon/app/forkjoin"
 	"github.com/obolnetwork/charon/app/lifecycle"
 	"github.com/obolnetwork/charon/app/log"
 	"github.com/obolnetwork/charon/app/z"
@@ -19,68 +26,560 @@ import (
 // routedAddrTTL is a peer store TTL used to notify libp2p of peer addresses.
 // We use a custom TTL (different from well-known peer store TTLs) since
 // this mitigates against other libp2p services (like Identify) modifying
-// or removing them.
+// or removing them, since they only update addresses by TTL.
+// TestRoutedAddrTTL enforces this invariant across libp2p upgrades.
 var routedAddrTTL = peerstore.TempAddrTTL + 1
 
+// relayRouterWorkers is the maximum number of peers whose relay addresses are routed concurrently.
+const relayRouterWorkers = 8
+
+// defaultReserveTimeout is the default maximum duration of a single relay circuit reservation attempt.
+const defaultReserveTimeout = 30 * time.Second
+
+// refreshBackoff is the backoff of refreshing consecutive relay circuit reservations that expire implausibly soon,
+// e.g. due to clock skew or a misbehaving relay. Its base delay is the minimum delay before refreshing a reservation.
+var refreshBackoff = expbackoff.Config{
+	BaseDelay:  10 * time.Second,
+	Multiplier: 1.6,
+	Jitter:     0.2,
+	MaxDelay:   5 * time.Minute,
+}
+
+// reserveFunc abstracts circuit.Reserve that reserves a relay circuit.
+type reserveFunc func(ctx context.Context, h host.Host, ai peer.AddrInfo) (*circuit.Reservation, error)
+
+type relayReserverOpts struct {
+	reserveTimeout time.Duration
+	peerIDs        []peer.ID
+	after          func(time.Duration) <-chan time.Time
+	release        func(ctx context.Context, tcpNode host.Host, relayID peer.ID)
+	readiness      *RelayReadiness
+	reservation    *relayReservation
+}
+
+// newRelayReserverOpts returns the default relay reserver options overridden by the provided options.
+func newRelayReserverOpts(opts ...func(*relayReserverOpts)) relayReserverOpts {
+	o := relayReserverOpts{
+		reserveTimeout: defaultReserveTimeout,
+		after:          time.After,
+		release:        releaseRelay,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.readiness != nil {
+		o.reservation = o.readiness.add()
+	}
+
+	return o
+}
+
+// WithReserveTimeout returns an option for NewRelayReserver that sets the maximum duration
+// of a single reservation attempt, so that a stuck relay fails fast and the attempt is retried with backoff.
+func WithReserveTimeout(timeout time.Duration) func(*relayReserverOpts) {
+	return func(opts *relayReserverOpts) {
+		opts.reserveTimeout = timeout
+	}
+}
+
+// WithReserveWhenPeersUnreachable returns an option for NewRelayReserver that only maintains a reservation
+// while at least one of the cluster peers isn't directly connected, releasing it when all peers are
+// directly connected and re-reserving when one drops. This reduces relay load in healthy clusters.
+func WithReserveWhenPeersUnreachable(peerIDs []peer.ID) func(*relayReserverOpts) {
+	return func(opts *relayReserverOpts) {
+		opts.peerIDs = peerIDs
+	}
+}
+
+// WithRelayReadiness returns an option for NewRelayReserver that tracks the reserver's relay circuit
+// reservation state in the readiness, see RelayReadiness.RelayReady.
+func WithRelayReadiness(readiness *RelayReadiness) func(*relayReserverOpts) {
+	return func(opts *relayReserverOpts) {
+		opts.readiness = readiness
+	}
+}
+
+// NewRelayReadiness returns a new relay readiness without any relay reservers.
+func NewRelayReadiness() *RelayReadiness {
+	return new(RelayReadiness)
+}
+
+// RelayReadiness tracks the relay circuit reservations of relay reservers configured via WithRelayReadiness.
+type RelayReadiness struct {
+	mu           sync.Mutex
+	reservations []*relayReservation
+}
+
+// RelayReady returns true if no relay reservers are configured or if at least one is ready, i.e., it has an active
+// relay circuit reservation or doesn't require one since the node is publicly reachable or all peers are directly
+// connected. This allows health handlers to gate readiness on relay connectivity.
+func (r *RelayReadiness) RelayReady() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.reservations) == 0 {
+		return true
+	}
+
+	for _, resv := range r.reservations {
+		if resv.ready {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add returns the reservation state of a new relay reserver, initially not ready.
+func (r *RelayReadiness) add() *relayReservation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resv := &relayReservation{readiness: r}
+	r.reservations = append(r.reservations, resv)
+
+	return resv
+}
+
+// relayReservation is the reservation state of a relay reserver tracked by a relay readiness.
+type relayReservation struct {
+	readiness *RelayReadiness
+	ready     bool // Protected by readiness mutex.
+}
+
+// Set sets whether the relay reserver is ready. It is a no-op if the reservation is nil, i.e., readiness isn't tracked.
+func (r *relayReservation) Set(ready bool) {
+	if r == nil {
+		return
+	}
+
+	r.readiness.mu.Lock()
+	defer r.readiness.mu.Unlock()
+
+	r.ready = ready
+}
+
+// setRelayConn sets the relay connection gauge to whether a reservation is active and the relay reserver's readiness
+// to whether it is ready, i.e., reserved or not requiring a reservation.
+func setRelayConn(o relayReserverOpts, name string, reserved, ready bool) {
+	if reserved {
+		relayConnGauge.WithLabelValues(name).Set(1)
+	} else {
+		relayConnGauge.WithLabelValues(name).Set(0)
+	}
+
+	o.reservation.Set(ready)
+}
+
 // NewRelayReserver returns a life cycle hook function that continuously
-// reserves a relay circuit until the context is closed.
-func NewRelayReserver(tcpNode host.Host, relay *MutablePeer) lifecycle.HookFunc {
+// reserves a relay circuit until the context is closed, releasing it then. Reservations are skipped
+// while libp2p AutoNAT detects that the node is publicly reachable.
+func NewRelayReserver(tcpNode host.Host, relay *MutablePeer, opts ...func(*relayReserverOpts)) lifecycle.HookFunc {
+	return newRelayReserver(tcpNode, relay, circuit.Reserve, opts...)
+}
+
+// newRelayReserver returns a life cycle hook function that continuously
+// reserves a relay circuit using the provided reserve function until the context is closed.
+func newRelayReserver(tcpNode host.Host, relay *MutablePeer, reserve reserveFunc, opts ...func(*relayReserverOpts)) lifecycle.HookFunc {
+	o := newRelayReserverOpts(opts...)
+
 	return func(ctx context.Context) error {
-		ctx = log.WithTopic(ctx, "relay")
-		backoff, resetBackoff := expbackoff.NewWithReset(ctx)
+		reachability, err := newReachabilityTracker(tcpNode)
+		if err != nil {
+			return err
+		}
+		defer reachability.Close()
 
-		for {
-			relayPeer, ok := relay.Peer()
-			if !ok {
-				time.Sleep(time.Second * 10) // Constant 10s backoff ok for mutexed lookups
-				continue
+		var peers *peerConnTracker
+		if len(o.peerIDs) > 0 {
+			peers = newPeerConnTracker(tcpNode, o.peerIDs, func(p peer.ID) bool {
+				return isDirectlyConnected(tcpNode, p)
+			})
+			defer peers.Close()
+		}
+
+		return reserveRelay(ctx, tcpNode, relay, reserve, reachability, peers, o)
+	}
+}
+
+// reserveRelay continuously reserves a relay circuit using the provided reserve function until the context is closed,
+// skipping reservations while the node is publicly reachable or while all peers are directly connected if peers
+// is not nil. Each reservation attempt times out after the reserve timeout.
+func reserveRelay(ctx context.Context, tcpNode host.Host, relay *MutablePeer, reserve reserveFunc,
+	reachability *reachabilityTracker, peers *peerConnTracker, o relayReserverOpts,
+) error {
+	ctx = log.WithTopic(ctx, "relay")
+
+	var (
+		implausible int  // Number of consecutive reservations expiring implausibly soon.
+		reserved    bool // Whether a reservation is active, which remains so while refreshing it.
+	)
+
+	for {
+		relayPeer, ok := relay.Peer()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second * 10): // Constant 10s backoff ok for mutexed lookups
 			}
 
-			name := PeerName(relayPeer.ID)
+			continue
+		}
 
-			relayConnGauge.WithLabelValues(name).Set(0)
+		name := PeerName(relayPeer.ID)
 
-			resv, err := circuit.Reserve(ctx, tcpNode, relayPeer.AddrInfo())
-			if err != nil {
-				log.Warn(ctx, "Reserve relay circuit", err, z.Str("relay_peer", name))
-				backoff()
+		// Capture the change notification before checking, so changes after the check aren't missed.
+		reachabilityChanged := reachability.Changed()
+		if reachability.Public() {
+			reserved = false
+			setRelayConn(o, name, false, true) // No reservation required.
+			logDebug(ctx, LogSubsystemRelay, "Skipping relay circuit reservation since node is publicly reachable",
+				z.Str("relay_peer", name))
 
-				continue
+			// Wait for reachability to change.
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-reachabilityChanged:
+			}
+
+			continue
+		}
+
+		var peersChanged <-chan struct{}
+		if peers != nil {
+			peersChanged = peers.Changed() // Capture before checking, see reachabilityChanged.
+		}
+		if peers != nil && !peers.Unreachable() {
+			reserved = false
+			setRelayConn(o, name, false, true) // No reservation required.
+			logDebug(ctx, LogSubsystemRelay, "Skipping relay circuit reservation since all peers are directly connected",
+				z.Str("relay_peer", name))
+
+			// Wait for a peer to drop.
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-peersChanged:
+			}
+
+			continue
+		}
+
+		if !reserved {
+			setRelayConn(o, name, false, false)
+		}
+
+		var resv *circuit.Reservation
+		err := retry(ctx, func(ctx context.Context) error {
+			relayReservationAttempts.WithLabelValues(name).Inc()
+
+			var err error
+			resv, err = reserve(ctx, tcpNode, relayPeer.AddrInfo())
+			if err != nil {
+				logWarn(ctx, LogSubsystemRelay, "Reserve relay circuit", err, z.Str("relay_peer", name))
+				relayReservationFailures.WithLabelValues(name).Inc()
 			}
-			resetBackoff()
 
-			// Note a single long-lived reservation (created by server-side) is mapped to
-			// many short-lived limited client-side connections.
-			// When the reservation expires, the server needs to re-reserve.
-			// When the connection expires (stream reset error), then client needs to reconnect.
+			return err
+		}, withAttemptTimeout(o.reserveTimeout))
+		if err != nil {
+			return nil // Unlimited retries only fail when the context is closed.
+		}
+
+		// Note a single long-lived reservation (created by server-side) is mapped to
+		// many short-lived limited client-side connections.
+		// When the reservation expires, the server needs to re-reserve.
+		// When the connection expires (stream reset error), then client needs to reconnect.
 
-			refreshDelay := time.Until(resv.Expiration.Add(-2 * time.Minute))
+		refreshDelay := time.Until(resv.Expiration.Add(-2 * time.Minute))
+		if refreshDelay < refreshBackoff.BaseDelay {
+			// Clamp and backoff to avoid hammering a relay returning reservations that expire implausibly soon.
+			refreshDelay = expbackoff.Backoff(refreshBackoff, implausible)
+			implausible++
 
-			log.Debug(ctx, "Relay circuit reserved",
-				z.Any("reservation_expire", resv.Expiration),        // Server side reservation expiry (long)
-				z.Any("connection_duration", resv.LimitDuration),    // Client side connection limit (short)
-				z.Any("connection_data_mb", resv.LimitData/(1<<20)), // Client side connection limit (short)
+			logWarn(ctx, LogSubsystemRelay, "Relay circuit reservation expires implausibly soon, check relay and clock", nil,
+				z.Any("reservation_expire", resv.Expiration),
 				z.Any("refresh_delay", refreshDelay),
 				z.Str("relay_peer", name),
 			)
-			relayConnGauge.WithLabelValues(name).Set(1)
+		} else {
+			implausible = 0
+		}
 
-			refresh := time.After(refreshDelay)
+		logDebug(ctx, LogSubsystemRelay, "Relay circuit reserved",
+			z.Any("reservation_expire", resv.Expiration),        // Server side reservation expiry (long)
+			z.Any("connection_duration", resv.LimitDuration),    // Client side connection limit (short)
+			z.Any("connection_data_mb", resv.LimitData/(1<<20)), // Client side connection limit (short)
+			z.Any("refresh_delay", refreshDelay),
+			z.Str("relay_peer", name),
+		)
+		reserved = true
+		setRelayConn(o, name, true, true)
+
+		refresh := o.after(refreshDelay)
+
+		if !waitRefresh(ctx, refresh, peers) {
+			if ctx.Err() != nil {
+				// Release the reservation on shutdown, after routers stopped routing relay addresses.
+				o.reservation.Set(false)
+				o.release(ctx, tcpNode, relayPeer.ID)
 
-			select {
-			case <-ctx.Done():
 				return nil
-			case <-refresh:
 			}
 
-			log.Debug(ctx, "Refreshing relay circuit reservation")
-			relayConnGauge.WithLabelValues(name).Set(0)
+			logDebug(ctx, LogSubsystemRelay, "Releasing relay circuit reservation since all peers are directly connected",
+				z.Str("relay_peer", name))
+			relayReservationReleases.WithLabelValues(name).Inc()
+			reserved = false
+			setRelayConn(o, name, false, true) // No reservation required.
+			o.release(ctx, tcpNode, relayPeer.ID)
+
+			continue
+		}
+
+		logDebug(ctx, LogSubsystemRelay, "Refreshing relay circuit reservation")
+		relayReservationRefreshes.WithLabelValues(name).Inc()
+	}
+}
+
+// waitRefresh blocks until the reservation should be refreshed and returns true. It returns false if the context
+// is closed or if all peers are directly connected, in which case the reservation should be released.
+func waitRefresh(ctx context.Context, refresh <-chan time.Time, peers *peerConnTracker) bool {
+	for {
+		var changed <-chan struct{} // Nil channel blocks forever if not tracking peers.
+		if peers != nil {
+			changed = peers.Changed()
+			if !peers.Unreachable() {
+				return false
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-refresh:
+			return true
+		case <-changed:
+		}
+	}
+}
+
+// releaseRelay releases the relay circuit reservation by closing the connections to the relay,
+// since the relay removes the reservations of disconnected peers.
+func releaseRelay(ctx context.Context, tcpNode host.Host, relayID peer.ID) {
+	if tcpNode == nil {
+		return
+	}
+
+	if err := tcpNode.Network().ClosePeer(relayID); err != nil {
+		logWarn(ctx, LogSubsystemRelay, "Close relay connection", err, z.Str("relay_peer", PeerName(relayID)))
+	}
+}
+
+// newReachabilityTracker returns a new reachability tracker subscribed to the libp2p local
+// reachability events of the node. The reachability is always unknown if the node is nil.
+func newReachabilityTracker(tcpNode host.Host) (*reachabilityTracker, error) {
+	r := &reachabilityTracker{
+		reachability: network.ReachabilityUnknown,
+		changed:      make(chan struct{}),
+		quit:         make(chan struct{}),
+	}
+
+	if tcpNode == nil {
+		return r, nil
+	}
+
+	sub, err := tcpNode.EventBus().Subscribe(new(event.EvtLocalReachabilityChanged))
+	if err != nil {
+		return nil, errors.Wrap(err, "subscribe reachability events")
+	}
+
+	go func() {
+		defer sub.Close()
+
+		for {
+			select {
+			case <-r.quit:
+				return
+			case e := <-sub.Out():
+				r.set(e)
+			}
+		}
+	}()
+
+	return r, nil
+}
+
+// reachabilityTracker tracks the libp2p local reachability of a node.
+type reachabilityTracker struct {
+	quit chan struct{}
+
+	mu           sync.Mutex
+	reachability network.Reachability
+	changed      chan struct{}
+}
+
+// set updates the reachability from the event and notifies waiters.
+func (r *reachabilityTracker) set(e interface{}) {
+	evt, ok := e.(event.EvtLocalReachabilityChanged)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reachability = evt.Reachability
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// Public returns true if the node is publicly reachable.
+func (r *reachabilityTracker) Public() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reachability == network.ReachabilityPublic
+}
+
+// Changed returns a channel that is closed when the reachability changes.
+func (r *reachabilityTracker) Changed() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.changed
+}
+
+// Close stops tracking reachability.
+func (r *reachabilityTracker) Close() {
+	close(r.quit)
+}
+
+// newPeerConnTracker returns a new peer connection tracker of the peers (excluding the node itself) that
+// refreshes on connection changes of the node. The direct function returns true if the peer is directly connected.
+// Connection changes are never observed if the node is nil.
+func newPeerConnTracker(tcpNode host.Host, peerIDs []peer.ID, direct func(peer.ID) bool) *peerConnTracker {
+	t := &peerConnTracker{
+		tcpNode: tcpNode,
+		direct:  direct,
+		notify:  make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+
+	for _, p := range peerIDs {
+		if tcpNode != nil && p == tcpNode.ID() {
+			continue // Skip self
+		}
+		t.peerIDs = append(t.peerIDs, p)
+	}
+
+	t.refresh()
+
+	if tcpNode == nil {
+		return t
+	}
+
+	// Notifications are called synchronously by libp2p, so only signal the refresh goroutine.
+	signal := func(network.Network, network.Conn) {
+		select {
+		case t.notify <- struct{}{}:
+		default:
 		}
 	}
+	t.notifiee = &network.NotifyBundle{ConnectedF: signal, DisconnectedF: signal}
+	tcpNode.Network().Notify(t.notifiee)
+
+	go func() {
+		for {
+			select {
+			case <-t.quit:
+				return
+			case <-t.notify:
+				t.refresh()
+			}
+		}
+	}()
+
+	return t
+}
+
+// peerConnTracker tracks whether any peer isn't directly connected to the node.
+type peerConnTracker struct {
+	tcpNode  host.Host
+	peerIDs  []peer.ID
+	direct   func(peer.ID) bool
+	notifiee network.Notifiee
+	notify   chan struct{}
+	quit     chan struct{}
+
+	mu          sync.Mutex
+	unreachable bool
+	changed     chan struct{}
+}
+
+// refresh updates whether any peer isn't directly connected and notifies waiters if it changed.
+func (t *peerConnTracker) refresh() {
+	var unreachable bool
+	for _, p := range t.peerIDs {
+		if !t.direct(p) {
+			unreachable = true
+			break
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.unreachable == unreachable {
+		return
+	}
+
+	t.unreachable = unreachable
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+// Unreachable returns true if any peer isn't directly connected.
+func (t *peerConnTracker) Unreachable() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.unreachable
+}
+
+// Changed returns a channel that is closed when Unreachable changes.
+func (t *peerConnTracker) Changed() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.changed
+}
+
+// Close stops tracking peer connections.
+func (t *peerConnTracker) Close() {
+	if t.notifiee != nil {
+		t.tcpNode.Network().StopNotify(t.notifiee)
+	}
+	close(t.quit)
+}
+
+// isDirectlyConnected returns true if the node has a non-relayed connection to the peer.
+func isDirectlyConnected(tcpNode host.Host, p peer.ID) bool {
+	for _, conn := range tcpNode.Network().ConnsToPeer(p) {
+		if !IsRelayAddr(conn.RemoteMultiaddr()) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // NewRelayRouter returns a life cycle hook that routes peers via relays in libp2p by
-// continuously adding peer relay addresses to libp2p peer store.
+// continuously adding peer relay addresses to libp2p peer store. The addresses are removed when the context is closed.
 func NewRelayRouter(tcpNode host.Host, peers []Peer, relays []*MutablePeer) lifecycle.HookFuncCtx {
 	return func(ctx context.Context) {
 		if len(relays) == 0 {
@@ -89,34 +588,168 @@ func NewRelayRouter(tcpNode host.Host, peers []Peer, relays []*MutablePeer) life
 
 		ctx = log.WithTopic(ctx, "p2p")
 
+		// Stop routing relay addresses when stopped, so no stale addresses remain once relays are released.
+		defer unrouteRelays(tcpNode, peers)
+
 		for ctx.Err() == nil {
-			for _, p := range peers {
-				if p.ID == tcpNode.ID() {
-					// Skip self
-					continue
-				}
+			routeRelays(ctx, tcpNode, peers, relays, multiAddrsViaRelay)
 
-				for _, mutable := range relays {
-					relay, ok := mutable.Peer()
-					if !ok {
-						continue
-					}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(routedAddrTTL * 9 / 10):
+			}
+		}
+	}
+}
+
+// unrouteRelays removes the relay addresses of all peers added by routeRelays from the libp2p peer store.
+func unrouteRelays(tcpNode host.Host, peers []Peer) {
+	for _, p := range peers {
+		if p.ID == tcpNode.ID() {
+			continue
+		}
+
+		// Only relay addresses use the custom routed address TTL.
+		tcpNode.Peerstore().UpdateAddrs(p.ID, routedAddrTTL, 0)
+	}
+}
+
+// routeRelays adds the deduplicated relay addresses of all peers to the libp2p peer store, pruning relay addresses
+// of relays that are no longer active. Peers are processed concurrently by a bounded number of workers,
+// so a slow peer address construction doesn't delay the rest.
+func routeRelays(ctx context.Context, tcpNode host.Host, peers []Peer, relays []*MutablePeer,
+	addrsFunc func(Peer, peer.ID) ([]ma.Multiaddr, error),
+) {
+	work := func(ctx context.Context, p Peer) (struct{}, error) {
+		var (
+			routed       []ma.Multiaddr
+			active       = make(map[string]bool) // Peer store addresses (without peer component) to retain.
+			failedRelays = make(map[peer.ID]bool)
+		)
+		for _, mutable := range relays {
+			relay, ok := mutable.Peer()
+			if !ok {
+				continue
+			}
+
+			relayAddrs, err := addrsFunc(relay, p.ID)
+			if err != nil {
+				logError(ctx, LogSubsystemRouter, "Failed discovering peer address", err)
+				failedRelays[relay.ID] = true // Retain existing addresses of the relay.
+
+				continue
+			}
 
-					relayAddrs, err := multiAddrsViaRelay(relay, p.ID)
-					if err != nil {
-						log.Error(ctx, "Failed discovering peer address", err)
-						continue
-					}
+			for _, addr := range relayAddrs {
+				if err := verifyRelayAddr(addr, relay.ID, p.ID); err != nil {
+					logWarn(ctx, LogSubsystemRouter, "Rejecting invalid relay address", err,
+						z.Str("relay_peer", PeerName(relay.ID)), z.Str("addr", addr.String()))
+					relayAddrRejected.WithLabelValues(PeerName(relay.ID)).Inc()
+
+					continue
+				}
 
-					tcpNode.Peerstore().AddAddrs(p.ID, relayAddrs, routedAddrTTL)
+				// The peer store stores addresses without the trailing peer component.
+				stored, _ := peer.SplitAddr(addr)
+				if active[stored.String()] {
+					continue
 				}
+				active[stored.String()] = true
+				routed = append(routed, addr)
 			}
+		}
 
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(routedAddrTTL * 9 / 10):
+		var stale []ma.Multiaddr
+		for _, addr := range tcpNode.Peerstore().Addrs(p.ID) {
+			relayID, ok := circuitRelayID(addr)
+			if !ok || active[addr.String()] || failedRelays[relayID] {
+				continue
 			}
+
+			stale = append(stale, addr)
+		}
+
+		tcpNode.Peerstore().SetAddrs(p.ID, stale, 0) // Zero TTL removes the addresses.
+		tcpNode.Peerstore().AddAddrs(p.ID, routed, routedAddrTTL)
+
+		return struct{}{}, nil
+	}
+
+	fork, join, cancel := forkjoin.New(ctx, work,
+		forkjoin.WithoutFailFast(),
+		forkjoin.WithWorkers(relayRouterWorkers),
+	)
+	defer cancel()
+
+	for _, p := range peers {
+		if p.ID == tcpNode.ID() {
+			// Skip self
+			continue
 		}
+
+		fork(p)
 	}
+
+	_, _ = join().Flatten() // Wait for all peers to be processed, errors are logged.
+}
+
+// verifyRelayAddr returns an error if the address is not a circuit address of the peer via the relay,
+// i.e. <transport>/p2p/<relay>/p2p-circuit/p2p/<peer>, so a relay cannot redirect peers to arbitrary addresses.
+func verifyRelayAddr(addr ma.Multiaddr, relayID, peerID peer.ID) error {
+	comps := ma.Split(addr)
+	if len(comps) < 4 {
+		return errors.New("malformed relay address")
+	}
+
+	n := len(comps)
+	if !isPeerComponent(comps[n-1], peerID) {
+		return errors.New("relay address of mismatching peer", z.Str("peer", PeerName(peerID)))
+	} else if comps[n-2].Protocols()[0].Code != ma.P_CIRCUIT {
+		return errors.New("relay address without circuit")
+	} else if !isPeerComponent(comps[n-3], relayID) {
+		return errors.New("relay address of mismatching relay", z.Str("relay_peer", PeerName(relayID)))
+	}
+
+	for _, comp := range comps[:n-3] {
+		if code := comp.Protocols()[0].Code; code == ma.P_P2P || code == ma.P_CIRCUIT {
+			return errors.New("malformed relay transport address")
+		}
+	}
+
+	return nil
+}
+
+// circuitRelayID returns the relay peer ID of the peer store relay address, i.e. <transport>/p2p/<relay>/p2p-circuit,
+// and true or false if the address is not a relay address.
+func circuitRelayID(addr ma.Multiaddr) (peer.ID, bool) {
+	comps := ma.Split(addr)
+	n := len(comps)
+	if n < 2 || comps[n-1].Protocols()[0].Code != ma.P_CIRCUIT {
+		return "", false
+	}
+
+	val, err := comps[n-2].ValueForProtocol(ma.P_P2P)
+	if err != nil {
+		return "", false
+	}
+
+	relayID, err := peer.Decode(val)
+	if err != nil {
+		return "", false
+	}
+
+	return relayID, true
+}
+
+// isPeerComponent returns true if the single component multiaddr is the /p2p component of the peer.
+func isPeerComponent(comp ma.Multiaddr, peerID peer.ID) bool {
+	val, err := comp.ValueForProtocol(ma.P_P2P)
+	if err != nil {
+		return false
+	}
+
+	id, err := peer.Decode(val)
+
+	return err == nil && id == peerID
 }
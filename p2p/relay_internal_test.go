@@ -0,0 +1,724 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/event"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/p2p/host/peerstore/pstoremem"
+	circuit "github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/client"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/expbackoff"
+	"github.com/obolnetwork/charon/app/lifecycle"
+	charontestutil "github.com/obolnetwork/charon/testutil"
+)
+
+func TestRelayReserverMetrics(t *testing.T) {
+	expbackoff.SetAfterForT(t, func(time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+
+		return ch
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	relayPeer := Peer{ID: peer.ID("relay-metrics")}
+	name := PeerName(relayPeer.ID)
+
+	var attempt int
+	reserve := func(_ context.Context, _ host.Host, ai peer.AddrInfo) (*circuit.Reservation, error) {
+		require.Equal(t, relayPeer.ID, ai.ID)
+
+		attempt++
+		switch attempt {
+		case 1:
+			return nil, errors.New("reserve failure")
+		case 2:
+			// Implausibly soon expiration results in refresh after the (immediate) minimum delay.
+			return &circuit.Reservation{Expiration: time.Now().Add(2 * time.Minute)}, nil
+		default:
+			cancel()
+			return &circuit.Reservation{Expiration: time.Now().Add(time.Hour)}, nil
+		}
+	}
+
+	err := newRelayReserver(nil, NewMutablePeer(relayPeer), reserve, withImmediateRefresh(nil))(ctx)
+	require.NoError(t, err)
+
+	require.EqualValues(t, 3, testutil.ToFloat64(relayReservationAttempts.WithLabelValues(name)))
+	require.EqualValues(t, 1, testutil.ToFloat64(relayReservationFailures.WithLabelValues(name)))
+	require.EqualValues(t, 1, testutil.ToFloat64(relayReservationRefreshes.WithLabelValues(name)))
+	require.EqualValues(t, 1, testutil.ToFloat64(relayConnGauge.WithLabelValues(name)))
+}
+
+func TestRelayReserverReachability(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tcpNode := charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+
+	emitter, err := tcpNode.EventBus().Emitter(new(event.EvtLocalReachabilityChanged))
+	require.NoError(t, err)
+	defer emitter.Close()
+
+	reachability, err := newReachabilityTracker(tcpNode)
+	require.NoError(t, err)
+	defer reachability.Close()
+
+	// Node is publicly reachable before reserving.
+	require.NoError(t, emitter.Emit(event.EvtLocalReachabilityChanged{Reachability: network.ReachabilityPublic}))
+	require.Eventually(t, reachability.Public, time.Second, time.Millisecond)
+
+	reserved := make(chan struct{}, 1)
+	reserve := func(context.Context, host.Host, peer.AddrInfo) (*circuit.Reservation, error) {
+		reserved <- struct{}{}
+		return &circuit.Reservation{Expiration: time.Now().Add(time.Hour)}, nil
+	}
+
+	relay := NewMutablePeer(Peer{ID: peer.ID("relay-reachability")})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- reserveRelay(ctx, tcpNode, relay, reserve, reachability, nil, newRelayReserverOpts())
+	}()
+
+	select {
+	case <-reserved:
+		require.Fail(t, "unexpected reservation while publicly reachable")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Reservation is enabled when reachability degrades.
+	require.NoError(t, emitter.Emit(event.EvtLocalReachabilityChanged{Reachability: network.ReachabilityPrivate}))
+
+	select {
+	case <-reserved:
+	case <-time.After(time.Second):
+		require.Fail(t, "reservation not enabled after reachability degraded")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestRelayReserverPeerConnectivity(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reachability, err := newReachabilityTracker(nil)
+	require.NoError(t, err)
+	defer reachability.Close()
+
+	peerA, peerB := peer.ID("peer-a"), peer.ID("peer-b")
+
+	var (
+		mu     sync.Mutex
+		direct = map[peer.ID]bool{peerA: true, peerB: true}
+	)
+	setDirect := func(p peer.ID, ok bool) {
+		mu.Lock()
+		direct[p] = ok
+		mu.Unlock()
+	}
+
+	peers := newPeerConnTracker(nil, []peer.ID{peerA, peerB}, func(p peer.ID) bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return direct[p]
+	})
+	defer peers.Close()
+
+	reserved := make(chan struct{}, 1)
+	reserve := func(context.Context, host.Host, peer.AddrInfo) (*circuit.Reservation, error) {
+		reserved <- struct{}{}
+		return &circuit.Reservation{Expiration: time.Now().Add(time.Hour)}, nil
+	}
+
+	relayPeer := Peer{ID: peer.ID("relay-peer-conns")}
+	name := PeerName(relayPeer.ID)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- reserveRelay(ctx, nil, NewMutablePeer(relayPeer), reserve, reachability, peers, newRelayReserverOpts())
+	}()
+
+	requireReserved := func(expect bool) {
+		t.Helper()
+
+		select {
+		case <-reserved:
+			require.True(t, expect, "unexpected reservation")
+		case <-time.After(100 * time.Millisecond):
+			require.False(t, expect, "reservation not acquired")
+		}
+	}
+
+	// No reservation while all peers are directly connected.
+	requireReserved(false)
+
+	// Reservation is acquired when a peer drops.
+	setDirect(peerB, false)
+	peers.refresh()
+	requireReserved(true)
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(relayConnGauge.WithLabelValues(name)) == 1
+	}, time.Second, time.Millisecond)
+
+	// Reservation is released when all peers are directly connected again.
+	setDirect(peerB, true)
+	peers.refresh()
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(relayReservationReleases.WithLabelValues(name)) == 1 &&
+			testutil.ToFloat64(relayConnGauge.WithLabelValues(name)) == 0
+	}, time.Second, time.Millisecond)
+	requireReserved(false)
+
+	// Reservation is re-acquired when a peer drops again.
+	setDirect(peerA, false)
+	peers.refresh()
+	requireReserved(true)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestRelayReady(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	readiness := NewRelayReadiness()
+	require.True(t, readiness.RelayReady(), "ready without relays")
+
+	reachability, err := newReachabilityTracker(nil)
+	require.NoError(t, err)
+	defer reachability.Close()
+
+	refresh := make(chan time.Time)
+	after := func(time.Duration) <-chan time.Time { return refresh }
+
+	results := make(chan error)
+	reserve := func(ctx context.Context, _ host.Host, _ peer.AddrInfo) (*circuit.Reservation, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-results:
+			if err != nil {
+				return nil, err
+			}
+
+			return &circuit.Reservation{Expiration: time.Now().Add(time.Hour)}, nil
+		}
+	}
+
+	expbackoff.SetAfterForT(t, func(time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+
+		return ch
+	})
+
+	opts := newRelayReserverOpts(WithRelayReadiness(readiness), func(o *relayReserverOpts) {
+		o.after = after
+		o.release = func(context.Context, host.Host, peer.ID) {}
+	})
+	require.False(t, readiness.RelayReady(), "not ready before reservation")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- reserveRelay(ctx, nil, NewMutablePeer(Peer{ID: peer.ID("relay-ready")}), reserve, reachability, nil, opts)
+	}()
+
+	// Failed reservations aren't ready.
+	results <- errors.New("reserve failure")
+	require.False(t, readiness.RelayReady())
+
+	// Ready once reserved.
+	results <- nil
+	require.Eventually(t, readiness.RelayReady, time.Second, time.Millisecond)
+
+	// Remains ready while refreshing the reservation, even if refreshing fails and is retried.
+	refresh <- time.Now()
+	results <- errors.New("refresh failure")
+	require.True(t, readiness.RelayReady())
+
+	results <- nil
+	require.True(t, readiness.RelayReady())
+
+	// Not ready once released on shutdown.
+	cancel()
+	require.NoError(t, <-done)
+	require.False(t, readiness.RelayReady())
+}
+
+func TestRelayReadyWithoutReservation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tcpNode := charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+
+	emitter, err := tcpNode.EventBus().Emitter(new(event.EvtLocalReachabilityChanged))
+	require.NoError(t, err)
+	defer emitter.Close()
+
+	reachability, err := newReachabilityTracker(tcpNode)
+	require.NoError(t, err)
+	defer reachability.Close()
+
+	peerA := peer.ID("peer-a")
+	var direct atomic.Bool
+	direct.Store(true)
+	peers := newPeerConnTracker(nil, []peer.ID{peerA}, func(peer.ID) bool { return direct.Load() })
+	defer peers.Close()
+
+	results := make(chan error)
+	reserve := func(ctx context.Context, _ host.Host, _ peer.AddrInfo) (*circuit.Reservation, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-results:
+			if err != nil {
+				return nil, err
+			}
+
+			return &circuit.Reservation{Expiration: time.Now().Add(time.Hour)}, nil
+		}
+	}
+
+	expbackoff.SetAfterForT(t, func(time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+
+		return ch
+	})
+
+	// Node is publicly reachable before reserving.
+	require.NoError(t, emitter.Emit(event.EvtLocalReachabilityChanged{Reachability: network.ReachabilityPublic}))
+	require.Eventually(t, reachability.Public, time.Second, time.Millisecond)
+
+	readiness := NewRelayReadiness()
+	opts := newRelayReserverOpts(WithRelayReadiness(readiness), func(o *relayReserverOpts) {
+		o.release = func(context.Context, host.Host, peer.ID) {}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- reserveRelay(ctx, tcpNode, NewMutablePeer(Peer{ID: peer.ID("relay-not-required")}), reserve, reachability, peers, opts)
+	}()
+
+	// Ready without a reservation while publicly reachable.
+	require.Eventually(t, readiness.RelayReady, time.Second, time.Millisecond)
+
+	// Ready without a reservation while all peers are directly connected.
+	require.NoError(t, emitter.Emit(event.EvtLocalReachabilityChanged{Reachability: network.ReachabilityPrivate}))
+	require.Eventually(t, func() bool { return !reachability.Public() }, time.Second, time.Millisecond)
+	require.True(t, readiness.RelayReady())
+
+	// Not ready when a peer drops until reserved.
+	direct.Store(false)
+	peers.refresh()
+	results <- errors.New("reserve failure")
+	require.False(t, readiness.RelayReady())
+
+	results <- nil
+	require.Eventually(t, readiness.RelayReady, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestRelayReserverImplausibleExpiry(t *testing.T) {
+	expbackoff.SetRandFloatForT(t, func() float64 { return 0.5 }) // No jitter.
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const attempts = 5
+
+	var attempt int
+	reserve := func(context.Context, host.Host, peer.AddrInfo) (*circuit.Reservation, error) {
+		attempt++
+		if attempt > attempts {
+			cancel()
+		}
+
+		// Expiration in the near past, e.g. due to clock skew.
+		return &circuit.Reservation{Expiration: time.Now().Add(-time.Second)}, nil
+	}
+
+	var delays []time.Duration
+	relay := NewMutablePeer(Peer{ID: peer.ID("relay-implausible")})
+	err := newRelayReserver(nil, relay, reserve, withImmediateRefresh(&delays))(ctx)
+	require.NoError(t, err)
+
+	// Refreshes are clamped to the minimum delay and backoff instead of spinning.
+	require.Len(t, delays, attempts+1)
+	for i, delay := range delays {
+		require.Equal(t, expbackoff.Backoff(refreshBackoff, i), delay)
+		require.GreaterOrEqual(t, delay, refreshBackoff.BaseDelay)
+		if i > 0 {
+			require.Greater(t, delay, delays[i-1])
+		}
+	}
+}
+
+// withImmediateRefresh returns an option for newRelayReserver that immediately refreshes reservations
+// expiring implausibly soon, appending their refresh delays to the slice if not nil.
+func withImmediateRefresh(delays *[]time.Duration) func(*relayReserverOpts) {
+	return func(opts *relayReserverOpts) {
+		opts.after = func(d time.Duration) <-chan time.Time {
+			if d > refreshBackoff.MaxDelay {
+				return time.After(d)
+			}
+
+			if delays != nil {
+				*delays = append(*delays, d)
+			}
+
+			ch := make(chan time.Time, 1)
+			ch <- time.Now()
+
+			return ch
+		}
+	}
+}
+
+func TestRelayReserverTimeout(t *testing.T) {
+	var backoffs int
+	expbackoff.SetAfterForT(t, func(time.Duration) <-chan time.Time {
+		backoffs++
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+
+		return ch
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		attempt int
+		errs    []error
+	)
+	reserve := func(ctx context.Context, _ host.Host, _ peer.AddrInfo) (*circuit.Reservation, error) {
+		attempt++
+		if attempt > 1 {
+			cancel()
+			return &circuit.Reservation{Expiration: time.Now().Add(time.Hour)}, nil
+		}
+
+		// Block like a stuck relay until the attempt times out.
+		<-ctx.Done()
+		errs = append(errs, ctx.Err())
+
+		return nil, ctx.Err()
+	}
+
+	relay := NewMutablePeer(Peer{ID: peer.ID("relay-timeout")})
+	err := newRelayReserver(nil, relay, reserve, WithReserveTimeout(time.Millisecond))(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, attempt)
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], context.DeadlineExceeded)
+	require.Equal(t, 1, backoffs)
+}
+
+func TestRoutedAddrTTL(t *testing.T) {
+	for _, ttl := range wellKnownAddrTTLs() {
+		require.NotEqual(t, ttl, routedAddrTTL, "routed address TTL collides with well-known TTL")
+	}
+
+	// Updating addresses by any well-known TTL (as libp2p services do) doesn't remove routed addresses.
+	pstore, err := pstoremem.NewPeerstore()
+	require.NoError(t, err)
+	defer pstore.Close()
+
+	p := peer.ID("routed-peer")
+	addr, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/9000")
+	require.NoError(t, err)
+
+	pstore.AddAddrs(p, []ma.Multiaddr{addr}, routedAddrTTL)
+	for _, ttl := range wellKnownAddrTTLs() {
+		pstore.UpdateAddrs(p, ttl, 0)
+	}
+
+	require.Equal(t, []ma.Multiaddr{addr}, pstore.Addrs(p))
+}
+
+// wellKnownAddrTTLs returns the well-known libp2p peer store TTLs that libp2p services
+// use to add or update addresses and that routedAddrTTL must not collide with.
+func wellKnownAddrTTLs() []time.Duration {
+	return []time.Duration{
+		peerstore.AddressTTL,
+		peerstore.TempAddrTTL,
+		peerstore.RecentlyConnectedAddrTTL,
+		peerstore.OwnObservedAddrTTL,
+		peerstore.PermanentAddrTTL,
+		peerstore.ConnectedAddrTTL,
+	}
+}
+
+func TestRouteRelays(t *testing.T) {
+	const numPeers = 4 * relayRouterWorkers
+
+	ctx := context.Background()
+	tcpNode := charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+
+	relayAddr, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/9000")
+	require.NoError(t, err)
+	// randomPeerID returns a deterministic peer ID for the seed.
+	randomPeerID := func(seed int) peer.ID {
+		id, err := PeerIDFromKey(charontestutil.GenerateInsecureK1Key(t, seed).PubKey())
+		require.NoError(t, err)
+
+		return id
+	}
+
+	relayPeer := Peer{ID: randomPeerID(0), Addrs: []ma.Multiaddr{relayAddr}}
+	relay := NewMutablePeer(relayPeer)
+
+	peers := []Peer{{ID: tcpNode.ID()}} // Self is skipped.
+	for i := 1; i <= numPeers; i++ {
+		peers = append(peers, Peer{ID: randomPeerID(i)})
+	}
+
+	var (
+		inflight    atomic.Int32
+		maxInflight atomic.Int32
+		allWorkers  = make(chan struct{})
+		closeOnce   sync.Once
+	)
+
+	// blockingAddrs constructs the relay addresses once all workers construct addresses concurrently,
+	// so sequential processing fails instead of completing slowly.
+	blockingAddrs := func(relay Peer, peerID peer.ID) ([]ma.Multiaddr, error) {
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+
+		for {
+			prev := maxInflight.Load()
+			if n <= prev || maxInflight.CompareAndSwap(prev, n) {
+				break
+			}
+		}
+
+		if n == relayRouterWorkers {
+			closeOnce.Do(func() { close(allWorkers) })
+		}
+
+		select {
+		case <-allWorkers:
+		case <-time.After(10 * time.Second):
+			return nil, errors.New("peers not processed concurrently")
+		}
+
+		return multiAddrsViaRelay(relay, peerID)
+	}
+
+	routeRelays(ctx, tcpNode, peers, []*MutablePeer{relay}, blockingAddrs)
+
+	// Peers are processed concurrently by at most the number of workers.
+	require.EqualValues(t, relayRouterWorkers, maxInflight.Load())
+
+	for _, p := range peers[1:] {
+		addrs := tcpNode.Peerstore().Addrs(p.ID)
+		require.Len(t, addrs, 1)
+		require.Contains(t, addrs[0].String(), "/p2p/"+relayPeer.ID.String()+"/p2p-circuit")
+	}
+	for _, addr := range tcpNode.Peerstore().Addrs(tcpNode.ID()) {
+		require.NotContains(t, addr.String(), "/p2p-circuit")
+	}
+}
+
+func TestRouteRelaysRejectsInvalidAddrs(t *testing.T) {
+	ctx := context.Background()
+	tcpNode := charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+
+	newPeerID := func(seed int) peer.ID {
+		id, err := PeerIDFromKey(charontestutil.GenerateInsecureK1Key(t, seed).PubKey())
+		require.NoError(t, err)
+
+		return id
+	}
+
+	relayAddr, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/9000")
+	require.NoError(t, err)
+
+	relayPeer := Peer{ID: newPeerID(100), Addrs: []ma.Multiaddr{relayAddr}}
+	target := Peer{ID: newPeerID(101)}
+	other := newPeerID(102)
+
+	// maliciousAddrs returns a valid address and addresses of other peers, other relays or without circuit.
+	maliciousAddrs := func(relay Peer, peerID peer.ID) ([]ma.Multiaddr, error) {
+		valid, err := multiAddrsViaRelay(relay, peerID)
+		require.NoError(t, err)
+
+		mismatchPeer, err := multiAddrsViaRelay(relay, other)
+		require.NoError(t, err)
+
+		mismatchRelay, err := multiAddrsViaRelay(Peer{ID: other, Addrs: relay.Addrs}, peerID)
+		require.NoError(t, err)
+
+		redirect, err := ma.NewMultiaddr("/ip4/1.2.3.4/tcp/1/p2p/" + peerID.String())
+		require.NoError(t, err)
+
+		return append(append(append(valid, mismatchPeer...), mismatchRelay...), redirect), nil
+	}
+
+	name := PeerName(relayPeer.ID)
+	before := testutil.ToFloat64(relayAddrRejected.WithLabelValues(name))
+
+	routeRelays(ctx, tcpNode, []Peer{target}, []*MutablePeer{NewMutablePeer(relayPeer)}, maliciousAddrs)
+
+	valid, err := multiAddrsViaRelay(relayPeer, target.ID)
+	require.NoError(t, err)
+
+	addrs := tcpNode.Peerstore().Addrs(target.ID)
+	require.Len(t, addrs, 1)
+	require.True(t, strings.HasPrefix(valid[0].String(), addrs[0].String()))
+	require.EqualValues(t, 3, testutil.ToFloat64(relayAddrRejected.WithLabelValues(name))-before)
+}
+
+func TestRelayShutdownOrder(t *testing.T) {
+	tcpNode := charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+
+	newPeerID := func(seed int) peer.ID {
+		id, err := PeerIDFromKey(charontestutil.GenerateInsecureK1Key(t, seed).PubKey())
+		require.NoError(t, err)
+
+		return id
+	}
+
+	relayAddr, err := ma.NewMultiaddr("/ip4/127.0.0.1/tcp/9000")
+	require.NoError(t, err)
+
+	relayPeer := Peer{ID: newPeerID(200), Addrs: []ma.Multiaddr{relayAddr}}
+	relay := NewMutablePeer(relayPeer)
+	target := Peer{ID: newPeerID(201)}
+	peers := []Peer{{ID: tcpNode.ID()}, target}
+
+	reserved := make(chan struct{}, 1)
+	reserve := func(context.Context, host.Host, peer.AddrInfo) (*circuit.Reservation, error) {
+		reserved <- struct{}{}
+		return &circuit.Reservation{Expiration: time.Now().Add(time.Hour)}, nil
+	}
+
+	// Record the number of routed relay addresses when the reservation is released.
+	routedOnRelease := make(chan int, 1)
+	withRelease := func(o *relayReserverOpts) {
+		o.release = func(_ context.Context, h host.Host, relayID peer.ID) {
+			require.Equal(t, relayPeer.ID, relayID)
+			routedOnRelease <- len(h.Peerstore().Addrs(target.ID))
+		}
+	}
+
+	life := new(lifecycle.Manager)
+
+	startReserver, stopReserver := lifecycle.NewStoppable(newRelayReserver(tcpNode, relay, reserve, withRelease))
+	life.RegisterStart(lifecycle.AsyncBackground, lifecycle.StartRelay, startReserver)
+	life.RegisterStop(lifecycle.StopRelay, stopReserver)
+
+	startRouter, stopRouter := lifecycle.NewStoppable(NewRelayRouter(tcpNode, peers, []*MutablePeer{relay}))
+	life.RegisterStart(lifecycle.AsyncBackground, lifecycle.StartP2PRouters, startRouter)
+	life.RegisterStop(lifecycle.StopP2PRouters, stopRouter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- life.Run(ctx)
+	}()
+
+	<-reserved
+	require.Eventually(t, func() bool {
+		return len(tcpNode.Peerstore().Addrs(target.ID)) > 0
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	// The router stopped routing relay addresses before the reservation was released.
+	require.Zero(t, <-routedOnRelease)
+}
+
+func TestRouteRelaysPrunesStaleAddrs(t *testing.T) {
+	ctx := context.Background()
+	tcpNode := charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+
+	newPeerID := func(seed int) peer.ID {
+		id, err := PeerIDFromKey(charontestutil.GenerateInsecureK1Key(t, seed).PubKey())
+		require.NoError(t, err)
+
+		return id
+	}
+	newAddr := func(port int) ma.Multiaddr {
+		addr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", port))
+		require.NoError(t, err)
+
+		return addr
+	}
+
+	relayA := NewMutablePeer(Peer{ID: newPeerID(300), Addrs: []ma.Multiaddr{newAddr(9000), newAddr(9000)}}) // Duplicate address
+	relayB := NewMutablePeer(Peer{ID: newPeerID(301), Addrs: []ma.Multiaddr{newAddr(9001)}})
+	target := Peer{ID: newPeerID(302)}
+
+	// routedAddrs returns the routed relay addresses of the target.
+	routedAddrs := func() []string {
+		var resp []string
+		for _, addr := range tcpNode.Peerstore().Addrs(target.ID) {
+			resp = append(resp, addr.String())
+		}
+		sort.Strings(resp)
+
+		return resp
+	}
+	relayAddr := func(relay *MutablePeer, port int) string {
+		p, ok := relay.Peer()
+		require.True(t, ok)
+
+		return fmt.Sprintf("/ip4/127.0.0.1/tcp/%d/p2p/%s/p2p-circuit", port, p.ID)
+	}
+
+	// No duplicates accumulate across cycles.
+	for i := 0; i < 3; i++ {
+		routeRelays(ctx, tcpNode, []Peer{target}, []*MutablePeer{relayA, relayB}, multiAddrsViaRelay)
+		require.Equal(t, []string{relayAddr(relayA, 9000), relayAddr(relayB, 9001)}, routedAddrs())
+	}
+
+	// Addresses of removed relays are pruned.
+	routeRelays(ctx, tcpNode, []Peer{target}, []*MutablePeer{relayA}, multiAddrsViaRelay)
+	require.Equal(t, []string{relayAddr(relayA, 9000)}, routedAddrs())
+
+	// Stale addresses of relays with changed addresses are pruned.
+	p, _ := relayA.Peer()
+	relayA.Set(Peer{ID: p.ID, Addrs: []ma.Multiaddr{newAddr(9002)}})
+	routeRelays(ctx, tcpNode, []Peer{target}, []*MutablePeer{relayA}, multiAddrsViaRelay)
+	require.Equal(t, []string{relayAddr(relayA, 9002)}, routedAddrs())
+
+	// Addresses of relays failing address discovery are retained.
+	failing := func(Peer, peer.ID) ([]ma.Multiaddr, error) {
+		return nil, errors.New("discovery failed")
+	}
+	routeRelays(ctx, tcpNode, []Peer{target}, []*MutablePeer{relayA}, failing)
+	require.Equal(t, []string{relayAddr(relayA, 9002)}, routedAddrs())
+}
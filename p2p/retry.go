@@ -0,0 +1,100 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"context"
+	"time"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/expbackoff"
+	"github.com/obolnetwork/charon/app/z"
+)
+
+type retryOpts struct {
+	maxAttempts    int
+	attemptTimeout time.Duration
+	retryable      func(error) bool
+	backoff        []func(*expbackoff.Config)
+}
+
+// withMaxAttempts returns an option for retry that limits the number of attempts, zero is unlimited.
+func withMaxAttempts(n int) func(*retryOpts) {
+	return func(opts *retryOpts) {
+		opts.maxAttempts = n
+	}
+}
+
+// withAttemptTimeout returns an option for retry that times out each attempt after the timeout, zero disables the timeout.
+func withAttemptTimeout(timeout time.Duration) func(*retryOpts) {
+	return func(opts *retryOpts) {
+		opts.attemptTimeout = timeout
+	}
+}
+
+// withRetryable returns an option for retry that only retries errors classified as retryable.
+func withRetryable(retryable func(error) bool) func(*retryOpts) {
+	return func(opts *retryOpts) {
+		opts.retryable = retryable
+	}
+}
+
+// withBackoff returns an option for retry that configures the backoff between attempts.
+func withBackoff(opts ...func(*expbackoff.Config)) func(*retryOpts) {
+	return func(o *retryOpts) {
+		o.backoff = opts
+	}
+}
+
+// retry calls the function until it succeeds, returns a non-retryable error, the maximum number of attempts
+// is reached or the context is closed, applying exponential backoff between attempts.
+// By default, all errors are retried indefinitely without per-attempt timeout.
+// It returns the last attempt's error or the context error if the context was closed before the first attempt.
+func retry(ctx context.Context, fn func(context.Context) error, opts ...func(*retryOpts)) error {
+	o := retryOpts{
+		retryable: func(error) bool { return true },
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	backoff := expbackoff.New(ctx, o.backoff...)
+
+	for attempt := 1; ; attempt++ {
+		err := attemptWithTimeout(ctx, fn, o.attemptTimeout)
+		if err == nil {
+			return nil
+		} else if !o.retryable(err) {
+			return err
+		} else if o.maxAttempts > 0 && attempt >= o.maxAttempts {
+			return err
+		}
+
+		backoff()
+
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// attemptWithTimeout calls the function with a context that times out after the timeout, if not zero.
+func attemptWithTimeout(ctx context.Context, fn func(context.Context) error, timeout time.Duration) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(attemptCtx)
+	if err != nil && attemptCtx.Err() != nil && ctx.Err() == nil {
+		return errors.Wrap(err, "attempt timeout", z.Any("timeout", timeout))
+	}
+
+	return err
+}
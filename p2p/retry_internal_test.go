@@ -0,0 +1,106 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/expbackoff"
+)
+
+func TestRetry(t *testing.T) {
+	var backoffs int
+	expbackoff.SetAfterForT(t, func(time.Duration) <-chan time.Time {
+		backoffs++
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+
+		return ch
+	})
+
+	errRetryable := errors.New("retryable")
+	errPermanent := errors.New("permanent")
+	retryable := withRetryable(func(err error) bool { return errors.Is(err, errRetryable) })
+
+	t.Run("success after n", func(t *testing.T) {
+		backoffs = 0
+
+		var attempts int
+		err := retry(context.Background(), func(context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errRetryable
+			}
+
+			return nil
+		}, retryable)
+		require.NoError(t, err)
+		require.Equal(t, 3, attempts)
+		require.Equal(t, 2, backoffs)
+	})
+
+	t.Run("exhaustion", func(t *testing.T) {
+		backoffs = 0
+
+		var attempts int
+		err := retry(context.Background(), func(context.Context) error {
+			attempts++
+			return errRetryable
+		}, retryable, withMaxAttempts(3))
+		require.ErrorIs(t, err, errRetryable)
+		require.Equal(t, 3, attempts)
+		require.Equal(t, 2, backoffs)
+	})
+
+	t.Run("non-retryable", func(t *testing.T) {
+		var attempts int
+		err := retry(context.Background(), func(context.Context) error {
+			attempts++
+			return errPermanent
+		}, retryable)
+		require.ErrorIs(t, err, errPermanent)
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var attempts int
+		err := retry(ctx, func(context.Context) error {
+			attempts++
+			if attempts == 2 {
+				cancel()
+			}
+
+			return errRetryable
+		})
+		require.ErrorIs(t, err, errRetryable)
+		require.Equal(t, 2, attempts)
+
+		err = retry(ctx, func(context.Context) error {
+			require.Fail(t, "attempt not expected after context closed")
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("attempt timeout", func(t *testing.T) {
+		var attempts int
+		err := retry(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+
+			return nil
+		}, withAttemptTimeout(time.Millisecond))
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+	})
+}
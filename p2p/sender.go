@@ -5,6 +5,8 @@ package p2p
 import (
 	"context"
 	"io"
+	"path"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/expbackoff"
 	"github.com/obolnetwork/charon/app/log"
 	"github.com/obolnetwork/charon/app/z"
 )
@@ -38,6 +41,7 @@ var (
 )
 
 type peerState struct {
+	mu      sync.Mutex
 	failing bool
 	buffer  []error
 }
@@ -46,15 +50,97 @@ type peerState struct {
 // It also provides log filtering for async sending, mitigating
 // error storms when peers are down.
 type Sender struct {
-	states sync.Map // map[peer.ID]peerState
+	states sync.Map // map[peer.ID]*peerState
+	prefs  sync.Map // map[protocolFamily][]protocol.ID
+	pools  sync.Map // map[protocol.ID]*streamPool
+
+	authKeys sync.Map // map[protocol.ID][]byte
+
+	allowedMu sync.RWMutex
+	allowed   map[peer.ID]bool // Nil allows all peers.
+}
+
+// SetAllowedPeers pins the set of peers (e.g. the cluster members) the sender is allowed to send to, refusing to send
+// to any other peer. This enforces strict peer identity, e.g. against a compromised relay substituting a peer.
+func (s *Sender) SetAllowedPeers(peerIDs ...peer.ID) {
+	allowed := make(map[peer.ID]bool)
+	for _, peerID := range peerIDs {
+		allowed[peerID] = true
+	}
+
+	s.allowedMu.Lock()
+	defer s.allowedMu.Unlock()
+
+	s.allowed = allowed
+}
+
+// verifyAllowed returns an error if the peer is not allowed, see SetAllowedPeers.
+func (s *Sender) verifyAllowed(peerID peer.ID) error {
+	s.allowedMu.RLock()
+	defer s.allowedMu.RUnlock()
+
+	if s.allowed != nil && !s.allowed[peerID] {
+		return errors.New("refusing to send to unexpected peer, not in allowed peers", z.Str("peer", PeerName(peerID)))
+	}
+
+	return nil
+}
+
+// protocolFamily is a protocol ID without its version, e.g. "/charon/parsigex" for "/charon/parsigex/2.0.0".
+type protocolFamily string
+
+// familyOf returns the protocol family of the protocol ID.
+func familyOf(pid protocol.ID) protocolFamily {
+	return protocolFamily(path.Dir(string(pid)))
+}
+
+// SetProtocolPreference sets the negotiation preference order of the protocol family of the provided protocols,
+// overriding the order provided to SendReceive. This allows operators rolling out a new protocol version to control
+// whether the new or old version is preferred during migration. Protocols not configured are tried last.
+func (s *Sender) SetProtocolPreference(pids ...protocol.ID) {
+	byFamily := make(map[protocolFamily][]protocol.ID)
+	for _, pid := range pids {
+		byFamily[familyOf(pid)] = append(byFamily[familyOf(pid)], pid)
+	}
+
+	for family, ordered := range byFamily {
+		s.prefs.Store(family, ordered)
+	}
+}
+
+// preferredOrder returns the protocols sorted by the configured preference order of their protocol family.
+func (s *Sender) preferredOrder(pids []protocol.ID) []protocol.ID {
+	rank := func(pid protocol.ID) int {
+		val, ok := s.prefs.Load(familyOf(pid))
+		if !ok {
+			return len(pids)
+		}
+
+		for i, pref := range val.([]protocol.ID) {
+			if pref == pid {
+				return i
+			}
+		}
+
+		return len(pids)
+	}
+
+	resp := append([]protocol.ID(nil), pids...)
+	sort.SliceStable(resp, func(i, j int) bool {
+		return rank(resp[i]) < rank(resp[j])
+	})
+
+	return resp
 }
 
 // addResult adds the result of sending a p2p message to the internal state and possibly logs a status change.
 func (s *Sender) addResult(ctx context.Context, peerID peer.ID, err error) {
-	var state peerState
-	if val, ok := s.states.Load(peerID); ok {
-		state = val.(peerState)
-	}
+	val, _ := s.states.LoadOrStore(peerID, new(peerState))
+	state := val.(*peerState)
+
+	// Hold the per-peer lock during the read-modify-write so concurrent results are not lost.
+	state.mu.Lock()
+	defer state.mu.Unlock()
 
 	state.buffer = append(state.buffer, err)
 	if len(state.buffer) > senderBuffer { // Trim buffer
@@ -78,31 +164,42 @@ func (s *Sender) addResult(ctx context.Context, peerID peer.ID, err error) {
 
 		if full && oldestFailure && othersSuccess {
 			state.failing = false
-			log.Info(ctx, "P2P sending recovered", z.Str("peer", PeerName(peerID)))
+			logInfo(ctx, LogSubsystemSender, "P2P sending recovered", z.Str("peer", PeerName(peerID)))
 		}
 	} else if failure && (len(state.buffer) == 1 || !state.failing) {
 		// First attempt failed or state changed to failing
 
 		if _, ok := dialErrMsgs(err); !ok { // Only log non-dial errors
-			log.Warn(ctx, "P2P sending failing", err, z.Str("peer", PeerName(peerID)))
+			logWarn(ctx, LogSubsystemSender, "P2P sending failing", err, z.Str("peer", PeerName(peerID)))
 		}
 
 		state.failing = true
 	}
-
-	s.states.Store(peerID, state) // Note there is a race if two results for the same peer is added at the same time, but this isn't critical.
 }
 
 // SendAsync returns nil and sends a libp2p message asynchronously.
+// It returns an error if the peer is not allowed, see SetAllowedPeers.
 // It logs results on state change (success to/from failure).
 // It implements SendFunc.
 func (s *Sender) SendAsync(parent context.Context, tcpNode host.Host, protoID protocol.ID, peerID peer.ID, msg proto.Message) error {
+	if err := s.verifyAllowed(peerID); err != nil {
+		return err
+	}
+
+	if key, ok := s.authKey(protoID); ok {
+		env, err := wrapAuth(key, tcpNode.ID(), msg)
+		if err != nil {
+			return err
+		}
+		msg = env
+	}
+
 	go func() {
 		// Clone the context since parent context may be closed soon.
 		ctx := log.CopyFields(context.Background(), parent)
 		ctx = log.WithCtx(ctx, z.Str("protocol", string(protoID)))
 
-		err := withRelayRetry(func() error {
+		err := withRelayRetry(ctx, func(ctx context.Context) error {
 			return Send(ctx, tcpNode, protoID, peerID, msg)
 		})
 		s.addResult(ctx, peerID, err)
@@ -111,35 +208,56 @@ func (s *Sender) SendAsync(parent context.Context, tcpNode host.Host, protoID pr
 	return nil
 }
 
-// SendReceive sends and receives a libp2p request and response message pair synchronously and then closes the stream.
+// SendReceive sends and receives a libp2p request and response message pair synchronously and then closes the stream,
+// or returns it to the stream pool if the protocol is pooled, see SetStreamPool.
 // The provided response proto will be populated if err is nil.
+// It returns an error if the peer is not allowed, see SetAllowedPeers.
 // It logs results on state change (success to/from failure).
 // It implements SendReceiveFunc.
 func (s *Sender) SendReceive(ctx context.Context, tcpNode host.Host, peerID peer.ID, req, resp proto.Message,
 	protocol protocol.ID, opts ...func(*sendRecvOpts),
 ) error {
-	err := withRelayRetry(func() error {
-		return SendReceive(ctx, tcpNode, peerID, req, resp, protocol, opts...)
+	if err := s.verifyAllowed(peerID); err != nil {
+		return err
+	}
+
+	opts = append([]func(*sendRecvOpts){}, opts...) // Copy the caller's options before appending.
+
+	if key, ok := s.authKey(protocol); ok {
+		opts = append(opts, WithSendReceiveAuthToken(key))
+	}
+
+	sendReceive := SendReceive
+	if pool, ok := s.pools.Load(protocol); ok {
+		sendReceive = pool.(*streamPool).SendReceive
+	} else {
+		opts = append(opts, withSendReceiveOrder(s.preferredOrder))
+	}
+
+	err := withRelayRetry(ctx, func(ctx context.Context) error {
+		return sendReceive(ctx, tcpNode, peerID, req, resp, protocol, opts...)
 	})
 	s.addResult(ctx, peerID, err)
 
 	return err
 }
 
-// withRelayRetry wraps a function and retries it once if the error is a relay error.
-func withRelayRetry(fn func() error) error {
-	err := fn()
-	if IsRelayError(err) { // Retry once if relay error
-		time.Sleep(time.Millisecond * 100)
-		err = fn()
-	}
-
-	return err
+// withRelayRetry wraps a function and retries it once after 100ms if the error is a relay error.
+func withRelayRetry(ctx context.Context, fn func(context.Context) error) error {
+	return retry(ctx, fn,
+		withMaxAttempts(2),
+		withRetryable(IsRelayError),
+		withBackoff(expbackoff.WithConfig(expbackoff.Config{BaseDelay: time.Millisecond * 100})),
+	)
 }
 
 type sendRecvOpts struct {
 	pids        []protocol.ID
+	order       func([]protocol.ID) []protocol.ID
 	rttCallback func(time.Duration)
+	recorder    *Recorder
+	forkDigest  *ForkDigest
+	authKey     []byte
 }
 
 // WithSendReceiveRTT returns an option for SendReceive that sets a callback for the RTT.
@@ -149,6 +267,14 @@ func WithSendReceiveRTT(callback func(time.Duration)) func(*sendRecvOpts) {
 	}
 }
 
+// WithSendReceiveRecorder returns an option for SendReceive that records the raw request
+// and response bytes of the stream, see Replay.
+func WithSendReceiveRecorder(recorder *Recorder) func(*sendRecvOpts) {
+	return func(opts *sendRecvOpts) {
+		opts.recorder = recorder
+	}
+}
+
 // WithSendReceiveProtocols returns an option for SendReceive that sets the protocols to use.
 // Note this overrides the protocol provided in the SendReceive.
 func WithSendReceiveProtocols(pids ...protocol.ID) func(*sendRecvOpts) {
@@ -157,6 +283,14 @@ func WithSendReceiveProtocols(pids ...protocol.ID) func(*sendRecvOpts) {
 	}
 }
 
+// withSendReceiveOrder returns an option for SendReceive that sorts the protocols to use
+// in the order of negotiation preference.
+func withSendReceiveOrder(order func([]protocol.ID) []protocol.ID) func(*sendRecvOpts) {
+	return func(opts *sendRecvOpts) {
+		opts.order = order
+	}
+}
+
 // SendReceive sends and receives a libp2p request and response message
 // pair synchronously and then closes the stream.
 // The provided response proto will be populated if err is nil.
@@ -171,8 +305,26 @@ func SendReceive(ctx context.Context, tcpNode host.Host, peerID peer.ID,
 	for _, opt := range opts {
 		opt(&o)
 	}
+	if o.order != nil {
+		o.pids = o.order(o.pids)
+	}
+	if o.forkDigest != nil {
+		var forked []protocol.ID // Copy to not mutate the provided protocols.
+		for _, pid := range o.pids {
+			forked = append(forked, ForkProtocolID(pid, *o.forkDigest))
+		}
+		o.pids = forked
+	}
 	ctx = log.WithCtx(ctx, z.Any("protocol", o.pids))
 
+	if o.authKey != nil {
+		env, err := wrapAuth(o.authKey, tcpNode.ID(), req)
+		if err != nil {
+			return err
+		}
+		req = env
+	}
+
 	b, err := proto.Marshal(req)
 	if err != nil {
 		return errors.Wrap(err, "marshal proto")
@@ -184,6 +336,11 @@ func SendReceive(ctx context.Context, tcpNode host.Host, peerID peer.ID,
 		return errors.Wrap(err, "new stream", z.Any("protocols", o.pids))
 	}
 
+	if err := circuitBudgets.Reserve(tcpNode, s.Conn(), len(b)); err != nil {
+		_ = s.Reset()
+		return err
+	}
+
 	t0 := time.Now()
 	if _, err = s.Write(b); err != nil {
 		return errors.Wrap(err, "write request")
@@ -196,10 +353,16 @@ func SendReceive(ctx context.Context, tcpNode host.Host, peerID peer.ID,
 	name := PeerName(peerID)
 	networkTXCounter.WithLabelValues(name, string(s.Protocol())).Add(float64(len(b)))
 
+	reqBytes := b
+
 	b, err = io.ReadAll(s)
 	if err != nil {
 		return errors.Wrap(err, "read response")
-	} else if len(b) == 0 {
+	}
+
+	record(ctx, LogSubsystemSender, o.recorder, s, reqBytes, b)
+
+	if len(b) == 0 {
 		return errors.New("peer errored, no response")
 	}
 
@@ -231,6 +394,11 @@ func Send(ctx context.Context, tcpNode host.Host, protoID protocol.ID, peerID pe
 		return errors.Wrap(err, "tcpNode stream")
 	}
 
+	if err := circuitBudgets.Reserve(tcpNode, s.Conn(), len(b)); err != nil {
+		_ = s.Reset()
+		return err
+	}
+
 	_, err = s.Write(b)
 	if err != nil {
 		return errors.Wrap(err, "tcpNode write")
@@ -3,7 +3,11 @@
 package p2p
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/binary"
+	"io"
 	"sync"
 	"testing"
 	"time"
@@ -11,10 +15,14 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/obolnetwork/charon/app/errors"
+	pbv1 "github.com/obolnetwork/charon/core/corepb/v1"
+	charontestutil "github.com/obolnetwork/charon/testutil"
 )
 
 func TestSenderAddResult(t *testing.T) {
@@ -25,11 +33,14 @@ func TestSenderAddResult(t *testing.T) {
 
 	assertFailing := func(t *testing.T, expect bool) {
 		t.Helper()
-		var state peerState
+		var failing bool
 		if val, ok := sender.states.Load(peerID); ok {
-			state = val.(peerState)
+			state := val.(*peerState)
+			state.mu.Lock()
+			failing = state.failing
+			state.mu.Unlock()
 		}
-		require.Equal(t, expect, state.failing)
+		require.Equal(t, expect, failing)
 	}
 
 	add := func(result error) {
@@ -60,6 +71,40 @@ func TestSenderAddResult(t *testing.T) {
 	// INFO P2P sending failing {"peer": "better-week"}
 }
 
+func TestSenderAddResultConcurrent(t *testing.T) {
+	sender := new(Sender)
+	peerID := peer.ID("test")
+	failure := errors.New("failure")
+
+	const n = 100
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var err error
+			if i%2 == 0 {
+				err = failure
+			}
+			sender.addResult(context.Background(), peerID, err)
+		}(i)
+	}
+	wg.Wait()
+
+	val, ok := sender.states.Load(peerID)
+	require.True(t, ok)
+	state := val.(*peerState)
+	require.Len(t, state.buffer, senderBuffer)
+
+	// A final run of failures always results in failing state.
+	for i := 0; i < senderBuffer; i++ {
+		sender.addResult(context.Background(), peerID, failure)
+	}
+	require.True(t, state.failing)
+}
+
 func TestSenderRetry(t *testing.T) {
 	sender := new(Sender)
 	ctx := context.Background()
@@ -77,6 +122,233 @@ func TestSenderRetry(t *testing.T) {
 	}, time.Second, time.Millisecond)
 }
 
+func TestSenderAllowedPeers(t *testing.T) {
+	sender := new(Sender)
+	ctx := context.Background()
+
+	allowed, disallowed := peer.ID("allowed"), peer.ID("disallowed")
+	sender.SetAllowedPeers(allowed)
+
+	// Sending to the allowed peer opens streams.
+	h := new(testHost)
+	err := sender.SendReceive(ctx, h, allowed, nil, nil, "")
+	require.ErrorIs(t, err, network.ErrReset)
+	require.Equal(t, 2, h.Count())
+
+	// Sending to a disallowed peer is refused without opening streams.
+	h = new(testHost)
+	err = sender.SendReceive(ctx, h, disallowed, nil, nil, "")
+	require.ErrorContains(t, err, "refusing to send to unexpected peer")
+
+	err = sender.SendAsync(ctx, h, "", disallowed, nil)
+	require.ErrorContains(t, err, "refusing to send to unexpected peer")
+	require.Zero(t, h.Count())
+}
+
+func TestSenderStreamPool(t *testing.T) {
+	var (
+		protocolID = protocol.ID("test-stream-pool")
+		ctx        = context.Background()
+		server     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+		client     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+	)
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	RegisterHandler("server", server, protocolID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(_ context.Context, _ peer.ID, req proto.Message) (proto.Message, bool, error) {
+			duty := req.(*pbv1.Duty)
+			if duty.Slot == 0 {
+				return nil, false, nil
+			}
+
+			return &pbv1.Duty{Slot: duty.Slot + 1}, true, nil
+		},
+		WithDelimitedMessages(),
+	)
+
+	sender := new(Sender)
+	require.NoError(t, sender.SetStreamPool(time.Second*30, protocolID))
+	h := &countingHost{Host: client}
+
+	sendReceive := func(t *testing.T, slot int64) error {
+		t.Helper()
+
+		resp := new(pbv1.Duty)
+		err := sender.SendReceive(ctx, h, server.ID(), &pbv1.Duty{Slot: slot}, resp, protocolID)
+		if err == nil {
+			require.Equal(t, slot+1, resp.Slot)
+		}
+
+		return err
+	}
+
+	// Repeated requests reuse the same stream.
+	for slot := int64(1); slot <= 3; slot++ {
+		require.NoError(t, sendReceive(t, slot))
+	}
+	require.Equal(t, 1, h.Count())
+
+	// Requests without responses also reuse the stream.
+	require.ErrorContains(t, sendReceive(t, 0), "peer errored, no response")
+	require.NoError(t, sendReceive(t, 4))
+	require.Equal(t, 1, h.Count())
+
+	// Reset streams are evicted on error and replaced when retrying.
+	val, ok := sender.pools.Load(protocolID)
+	require.True(t, ok)
+	pool := val.(*streamPool)
+	ps := pool.take(poolKey{peerID: server.ID(), pid: protocolID})
+	require.NotNil(t, ps)
+	require.NoError(t, ps.stream.Reset())
+	pool.put(poolKey{peerID: server.ID(), pid: protocolID}, ps)
+
+	require.NoError(t, sendReceive(t, 5))
+	require.Equal(t, 2, h.Count())
+	require.NoError(t, sendReceive(t, 6))
+	require.Equal(t, 2, h.Count())
+
+	// Requests on pooled streams closed by the peer are retried on a new stream by the pool itself.
+	for _, conn := range server.Network().ConnsToPeer(client.ID()) {
+		for _, s := range conn.GetStreams() {
+			if s.Protocol() == protocolID {
+				require.NoError(t, s.Close())
+			}
+		}
+	}
+
+	resp := new(pbv1.Duty)
+	require.NoError(t, pool.SendReceive(ctx, h, server.ID(), &pbv1.Duty{Slot: 7}, resp, protocolID))
+	require.EqualValues(t, 8, resp.Slot)
+	require.Equal(t, 3, h.Count())
+
+	// Idle streams are evicted.
+	require.NoError(t, sender.SetStreamPool(time.Millisecond, protocolID))
+	require.NoError(t, sendReceive(t, 7))
+	require.Equal(t, 4, h.Count())
+
+	val, ok = sender.pools.Load(protocolID)
+	require.True(t, ok)
+	pool = val.(*streamPool)
+	require.Eventually(t, func() bool {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+
+		return len(pool.idle) == 0
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, sendReceive(t, 8))
+	require.Equal(t, 5, h.Count())
+}
+
+func TestReadDelimited(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeDelimited(&buf, []byte("first")))
+	require.NoError(t, writeDelimited(&buf, nil))
+
+	r := bufio.NewReader(&buf)
+	b, err := readDelimited(r)
+	require.NoError(t, err)
+	require.Equal(t, []byte("first"), b)
+
+	b, err = readDelimited(r)
+	require.NoError(t, err)
+	require.Empty(t, b)
+
+	_, err = readDelimited(r)
+	require.ErrorIs(t, err, io.EOF)
+
+	// Messages shorter than their prefixed size are truncated, even if the size is large.
+	prefix := binary.AppendUvarint(nil, maxDelimitedSize)
+	_, err = readDelimited(bufio.NewReader(bytes.NewReader(append(prefix, "short"...))))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// Messages larger than the maximum size are rejected.
+	prefix = binary.AppendUvarint(nil, maxDelimitedSize+1)
+	_, err = readDelimited(bufio.NewReader(bytes.NewReader(prefix)))
+	require.ErrorContains(t, err, "delimited message too large")
+}
+
+func TestSenderStreamPoolIdleTimeout(t *testing.T) {
+	sender := new(Sender)
+
+	// Pooled streams must be evicted before handlers close them.
+	for _, timeout := range []time.Duration{0, delimitedIdleTimeout, time.Hour} {
+		err := sender.SetStreamPool(timeout, "test-stream-pool")
+		require.ErrorContains(t, err, "stream pool idle timeout must be shorter than the delimited handler idle timeout")
+	}
+
+	_, ok := sender.pools.Load(protocol.ID("test-stream-pool"))
+	require.False(t, ok)
+
+	require.NoError(t, sender.SetStreamPool(delimitedIdleTimeout-time.Second, "test-stream-pool"))
+}
+
+func TestSenderStreamPoolWorkerPool(t *testing.T) {
+	var (
+		protocolID = protocol.ID("test-stream-pool-workers")
+		ctx        = context.Background()
+		server     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+		client     = charontestutil.CreateHost(t, charontestutil.AvailableAddr(t))
+	)
+
+	client.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+
+	RegisterHandler("server", server, protocolID,
+		func() proto.Message { return new(pbv1.Duty) },
+		func(_ context.Context, _ peer.ID, req proto.Message) (proto.Message, bool, error) {
+			return req, true, nil
+		},
+		WithDelimitedMessages(),
+		WithWorkerPool(ctx, 1, 1),
+	)
+
+	sendReceive := func(sender *Sender) error {
+		ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+		defer cancel()
+
+		return sender.SendReceive(ctx, client, server.ID(), &pbv1.Duty{Slot: 1}, new(pbv1.Duty), protocolID)
+	}
+
+	// Senders pool separate streams, both idle after their first request.
+	var senders []*Sender
+	for i := 0; i < 2; i++ {
+		sender := new(Sender)
+		require.NoError(t, sender.SetStreamPool(time.Second*30, protocolID))
+		senders = append(senders, sender)
+	}
+
+	// The single worker isn't occupied by idle pooled streams, so requests on all streams are processed.
+	for i := 0; i < 3; i++ {
+		for _, sender := range senders {
+			require.NoError(t, sendReceive(sender))
+		}
+	}
+}
+
+// countingHost wraps a host counting opened streams.
+type countingHost struct {
+	host.Host
+	mu    sync.Mutex
+	count int
+}
+
+func (h *countingHost) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.count
+}
+
+func (h *countingHost) NewStream(ctx context.Context, p peer.ID, pids ...protocol.ID) (network.Stream, error) {
+	h.mu.Lock()
+	h.count++
+	h.mu.Unlock()
+
+	return h.Host.NewStream(ctx, p, pids...)
+}
+
 type testHost struct {
 	host.Host
 	mu    sync.Mutex
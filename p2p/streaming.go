@@ -0,0 +1,238 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/log"
+	"github.com/obolnetwork/charon/app/z"
+)
+
+// streamingTimeout is the timeout of reading and handling a streaming request.
+const streamingTimeout = time.Second * 5
+
+// StreamingHandlerFunc abstracts the handler logic that processes a p2p received proto message
+// and streams zero or more responses back to the peer via the send function before returning.
+type StreamingHandlerFunc func(ctx context.Context, peerID peer.ID, req proto.Message, send func(proto.Message) error) error
+
+// RegisterStreamingHandler registers a canonical proto request and streaming response handler for the provided protocol.
+// - The zeroReq function returns a zero request to unmarshal.
+// - A single length-delimited request is read per stream.
+// - The handlerFunc is called with the unmarshalled request and sends length-delimited responses as they become available.
+// - The stream is closed when the handlerFunc returns, signalling the end of the responses.
+// - Unauthenticated requests are rejected if an auth token is configured.
+// - The request and length-delimited response bytes are recorded if a recorder is configured.
+// - The streams are processed by a worker pool if configured.
+// Note that WithDelimitedMessages doesn't apply since streaming messages are always length-delimited.
+func RegisterStreamingHandler(logTopic string, tcpNode host.Host, protocol protocol.ID,
+	zeroReq func() proto.Message, handlerFunc StreamingHandlerFunc, opts ...func(*registerHandlerOpts),
+) {
+	var o registerHandlerOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.forkDigest != nil {
+		protocol = ForkProtocolID(protocol, *o.forkDigest)
+	}
+
+	handle := func(s network.Stream) {
+		t0 := time.Now()
+		name := PeerName(s.Conn().RemotePeer())
+
+		_ = s.SetDeadline(time.Now().Add(streamingTimeout))
+		ctx, cancel := context.WithTimeout(context.Background(), streamingTimeout)
+		ctx = log.WithTopic(ctx, logTopic)
+		ctx = log.WithCtx(ctx,
+			z.Str("peer", name),
+			z.Str("protocol", string(protocol)),
+		)
+		defer cancel()
+		defer s.Close()
+
+		b, err := readDelimited(bufio.NewReader(s))
+		if IsRelayError(err) {
+			return // Ignore relay errors.
+		} else if err != nil {
+			logError(ctx, LogSubsystemReceive, "LibP2P read streaming request", err, z.Any("duration", time.Since(t0)))
+			return
+		}
+
+		if o.authKey != nil {
+			payload, err := unwrapAuth(o.authKey, s.Conn().RemotePeer(), b)
+			if err != nil {
+				handlerUnauthenticated.WithLabelValues(string(protocol)).Inc()
+				logWarn(ctx, LogSubsystemReceive, "LibP2P rejecting unauthenticated request", err)
+
+				return
+			}
+			b = payload
+		}
+
+		req := zeroReq()
+		if err := proto.Unmarshal(b, req); err != nil {
+			logError(ctx, LogSubsystemReceive, "LibP2P unmarshal request", err,
+				z.I64("bytes", int64(len(b))),
+				z.Hex("payload_prefix", payloadPrefix(b)),
+			)
+
+			return
+		}
+
+		networkRXCounter.WithLabelValues(name, string(s.Protocol())).Add(float64(len(b)))
+		networkRXSizeBytes.WithLabelValues(string(s.Protocol())).Observe(float64(len(b)))
+
+		reqBytes := b
+		var respBytes bytes.Buffer // Length-delimited responses, only populated if recording.
+
+		send := func(resp proto.Message) error {
+			b, err := proto.Marshal(resp)
+			if err != nil {
+				return errors.Wrap(err, "marshal response")
+			}
+
+			if err := circuitBudgets.Reserve(tcpNode, s.Conn(), len(b)); err != nil {
+				return err
+			}
+
+			// Observe the response size before writing it, so it is observed by the time the peer received it.
+			networkTXSizeBytes.WithLabelValues(string(s.Protocol())).Observe(float64(len(b)))
+
+			if err := writeDelimited(s, b); err != nil {
+				return errors.Wrap(err, "write response")
+			}
+
+			networkTXCounter.WithLabelValues(name, string(s.Protocol())).Add(float64(len(b)))
+
+			if o.recorder != nil {
+				_ = writeDelimited(&respBytes, b)
+			}
+
+			return nil
+		}
+
+		err = handlerFunc(ctx, s.Conn().RemotePeer(), req, send)
+		record(ctx, LogSubsystemReceive, o.recorder, s, reqBytes, respBytes.Bytes())
+		if IsRelayError(err) {
+			return // Ignore relay errors.
+		} else if err != nil {
+			logError(ctx, LogSubsystemReceive, "LibP2P handle stream error", err, z.Any("duration", time.Since(t0)))
+		}
+	}
+
+	if o.workers > 0 {
+		handle = newWorkerPool(o.poolCtx, logTopic, protocol, o.workers, o.queueSize).Handler(handle)
+	}
+
+	tcpNode.SetStreamHandler(protocol, handle)
+}
+
+// SendReceiveStreaming sends a libp2p request and calls the receive function with each streamed response
+// as soon as it is received, until the peer closes the stream. Responses are unmarshalled into new messages
+// returned by zeroResp. It returns the first error of the receive function, which also aborts the stream.
+// It supports the WithSendReceiveForkDigest, WithSendReceiveAuthToken and WithSendReceiveRecorder options.
+func SendReceiveStreaming(ctx context.Context, tcpNode host.Host, peerID peer.ID, req proto.Message,
+	zeroResp func() proto.Message, recvFunc func(proto.Message) error, pID protocol.ID, opts ...func(*sendRecvOpts),
+) error {
+	var o sendRecvOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.forkDigest != nil {
+		pID = ForkProtocolID(pID, *o.forkDigest)
+	}
+	ctx = log.WithCtx(ctx, z.Str("protocol", string(pID)))
+
+	if o.authKey != nil {
+		env, err := wrapAuth(o.authKey, tcpNode.ID(), req)
+		if err != nil {
+			return err
+		}
+		req = env
+	}
+
+	b, err := proto.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "marshal proto")
+	}
+
+	// Circuit relay connections are transient
+	s, err := tcpNode.NewStream(network.WithUseTransient(ctx, ""), peerID, pID)
+	if err != nil {
+		return errors.Wrap(err, "new stream", z.Str("protocol", string(pID)))
+	}
+
+	deadline, _ := ctx.Deadline() // Zero deadline if none.
+	if err := s.SetDeadline(deadline); err != nil {
+		_ = s.Reset()
+		return errors.Wrap(err, "set deadline")
+	}
+
+	if err := circuitBudgets.Reserve(tcpNode, s.Conn(), len(b)); err != nil {
+		_ = s.Reset()
+		return err
+	}
+
+	if err := writeDelimited(s, b); err != nil {
+		_ = s.Reset()
+		return errors.Wrap(err, "write request")
+	}
+
+	if err := s.CloseWrite(); err != nil {
+		_ = s.Reset()
+		return errors.Wrap(err, "close write")
+	}
+
+	name := PeerName(peerID)
+	networkTXCounter.WithLabelValues(name, string(s.Protocol())).Add(float64(len(b)))
+
+	reqBytes := b
+	var respBytes bytes.Buffer // Length-delimited responses, only populated if recording.
+
+	r := bufio.NewReader(s)
+	for {
+		b, err := readDelimited(r)
+		if errors.Is(err, io.EOF) {
+			break // Stream closed by peer, all responses received.
+		} else if err != nil {
+			_ = s.Reset()
+			return errors.Wrap(err, "read response")
+		}
+
+		networkRXCounter.WithLabelValues(name, string(s.Protocol())).Add(float64(len(b)))
+
+		if o.recorder != nil {
+			_ = writeDelimited(&respBytes, b)
+		}
+
+		resp := zeroResp()
+		if err := proto.Unmarshal(b, resp); err != nil {
+			_ = s.Reset()
+			return errors.Wrap(err, "unmarshal response")
+		}
+
+		if err := recvFunc(resp); err != nil {
+			_ = s.Reset()
+			return err
+		}
+	}
+
+	record(ctx, LogSubsystemSender, o.recorder, s, reqBytes, respBytes.Bytes())
+
+	if err := s.Close(); err != nil {
+		return errors.Wrap(err, "close stream")
+	}
+
+	return nil
+}
@@ -0,0 +1,369 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package p2p
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/log"
+	"github.com/obolnetwork/charon/app/z"
+)
+
+const (
+	// delimitedIdleTimeout is the duration after which handlers close idle delimited streams.
+	// Sender stream pool idle timeouts must be shorter.
+	delimitedIdleTimeout = time.Minute
+	// delimitedMsgTimeout is the timeout of reading and handling a delimited request once it started.
+	delimitedMsgTimeout = time.Second * 5
+	// maxDelimitedSize is the maximum size of a length-delimited message.
+	maxDelimitedSize = 128 << 20
+)
+
+// writeDelimited writes the uvarint length-prefixed message.
+func writeDelimited(w io.Writer, b []byte) error {
+	buf := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(b)), uint64(len(b)))
+	_, err := w.Write(append(buf, b...))
+
+	return err
+}
+
+// readDelimited reads a uvarint length-prefixed message. The message buffer grows as bytes are received,
+// rather than allocating the prefixed size upfront, so peers cannot force large allocations for free.
+func readDelimited(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	} else if size > maxDelimitedSize {
+		return nil, errors.New("delimited message too large", z.U64("size", size))
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r, int64(size)))
+	if err != nil {
+		return nil, err
+	} else if uint64(len(b)) < size {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return b, nil
+}
+
+// delimitedHandler returns a stream handler that processes length-delimited requests until the stream is closed,
+// idle or errors, responding to each with a length-delimited response, empty if there is no response.
+// The requests are processed by the worker pool if not nil, so idle streams don't occupy workers.
+func delimitedHandler(logTopic string, tcpNode host.Host, protocol protocol.ID, pool *workerPool,
+	process func(context.Context, network.Stream, time.Time, []byte) ([]byte, bool),
+) network.StreamHandler {
+	return func(s network.Stream) {
+		name := PeerName(s.Conn().RemotePeer())
+		ctx := log.WithTopic(context.Background(), logTopic)
+		ctx = log.WithCtx(ctx,
+			z.Str("peer", name),
+			z.Str("protocol", string(protocol)),
+		)
+		defer s.Close()
+
+		r := bufio.NewReader(s)
+		for {
+			_ = s.SetReadDeadline(time.Now().Add(delimitedIdleTimeout))
+
+			b, err := readDelimited(r)
+			if errors.Is(err, io.EOF) || IsRelayError(err) {
+				return // Stream closed by sender or relay errors.
+			} else if netErr := net.Error(nil); errors.As(err, &netErr) && netErr.Timeout() {
+				return // Stream idle.
+			} else if err != nil {
+				logError(ctx, LogSubsystemReceive, "LibP2P read delimited request", err)
+				return
+			}
+
+			resp, ok := processDelimited(ctx, s, pool, b, process)
+			if !ok {
+				_ = s.Reset() // Request shed or worker pool stopped.
+				return
+			}
+
+			if err := circuitBudgets.Reserve(tcpNode, s.Conn(), len(resp)); err != nil {
+				logError(ctx, LogSubsystemReceive, "LibP2P delimited response exceeds relay circuit data limit", err)
+				_ = s.Reset()
+
+				return
+			}
+
+			if len(resp) > 0 {
+				// Observe the response size before writing it, so it is observed by the time the peer received it.
+				networkTXSizeBytes.WithLabelValues(string(s.Protocol())).Observe(float64(len(resp)))
+			}
+
+			if err := writeDelimited(s, resp); IsRelayError(err) {
+				return // Ignore relay errors.
+			} else if err != nil {
+				logError(ctx, LogSubsystemReceive, "LibP2P write delimited response", err)
+				return
+			}
+
+			if len(resp) > 0 {
+				networkTXCounter.WithLabelValues(name, string(s.Protocol())).Add(float64(len(resp)))
+			}
+		}
+	}
+}
+
+// processDelimited processes the delimited request within delimitedMsgTimeout by a worker of the pool if not nil
+// and returns the response, empty if there is no response. It returns false if the request was shed
+// or the pool was stopped.
+func processDelimited(ctx context.Context, s network.Stream, pool *workerPool, req []byte,
+	process func(context.Context, network.Stream, time.Time, []byte) ([]byte, bool),
+) ([]byte, bool) {
+	t0 := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, delimitedMsgTimeout)
+	defer cancel()
+
+	if pool == nil {
+		resp, _ := process(ctx, s, t0, req)
+		return resp, true
+	}
+
+	result := make(chan []byte, 1)
+	run := func() {
+		resp, _ := process(ctx, s, t0, req)
+		result <- resp
+	}
+	if !pool.Queue(s, run, func() { close(result) }) {
+		return nil, false
+	}
+
+	resp, ok := <-result
+
+	return resp, ok
+}
+
+// SetStreamPool enables reusing streams of the protocols for multiple SendReceive requests to the same peer,
+// avoiding the overhead of opening a stream per request of chatty protocols. Idle streams are closed after
+// idleTimeout. The protocols must be registered with WithDelimitedMessages by all peers.
+// Pooled requests do not support protocol negotiation. It returns an error if the idle timeout
+// isn't positive or not shorter than delimitedIdleTimeout, since handlers close streams idle for longer,
+// so requests sent on such streams would fail.
+func (s *Sender) SetStreamPool(idleTimeout time.Duration, pids ...protocol.ID) error {
+	if idleTimeout <= 0 || idleTimeout >= delimitedIdleTimeout {
+		return errors.New("stream pool idle timeout must be shorter than the delimited handler idle timeout",
+			z.Any("idle_timeout", idleTimeout),
+			z.Any("handler_idle_timeout", delimitedIdleTimeout),
+		)
+	}
+
+	pool := newStreamPool(idleTimeout)
+	for _, pid := range pids {
+		s.pools.Store(pid, pool)
+	}
+
+	return nil
+}
+
+// newStreamPool returns a new empty stream pool.
+func newStreamPool(idleTimeout time.Duration) *streamPool {
+	return &streamPool{
+		idleTimeout: idleTimeout,
+		idle:        make(map[poolKey][]*pooledStream),
+	}
+}
+
+// poolKey identifies pooled streams.
+type poolKey struct {
+	peerID peer.ID
+	pid    protocol.ID
+}
+
+// pooledStream is an idle or in-use pooled stream.
+type pooledStream struct {
+	stream network.Stream
+	reader *bufio.Reader
+	timer  *time.Timer
+}
+
+// streamPool pools idle delimited streams by peer and protocol, closing streams idle for longer than idleTimeout.
+type streamPool struct {
+	idleTimeout time.Duration
+
+	mu   sync.Mutex
+	idle map[poolKey][]*pooledStream
+}
+
+// take returns an idle stream of the key or nil if none is available.
+func (p *streamPool) take(key poolKey) *pooledStream {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	streams := p.idle[key]
+	if len(streams) == 0 {
+		return nil
+	}
+
+	ps := streams[len(streams)-1]
+	p.idle[key] = streams[:len(streams)-1]
+	ps.timer.Stop()
+
+	return ps
+}
+
+// put returns the stream to the pool, closing it if idle for longer than the idle timeout.
+func (p *streamPool) put(key poolKey, ps *pooledStream) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.idle[key] = append(p.idle[key], ps)
+	ps.timer = time.AfterFunc(p.idleTimeout, func() { p.evict(key, ps) })
+}
+
+// evict removes the idle stream from the pool and closes it, unless it was taken since.
+func (p *streamPool) evict(key poolKey, ps *pooledStream) {
+	p.mu.Lock()
+	var found bool
+	streams := p.idle[key]
+	for i, other := range streams {
+		if other == ps {
+			p.idle[key] = append(streams[:i], streams[i+1:]...)
+			found = true
+
+			break
+		}
+	}
+	if len(p.idle[key]) == 0 {
+		delete(p.idle, key)
+	}
+	p.mu.Unlock()
+
+	if found {
+		_ = ps.stream.Close()
+	}
+}
+
+// SendReceive sends the request and receives the response on an idle pooled stream or on a new stream
+// which is pooled afterwards. Streams are reset and not pooled on error. Requests failing on stale pooled streams,
+// i.e. when writing the request or when the stream is closed before the response, are retried once on a new stream.
+func (p *streamPool) SendReceive(ctx context.Context, tcpNode host.Host, peerID peer.ID,
+	req, resp proto.Message, pID protocol.ID, opts ...func(*sendRecvOpts),
+) error {
+	o := sendRecvOpts{
+		rttCallback: func(time.Duration) {},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.forkDigest != nil {
+		pID = ForkProtocolID(pID, *o.forkDigest)
+	}
+	ctx = log.WithCtx(ctx, z.Str("protocol", string(pID)))
+
+	if o.authKey != nil {
+		env, err := wrapAuth(o.authKey, tcpNode.ID(), req)
+		if err != nil {
+			return err
+		}
+		req = env
+	}
+
+	b, err := proto.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "marshal proto")
+	}
+
+	key := poolKey{peerID: peerID, pid: pID}
+	ps := p.take(key)
+	pooled := ps != nil
+
+	var (
+		t0        time.Time
+		respBytes []byte
+	)
+	for {
+		if ps == nil {
+			// Circuit relay connections are transient
+			s, err := tcpNode.NewStream(network.WithUseTransient(ctx, ""), peerID, pID)
+			if err != nil {
+				return errors.Wrap(err, "new stream", z.Str("protocol", string(pID)))
+			}
+			ps = &pooledStream{stream: s, reader: bufio.NewReader(s)}
+		}
+
+		if err := circuitBudgets.Reserve(tcpNode, ps.stream.Conn(), len(b)); err != nil {
+			p.put(key, ps) // Nothing was written, so the stream remains usable.
+			return err
+		}
+
+		t0 = time.Now()
+		var stale bool
+		respBytes, stale, err = roundTrip(ctx, ps, b)
+		if err != nil {
+			_ = ps.stream.Reset()
+			if pooled && stale {
+				// The pooled stream was closed by the peer or a relay before the request was processed,
+				// so retry once on a new stream.
+				ps, pooled = nil, false
+				continue
+			}
+
+			return err
+		}
+
+		break
+	}
+
+	p.put(key, ps)
+
+	name := PeerName(peerID)
+	networkTXCounter.WithLabelValues(name, string(pID)).Add(float64(len(b)))
+
+	record(ctx, LogSubsystemSender, o.recorder, ps.stream, b, respBytes)
+
+	if len(respBytes) == 0 {
+		return errors.New("peer errored, no response")
+	}
+
+	if err = proto.Unmarshal(respBytes, resp); err != nil {
+		return errors.Wrap(err, "unmarshal response")
+	}
+
+	o.rttCallback(time.Since(t0))
+
+	networkRXCounter.WithLabelValues(name, string(pID)).Add(float64(len(respBytes)))
+
+	return nil
+}
+
+// roundTrip writes the delimited request to the pooled stream and returns the delimited response,
+// within the context deadline if any. It also returns true if the stream is stale, i.e. if writing the request failed
+// or the stream was closed before the response, in which case the request wasn't processed by the peer.
+func roundTrip(ctx context.Context, ps *pooledStream, req []byte) ([]byte, bool, error) {
+	deadline, _ := ctx.Deadline() // Zero deadline if none.
+	if err := ps.stream.SetDeadline(deadline); err != nil {
+		return nil, false, errors.Wrap(err, "set deadline")
+	}
+
+	if err := writeDelimited(ps.stream, req); err != nil {
+		return nil, true, errors.Wrap(err, "write request")
+	}
+
+	resp, err := readDelimited(ps.reader)
+	if err != nil {
+		return nil, errors.Is(err, io.EOF), errors.Wrap(err, "read response")
+	}
+
+	if err := ps.stream.SetDeadline(time.Time{}); err != nil {
+		return nil, false, errors.Wrap(err, "clear deadline")
+	}
+
+	return resp, false, nil
+}
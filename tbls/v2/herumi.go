@@ -36,6 +36,11 @@ func init() {
 // Herumi is an Implementation with Herumi-specific inner logic.
 type Herumi struct{}
 
+// Name returns the identifier of the implementation.
+func (Herumi) Name() string {
+	return "herumi"
+}
+
 func (Herumi) GenerateSecretKey() (PrivateKey, error) {
 	var p bls.SecretKey
 	p.SetByCSPRNG()
@@ -63,11 +68,16 @@ func (Herumi) SecretToPublicKey(secret PrivateKey) (PublicKey, error) {
 	return *(*PublicKey)(pubk.Serialize()), nil
 }
 
-func (Herumi) ThresholdSplit(secret PrivateKey, total uint, threshold uint) (map[int]PrivateKey, error) {
+func (h Herumi) ThresholdSplit(secret PrivateKey, total uint, threshold uint) (map[int]PrivateKey, error) {
+	shares, _, err := h.ThresholdSplitWithCommitments(secret, total, threshold)
+	return shares, err
+}
+
+func (Herumi) ThresholdSplitWithCommitments(secret PrivateKey, total uint, threshold uint) (map[int]PrivateKey, []PublicKey, error) {
 	var p bls.SecretKey
 
 	if err := p.Deserialize(secret[:]); err != nil {
-		return nil, errors.Wrap(err, "cannot unmarshal bytes into Herumi secret key")
+		return nil, nil, errors.Wrap(err, "cannot unmarshal bytes into Herumi secret key")
 	}
 
 	// master key Polynomial
@@ -88,7 +98,7 @@ func (Herumi) ThresholdSplit(secret PrivateKey, total uint, threshold uint) (map
 
 		err := blsID.SetDecString(fmt.Sprintf("%d", i))
 		if err != nil {
-			return nil, errors.Wrap(
+			return nil, nil, errors.Wrap(
 				err,
 				"cannot set ID",
 				z.Int("id_number", i),
@@ -100,13 +110,19 @@ func (Herumi) ThresholdSplit(secret PrivateKey, total uint, threshold uint) (map
 
 		err = sk.Set(poly, &blsID)
 		if err != nil {
-			return nil, errors.Wrap(err, "cannot set ID on polynomial", z.Int("id_number", i))
+			return nil, nil, errors.Wrap(err, "cannot set ID on polynomial", z.Int("id_number", i))
 		}
 
 		ret[i] = *(*PrivateKey)(sk.Serialize())
 	}
 
-	return ret, nil
+	// Commitments are the public keys of the polynomial coefficients.
+	var commitments []PublicKey
+	for _, coefficient := range poly {
+		commitments = append(commitments, *(*PublicKey)(coefficient.GetPublicKey().Serialize()))
+	}
+
+	return ret, commitments, nil
 }
 
 func (Herumi) RecoverSecret(shares map[int]PrivateKey, _, _ uint) (PrivateKey, error) {
@@ -232,6 +248,57 @@ func (Herumi) Verify(compressedPublicKey PublicKey, data []byte, rawSignature Si
 	return nil
 }
 
+// BatchVerify verifies the batch with a single randomised multi-pairing check if all data are 32 byte
+// signing roots, since herumi only supports batches of these, otherwise it verifies each signature.
+// It also verifies each signature if built with the race detector, see multiVerifyEnabled.
+func (h Herumi) BatchVerify(compressedPublicKeys []PublicKey, data [][]byte, signatures []Signature) error {
+	if len(compressedPublicKeys) != len(signatures) || len(data) != len(signatures) {
+		return errors.New("batch length mismatch",
+			z.Int("pubkeys", len(compressedPublicKeys)),
+			z.Int("data", len(data)),
+			z.Int("signatures", len(signatures)),
+		)
+	} else if len(signatures) == 0 {
+		return nil
+	}
+
+	const rootLen = 32
+
+	var concatenated []byte
+	for _, d := range data {
+		if len(d) != rootLen || !multiVerifyEnabled {
+			for i := range signatures {
+				if err := h.Verify(compressedPublicKeys[i], data[i], signatures[i]); err != nil {
+					return errors.Wrap(err, "batch verify", z.Int("signature_number", i))
+				}
+			}
+
+			return nil
+		}
+		concatenated = append(concatenated, d...)
+	}
+
+	rawKeys := make([]bls.PublicKey, len(compressedPublicKeys))
+	for i, key := range compressedPublicKeys {
+		if err := rawKeys[i].Deserialize(key[:]); err != nil {
+			return errors.Wrap(err, "cannot set compressed public key in Herumi format", z.Int("pubkey_number", i))
+		}
+	}
+
+	rawSigns := make([]bls.Sign, len(signatures))
+	for i, sig := range signatures {
+		if err := rawSigns[i].Deserialize(sig[:]); err != nil {
+			return errors.Wrap(err, "cannot unmarshal signature into Herumi signature", z.Int("signature_number", i))
+		}
+	}
+
+	if !bls.MultiVerify(rawSigns, rawKeys, concatenated) {
+		return errors.New("batch signature verification failed")
+	}
+
+	return nil
+}
+
 func (Herumi) Sign(privateKey PrivateKey, data []byte) (Signature, error) {
 	var p bls.SecretKey
 
@@ -269,3 +336,75 @@ func (Herumi) VerifyAggregate(publicShares []PublicKey, signature Signature, dat
 
 	return nil
 }
+
+func (Herumi) VerifyShare(share PrivateKey, index int, commitments []PublicKey) error {
+	if len(commitments) == 0 {
+		return errors.New("empty commitments")
+	}
+
+	var sk bls.SecretKey
+	if err := sk.Deserialize(share[:]); err != nil {
+		return errors.Wrap(err, "cannot unmarshal share into Herumi secret key")
+	}
+
+	var rawCommitments []bls.PublicKey
+	for _, commitment := range commitments {
+		var pubKey bls.PublicKey
+		if err := pubKey.Deserialize(commitment[:]); err != nil {
+			return errors.Wrap(err, "cannot set compressed commitment in Herumi format")
+		}
+
+		rawCommitments = append(rawCommitments, pubKey)
+	}
+
+	var id bls.ID
+	if err := id.SetDecString(strconv.Itoa(index)); err != nil {
+		return errors.Wrap(err, "cannot set ID", z.Int("id_number", index))
+	}
+
+	// Evaluate the commitment polynomial at the share index.
+	var expect bls.PublicKey
+	if err := expect.Set(rawCommitments, &id); err != nil {
+		return errors.Wrap(err, "cannot evaluate commitments", z.Int("id_number", index))
+	}
+
+	if !sk.GetPublicKey().IsEqual(&expect) {
+		return errors.New("share inconsistent with commitments", z.Int("id_number", index))
+	}
+
+	return nil
+}
+
+func (Herumi) RecoverPublicKey(pubShares map[int]PublicKey) (PublicKey, error) {
+	if len(pubShares) == 0 {
+		return PublicKey{}, errors.New("no public shares to recover")
+	}
+
+	var (
+		rawKeys []bls.PublicKey
+		rawIDs  []bls.ID
+	)
+
+	for idx, pubShare := range pubShares {
+		var pubKey bls.PublicKey
+		if err := pubKey.Deserialize(pubShare[:]); err != nil {
+			return PublicKey{}, errors.Wrap(err, "cannot set compressed public share in Herumi format", z.Int("id_number", idx))
+		}
+
+		rawKeys = append(rawKeys, pubKey)
+
+		var id bls.ID
+		if err := id.SetDecString(strconv.Itoa(idx)); err != nil {
+			return PublicKey{}, errors.Wrap(err, "cannot set ID", z.Int("id_number", idx))
+		}
+
+		rawIDs = append(rawIDs, id)
+	}
+
+	var pubKey bls.PublicKey
+	if err := pubKey.Recover(rawKeys, rawIDs); err != nil {
+		return PublicKey{}, errors.Wrap(err, "cannot recover public key from public shares")
+	}
+
+	return *(*PublicKey)(pubKey.Serialize()), nil
+}
@@ -0,0 +1,8 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+//go:build !race
+
+package v2
+
+// multiVerifyEnabled is true if herumi's bls.MultiVerify is used to batch verify signatures, see herumi_race.go.
+const multiVerifyEnabled = true
@@ -0,0 +1,9 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+//go:build race
+
+package v2
+
+// multiVerifyEnabled is false when built with the race detector, since herumi's bls.MultiVerify
+// converts uintptr to unsafe.Pointer, which fails the race detector's checkptr instrumentation.
+const multiVerifyEnabled = false
@@ -21,6 +21,11 @@ var blsScheme = bls_sig.NewSigEth2()
 // Kryptology is an Implementation with Kryptology-specific inner logic.
 type Kryptology struct{}
 
+// Name returns the identifier of the implementation.
+func (Kryptology) Name() string {
+	return "kryptology"
+}
+
 func (Kryptology) GenerateSecretKey() (PrivateKey, error) {
 	_, secret, err := blsScheme.Keygen()
 	if err != nil {
@@ -59,20 +64,25 @@ func (Kryptology) SecretToPublicKey(key PrivateKey) (PublicKey, error) {
 	return *(*PublicKey)(ret), nil
 }
 
-func (Kryptology) ThresholdSplit(secret PrivateKey, total uint, threshold uint) (map[int]PrivateKey, error) {
+func (k Kryptology) ThresholdSplit(secret PrivateKey, total uint, threshold uint) (map[int]PrivateKey, error) {
+	shares, _, err := k.ThresholdSplitWithCommitments(secret, total, threshold)
+	return shares, err
+}
+
+func (Kryptology) ThresholdSplitWithCommitments(secret PrivateKey, total uint, threshold uint) (map[int]PrivateKey, []PublicKey, error) {
 	scheme, err := share.NewFeldman(uint32(threshold), uint32(total), curves.BLS12381G1())
 	if err != nil {
-		return nil, errors.Wrap(err, "new Feldman VSS")
+		return nil, nil, errors.Wrap(err, "new Feldman VSS")
 	}
 
 	secretScaler, err := curves.BLS12381G1().NewScalar().SetBytes(secret[:])
 	if err != nil {
-		return nil, errors.Wrap(err, "convert to scaler")
+		return nil, nil, errors.Wrap(err, "convert to scaler")
 	}
 
-	_, shares, err := scheme.Split(secretScaler, rand.Reader)
+	verifier, shares, err := scheme.Split(secretScaler, rand.Reader)
 	if err != nil {
-		return nil, errors.Wrap(err, "split Secret Key")
+		return nil, nil, errors.Wrap(err, "split Secret Key")
 	}
 
 	sks := make(map[int]PrivateKey)
@@ -81,7 +91,12 @@ func (Kryptology) ThresholdSplit(secret PrivateKey, total uint, threshold uint)
 		sks[int(s.Id)] = *(*PrivateKey)(s.Value)
 	}
 
-	return sks, nil
+	var commitments []PublicKey
+	for _, commitment := range verifier.Commitments {
+		commitments = append(commitments, *(*PublicKey)(commitment.ToAffineCompressed()))
+	}
+
+	return sks, commitments, nil
 }
 
 func (Kryptology) RecoverSecret(shares map[int]PrivateKey, total uint, threshold uint) (PrivateKey, error) {
@@ -206,6 +221,25 @@ func (Kryptology) Verify(compressedPublicKey PublicKey, data []byte, signature S
 	return nil
 }
 
+// BatchVerify verifies each signature since kryptology doesn't support batch verification.
+func (k Kryptology) BatchVerify(compressedPublicKeys []PublicKey, data [][]byte, signatures []Signature) error {
+	if len(compressedPublicKeys) != len(signatures) || len(data) != len(signatures) {
+		return errors.New("batch length mismatch",
+			z.Int("pubkeys", len(compressedPublicKeys)),
+			z.Int("data", len(data)),
+			z.Int("signatures", len(signatures)),
+		)
+	}
+
+	for i := range signatures {
+		if err := k.Verify(compressedPublicKeys[i], data[i], signatures[i]); err != nil {
+			return errors.Wrap(err, "batch verify", z.Int("signature_number", i))
+		}
+	}
+
+	return nil
+}
+
 func (Kryptology) Sign(privateKey PrivateKey, data []byte) (Signature, error) {
 	rawKey := new(bls_sig.SecretKey)
 	if err := rawKey.UnmarshalBinary(privateKey[:]); err != nil {
@@ -254,3 +288,55 @@ func (Kryptology) VerifyAggregate(shares []PublicKey, signature Signature, data
 
 	return nil
 }
+
+func (Kryptology) VerifyShare(shareKey PrivateKey, index int, commitments []PublicKey) error {
+	if len(commitments) == 0 {
+		return errors.New("empty commitments")
+	}
+
+	verifier := share.FeldmanVerifier{Commitments: make([]curves.Point, 0, len(commitments))}
+	for _, commitment := range commitments {
+		point, err := curves.BLS12381G1().Point.FromAffineCompressed(commitment[:])
+		if err != nil {
+			return errors.Wrap(err, "unmarshal commitment into kryptology object")
+		}
+
+		verifier.Commitments = append(verifier.Commitments, point)
+	}
+
+	if err := verifier.Verify(&share.ShamirShare{Id: uint32(index), Value: shareKey[:]}); err != nil {
+		return errors.Wrap(err, "share inconsistent with commitments", z.Int("id_number", index))
+	}
+
+	return nil
+}
+
+func (Kryptology) RecoverPublicKey(pubShares map[int]PublicKey) (PublicKey, error) {
+	if len(pubShares) == 0 {
+		return PublicKey{}, errors.New("no public shares to recover")
+	}
+
+	curve := curves.BLS12381G1()
+
+	ids := make(map[uint32]*share.ShamirShare)
+	for idx := range pubShares {
+		ids[uint32(idx)] = &share.ShamirShare{Id: uint32(idx)}
+	}
+
+	coeffs, err := share.Feldman{Curve: curve}.LagrangeCoeffs(ids)
+	if err != nil {
+		return PublicKey{}, errors.Wrap(err, "lagrange coefficients")
+	}
+
+	result := curve.Point.Identity()
+	for idx, pubShare := range pubShares {
+		point, err := curve.Point.FromAffineCompressed(pubShare[:])
+		if err != nil {
+			return PublicKey{}, errors.Wrap(err, "unmarshal public share into kryptology object", z.Int("id_number", idx))
+		}
+
+		result = result.Add(point.Mul(coeffs[uint32(idx)]))
+	}
+
+	return *(*PublicKey)(result.ToAffineCompressed()), nil
+}
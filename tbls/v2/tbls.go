@@ -2,7 +2,18 @@
 
 package v2
 
-import "sync"
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/prysmaticlabs/go-bitfield"
+
+	"github.com/obolnetwork/charon/app/errors"
+	"github.com/obolnetwork/charon/app/z"
+)
 
 var (
 	impl     Implementation = Kryptology{}
@@ -33,6 +44,10 @@ type Implementation interface {
 	// It returns a map that associates each private, compressed private key to its ID.
 	ThresholdSplit(secret PrivateKey, total uint, threshold uint) (map[int]PrivateKey, error)
 
+	// ThresholdSplitWithCommitments is ThresholdSplit that also returns the Feldman VSS commitment vector,
+	// the public keys of the polynomial coefficients, that a dealer can publish for others to verify their shares.
+	ThresholdSplitWithCommitments(secret PrivateKey, total uint, threshold uint) (map[int]PrivateKey, []PublicKey, error)
+
 	// RecoverSecret recovers the original secret off the input shares.
 	RecoverSecret(shares map[int]PrivateKey, total uint, threshold uint) (PrivateKey, error)
 
@@ -51,9 +66,21 @@ type Implementation interface {
 	// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-03#section-3.3.4.
 	VerifyAggregate(shares []PublicKey, signature Signature, data []byte) error
 
+	// BatchVerify verifies that each signature has been produced with the private key associated with the public key
+	// of the same index, on the data of the same index. Implementations may verify the batch at once, faster than
+	// verifying each signature, in which case the returned error doesn't identify the invalid signatures.
+	BatchVerify(compressedPublicKeys []PublicKey, data [][]byte, signatures []Signature) error
+
 	// Aggregate combines signs in a single Signature with standard BLS signature aggregation,
 	// as defined by the standard: https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-03#section-2.8.
 	Aggregate(signs []Signature) (Signature, error)
+
+	// VerifyShare verifies that the secret share with the given index is consistent with the published
+	// polynomial commitments (Feldman VSS verification vector), without reconstructing the secret.
+	VerifyShare(share PrivateKey, index int, commitments []PublicKey) error
+
+	// RecoverPublicKey recovers the group public key off the input public shares by Lagrange interpolation.
+	RecoverPublicKey(pubShares map[int]PublicKey) (PublicKey, error)
 }
 
 // SetImplementation sets newImpl as the package backing implementation.
@@ -63,6 +90,26 @@ func SetImplementation(newImpl Implementation) {
 	impl = newImpl
 }
 
+// namer is optionally implemented by implementations to identify themselves.
+type namer interface {
+	// Name returns the identifier of the implementation.
+	Name() string
+}
+
+// ImplementationName returns the identifier of the active backing implementation, e.g. "herumi" or "kryptology",
+// so operators can confirm which crypto backend is running. It returns the type name of implementations
+// that do not identify themselves.
+func ImplementationName() string {
+	implLock.Lock()
+	defer implLock.Unlock()
+
+	if n, ok := impl.(namer); ok {
+		return n.Name()
+	}
+
+	return fmt.Sprintf("%T", impl)
+}
+
 func GenerateSecretKey() (PrivateKey, error) {
 	return impl.GenerateSecretKey()
 }
@@ -75,6 +122,10 @@ func ThresholdSplit(secret PrivateKey, total uint, threshold uint) (map[int]Priv
 	return impl.ThresholdSplit(secret, total, threshold)
 }
 
+func ThresholdSplitWithCommitments(secret PrivateKey, total uint, threshold uint) (map[int]PrivateKey, []PublicKey, error) {
+	return impl.ThresholdSplitWithCommitments(secret, total, threshold)
+}
+
 func RecoverSecret(shares map[int]PrivateKey, total uint, threshold uint) (PrivateKey, error) {
 	return impl.RecoverSecret(shares, total, threshold)
 }
@@ -95,6 +146,349 @@ func VerifyAggregate(shares []PublicKey, signature Signature, data []byte) error
 	return impl.VerifyAggregate(shares, signature, data)
 }
 
+func BatchVerify(compressedPublicKeys []PublicKey, data [][]byte, signatures []Signature) error {
+	return impl.BatchVerify(compressedPublicKeys, data, signatures)
+}
+
 func Aggregate(signs []Signature) (Signature, error) {
 	return impl.Aggregate(signs)
 }
+
+func VerifyShare(share PrivateKey, index int, commitments []PublicKey) error {
+	return impl.VerifyShare(share, index, commitments)
+}
+
+func RecoverPublicKey(pubShares map[int]PublicKey) (PublicKey, error) {
+	return impl.RecoverPublicKey(pubShares)
+}
+
+// DeviatingPartialError is returned by ThresholdAggregateVerifyMessage if a partial signature
+// didn't sign the message, identifying the share index of the deviating (faulty or malicious) peer.
+type DeviatingPartialError struct {
+	ShareIdx int
+	Err      error
+}
+
+func (e DeviatingPartialError) Error() string {
+	return "partial signature of different message: share_index=" + strconv.Itoa(e.ShareIdx) + ": " + e.Err.Error()
+}
+
+func (e DeviatingPartialError) Unwrap() error {
+	return e.Err
+}
+
+// ThresholdAggregateVerifyMessage verifies that each partial signature signed exactly msg with the public share
+// of its index before aggregating them into the final signature. It returns a DeviatingPartialError of the first
+// (lowest index) deviating partial signature, detecting equivocating peers before producing a bad aggregate.
+func ThresholdAggregateVerifyMessage(pubShares map[int]PublicKey, msg []byte, partials map[int]Signature) (Signature, error) {
+	var idxs []int
+	for idx := range partials {
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+
+	for _, idx := range idxs {
+		pubShare, ok := pubShares[idx]
+		if !ok {
+			return Signature{}, errors.New("missing public share", z.Int("share_index", idx))
+		}
+
+		if err := Verify(pubShare, msg, partials[idx]); err != nil {
+			return Signature{}, DeviatingPartialError{ShareIdx: idx, Err: err}
+		}
+	}
+
+	return ThresholdAggregate(partials)
+}
+
+// VerifyPartials verifies the partial signatures over the common message against the public shares of their indices
+// using a pool of workers, defaulting to GOMAXPROCS if zero or negative. It returns the sorted indices of invalid
+// partial signatures, including those without a public share, or nil if all are valid.
+// This speeds up verifying the partial signatures of all peers before aggregating them.
+func VerifyPartials(pubShares map[int]PublicKey, msg []byte, partials map[int]Signature, workers int) []int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var (
+		queue   = make(chan int)
+		mu      sync.Mutex
+		invalid []int
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range queue {
+				pubShare, ok := pubShares[idx]
+				if ok && Verify(pubShare, msg, partials[idx]) == nil {
+					continue
+				}
+
+				mu.Lock()
+				invalid = append(invalid, idx)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for idx := range partials {
+		queue <- idx
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Ints(invalid)
+
+	return invalid
+}
+
+// VerifyAggregateBitfield verifies that the aggregate signature was produced on data by exactly the participants
+// selected by the bitfield, where bit i selects the public key at index i, e.g. a final aggregated attestation
+// signature of the committee validators indicated by the aggregation bits.
+func VerifyAggregateBitfield(pubkeys []PublicKey, bits bitfield.Bitlist, signature Signature, data []byte) error {
+	if bits.Len() != uint64(len(pubkeys)) {
+		return errors.New("bitfield length mismatches public keys",
+			z.U64("bitfield_length", bits.Len()), z.Int("public_keys", len(pubkeys)))
+	}
+
+	var participants []PublicKey
+	for _, idx := range bits.BitIndices() {
+		participants = append(participants, pubkeys[idx])
+	}
+
+	if len(participants) == 0 {
+		return errors.New("no participants in bitfield")
+	}
+
+	return VerifyAggregate(participants, signature, data)
+}
+
+// reshareCheckMsg is the message signed by old and new shares to verify that resharing preserves the group public key.
+var reshareCheckMsg = []byte("charon tbls reshare check")
+
+// ReshareDeal is an old share holder's dealing of its secret share to the new share holders, see DealReshare.
+type ReshareDeal struct {
+	// SubShares are the sub-shares of the old share by new share index.
+	SubShares map[int]PrivateKey
+	// Commitments are the Feldman VSS commitments of the sub-sharing polynomial,
+	// the first is the public share of the old share.
+	Commitments []PublicKey
+}
+
+// DealReshare returns the old share holder's dealing of its secret share into newTotal sub-shares with newThreshold,
+// together with the Feldman commitments that new share holders use to verify their sub-shares, see CombineReshare.
+func DealReshare(oldShare PrivateKey, newTotal uint, newThreshold uint) (ReshareDeal, error) {
+	if err := validateThreshold(newTotal, newThreshold); err != nil {
+		return ReshareDeal{}, err
+	}
+
+	subShares, commitments, err := ThresholdSplitWithCommitments(oldShare, newTotal, newThreshold)
+	if err != nil {
+		return ReshareDeal{}, err
+	}
+
+	return ReshareDeal{SubShares: subShares, Commitments: commitments}, nil
+}
+
+// CombineReshare returns the new secret share of the new share index by combining its sub-shares dealt by
+// at least oldThreshold old share holders, see DealReshare. The deals and old public shares are by old share index.
+// Each sub-share is verified against its deal's commitments, which must commit to the dealer's old public share.
+// The sub-shares are combined by Lagrange interpolation at zero over the old share indices,
+// so neither the old group secret nor any other old share is ever reconstructed.
+func CombineReshare(deals map[int]ReshareDeal, oldPubShares map[int]PublicKey, oldThreshold uint, newIdx int,
+) (PrivateKey, error) {
+	if oldThreshold == 0 {
+		return PrivateKey{}, errors.New("zero old threshold")
+	} else if uint(len(deals)) < oldThreshold {
+		return PrivateKey{}, errors.New("insufficient reshare deals",
+			z.Int("deals", len(deals)), z.U64("threshold", uint64(oldThreshold)))
+	}
+
+	var maxIdx int
+	subShares := make(map[int]PrivateKey)
+	for oldIdx, deal := range deals {
+		pubShare, ok := oldPubShares[oldIdx]
+		if !ok {
+			return PrivateKey{}, errors.New("missing old public share", z.Int("old_share_idx", oldIdx))
+		} else if len(deal.Commitments) == 0 || deal.Commitments[0] != pubShare {
+			return PrivateKey{}, errors.New("reshare deal doesn't commit to old public share", z.Int("old_share_idx", oldIdx))
+		}
+
+		subShare, ok := deal.SubShares[newIdx]
+		if !ok {
+			return PrivateKey{}, errors.New("missing sub-share",
+				z.Int("old_share_idx", oldIdx), z.Int("new_share_idx", newIdx))
+		}
+
+		if err := VerifyShare(subShare, newIdx, deal.Commitments); err != nil {
+			return PrivateKey{}, errors.Wrap(err, "inconsistent sub-share",
+				z.Int("old_share_idx", oldIdx), z.Int("new_share_idx", newIdx))
+		}
+
+		subShares[oldIdx] = subShare
+		if oldIdx > maxIdx {
+			maxIdx = oldIdx
+		}
+	}
+
+	// Interpolating the sub-shares (old index, sub-share) at zero, i.e. their Lagrange combination,
+	// yields the new share, since the sub-share of each old share is a point of that share's sub-sharing polynomial.
+	return RecoverSecret(subShares, uint(maxIdx), uint(len(subShares)))
+}
+
+// Reshare returns a new set of newTotal secret shares with newThreshold of the secret shared by oldShares,
+// preserving the group public key, without reconstructing the secret. A threshold of old share holders each deal
+// verifiable sub-shares of their share (see DealReshare), which are combined into the new shares (see CombineReshare).
+// It verifies that the old shares are consistent and that the new shares produce the same group signatures.
+func Reshare(oldShares map[int]PrivateKey, oldThreshold uint, newTotal uint, newThreshold uint) (map[int]PrivateKey, error) {
+	if oldThreshold == 0 {
+		return nil, errors.New("zero old threshold")
+	} else if err := validateThreshold(newTotal, newThreshold); err != nil {
+		return nil, err
+	} else if uint(len(oldShares)) < oldThreshold {
+		return nil, errors.New("insufficient shares to reshare",
+			z.Int("shares", len(oldShares)), z.U64("threshold", uint64(oldThreshold)))
+	}
+
+	oldIdxs := sortedIndices(oldShares)
+
+	groupSig, err := thresholdSign(oldShares, oldIdxs[:oldThreshold])
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify old shares are consistent by comparing the group signatures of the first and last threshold subsets.
+	if uint(len(oldShares)) > oldThreshold {
+		otherSig, err := thresholdSign(oldShares, oldIdxs[uint(len(oldIdxs))-oldThreshold:])
+		if err != nil {
+			return nil, err
+		} else if otherSig != groupSig {
+			return nil, errors.New("inconsistent old shares")
+		}
+	}
+
+	deals := make(map[int]ReshareDeal)
+	oldPubShares := make(map[int]PublicKey)
+	for _, idx := range oldIdxs[:oldThreshold] {
+		deals[idx], err = DealReshare(oldShares[idx], newTotal, newThreshold)
+		if err != nil {
+			return nil, err
+		}
+
+		oldPubShares[idx], err = SecretToPublicKey(oldShares[idx])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newShares := make(map[int]PrivateKey)
+	for newIdx := 1; newIdx <= int(newTotal); newIdx++ {
+		newShares[newIdx], err = CombineReshare(deals, oldPubShares, oldThreshold, newIdx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newSig, err := thresholdSign(newShares, sortedIndices(newShares)[:newThreshold])
+	if err != nil {
+		return nil, err
+	} else if newSig != groupSig {
+		return nil, errors.New("new shares do not preserve group public key")
+	}
+
+	return newShares, nil
+}
+
+// validateThreshold returns an error if the threshold isn't between one and the total number of new shares.
+func validateThreshold(total uint, threshold uint) error {
+	if threshold == 0 || threshold > total {
+		return errors.New("invalid new threshold", z.U64("total", uint64(total)), z.U64("threshold", uint64(threshold)))
+	}
+
+	return nil
+}
+
+// thresholdSign returns the group signature of reshareCheckMsg threshold aggregated from the partial signatures
+// of the shares of the indices, without reconstructing the secret.
+func thresholdSign(shares map[int]PrivateKey, idxs []int) (Signature, error) {
+	partials := make(map[int]Signature)
+	for _, idx := range idxs {
+		sig, err := Sign(shares[idx], reshareCheckMsg)
+		if err != nil {
+			return Signature{}, err
+		}
+		partials[idx] = sig
+	}
+
+	return ThresholdAggregate(partials)
+}
+
+// sortedIndices returns the share indices in increasing order.
+func sortedIndices(shares map[int]PrivateKey) []int {
+	var idxs []int
+	for idx := range shares {
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+
+	return idxs
+}
+
+// RecoverAndVerifyAgainstShares recovers the secret from the shares and verifies that the public key of each
+// share matches the share public key of its index, e.g. the public shares of a cluster lock. This confirms
+// that the recovered secret belongs to the cluster and not to shares of a different split.
+// All provided shares are used to recover the secret, so it requires at least threshold shares, else it errors.
+func RecoverAndVerifyAgainstShares(shares map[int]PrivateKey, sharePubKeys map[int]PublicKey) (PrivateKey, error) {
+	if len(shares) == 0 {
+		return PrivateKey{}, errors.New("no shares to recover")
+	}
+
+	// Total and threshold are only used for bounds checks, so use the largest index and the number of shares.
+	var total uint
+	for idx := range sharePubKeys {
+		if uint(idx) > total {
+			total = uint(idx)
+		}
+	}
+
+	for idx, share := range shares {
+		sharePubKey, ok := sharePubKeys[idx]
+		if !ok {
+			return PrivateKey{}, errors.New("missing share public key", z.Int("share_idx", idx))
+		}
+
+		pubkey, err := SecretToPublicKey(share)
+		if err != nil {
+			return PrivateKey{}, err
+		}
+
+		if pubkey != sharePubKey {
+			return PrivateKey{}, errors.New("share mismatches share public key", z.Int("share_idx", idx))
+		}
+	}
+
+	secret, err := RecoverSecret(shares, total, uint(len(shares)))
+	if err != nil {
+		return PrivateKey{}, err
+	}
+
+	// Fewer than threshold shares recover a different secret, so verify it against the group public key
+	// interpolated from all share public keys.
+	groupPubKey, err := RecoverPublicKey(sharePubKeys)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+
+	pubkey, err := SecretToPublicKey(secret)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+
+	if pubkey != groupPubKey {
+		return PrivateKey{}, errors.New("recovered secret mismatches group public key, insufficient shares", z.Int("shares", len(shares)))
+	}
+
+	return secret, nil
+}
@@ -3,13 +3,17 @@
 package v2_test
 
 import (
+	"bytes"
 	"crypto/rand"
+	"fmt"
 	"math/big"
 	"testing"
 
+	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/obolnetwork/charon/app/errors"
 	v2 "github.com/obolnetwork/charon/tbls/v2"
 )
 
@@ -55,6 +59,30 @@ func (ts *TestSuite) Test_ThresholdSplit() {
 	require.NotEmpty(ts.T(), shares)
 }
 
+func (ts *TestSuite) Test_ThresholdSplitWithCommitments() {
+	secret, err := v2.GenerateSecretKey()
+	require.NoError(ts.T(), err)
+
+	pubkey, err := v2.SecretToPublicKey(secret)
+	require.NoError(ts.T(), err)
+
+	shares, commitments, err := v2.ThresholdSplitWithCommitments(secret, 5, 3)
+	require.NoError(ts.T(), err)
+	require.Len(ts.T(), shares, 5)
+	require.Len(ts.T(), commitments, 3)
+
+	// The first commitment is the group public key.
+	require.Equal(ts.T(), pubkey, commitments[0])
+
+	for idx, share := range shares {
+		require.NoError(ts.T(), v2.VerifyShare(share, idx, commitments))
+	}
+
+	recovered, err := v2.RecoverSecret(shares, 5, 3)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), secret, recovered)
+}
+
 func (ts *TestSuite) Test_RecoverSecret() {
 	secret, err := v2.GenerateSecretKey()
 	require.NoError(ts.T(), err)
@@ -114,6 +142,50 @@ func (ts *TestSuite) Test_Verify() {
 	require.NoError(ts.T(), v2.Verify(pubkey, data, signature))
 }
 
+func (ts *TestSuite) Test_BatchVerify() {
+	var (
+		pubkeys []v2.PublicKey
+		roots   [][]byte
+		sigs    []v2.Signature
+	)
+	for i := 0; i < 20; i++ {
+		secret, err := v2.GenerateSecretKey()
+		require.NoError(ts.T(), err)
+
+		pubkey, err := v2.SecretToPublicKey(secret)
+		require.NoError(ts.T(), err)
+
+		root := make([]byte, 32)
+		root[0] = byte(i % 2) // Include identical data.
+
+		sig, err := v2.Sign(secret, root)
+		require.NoError(ts.T(), err)
+
+		pubkeys = append(pubkeys, pubkey)
+		roots = append(roots, root)
+		sigs = append(sigs, sig)
+	}
+
+	require.NoError(ts.T(), v2.BatchVerify(pubkeys, roots, sigs))
+	require.NoError(ts.T(), v2.BatchVerify(nil, nil, nil))
+	require.Error(ts.T(), v2.BatchVerify(pubkeys[1:], roots, sigs))
+
+	// Data other than signing roots.
+	secret, err := v2.GenerateSecretKey()
+	require.NoError(ts.T(), err)
+	pubkey, err := v2.SecretToPublicKey(secret)
+	require.NoError(ts.T(), err)
+	data := []byte("hello obol!")
+	sig, err := v2.Sign(secret, data)
+	require.NoError(ts.T(), err)
+	require.NoError(ts.T(), v2.BatchVerify(append(pubkeys, pubkey), append(roots, data), append(sigs, sig)))
+	require.Error(ts.T(), v2.BatchVerify(append(pubkeys, pubkey), append(roots, data), append(sigs, sigs[0])))
+
+	// A single swapped signature fails the batch.
+	sigs[3], sigs[4] = sigs[4], sigs[3]
+	require.Error(ts.T(), v2.BatchVerify(pubkeys, roots, sigs))
+}
+
 func (ts *TestSuite) Test_Sign() {
 	data := []byte("hello obol!")
 
@@ -166,6 +238,392 @@ func (ts *TestSuite) Test_VerifyAggregate() {
 	require.NoError(ts.T(), v2.VerifyAggregate(pshares, sig, data))
 }
 
+func (ts *TestSuite) Test_ThresholdAggregateVerifyMessage() {
+	msg := []byte("hello obol!")
+
+	secret, err := v2.GenerateSecretKey()
+	require.NoError(ts.T(), err)
+
+	groupKey, err := v2.SecretToPublicKey(secret)
+	require.NoError(ts.T(), err)
+
+	shares, err := v2.ThresholdSplit(secret, 4, 3)
+	require.NoError(ts.T(), err)
+
+	pubShares := make(map[int]v2.PublicKey)
+	partials := make(map[int]v2.Signature)
+	for idx, share := range shares {
+		pubShare, err := v2.SecretToPublicKey(share)
+		require.NoError(ts.T(), err)
+		pubShares[idx] = pubShare
+
+		partial, err := v2.Sign(share, msg)
+		require.NoError(ts.T(), err)
+		partials[idx] = partial
+	}
+
+	sig, err := v2.ThresholdAggregateVerifyMessage(pubShares, msg, partials)
+	require.NoError(ts.T(), err)
+	require.NoError(ts.T(), v2.Verify(groupKey, msg, sig))
+
+	// Peer 3 signed a different message.
+	partials[3], err = v2.Sign(shares[3], []byte("hello equivocation!"))
+	require.NoError(ts.T(), err)
+
+	_, err = v2.ThresholdAggregateVerifyMessage(pubShares, msg, partials)
+	require.ErrorContains(ts.T(), err, "partial signature of different message")
+
+	var deviating v2.DeviatingPartialError
+	require.True(ts.T(), errors.As(err, &deviating))
+	require.Equal(ts.T(), 3, deviating.ShareIdx)
+
+	// Partial signatures without public share are rejected.
+	delete(pubShares, 3)
+	_, err = v2.ThresholdAggregateVerifyMessage(pubShares, msg, partials)
+	require.ErrorContains(ts.T(), err, "missing public share")
+}
+
+func (ts *TestSuite) Test_VerifyPartials() {
+	msg := []byte("hello obol!")
+
+	pubShares, partials := newPartials(ts.T(), 7, msg)
+	require.Empty(ts.T(), v2.VerifyPartials(pubShares, msg, partials, 3))
+
+	// Peers 2 and 5 signed a different message and peer 6 has no public share.
+	for _, idx := range []int{2, 5} {
+		partials[idx] = partials[1]
+	}
+	delete(pubShares, 6)
+
+	require.Equal(ts.T(), []int{2, 5, 6}, v2.VerifyPartials(pubShares, msg, partials, 3))
+	require.Equal(ts.T(), []int{2, 5, 6}, v2.VerifyPartials(pubShares, msg, partials, 0))
+}
+
+// newPartials returns the public shares and partial signatures of msg of total shares of a new secret.
+func newPartials(t testing.TB, total uint, msg []byte) (map[int]v2.PublicKey, map[int]v2.Signature) {
+	t.Helper()
+
+	secret, err := v2.GenerateSecretKey()
+	require.NoError(t, err)
+
+	shares, err := v2.ThresholdSplit(secret, total, total)
+	require.NoError(t, err)
+
+	pubShares := make(map[int]v2.PublicKey)
+	partials := make(map[int]v2.Signature)
+	for idx, share := range shares {
+		pubShares[idx], err = v2.SecretToPublicKey(share)
+		require.NoError(t, err)
+
+		partials[idx], err = v2.Sign(share, msg)
+		require.NoError(t, err)
+	}
+
+	return pubShares, partials
+}
+
+func (ts *TestSuite) Test_VerifyAggregateBitfield() {
+	const committeeSize = 5
+
+	msg := []byte("attestation data root")
+
+	var (
+		pubkeys []v2.PublicKey
+		sigs    []v2.Signature
+	)
+	for i := 0; i < committeeSize; i++ {
+		secret, err := v2.GenerateSecretKey()
+		require.NoError(ts.T(), err)
+
+		pubkey, err := v2.SecretToPublicKey(secret)
+		require.NoError(ts.T(), err)
+
+		sig, err := v2.Sign(secret, msg)
+		require.NoError(ts.T(), err)
+
+		pubkeys = append(pubkeys, pubkey)
+		sigs = append(sigs, sig)
+	}
+
+	// Aggregate the signatures of a subset of the committee.
+	bits := bitfield.NewBitlist(committeeSize)
+	bits.SetBitAt(0, true)
+	bits.SetBitAt(2, true)
+	bits.SetBitAt(3, true)
+
+	aggSig, err := v2.Aggregate([]v2.Signature{sigs[0], sigs[2], sigs[3]})
+	require.NoError(ts.T(), err)
+
+	require.NoError(ts.T(), v2.VerifyAggregateBitfield(pubkeys, bits, aggSig, msg))
+
+	// Bitfields selecting different participants are rejected.
+	others := bitfield.NewBitlist(committeeSize)
+	others.SetBitAt(0, true)
+	others.SetBitAt(2, true)
+	require.Error(ts.T(), v2.VerifyAggregateBitfield(pubkeys, others, aggSig, msg))
+
+	others.SetBitAt(3, true)
+	others.SetBitAt(4, true)
+	require.Error(ts.T(), v2.VerifyAggregateBitfield(pubkeys, others, aggSig, msg))
+
+	// Mismatching bitfield lengths and empty bitfields are rejected.
+	err = v2.VerifyAggregateBitfield(pubkeys[:4], bits, aggSig, msg)
+	require.ErrorContains(ts.T(), err, "bitfield length mismatches public keys")
+
+	err = v2.VerifyAggregateBitfield(pubkeys, bitfield.NewBitlist(committeeSize), aggSig, msg)
+	require.ErrorContains(ts.T(), err, "no participants in bitfield")
+}
+
+func (ts *TestSuite) Test_Reshare() {
+	secret, err := v2.GenerateSecretKey()
+	require.NoError(ts.T(), err)
+
+	groupKey, err := v2.SecretToPublicKey(secret)
+	require.NoError(ts.T(), err)
+
+	oldShares, err := v2.ThresholdSplit(secret, 3, 2)
+	require.NoError(ts.T(), err)
+
+	// Reshare 2-of-3 into 3-of-5.
+	newShares, err := v2.Reshare(oldShares, 2, 5, 3)
+	require.NoError(ts.T(), err)
+	require.Len(ts.T(), newShares, 5)
+
+	// Any 3 new shares recover the same secret and group public key.
+	subset := map[int]v2.PrivateKey{2: newShares[2], 4: newShares[4], 5: newShares[5]}
+	recovered, err := v2.RecoverSecret(subset, 5, 3)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), secret, recovered)
+
+	recoveredKey, err := v2.SecretToPublicKey(recovered)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), groupKey, recoveredKey)
+
+	// Inconsistent old shares are rejected.
+	otherShares, err := v2.ThresholdSplit(secret, 3, 2)
+	require.NoError(ts.T(), err)
+
+	inconsistent := map[int]v2.PrivateKey{1: oldShares[1], 2: oldShares[2], 3: otherShares[3]}
+	_, err = v2.Reshare(inconsistent, 2, 5, 3)
+	require.ErrorContains(ts.T(), err, "inconsistent old shares")
+
+	// Insufficient old shares are rejected.
+	delete(oldShares, 1)
+	delete(oldShares, 2)
+	_, err = v2.Reshare(oldShares, 2, 5, 3)
+	require.ErrorContains(ts.T(), err, "insufficient shares")
+
+	// Invalid thresholds are rejected.
+	_, err = v2.Reshare(oldShares, 0, 5, 3)
+	require.ErrorContains(ts.T(), err, "zero old threshold")
+
+	_, err = v2.Reshare(oldShares, 1, 5, 0)
+	require.ErrorContains(ts.T(), err, "invalid new threshold")
+
+	_, err = v2.Reshare(oldShares, 1, 5, 6)
+	require.ErrorContains(ts.T(), err, "invalid new threshold")
+
+	_, err = v2.DealReshare(oldShares[3], 5, 0)
+	require.ErrorContains(ts.T(), err, "invalid new threshold")
+}
+
+func (ts *TestSuite) Test_ReshareDeals() {
+	secret, err := v2.GenerateSecretKey()
+	require.NoError(ts.T(), err)
+
+	oldShares, err := v2.ThresholdSplit(secret, 3, 2)
+	require.NoError(ts.T(), err)
+
+	oldPubShares := make(map[int]v2.PublicKey)
+	for idx, share := range oldShares {
+		oldPubShares[idx], err = v2.SecretToPublicKey(share)
+		require.NoError(ts.T(), err)
+	}
+
+	// Old share holders 1 and 3 deal their shares to 4 new share holders with threshold 3.
+	newDeals := func() map[int]v2.ReshareDeal {
+		deals := make(map[int]v2.ReshareDeal)
+		for _, idx := range []int{1, 3} {
+			deals[idx], err = v2.DealReshare(oldShares[idx], 4, 3)
+			require.NoError(ts.T(), err)
+		}
+
+		return deals
+	}
+
+	deals := newDeals()
+	newShares := make(map[int]v2.PrivateKey)
+	for newIdx := 1; newIdx <= 4; newIdx++ {
+		newShares[newIdx], err = v2.CombineReshare(deals, oldPubShares, 2, newIdx)
+		require.NoError(ts.T(), err)
+	}
+
+	recovered, err := v2.RecoverSecret(map[int]v2.PrivateKey{1: newShares[1], 2: newShares[2], 4: newShares[4]}, 4, 3)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), secret, recovered)
+
+	// A holder dealing an inconsistent sub-share is detected.
+	deals = newDeals()
+	bad, err := v2.GenerateSecretKey()
+	require.NoError(ts.T(), err)
+	deals[3].SubShares[2] = bad
+
+	_, err = v2.CombineReshare(deals, oldPubShares, 2, 2)
+	require.ErrorContains(ts.T(), err, "inconsistent sub-share")
+
+	// A holder dealing a different share than its old share is detected.
+	deals = newDeals()
+	deals[1], err = v2.DealReshare(bad, 4, 3)
+	require.NoError(ts.T(), err)
+
+	_, err = v2.CombineReshare(deals, oldPubShares, 2, 2)
+	require.ErrorContains(ts.T(), err, "reshare deal doesn't commit to old public share")
+
+	// Fewer deals than the old threshold are rejected.
+	deals = newDeals()
+	delete(deals, 1)
+
+	_, err = v2.CombineReshare(deals, oldPubShares, 2, 2)
+	require.ErrorContains(ts.T(), err, "insufficient reshare deals")
+}
+
+func (ts *TestSuite) Test_RecoverPublicKey() {
+	secret, err := v2.GenerateSecretKey()
+	require.NoError(ts.T(), err)
+
+	pubkey, err := v2.SecretToPublicKey(secret)
+	require.NoError(ts.T(), err)
+
+	shares, err := v2.ThresholdSplit(secret, 4, 3)
+	require.NoError(ts.T(), err)
+
+	pubShares := make(map[int]v2.PublicKey)
+	for idx, share := range shares {
+		pubShares[idx], err = v2.SecretToPublicKey(share)
+		require.NoError(ts.T(), err)
+	}
+
+	recovered, err := v2.RecoverPublicKey(pubShares)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), pubkey, recovered)
+
+	delete(pubShares, 2)
+	recovered, err = v2.RecoverPublicKey(pubShares)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), pubkey, recovered)
+}
+
+func (ts *TestSuite) Test_RecoverAndVerifyAgainstShares() {
+	const (
+		total     = 4
+		threshold = 3
+	)
+
+	secret, err := v2.GenerateSecretKey()
+	require.NoError(ts.T(), err)
+
+	shares, err := v2.ThresholdSplit(secret, total, threshold)
+	require.NoError(ts.T(), err)
+
+	sharePubKeys := make(map[int]v2.PublicKey)
+	for idx, share := range shares {
+		sharePubKeys[idx], err = v2.SecretToPublicKey(share)
+		require.NoError(ts.T(), err)
+	}
+
+	// A consistent threshold subset recovers the secret.
+	subset := map[int]v2.PrivateKey{1: shares[1], 3: shares[3], 4: shares[4]}
+	recovered, err := v2.RecoverAndVerifyAgainstShares(subset, sharePubKeys)
+	require.NoError(ts.T(), err)
+	require.Equal(ts.T(), secret, recovered)
+
+	// A below threshold subset is rejected.
+	_, err = v2.RecoverAndVerifyAgainstShares(map[int]v2.PrivateKey{1: shares[1], 3: shares[3]}, sharePubKeys)
+	require.ErrorContains(ts.T(), err, "recovered secret mismatches group public key")
+
+	// A share of a different split is rejected.
+	otherSecret, err := v2.GenerateSecretKey()
+	require.NoError(ts.T(), err)
+
+	otherShares, err := v2.ThresholdSplit(otherSecret, total, threshold)
+	require.NoError(ts.T(), err)
+
+	subset[3] = otherShares[3]
+	_, err = v2.RecoverAndVerifyAgainstShares(subset, sharePubKeys)
+	require.ErrorContains(ts.T(), err, "share mismatches share public key")
+
+	// Shares without public keys are rejected.
+	delete(sharePubKeys, 1)
+	_, err = v2.RecoverAndVerifyAgainstShares(map[int]v2.PrivateKey{1: shares[1]}, sharePubKeys)
+	require.ErrorContains(ts.T(), err, "missing share public key")
+}
+
+func (ts *TestSuite) Test_VerifyShare() {
+	const (
+		total     = 4
+		threshold = 3
+	)
+
+	shares, commitments := splitWithCommitments(ts.T(), total, threshold)
+
+	for idx, share := range shares {
+		require.NoError(ts.T(), v2.VerifyShare(share, idx, commitments))
+	}
+
+	// A share verified at a different index is rejected.
+	require.Error(ts.T(), v2.VerifyShare(shares[1], 2, commitments))
+
+	// A tampered share is rejected.
+	tampered := shares[3]
+	tampered[len(tampered)-1] ^= 1
+	require.Error(ts.T(), v2.VerifyShare(tampered, 3, commitments))
+
+	// A share of a different polynomial is rejected.
+	otherShares, _ := splitWithCommitments(ts.T(), total, threshold)
+	require.Error(ts.T(), v2.VerifyShare(otherShares[1], 1, commitments))
+}
+
+// blsScalarOrder is the order of the BLS12-381 scalar field.
+var blsScalarOrder, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+// splitWithCommitments returns secret shares of a random polynomial of the threshold degree,
+// and the polynomial commitments (public keys of the coefficients).
+func splitWithCommitments(t *testing.T, total, threshold int) (map[int]v2.PrivateKey, []v2.PublicKey) {
+	t.Helper()
+
+	var (
+		coefficients []*big.Int
+		commitments  []v2.PublicKey
+	)
+	for i := 0; i < threshold; i++ {
+		coefficient, err := v2.GenerateSecretKey()
+		require.NoError(t, err)
+
+		commitment, err := v2.SecretToPublicKey(coefficient)
+		require.NoError(t, err)
+
+		coefficients = append(coefficients, new(big.Int).SetBytes(coefficient[:]))
+		commitments = append(commitments, commitment)
+	}
+
+	shares := make(map[int]v2.PrivateKey)
+	for idx := 1; idx <= total; idx++ {
+		// Evaluate the polynomial at the index using Horner's method.
+		val := new(big.Int)
+		for i := len(coefficients) - 1; i >= 0; i-- {
+			val.Mul(val, big.NewInt(int64(idx)))
+			val.Add(val, coefficients[i])
+			val.Mod(val, blsScalarOrder)
+		}
+
+		var share v2.PrivateKey
+		val.FillBytes(share[:])
+		shares[idx] = share
+	}
+
+	return shares, commitments
+}
+
 func runSuite(t *testing.T, i v2.Implementation) {
 	t.Helper()
 	ts := NewTestSuite(i)
@@ -214,6 +672,35 @@ func BenchmarkKryptologyImplementation(b *testing.B) {
 	runBenchmark(b, v2.Kryptology{})
 }
 
+func BenchmarkVerifyPartials(b *testing.B) {
+	v2.SetImplementation(v2.Herumi{})
+
+	msg := []byte("hello obol!")
+	pubShares, partials := newPartials(b, 10, msg)
+
+	for _, workers := range []int{1, 0} {
+		b.Run(fmt.Sprintf("workers_%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				require.Empty(b, v2.VerifyPartials(pubShares, msg, partials, workers))
+			}
+		})
+	}
+}
+
+func TestImplementationName(t *testing.T) {
+	t.Cleanup(func() { v2.SetImplementation(v2.Kryptology{}) })
+
+	v2.SetImplementation(v2.Herumi{})
+	require.Equal(t, "herumi", v2.ImplementationName())
+
+	v2.SetImplementation(v2.Kryptology{})
+	require.Equal(t, "kryptology", v2.ImplementationName())
+
+	// Implementations not identifying themselves default to their type name.
+	v2.SetImplementation(randomizedImpl{})
+	require.Equal(t, "v2_test.randomizedImpl", v2.ImplementationName())
+}
+
 func TestRandomized(t *testing.T) {
 	runSuite(t, randomizedImpl{
 		implementations: []v2.Implementation{
@@ -272,6 +759,15 @@ func (r randomizedImpl) ThresholdSplit(secret v2.PrivateKey, total uint, thresho
 	return impl.ThresholdSplit(secret, total, threshold)
 }
 
+func (r randomizedImpl) ThresholdSplitWithCommitments(secret v2.PrivateKey, total uint, threshold uint) (map[int]v2.PrivateKey, []v2.PublicKey, error) {
+	impl, err := r.selectImpl()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return impl.ThresholdSplitWithCommitments(secret, total, threshold)
+}
+
 func (r randomizedImpl) RecoverSecret(shares map[int]v2.PrivateKey, total uint, threshold uint) (v2.PrivateKey, error) {
 	impl, err := r.selectImpl()
 	if err != nil {
@@ -317,6 +813,33 @@ func (r randomizedImpl) VerifyAggregate(shares []v2.PublicKey, signature v2.Sign
 	return impl.VerifyAggregate(shares, signature, data)
 }
 
+func (r randomizedImpl) BatchVerify(compressedPublicKeys []v2.PublicKey, data [][]byte, signatures []v2.Signature) error {
+	impl, err := r.selectImpl()
+	if err != nil {
+		return err
+	}
+
+	return impl.BatchVerify(compressedPublicKeys, data, signatures)
+}
+
+func (r randomizedImpl) VerifyShare(share v2.PrivateKey, index int, commitments []v2.PublicKey) error {
+	impl, err := r.selectImpl()
+	if err != nil {
+		return err
+	}
+
+	return impl.VerifyShare(share, index, commitments)
+}
+
+func (r randomizedImpl) RecoverPublicKey(pubShares map[int]v2.PublicKey) (v2.PublicKey, error) {
+	impl, err := r.selectImpl()
+	if err != nil {
+		return v2.PublicKey{}, err
+	}
+
+	return impl.RecoverPublicKey(pubShares)
+}
+
 func (r randomizedImpl) Aggregate(signs []v2.Signature) (v2.Signature, error) {
 	impl, err := r.selectImpl()
 	if err != nil {
@@ -331,3 +854,65 @@ func FuzzRandomImplementations(f *testing.F) {
 		TestRandomized(t)
 	})
 }
+
+// fuzzImplementations are the implementations that must handle malformed keys and signatures gracefully.
+var fuzzImplementations = []v2.Implementation{v2.Herumi{}, v2.Kryptology{}}
+
+// addFuzzSeeds adds a valid public key and signature as well as malformed variants to the fuzz corpus.
+func addFuzzSeeds(f *testing.F) {
+	f.Helper()
+
+	data := []byte("hello obol!")
+
+	secret, err := v2.Kryptology{}.GenerateSecretKey()
+	require.NoError(f, err)
+
+	pubkey, err := v2.Kryptology{}.SecretToPublicKey(secret)
+	require.NoError(f, err)
+
+	sig, err := v2.Kryptology{}.Sign(secret, data)
+	require.NoError(f, err)
+
+	f.Add(pubkey[:], sig[:], data)
+	f.Add(pubkey[:len(pubkey)-1], sig[:len(sig)-1], data) // Truncated
+	f.Add(make([]byte, len(pubkey)), make([]byte, len(sig)), data)
+	f.Add(bytes.Repeat([]byte{0xff}, len(pubkey)), bytes.Repeat([]byte{0xff}, len(sig)), []byte{})
+	f.Add(bytes.Repeat([]byte{0xff}, 2*len(pubkey)), bytes.Repeat([]byte{0xff}, 2*len(sig)), data) // Oversized
+}
+
+func FuzzVerify(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, pubkeyBytes, sigBytes, data []byte) {
+		var (
+			pubkey v2.PublicKey
+			sig    v2.Signature
+		)
+		copy(pubkey[:], pubkeyBytes)
+		copy(sig[:], sigBytes)
+
+		for _, impl := range fuzzImplementations {
+			require.NotPanics(t, func() {
+				_ = impl.Verify(pubkey, data, sig)
+				_ = impl.VerifyAggregate([]v2.PublicKey{pubkey}, sig, data)
+			})
+		}
+	})
+}
+
+func FuzzAggregate(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, sigBytes1, sigBytes2, _ []byte) {
+		var sig1, sig2 v2.Signature
+		copy(sig1[:], sigBytes1)
+		copy(sig2[:], sigBytes2)
+
+		for _, impl := range fuzzImplementations {
+			require.NotPanics(t, func() {
+				_, _ = impl.Aggregate([]v2.Signature{sig1, sig2})
+				_, _ = impl.ThresholdAggregate(map[int]v2.Signature{1: sig1, 2: sig2})
+			})
+		}
+	})
+}
@@ -124,6 +124,7 @@ type Mock struct {
 	NodePeerCountFunc                      func(ctx context.Context) (int, error)
 	BlindedBeaconBlockProposalFunc         func(ctx context.Context, slot eth2p0.Slot, randaoReveal eth2p0.BLSSignature, graffiti []byte) (*eth2api.VersionedBlindedBeaconBlock, error)
 	BeaconCommitteesFunc                   func(ctx context.Context, stateID string) ([]*eth2v1.BeaconCommittee, error)
+	BeaconCommitteesAtEpochFunc            func(ctx context.Context, stateID string, epoch eth2p0.Epoch) ([]*eth2v1.BeaconCommittee, error)
 	BeaconBlockProposalFunc                func(ctx context.Context, slot eth2p0.Slot, randaoReveal eth2p0.BLSSignature, graffiti []byte) (*eth2spec.VersionedBeaconBlock, error)
 	BeaconBlockRootFunc                    func(ctx context.Context, blockID string) (*eth2p0.Root, error)
 	SignedBeaconBlockFunc                  func(ctx context.Context, blockID string) (*eth2spec.VersionedSignedBeaconBlock, error)
@@ -134,6 +135,7 @@ type Mock struct {
 	SubmitVoluntaryExitFunc                func(context.Context, *eth2p0.SignedVoluntaryExit) error
 	ValidatorsByPubKeyFunc                 func(context.Context, string, []eth2p0.BLSPubKey) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error)
 	ValidatorsFunc                         func(context.Context, string, []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error)
+	ValidatorBalancesFunc                  func(context.Context, string, []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]eth2p0.Gwei, error)
 	GenesisTimeFunc                        func(context.Context) (time.Time, error)
 	NodeSyncingFunc                        func(context.Context) (*eth2v1.SyncState, error)
 	EventsFunc                             func(context.Context, []string, eth2client.EventHandlerFunc) error
@@ -197,6 +199,10 @@ func (m Mock) BeaconCommittees(ctx context.Context, stateID string) ([]*eth2v1.B
 	return m.BeaconCommitteesFunc(ctx, stateID)
 }
 
+func (m Mock) BeaconCommitteesAtEpoch(ctx context.Context, stateID string, epoch eth2p0.Epoch) ([]*eth2v1.BeaconCommittee, error) {
+	return m.BeaconCommitteesAtEpochFunc(ctx, stateID, epoch)
+}
+
 func (m Mock) ProposerDuties(ctx context.Context, epoch eth2p0.Epoch, validatorIndices []eth2p0.ValidatorIndex) ([]*eth2v1.ProposerDuty, error) {
 	return m.ProposerDutiesFunc(ctx, epoch, validatorIndices)
 }
@@ -209,6 +215,10 @@ func (m Mock) Validators(ctx context.Context, stateID string, validatorIndices [
 	return m.ValidatorsFunc(ctx, stateID, validatorIndices)
 }
 
+func (m Mock) ValidatorBalances(ctx context.Context, stateID string, validatorIndices []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]eth2p0.Gwei, error) {
+	return m.ValidatorBalancesFunc(ctx, stateID, validatorIndices)
+}
+
 func (m Mock) ValidatorsByPubKey(ctx context.Context, stateID string, validatorPubKeys []eth2p0.BLSPubKey) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
 	return m.ValidatorsByPubKeyFunc(ctx, stateID, validatorPubKeys)
 }
@@ -4,6 +4,7 @@ package beaconmock_test
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	eth2p0 "github.com/attestantio/go-eth2-client/spec/phase0"
@@ -13,6 +14,10 @@ import (
 	"github.com/obolnetwork/charon/testutil/beaconmock"
 )
 
+func TestMain(m *testing.M) {
+	os.Exit(testutil.MainWithGoldens(m))
+}
+
 //go:generate go test . -update
 
 func TestDeterministicAttesterDuties(t *testing.T) {
@@ -165,6 +165,28 @@ func WithValidatorSet(set ValidatorSet) Option {
 			return resp, nil
 		}
 
+		mock.ValidatorBalancesFunc = func(ctx context.Context, stateID string, indexes []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]eth2p0.Gwei, error) {
+			resp := make(map[eth2p0.ValidatorIndex]eth2p0.Gwei)
+			if len(indexes) == 0 {
+				for index, val := range set {
+					resp[index] = val.Balance
+				}
+
+				return resp, nil
+			}
+
+			for _, index := range indexes {
+				val, ok := set[index]
+				if ok {
+					resp[index] = val.Balance
+				} else {
+					log.Debug(ctx, "Index not found")
+				}
+			}
+
+			return resp, nil
+		}
+
 		mock.getAllValidatorsFunc = func(ctx context.Context) []*eth2v1.Validator {
 			return set.Validators()
 		}
@@ -234,6 +256,23 @@ func WithSlotsPerEpoch(slotsPerEpoch int) Option {
 	}
 }
 
+// WithMinimalPreset configures the http mock with the minimal preset constants used by testnets.
+func WithMinimalPreset() Option {
+	return func(mock *Mock) {
+		for key, value := range map[string]string{
+			"PRESET_BASE":             "minimal",
+			"SLOTS_PER_EPOCH":         "8",
+			"MAX_COMMITTEES_PER_SLOT": "4",
+		} {
+			mock.overrides = append(mock.overrides, staticOverride{
+				Endpoint: "/eth/v1/config/spec",
+				Key:      key,
+				Value:    value,
+			})
+		}
+	}
+}
+
 // WithDeterministicAttesterDuties configures the mock to provide deterministic
 // duties based on provided arguments and config.
 // Note it depends on ValidatorsFunc being populated, e.g. via WithValidatorSet.
@@ -285,6 +324,33 @@ func WithDeterministicAttesterDuties(factor int) Option {
 
 			return resp, nil
 		}
+
+		// Provide beacon committees consistent with the attester duties of all validators.
+		mock.BeaconCommitteesAtEpochFunc = func(ctx context.Context, _ string, epoch eth2p0.Epoch) ([]*eth2v1.BeaconCommittee, error) {
+			var indices []eth2p0.ValidatorIndex
+			for _, val := range mock.getAllValidators(ctx) {
+				indices = append(indices, val.Index)
+			}
+
+			duties, err := mock.AttesterDuties(ctx, epoch, indices)
+			if err != nil {
+				return nil, err
+			}
+
+			var resp []*eth2v1.BeaconCommittee
+			for _, duty := range duties {
+				validators := make([]eth2p0.ValidatorIndex, duty.CommitteeLength)
+				validators[duty.ValidatorCommitteeIndex] = duty.ValidatorIndex
+
+				resp = append(resp, &eth2v1.BeaconCommittee{
+					Slot:       duty.Slot,
+					Index:      duty.CommitteeIndex,
+					Validators: validators,
+				})
+			}
+
+			return resp, nil
+		}
 	}
 }
 
@@ -467,6 +533,9 @@ func defaultMock(httpMock HTTPMock, httpServer *http.Server, clock clockwork.Clo
 		BeaconCommitteesFunc: func(context.Context, string) ([]*eth2v1.BeaconCommittee, error) {
 			return []*eth2v1.BeaconCommittee{}, nil
 		},
+		BeaconCommitteesAtEpochFunc: func(context.Context, string, eth2p0.Epoch) ([]*eth2v1.BeaconCommittee, error) {
+			return []*eth2v1.BeaconCommittee{}, nil
+		},
 		AttesterDutiesFunc: func(context.Context, eth2p0.Epoch, []eth2p0.ValidatorIndex) ([]*eth2v1.AttesterDuty, error) {
 			return []*eth2v1.AttesterDuty{}, nil
 		},
@@ -496,6 +565,9 @@ func defaultMock(httpMock HTTPMock, httpServer *http.Server, clock clockwork.Clo
 		ValidatorsByPubKeyFunc: func(context.Context, string, []eth2p0.BLSPubKey) (map[eth2p0.ValidatorIndex]*eth2v1.Validator, error) {
 			return nil, nil
 		},
+		ValidatorBalancesFunc: func(context.Context, string, []eth2p0.ValidatorIndex) (map[eth2p0.ValidatorIndex]eth2p0.Gwei, error) {
+			return nil, nil
+		},
 		SubmitAttestationsFunc: func(context.Context, []*eth2p0.Attestation) error {
 			return nil
 		},
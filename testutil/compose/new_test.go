@@ -14,6 +14,10 @@ import (
 	"github.com/obolnetwork/charon/testutil/compose"
 )
 
+func TestMain(m *testing.M) {
+	os.Exit(testutil.MainWithGoldens(m))
+}
+
 //go:generate go test . -update -clean
 
 func TestNewDefaultConfig(t *testing.T) {
@@ -5,6 +5,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -12,6 +13,10 @@ import (
 	"github.com/obolnetwork/charon/testutil"
 )
 
+func TestMain(m *testing.M) {
+	os.Exit(testutil.MainWithGoldens(m))
+}
+
 var git = flag.Bool("git", false, "Enables tests that require git and a full checkout")
 
 func TestPRFromLog(t *testing.T) {
@@ -4,58 +4,105 @@
 package testutil
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
+	"fmt"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/obolnetwork/charon/app/errors"
 )
 
 var (
-	update = flag.Bool("update", false, "Create or update golden files, instead of comparing them")
-	clean  = flag.Bool("clean", false, "Deletes the testdata folder before updating (noop of update==false)")
+	update  = flag.Bool("update", false, "Create or update golden files, instead of comparing them")
+	clean   = flag.Bool("clean", false, "Deletes the testdata folder before updating (noop of update==false)")
+	prune   = flag.Bool("prune", false, "Deletes stale golden files not written by any test, see PruneStaleGoldens (noop of update==false)")
+	summary = flag.String("golden-summary", "", "Appends the created, modified and deleted golden files to this summary file, see WriteGoldenSummary (noop of update==false)")
+	cache   = flag.Bool("golden-cache", true, "Caches golden files in memory, reading each from disk once per run instead of per comparison")
 )
 
-var cleanOnce sync.Once
+var (
+	cleanOnce sync.Once
+	goldens   = newGoldenChanges()
+	files     = newGoldenFiles(os.ReadFile)
+)
+
+// defaultFilePerm is the default file mode of written golden files.
+const defaultFilePerm os.FileMode = 0o644
+
+type goldenOpts struct {
+	filename string
+	perm     os.FileMode
+}
 
 // WithFilename configures a custom golden test filename.
-func WithFilename(name string) func(*string) {
-	return func(filename *string) {
-		*filename = name
+func WithFilename(name string) func(*goldenOpts) {
+	return func(opts *goldenOpts) {
+		opts.filename = name
+	}
+}
+
+// WithFilePerm configures the file mode of the golden file written during -update, instead of the default 0o644.
+// This allows e.g. group-writable fixtures in environments with shared ownership.
+func WithFilePerm(perm os.FileMode) func(*goldenOpts) {
+	return func(opts *goldenOpts) {
+		opts.perm = perm
 	}
 }
 
 // RequireGoldenBytes asserts that a golden testdata file exists containing the exact data.
 // This is heavily inspired from https://github.com/sebdah/goldie.
-func RequireGoldenBytes(t *testing.T, data []byte, opts ...func(*string)) {
+func RequireGoldenBytes(t *testing.T, data []byte, opts ...func(*goldenOpts)) {
 	t.Helper()
 
-	filename := strings.ReplaceAll(t.Name(), "/", "_") + ".golden"
+	o := goldenOpts{
+		filename: strings.ReplaceAll(t.Name(), "/", "_") + ".golden",
+		perm:     defaultFilePerm,
+	}
 	for _, opt := range opts {
-		opt(&filename)
+		opt(&o)
 	}
-	filename = path.Join("testdata", filename)
+	filename := path.Join("testdata", o.filename)
 
 	if *update {
 		if *clean {
 			cleanOnce.Do(func() {
+				require.NoError(t, goldens.Snapshot("testdata"))
 				_ = os.RemoveAll("testdata")
 			})
 		}
 
 		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		goldens.Written(filename, data)
+		t.Cleanup(func() {
+			if t.Skipped() || t.Failed() {
+				goldens.Incomplete() // Other goldens of the test may not have been written.
+			}
+		})
 
 		_ = os.Remove(filename)
-		require.NoError(t, os.WriteFile(filename, data, 0o644)) //nolint:gosec
+		require.NoError(t, os.WriteFile(filename, data, o.perm)) //nolint:gosec
+		require.NoError(t, os.Chmod(filename, o.perm))           //nolint:gosec // Apply the mode regardless of the umask.
+		files.Invalidate(filename)
 
 		return
 	}
 
-	expected, err := os.ReadFile(filename)
+	readFile := os.ReadFile
+	if *cache {
+		readFile = files.Read
+	}
+
+	expected, err := readFile(filename)
 	if os.IsNotExist(err) {
 		t.Fatalf("golden file does not exist, %s, generate by running with -update", filename)
 		return
@@ -66,7 +113,7 @@ func RequireGoldenBytes(t *testing.T, data []byte, opts ...func(*string)) {
 
 // RequireGoldenJSON asserts that a golden testdata file exists containing the JSON serialised form of the data object.
 // This is heavily inspired from https://github.com/sebdah/goldie.
-func RequireGoldenJSON(t *testing.T, data interface{}, opts ...func(*string)) {
+func RequireGoldenJSON(t *testing.T, data interface{}, opts ...func(*goldenOpts)) {
 	t.Helper()
 
 	b, err := json.MarshalIndent(data, "", " ")
@@ -74,3 +121,334 @@ func RequireGoldenJSON(t *testing.T, data interface{}, opts ...func(*string)) {
 
 	RequireGoldenBytes(t, b, opts...)
 }
+
+// MainWithGoldens runs the tests of the package and maintains its golden files after -update runs, pruning stale
+// golden files (see PruneStaleGoldens) and writing the golden summary (see WriteGoldenSummary). It returns the exit code,
+// which is non-zero if the tests failed, if stale golden files remain or if the summary can't be written.
+// Call it from the TestMain of packages asserting golden files:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(testutil.MainWithGoldens(m))
+//	}
+func MainWithGoldens(m *testing.M) int {
+	code := m.Run()
+
+	stale, err := PruneStaleGoldens(code)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed pruning stale golden files: %v\n", err)
+		code = 1
+	} else if len(stale) > 0 && !*prune {
+		fmt.Fprintf(os.Stderr, "Stale golden files not written by any test, delete them via -update -prune: %s\n", strings.Join(stale, ", "))
+		code = 1
+	}
+
+	if err := WriteGoldenSummary(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed writing golden summary: %v\n", err)
+		code = 1
+	}
+
+	return code
+}
+
+// WriteGoldenSummary appends the golden files created, modified or deleted (by -clean) during an -update run
+// of the package to the -golden-summary file, one "<created|modified|deleted> <path>" line per file.
+// This makes large golden changes reviewable, e.g. by CI surfacing the set of changed fixtures.
+// It is a noop if not updating or if no summary file is configured. It is called by MainWithGoldens.
+func WriteGoldenSummary() error {
+	if !*update || *summary == "" {
+		return nil
+	}
+
+	lines := goldens.Summary()
+	if len(lines) == 0 {
+		return nil
+	}
+
+	// Prefix paths with the package directory relative to the module root, since packages share the summary file.
+	prefix := pkgDir()
+	var b bytes.Buffer
+	for _, line := range lines {
+		b.WriteString(line.Status + " " + path.Join(prefix, line.Filename) + "\n")
+	}
+
+	f, err := os.OpenFile(*summary, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec
+	if err != nil {
+		return errors.Wrap(err, "open golden summary")
+	}
+	defer f.Close()
+
+	if _, err := f.Write(b.Bytes()); err != nil {
+		return errors.Wrap(err, "write golden summary")
+	}
+
+	return nil
+}
+
+// PruneStaleGoldens returns the stale golden files of the package, i.e., the "*.golden" files in the testdata folder
+// not written by any test during an -update run, e.g. of deleted tests. The stale files are deleted if -prune is set
+// and included as deleted in the golden summary, see WriteGoldenSummary. Golden files with custom non-".golden"
+// filenames are never considered stale. Since only a complete run writes all golden files, it is a noop if not
+// updating, if the run failed (non-zero code), if tests are filtered by -run, -skip or -short, or if a test asserting
+// goldens was skipped or failed. Note that tests skipped before asserting any golden can't be detected.
+// It is called by MainWithGoldens with the code returned by m.Run, before WriteGoldenSummary.
+func PruneStaleGoldens(code int) ([]string, error) {
+	if !*update || code != 0 || testsFiltered() || goldens.IsIncomplete() {
+		return nil, nil
+	}
+
+	stale, err := goldens.Stale("testdata")
+	if err != nil || !*prune {
+		return stale, err
+	}
+
+	for _, filename := range stale {
+		if err := goldens.Delete(filename); err != nil {
+			return nil, err
+		}
+	}
+
+	return stale, nil
+}
+
+// testsFiltered returns true if the tests of the run are filtered by the -run, -skip or -short flags.
+func testsFiltered() bool {
+	for _, name := range []string{"test.run", "test.skip"} {
+		if f := flag.Lookup(name); f != nil && f.Value.String() != "" {
+			return true
+		}
+	}
+
+	return testing.Short()
+}
+
+func newGoldenFiles(readFile func(string) ([]byte, error)) *goldenFiles {
+	return &goldenFiles{
+		readFile: readFile,
+		files:    make(map[string][]byte),
+	}
+}
+
+// goldenFiles caches the contents of golden files by absolute path, so repeated comparisons
+// against the same golden file only read it from disk once.
+type goldenFiles struct {
+	readFile func(string) ([]byte, error)
+
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// Read returns the cached contents of the file, reading it from disk if not cached.
+// Read errors are not cached.
+func (c *goldenFiles) Read(filename string) ([]byte, error) {
+	key, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "golden file path")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if b, ok := c.files[key]; ok {
+		return b, nil
+	}
+
+	b, err := c.readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	c.files[key] = b
+
+	return b, nil
+}
+
+// Invalidate removes the cached contents of the file, e.g. since it was written.
+func (c *goldenFiles) Invalidate(filename string) {
+	key, err := filepath.Abs(filename)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.files, key)
+}
+
+// goldenChange is a summary line of a changed golden file.
+type goldenChange struct {
+	Status   string
+	Filename string
+}
+
+func newGoldenChanges() *goldenChanges {
+	return &goldenChanges{
+		cleaned: make(map[string][]byte),
+		written: make(map[string]string),
+	}
+}
+
+// goldenChanges tracks the golden files changed during an update run.
+type goldenChanges struct {
+	mu         sync.Mutex
+	cleaned    map[string][]byte // Contents of files before being deleted by -clean.
+	written    map[string]string // Status of written files, empty if unchanged.
+	incomplete bool              // True if a test asserting goldens was skipped or failed.
+}
+
+// Incomplete records that not all golden files may have been written, since a test was skipped or failed.
+func (c *goldenChanges) Incomplete() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.incomplete = true
+}
+
+// IsIncomplete returns true if not all golden files may have been written.
+func (c *goldenChanges) IsIncomplete() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.incomplete
+}
+
+// Snapshot records the contents of the files in the directory before it is deleted.
+func (c *goldenChanges) Snapshot(dir string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := filepath.WalkDir(dir, func(filename string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			return errors.Wrap(err, "read golden file")
+		}
+		c.cleaned[filepath.ToSlash(filename)] = b
+
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// Written records the file being written with the data, comparing it to the previous contents.
+// It must be called before the file is written.
+func (c *goldenChanges) Written(filename string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev, ok := c.cleaned[filename]
+	if !ok {
+		var err error
+		prev, err = os.ReadFile(filename)
+		ok = err == nil
+	}
+
+	var status string
+	if !ok {
+		status = "created"
+	} else if !bytes.Equal(prev, data) {
+		status = "modified"
+	}
+
+	c.written[filename] = status
+}
+
+// Stale returns the "*.golden" files in the directory not written during the update run, sorted by filename.
+func (c *goldenChanges) Stale(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "read golden dir")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var resp []string
+	for _, entry := range entries {
+		filename := path.Join(dir, entry.Name())
+		if entry.IsDir() || path.Ext(filename) != ".golden" {
+			continue
+		} else if _, ok := c.written[filename]; ok {
+			continue
+		}
+
+		resp = append(resp, filename)
+	}
+
+	sort.Strings(resp)
+
+	return resp, nil
+}
+
+// Delete records the contents of the stale file and deletes it.
+func (c *goldenChanges) Delete(filename string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return errors.Wrap(err, "read golden file")
+	}
+	c.cleaned[filename] = b
+
+	if err := os.Remove(filename); err != nil {
+		return errors.Wrap(err, "delete golden file")
+	}
+
+	return nil
+}
+
+// Summary returns the changed golden files sorted by filename.
+func (c *goldenChanges) Summary() []goldenChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var resp []goldenChange
+	for filename, status := range c.written {
+		if status != "" {
+			resp = append(resp, goldenChange{Status: status, Filename: filename})
+		}
+	}
+	for filename := range c.cleaned {
+		if _, ok := c.written[filename]; !ok {
+			resp = append(resp, goldenChange{Status: "deleted", Filename: filename})
+		}
+	}
+
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Filename < resp[j].Filename
+	})
+
+	return resp
+}
+
+// pkgDir returns the working directory relative to the module root or an empty string if not found.
+func pkgDir() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for dir := wd; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			rel, err := filepath.Rel(dir, wd)
+			if err != nil {
+				return ""
+			}
+
+			return filepath.ToSlash(rel)
+		}
+
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright © 2022-2023 Obol Labs Inc. Licensed under the terms of a Business Source License 1.1
+
+package testutil
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteGoldenSummary(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.Chdir(dir))
+
+	summaryFile := filepath.Join(dir, "summary.txt")
+
+	// Configure an -update -clean -golden-summary run.
+	prevUpdate, prevClean, prevSummary := *update, *clean, *summary
+	*update, *clean, *summary = true, true, summaryFile
+	goldens = newGoldenChanges()
+	cleanOnce = sync.Once{}
+
+	t.Cleanup(func() {
+		*update, *clean, *summary = prevUpdate, prevClean, prevSummary
+		goldens = newGoldenChanges()
+		cleanOnce = sync.Once{}
+		require.NoError(t, os.Chdir(wd))
+	})
+
+	require.NoError(t, os.MkdirAll("testdata", 0o755))
+	for name, data := range map[string]string{
+		"modified.golden":  "old",
+		"unchanged.golden": "same",
+		"deleted.golden":   "stale",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join("testdata", name), []byte(data), 0o644))
+	}
+
+	RequireGoldenBytes(t, []byte("new"), WithFilename("modified.golden"))
+	RequireGoldenBytes(t, []byte("same"), WithFilename("unchanged.golden"))
+	RequireGoldenBytes(t, []byte("new"), WithFilename("created.golden"))
+
+	require.NoError(t, WriteGoldenSummary())
+
+	b, err := os.ReadFile(summaryFile)
+	require.NoError(t, err)
+	require.Equal(t, "created testdata/created.golden\n"+
+		"deleted testdata/deleted.golden\n"+
+		"modified testdata/modified.golden\n", string(b))
+}
+
+func TestGoldenFilePerm(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	require.NoError(t, os.Chdir(t.TempDir()))
+
+	prevUpdate, prevClean, prevSummary := *update, *clean, *summary
+	*update, *clean, *summary = true, false, ""
+	goldens = newGoldenChanges()
+
+	t.Cleanup(func() {
+		*update, *clean, *summary = prevUpdate, prevClean, prevSummary
+		goldens = newGoldenChanges()
+		require.NoError(t, os.Chdir(wd))
+	})
+
+	RequireGoldenBytes(t, []byte("default"), WithFilename("default.golden"))
+	RequireGoldenBytes(t, []byte("shared"), WithFilename("shared.golden"), WithFilePerm(0o664))
+
+	for name, perm := range map[string]os.FileMode{
+		"default.golden": 0o644,
+		"shared.golden":  0o664,
+	} {
+		info, err := os.Stat(filepath.Join("testdata", name))
+		require.NoError(t, err)
+		require.Equal(t, perm, info.Mode().Perm(), name)
+	}
+}
+
+func TestPruneStaleGoldens(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.Chdir(dir))
+
+	summaryFile := filepath.Join(dir, "summary.txt")
+
+	// Configure a full -update -prune -golden-summary run.
+	prevUpdate, prevPrune, prevSummary := *update, *prune, *summary
+	prevRun := flag.Lookup("test.run").Value.String()
+	prevSkip := flag.Lookup("test.skip").Value.String()
+	*update, *prune, *summary = true, true, summaryFile
+	require.NoError(t, flag.Set("test.run", ""))
+	require.NoError(t, flag.Set("test.skip", ""))
+	goldens = newGoldenChanges()
+
+	t.Cleanup(func() {
+		*update, *prune, *summary = prevUpdate, prevPrune, prevSummary
+		require.NoError(t, flag.Set("test.run", prevRun))
+		require.NoError(t, flag.Set("test.skip", prevSkip))
+		goldens = newGoldenChanges()
+		require.NoError(t, os.Chdir(wd))
+	})
+
+	require.NoError(t, os.MkdirAll("testdata", 0o755))
+	for name, data := range map[string]string{
+		"asserted.golden":  "old",
+		"orphan_a.golden":  "stale",
+		"orphan_b.golden":  "stale",
+		"fixture.json":     "input",
+		"custom_name.json": "custom",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join("testdata", name), []byte(data), 0o644))
+	}
+
+	RequireGoldenBytes(t, []byte("new"), WithFilename("asserted.golden"))
+
+	// Goldens aren't pruned if the run failed or tests were filtered.
+	stale, err := PruneStaleGoldens(1)
+	require.NoError(t, err)
+	require.Empty(t, stale)
+
+	require.NoError(t, flag.Set("test.skip", "TestOther"))
+	stale, err = PruneStaleGoldens(0)
+	require.NoError(t, err)
+	require.Empty(t, stale)
+	require.NoError(t, flag.Set("test.skip", ""))
+
+	stale, err = PruneStaleGoldens(0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"testdata/orphan_a.golden", "testdata/orphan_b.golden"}, stale)
+
+	entries, err := os.ReadDir("testdata")
+	require.NoError(t, err)
+	var remaining []string
+	for _, entry := range entries {
+		remaining = append(remaining, entry.Name())
+	}
+	require.Equal(t, []string{"asserted.golden", "custom_name.json", "fixture.json"}, remaining)
+
+	require.NoError(t, WriteGoldenSummary())
+
+	b, err := os.ReadFile(summaryFile)
+	require.NoError(t, err)
+	require.Equal(t, "modified testdata/asserted.golden\n"+
+		"deleted testdata/orphan_a.golden\n"+
+		"deleted testdata/orphan_b.golden\n", string(b))
+
+	// Goldens aren't pruned if a test asserting goldens was skipped, since its other goldens may be stale.
+	t.Run("skipped", func(t *testing.T) {
+		RequireGoldenBytes(t, []byte("skipped"), WithFilename("skipped.golden"))
+		t.Skip("skipped after asserting a golden")
+	})
+
+	require.NoError(t, os.WriteFile(filepath.Join("testdata", "orphan_c.golden"), []byte("stale"), 0o644))
+	stale, err = PruneStaleGoldens(0)
+	require.NoError(t, err)
+	require.Empty(t, stale)
+	require.FileExists(t, filepath.Join("testdata", "orphan_c.golden"))
+}
+
+func TestGoldenCache(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	require.NoError(t, os.Chdir(t.TempDir()))
+
+	var reads int
+	prevUpdate, prevCache, prevFiles := *update, *cache, files
+	*update, *cache = false, true
+	files = newGoldenFiles(func(filename string) ([]byte, error) {
+		reads++
+		return os.ReadFile(filename)
+	})
+
+	t.Cleanup(func() {
+		*update, *cache, files = prevUpdate, prevCache, prevFiles
+		goldens = newGoldenChanges()
+		require.NoError(t, os.Chdir(wd))
+	})
+
+	require.NoError(t, os.MkdirAll("testdata", 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join("testdata", "cached.golden"), []byte("old"), 0o644))
+
+	for i := 0; i < 3; i++ {
+		RequireGoldenBytes(t, []byte("old"), WithFilename("cached.golden"))
+	}
+	require.Equal(t, 1, reads)
+
+	// Updating the golden file invalidates the cache.
+	*update = true
+	RequireGoldenBytes(t, []byte("new"), WithFilename("cached.golden"))
+
+	*update = false
+	for i := 0; i < 3; i++ {
+		RequireGoldenBytes(t, []byte("new"), WithFilename("cached.golden"))
+	}
+	require.Equal(t, 2, reads)
+}
@@ -743,7 +743,7 @@ func RandomExecutionAddress() bellatrix.ExecutionAddress {
 
 // SkipIfBindErr skips the test if the error is "bind: address already in use".
 // This is a workaround for the issue related to AvailableAddr.
-func SkipIfBindErr(t *testing.T, err error) {
+func SkipIfBindErr(t testing.TB, err error) {
 	t.Helper()
 
 	if err != nil && strings.Contains(err.Error(), "bind: address already in use") {
@@ -756,7 +756,7 @@ func SkipIfBindErr(t *testing.T, err error) {
 // Note that this is unfortunately only best-effort. Since the port is not
 // "locked" or "reserved", other processes sometimes grab the port.
 // Remember to call SkipIfBindErr as workaround for this issue.
-func AvailableAddr(t *testing.T) *net.TCPAddr {
+func AvailableAddr(t testing.TB) *net.TCPAddr {
 	t.Helper()
 
 	l, err := net.Listen("tcp", "localhost:0")
@@ -790,7 +790,7 @@ func AvailableMultiAddr(t *testing.T) multiaddr.Multiaddr {
 	return addr
 }
 
-func CreateHost(t *testing.T, addr *net.TCPAddr, opts ...libp2p.Option) host.Host {
+func CreateHost(t testing.TB, addr *net.TCPAddr, opts ...libp2p.Option) host.Host {
 	t.Helper()
 	pkey, _, err := p2pcrypto.GenerateSecp256k1Key(crand.Reader)
 	require.NoError(t, err)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"sort"
 	"testing"
 	"time"
@@ -21,6 +22,10 @@ import (
 	"github.com/obolnetwork/charon/testutil/validatormock"
 )
 
+func TestMain(m *testing.M) {
+	os.Exit(testutil.MainWithGoldens(m))
+}
+
 //go:generate go test -run=TestAttest -update -clean
 
 func TestAttest(t *testing.T) {